@@ -0,0 +1,158 @@
+// Package apperror berisi error domain bertipe yang dikembalikan lapisan
+// service. Setiap error membawa jenis (Kind) yang menentukan status HTTP dan
+// kode stabil yang bisa dipakai client untuk membedakan error, tanpa
+// bergantung pada isi pesan.
+package apperror
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Kind mengelompokkan error domain berdasarkan penyebabnya.
+type Kind string
+
+const (
+	KindValidation      Kind = "validation"        // input tidak valid
+	KindUnauthorized    Kind = "unauthorized"      // belum login atau kredensial salah
+	KindForbidden       Kind = "forbidden"         // tidak punya akses
+	KindNotFound        Kind = "not_found"         // data tidak ditemukan
+	KindConflict        Kind = "conflict"          // bentrok dengan data yang sudah ada
+	KindBusinessRule    Kind = "business_rule"     // input valid tetapi melanggar aturan bisnis
+	KindTooManyRequests Kind = "too_many_requests" // rate limit atau akun terkunci
+	KindUnavailable     Kind = "unavailable"       // dependency lambat atau tidak tersedia
+	KindInternal        Kind = "internal"          // error tak terduga
+)
+
+// Status mengembalikan status HTTP untuk jenis error.
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindBusinessRule:
+		return http.StatusUnprocessableEntity
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error adalah error domain. Code bersifat stabil (snake_case, mis.
+// "booking_date_unavailable"), sedangkan Message boleh berubah dan ditujukan
+// untuk manusia.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError // field request yang tidak valid, untuk KindValidation
+	Err     error        // penyebab, tidak ditampilkan ke client
+}
+
+// FieldError menjelaskan satu field request yang gagal validasi. Rule adalah
+// nama aturan yang dilanggar (mis. "required" atau "phone_id"), sedangkan
+// Message sudah diterjemahkan sesuai bahasa request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Validation(code, message string) *Error      { return New(KindValidation, code, message) }
+func Unauthorized(code, message string) *Error    { return New(KindUnauthorized, code, message) }
+func Forbidden(code, message string) *Error       { return New(KindForbidden, code, message) }
+func NotFound(code, message string) *Error        { return New(KindNotFound, code, message) }
+func Conflict(code, message string) *Error        { return New(KindConflict, code, message) }
+func BusinessRule(code, message string) *Error    { return New(KindBusinessRule, code, message) }
+func TooManyRequests(code, message string) *Error { return New(KindTooManyRequests, code, message) }
+
+// Internal membungkus error tak terduga. Pesan aslinya hanya masuk log.
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "internal server error", Err: err}
+}
+
+// InvalidRequest dipakai saat body atau form request tidak bisa dibaca.
+func InvalidRequest(err error) *Error {
+	return &Error{Kind: KindValidation, Code: "invalid_request", Message: err.Error(), Err: err}
+}
+
+// InvalidParameter dipakai saat parameter path atau query tidak valid.
+func InvalidParameter(message string) *Error {
+	return Validation("invalid_parameter", message)
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil && e.Kind == KindInternal {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is membuat errors.Is mencocokkan error dengan Kind dan Code yang sama,
+// sehingga error sentinel tetap cocok setelah dibungkus dengan penyebab atau
+// pesan lain.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap mengembalikan salinan error dengan penyebab err.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithMessage mengembalikan salinan error dengan pesan lain, mis. untuk
+// menyebut nilai yang ditolak.
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithFields mengembalikan salinan error dengan daftar field yang tidak valid.
+func (e *Error) WithFields(fields []FieldError) *Error {
+	c := *e
+	c.Fields = fields
+	return &c
+}
+
+// From mengubah err menjadi *Error. Record yang tidak ditemukan dan query
+// yang melewati batas waktu tetap dipetakan dengan benar meskipun service
+// lupa membungkusnya; error lain yang bukan error domain dianggap error
+// internal.
+func From(err error) *Error {
+	var appErr *Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return NotFound("not_found", "resource not found").Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return New(KindUnavailable, "timeout", "the request took too long, please try again").Wrap(err)
+	default:
+		return Internal(err)
+	}
+}
@@ -0,0 +1,62 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestErrorIsMatchesKindAndCode(t *testing.T) {
+	errTaken := Conflict("email_taken", "email already registered")
+
+	wrapped := fmt.Errorf("register: %w", errTaken.WithMessage("email a@b.c already registered").Wrap(errors.New("unique constraint")))
+	assert.ErrorIs(t, wrapped, errTaken)
+	assert.NotErrorIs(t, wrapped, Conflict("other_code", "x"))
+	assert.NotErrorIs(t, wrapped, NotFound("email_taken", "x"))
+
+	// Sentinel tidak ikut berubah oleh WithMessage atau Wrap
+	assert.Equal(t, "email already registered", errTaken.Error())
+	assert.Nil(t, errTaken.Err)
+}
+
+func TestFrom(t *testing.T) {
+	cause := errors.New("database is locked")
+
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("get: %w", NotFound("booking_not_found", "booking not found")), http.StatusNotFound, "booking_not_found"},
+		{fmt.Errorf("get: %w", gorm.ErrRecordNotFound), http.StatusNotFound, "not_found"},
+		{context.DeadlineExceeded, http.StatusServiceUnavailable, "timeout"},
+		{cause, http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		appErr := From(tt.err)
+		assert.Equal(t, tt.status, appErr.Kind.Status(), tt.err.Error())
+		assert.Equal(t, tt.code, appErr.Code, tt.err.Error())
+	}
+
+	// Pesan error internal tetap tersedia untuk log, tetapi tidak untuk client
+	internal := From(cause)
+	assert.Equal(t, "database is locked", internal.Error())
+	assert.Equal(t, "internal server error", internal.Problem("/bookings").Detail)
+}
+
+func TestProblem(t *testing.T) {
+	problem := BusinessRule("booking_date_not_allowed", "booking date must be at least tomorrow").Problem("/bookings")
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Unprocessable Entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "booking date must be at least tomorrow",
+		Instance: "/bookings",
+		Code:     "booking_date_not_allowed",
+	}, problem)
+}
@@ -0,0 +1,34 @@
+package apperror
+
+import "net/http"
+
+// ProblemContentType adalah media type response error (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Problem adalah body response error sesuai RFC 7807. Type selalu
+// "about:blank" sehingga Title berisi teks status HTTP; client sebaiknya
+// membedakan error lewat Code.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"` // hanya untuk code validation_failed
+}
+
+// Problem membentuk body response untuk error. instance berisi path request.
+func (e *Error) Problem(instance string) Problem {
+	status := e.Kind.Status()
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Errors:   e.Fields,
+	}
+}
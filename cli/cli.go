@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "start the HTTP API, event dispatcher and scheduler (default)", runServe},
+		{"migrate", "apply, revert, list or create database migrations", runMigrate},
+		{"seed", "insert demo users, technicians, services, bookings, payments and reviews", runSeed},
+		{"create-admin", "create an admin account", runCreateAdmin},
+		{"reset-password", "set a new password for a user", runResetPassword},
+		{"rotate-secrets", "generate new signing secrets for webhook endpoints", runRotateSecrets},
+		{"export", "export users, services, bookings, payments or reviews as JSON or CSV", runExport},
+		{"purge-expired", "delete expired bookings and payments older than a cutoff", runPurgeExpired},
+	}
+}
+
+// errConfigPrinted menandakan --print-config sudah ditampilkan dan command
+// tidak perlu dilanjutkan.
+var errConfigPrinted = errors.New("config printed")
+
+// Run menjalankan command sesuai argumen pertama dan mengembalikan exit code.
+// Tanpa nama command (atau jika argumen pertama berupa flag) server dijalankan.
+func Run(args []string) int {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" {
+		usage(os.Stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		err := cmd.run(args)
+		switch {
+		case err == nil, errors.Is(err, errConfigPrinted), errors.Is(err, flag.ErrHelp):
+			return 0
+		default:
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage(os.Stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: capstone <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'capstone <command> -h' to see the flags of a command.")
+}
+
+// setup memuat konfigurasi (flag milik command sudah didaftarkan di fs),
+// menerapkannya ke package utils lalu menghubungkan database.
+func setup(fs *flag.FlagSet, args []string) (config.Config, config.Options, error) {
+	cfg, opts, err := load(fs, args)
+	if err != nil {
+		return cfg, opts, err
+	}
+	if cfg.Storage != config.StorageDatabase {
+		return cfg, opts, fmt.Errorf("storage %q is only supported by serve; this command needs a database", cfg.Storage)
+	}
+
+	config.ConnectDatabase(cfg.Database, logging.NewGormLogger())
+	return cfg, opts, nil
+}
+
+// load seperti setup tetapi tanpa koneksi database.
+func load(fs *flag.FlagSet, args []string) (config.Config, config.Options, error) {
+	cfg, opts, err := config.LoadFlagSet(fs, args)
+	if opts.PrintConfig {
+		fmt.Print(cfg)
+	}
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return cfg, opts, err
+		}
+		return cfg, opts, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if opts.PrintConfig {
+		return cfg, opts, errConfigPrinted
+	}
+
+	// Log ditulis ke stderr agar tidak tercampur dengan output command, mis. export
+	if err := logging.Setup(os.Stderr, cfg.Log.Level, cfg.Log.Format); err != nil {
+		return cfg, opts, err
+	}
+	utils.ConfigureJWT(cfg.JWT.Secret, time.Duration(cfg.JWT.TTL))
+	utils.SetUploadDir(cfg.Upload.Dir)
+	return cfg, opts, nil
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("capstone "+name, flag.ContinueOnError)
+}
+
+// randomPassword membuat password acak untuk akun yang dibuat lewat CLI.
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+)
+
+const exportPageSize = 500
+
+// exportFunc mengambil semua data yang diekspor dalam bentuk response API.
+type exportFunc func(ctx context.Context) ([]interface{}, error)
+
+// exporter mengambil data per halaman dengan cursor agar tabel besar tidak
+// dimuat sekaligus, lalu mengubah setiap item menjadi baris ekspor.
+func exporter[T any](spec listquery.Spec, fetch func(context.Context, listquery.Query) (listquery.Page[T], error), row func(T) interface{}) exportFunc {
+	return func(ctx context.Context) ([]interface{}, error) {
+		var rows []interface{}
+		q := listquery.New(spec, exportPageSize)
+		for {
+			page, err := fetch(ctx, q)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range page.Items {
+				rows = append(rows, row(item))
+			}
+			var more bool
+			if q, more = listquery.Next(q, page); !more {
+				return rows, nil
+			}
+		}
+	}
+}
+
+func exporters() map[string]exportFunc {
+	db := config.DB
+	uow := repository.NewUnitOfWork(db)
+	userService := service.NewUserService(repository.NewUserRepository(db), uow, nil)
+	serviceService := service.NewServiceService(repository.NewServiceRepository(db), repository.NewTechnicianApplicationRepository(db))
+	bookingService := service.NewBookingService(repository.NewBookingRepository(db))
+	paymentService := service.NewPaymentService(repository.NewPaymentRepository(db), uow)
+	reviewService := service.NewReviewService(repository.NewReviewRepository(db))
+
+	return map[string]exportFunc{
+		"users": exporter(service.UserListSpec, userService.GetAllUsers, func(user *entity.UserRes) interface{} {
+			return *user
+		}),
+		"services": exporter(service.ServiceListSpec, serviceService.GetAllServices, func(s entity.Service) interface{} {
+			return entity.ServiceRes{ID: s.ID, UserID: s.UserID, Name: s.Name, Description: s.Description, Cost: s.Cost, CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt}
+		}),
+		"bookings": exporter(service.BookingListSpec, bookingService.GetAllBookings, func(b entity.Booking) interface{} {
+			return entity.BookingRes{ID: b.ID, UserID: b.UserID, ServiceID: b.ServiceID, Date: b.Date, Status: b.Status, Description: b.Description, CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt}
+		}),
+		"payments": exporter(service.PaymentListSpec, paymentService.GetAllPayments, func(p entity.Payment) interface{} {
+			return entity.PaymentRes{ID: p.ID, BookingID: p.BookingID, Amount: p.Amount, Status: p.Status, CreatedAt: p.CreatedAt, UpdatedAt: p.UpdatedAt}
+		}),
+		"reviews": exporter(service.ReviewListSpec, reviewService.GetAllReviews, func(r entity.Review) interface{} {
+			return entity.ReviewRes{ID: r.ID, BookingID: r.BookingID, Rating: r.Rating, Comment: r.Comment, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+		}),
+	}
+}
+
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	resource := fs.String("resource", "", "data to export: users, services, bookings, payments or reviews (required)")
+	format := fs.String("format", "json", "output format: json or csv")
+	output := fs.String("output", "-", "output file; - writes to stdout")
+	if _, _, err := setup(fs, args); err != nil {
+		return err
+	}
+
+	all := exporters()
+	fetch, ok := all[*resource]
+	if !ok {
+		names := make([]string, 0, len(all))
+		for name := range all {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("--resource must be one of %s", strings.Join(names, ", "))
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("--format must be json or csv")
+	}
+
+	rows, err := fetch(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "csv" {
+		err := writeCSV(w, rows)
+		if err == nil && *output != "-" {
+			fmt.Fprintf(os.Stderr, "Exported %d %s to %s\n", len(rows), *resource, *output)
+		}
+		return err
+	}
+
+	if rows == nil {
+		rows = []interface{}{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(rows); err != nil {
+		return err
+	}
+	if *output != "-" {
+		fmt.Fprintf(os.Stderr, "Exported %d %s to %s\n", len(rows), *resource, *output)
+	}
+	return nil
+}
+
+// writeCSV menulis struct response sebagai CSV; nama kolom diambil dari tag json.
+func writeCSV(w io.Writer, rows []interface{}) error {
+	writer := csv.NewWriter(w)
+	if len(rows) == 0 {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	rowType := reflect.TypeOf(rows[0])
+	var header []string
+	var fields []int
+	for i := 0; i < rowType.NumField(); i++ {
+		name := strings.Split(rowType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		value := reflect.ValueOf(row)
+		record := make([]string, 0, len(fields))
+		for _, i := range fields {
+			record = append(record, formatCSVValue(value.Field(i)))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case string:
+		return value
+	case int:
+		return strconv.Itoa(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
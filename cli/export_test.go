@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV_UsesJSONTagsAsHeader(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	rows := []interface{}{
+		entity.PaymentRes{ID: 1, BookingID: 7, Amount: "75000", Status: "Paid", CreatedAt: created, UpdatedAt: created},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, rows))
+	assert.Equal(t,
+		"id,booking_id,amount,status,created_at,updated_at\n"+
+			"1,7,75000,Paid,2024-05-01T09:30:00Z,2024-05-01T09:30:00Z\n",
+		buf.String())
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	assert.Equal(t, 2, Run([]string{"frobnicate"}))
+	assert.Equal(t, 0, Run([]string{"help"}))
+}
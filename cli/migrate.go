@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/Ayyasy123/dibimbing-capstone.git/migrate"
+)
+
+const migrateUsage = `usage: capstone migrate [flags] <command>
+
+commands:
+  up            apply all pending migrations
+  down [n]      revert the last n migrations (default 1)
+  status        list migrations and when they were applied
+  create <name> create a new pair of migration files for every dialect in ` + migrate.Dir
+
+func runMigrate(args []string) error {
+	// create hanya membuat file, tidak butuh konfigurasi maupun database
+	if len(args) > 0 && args[0] == "create" {
+		// Setiap dialek mendapat file sendiri dengan versi yang sama
+		for _, dialect := range migrate.Dialects {
+			up, down, err := migrate.Create(filepath.Join(migrate.Dir, dialect), strings.Join(args[1:], "_"))
+			if err != nil {
+				return fmt.Errorf("failed to create migration: %w", err)
+			}
+			fmt.Println("Created", up)
+			fmt.Println("Created", down)
+		}
+		return nil
+	}
+
+	fs := newFlagSet("migrate")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		fmt.Fprintln(os.Stderr, "\nflags:")
+		fs.PrintDefaults()
+	}
+
+	cfg, opts, err := load(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(opts.Args) == 0 {
+		fs.Usage()
+		return errors.New("missing migrate command")
+	}
+
+	config.ConnectDatabase(cfg.Database, logging.NewGormLogger())
+	migrator, err := migrate.New(config.DB)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	ctx := context.Background()
+	switch opts.Args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", len(applied))
+	case "down":
+		steps := 1
+		if len(opts.Args) > 1 {
+			steps, err = strconv.Atoi(opts.Args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %s", opts.Args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		fmt.Printf("Reverted %d migration(s)\n", len(reverted))
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read migration status: %w", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-40s %s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate command %q", opts.Args[0])
+	}
+	return nil
+}
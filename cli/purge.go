@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+)
+
+func runPurgeExpired(args []string) error {
+	fs := newFlagSet("purge-expired")
+	olderThan := fs.Duration("older-than", 30*24*time.Hour, "only purge records that expired longer ago than this")
+	if _, _, err := setup(fs, args); err != nil {
+		return err
+	}
+
+	if *olderThan < 0 {
+		return errors.New("--older-than must not be negative")
+	}
+
+	db := config.DB
+	maintenanceService := service.NewMaintenanceService(
+		repository.NewBookingRepository(db),
+		repository.NewPaymentRepository(db),
+		repository.NewReviewRepository(db),
+		repository.NewOutboxRepository(db),
+		repository.NewUnitOfWork(db),
+		nil, // Tidak ada job yang dijadwalkan saat purge
+	)
+
+	bookings, payments, err := maintenanceService.PurgeExpired(context.Background(), time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Purged %d expired booking(s) and %d expired payment(s)\n", bookings, payments)
+	return nil
+}
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+)
+
+const seedAdminEmail = "admin@perbaiki.id"
+
+// demoPassword adalah password semua akun demo di mode --storage=memory.
+const demoPassword = "password123"
+
+type seedTechnician struct {
+	name, email, address, phone, expertise string
+	services                               []entity.CreateServiceReq
+}
+
+var seedTechnicians = []seedTechnician{
+	{
+		name: "Budi Santoso", email: "budi.santoso@perbaiki.id", address: "Jl. Merdeka No. 12, Bandung", phone: "081234567801", expertise: "AC & Elektronik",
+		services: []entity.CreateServiceReq{
+			{Name: "Cuci AC Split", Description: "Pembersihan unit indoor dan outdoor AC split hingga 2 PK", Cost: 75000},
+			{Name: "Servis Kulkas", Description: "Pengecekan dan perbaikan kulkas tidak dingin", Cost: 150000},
+		},
+	},
+	{
+		name: "Siti Rahayu", email: "siti.rahayu@perbaiki.id", address: "Jl. Diponegoro No. 5, Surabaya", phone: "081234567802", expertise: "Pipa & Sanitasi",
+		services: []entity.CreateServiceReq{
+			{Name: "Perbaikan Pipa Bocor", Description: "Penggantian sambungan dan pipa PVC yang bocor", Cost: 120000},
+			{Name: "Pasang Water Heater", Description: "Instalasi water heater listrik beserta jalur pipa", Cost: 250000},
+		},
+	},
+	{
+		name: "Agus Wijaya", email: "agus.wijaya@perbaiki.id", address: "Jl. Sudirman No. 88, Jakarta", phone: "081234567803", expertise: "Listrik",
+		services: []entity.CreateServiceReq{
+			{Name: "Instalasi Stop Kontak", Description: "Penambahan titik stop kontak dan saklar", Cost: 90000},
+			{Name: "Perbaikan Korsleting", Description: "Pelacakan dan perbaikan korsleting instalasi rumah", Cost: 200000},
+		},
+	},
+}
+
+var seedCustomers = []string{"Dewi Lestari", "Rizky Pratama", "Maya Putri", "Andi Saputra", "Lina Marlina"}
+
+var seedReviews = []entity.CreateReviewReq{
+	{Rating: 5, Comment: "Teknisi datang tepat waktu dan hasilnya rapi."},
+	{Rating: 4, Comment: "Pekerjaan bagus, hanya sedikit terlambat."},
+}
+
+func runSeed(args []string) error {
+	fs := newFlagSet("seed")
+	password := fs.String("password", demoPassword, "password for every seeded account")
+	if _, _, err := setup(fs, args); err != nil {
+		return err
+	}
+
+	summary, err := seedDemoData(context.Background(), repository.NewStorage(config.DB), *password)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(summary)
+	fmt.Printf("Log in as %s (or any seeded account) with password %q\n", seedAdminEmail, *password)
+	return nil
+}
+
+// seedSummary mencatat jumlah data demo yang dibuat oleh seedDemoData.
+type seedSummary struct {
+	services, bookings, payments, reviews int
+}
+
+func (s seedSummary) String() string {
+	return fmt.Sprintf("Seeded 1 admin, %d technicians, %d services, %d customers, %d bookings, %d payments and %d reviews",
+		len(seedTechnicians), s.services, len(seedCustomers), s.bookings, s.payments, s.reviews)
+}
+
+// seedDemoData mengisi storage dengan data demo melalui service layer, baik
+// untuk database (command seed) maupun untuk serve --storage=memory.
+func seedDemoData(ctx context.Context, storage repository.Storage, password string) (seedSummary, error) {
+	var summary seedSummary
+	userService := service.NewUserService(storage.Users, storage.UnitOfWork, nil)
+	applicationService := service.NewTechnicianApplicationService(storage.TechnicianApplications, storage.Users, storage.UnitOfWork)
+	serviceService := service.NewServiceService(storage.Services, storage.TechnicianApplications)
+	bookingService := service.NewBookingService(storage.Bookings)
+	paymentService := service.NewPaymentService(storage.Payments, storage.UnitOfWork)
+	reviewService := service.NewReviewService(storage.Reviews)
+
+	// Seed hanya dijalankan sekali agar data demo tidak terduplikasi
+	exists, err := storage.Users.IsEmailExists(ctx, seedAdminEmail)
+	if err != nil {
+		return summary, err
+	}
+	if exists {
+		return summary, errors.New("database is already seeded (" + seedAdminEmail + " exists)")
+	}
+
+	admin, err := userService.RegisterAsAdmin(ctx, &entity.RegisterUserReq{Name: "Admin Perbaiki", Email: seedAdminEmail, Password: password})
+	if err != nil {
+		return summary, fmt.Errorf("create admin: %w", err)
+	}
+
+	// Technician melalui alur pengajuan yang sama seperti lewat API
+	var services []*entity.Service
+	for _, t := range seedTechnicians {
+		user, err := userService.Register(ctx, &entity.RegisterUserReq{Name: t.name, Email: t.email, Password: password})
+		if err != nil {
+			return summary, fmt.Errorf("register technician %s: %w", t.email, err)
+		}
+
+		application, err := applicationService.SubmitApplication(ctx, user.ID, &entity.RegisterAsTechnicianReq{
+			Address:                t.address,
+			Phone:                  t.phone,
+			Expertise:              t.expertise,
+			Availability:           "Senin - Sabtu, 08.00 - 17.00",
+			CertificationExpiresAt: time.Now().AddDate(2, 0, 0),
+		}, "seed/id_document.pdf", "seed/certificate.pdf")
+		if err != nil {
+			return summary, fmt.Errorf("submit application %s: %w", t.email, err)
+		}
+		if _, err := applicationService.StartReview(ctx, application.ID, admin.ID); err != nil {
+			return summary, fmt.Errorf("review application %s: %w", t.email, err)
+		}
+		if _, err := applicationService.ApproveApplication(ctx, application.ID, admin.ID, &entity.ReviewTechnicianApplicationReq{Reason: "Dokumen lengkap"}); err != nil {
+			return summary, fmt.Errorf("approve application %s: %w", t.email, err)
+		}
+
+		for _, req := range t.services {
+			req.UserID = user.ID
+			created, err := serviceService.CreateService(ctx, req)
+			if err != nil {
+				return summary, fmt.Errorf("create service %q: %w", req.Name, err)
+			}
+			services = append(services, created)
+			summary.services++
+		}
+	}
+
+	// Setiap customer memiliki dua booking dengan hasil akhir yang berbeda-beda
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i, name := range seedCustomers {
+		email := strings.ToLower(strings.ReplaceAll(name, " ", ".")) + "@example.com"
+		customer, err := userService.Register(ctx, &entity.RegisterUserReq{Name: name, Email: email, Password: password})
+		if err != nil {
+			return summary, fmt.Errorf("register customer %s: %w", email, err)
+		}
+
+		for j := 0; j < 2; j++ {
+			n := i*2 + j
+			svc := services[n%len(services)]
+			booking, err := bookingService.CreateBooking(ctx, entity.CreateBookingReq{
+				UserID:      customer.ID,
+				ServiceID:   svc.ID,
+				Date:        today.AddDate(0, 0, 3+n),
+				Description: "Mohon datang pagi hari",
+			})
+			if err != nil {
+				return summary, fmt.Errorf("create booking for %s: %w", email, err)
+			}
+			summary.bookings++
+
+			bookingID := strconv.Itoa(booking.ID)
+			switch n % 4 {
+			case 0: // dibiarkan Pending
+			case 1, 2:
+				payment, err := paymentService.CreatePayment(ctx, entity.CreatePaymentReq{BookingID: booking.ID, Amount: strconv.Itoa(svc.Cost)})
+				if err != nil {
+					return summary, fmt.Errorf("create payment: %w", err)
+				}
+				if err := paymentService.UpdatePaymentStatus(ctx, strconv.Itoa(payment.ID), "Paid"); err != nil {
+					return summary, fmt.Errorf("pay booking %d: %w", booking.ID, err)
+				}
+				summary.payments++
+
+				if n%4 == 2 {
+					for _, status := range []string{"In Progress", "Completed"} {
+						if err := bookingService.UpdateBookingStatus(ctx, bookingID, status); err != nil {
+							return summary, fmt.Errorf("update booking %d: %w", booking.ID, err)
+						}
+					}
+					review := seedReviews[summary.reviews%len(seedReviews)]
+					review.BookingID = booking.ID
+					if _, err := reviewService.CreateReview(ctx, review); err != nil {
+						return summary, fmt.Errorf("create review: %w", err)
+					}
+					summary.reviews++
+				}
+			case 3:
+				if err := bookingService.UpdateBookingStatus(ctx, bookingID, "Cancelled"); err != nil {
+					return summary, fmt.Errorf("cancel booking %d: %w", booking.ID, err)
+				}
+			}
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/health"
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/Ayyasy123/dibimbing-capstone.git/metrics"
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/Ayyasy123/dibimbing-capstone.git/migrate"
+	"github.com/Ayyasy123/dibimbing-capstone.git/notification"
+	"github.com/Ayyasy123/dibimbing-capstone.git/outbox"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository/memory"
+	"github.com/Ayyasy123/dibimbing-capstone.git/routes"
+	"github.com/Ayyasy123/dibimbing-capstone.git/scheduler"
+	"github.com/Ayyasy123/dibimbing-capstone.git/tracing"
+	"github.com/Ayyasy123/dibimbing-capstone.git/validation"
+	"github.com/gin-gonic/gin"
+)
+
+func runServe(args []string) error {
+	cfg, _, err := load(newFlagSet("serve"), args)
+	if err != nil {
+		return err
+	}
+	limits, err := rateLimits(cfg.RateLimit)
+	if err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// SIGTERM (mis. saat deploy) dan Ctrl+C memulai graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Tracing dipasang sebelum storage agar query GORM ikut ditrace. Exporter
+	// stdout menulis span ke stdout, terpisah dari log di stderr
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	storage, err := openStorage(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Validasi request memakai tag `validate` di struct entity
+	if err := validation.Setup(); err != nil {
+		return fmt.Errorf("failed to set up request validation: %w", err)
+	}
+
+	// Setup Gin Router. Log request ditulis oleh middleware.Logger dalam
+	// format terstruktur, bukan logger teks bawaan gin.Default. Tracing
+	// dipasang sebelum Logger agar log request membawa trace_id, dan
+	// ErrorHandler dipasang terakhir agar status response error ikut tercatat
+	// di log dan metrik
+	r := gin.New()
+	r.Use(
+		middleware.RequestID(),
+		middleware.Tracing(cfg.Tracing.ServiceName),
+		middleware.Logger(),
+		middleware.Recovery(),
+		middleware.Metrics(),
+		middleware.ErrorHandler(),
+	)
+
+	// Batas waktu query database per request (server.query_timeout, default 10s)
+	r.Use(middleware.QueryTimeout(time.Duration(cfg.Server.QueryTimeout)))
+
+	// Gauge technician aktif diisi dari database; setelah itu diperbarui oleh service
+	if err := metrics.RefreshActiveTechnicians(ctx, storage.Users); err != nil {
+		slog.Warn("failed to initialise active technicians metric", "error", err)
+	}
+
+	// Hub untuk mengirim event realtime ke client yang terhubung
+	hub := realtime.NewHub()
+
+	// Dispatcher mengirim domain event dari tabel outbox ke hub realtime, notifikasi dan webhook
+	dispatcher := outbox.NewDispatcher(storage.Outbox)
+	notifier := notification.NewNotifier(
+		storage.Users,
+		storage.NotificationPreferences,
+		notification.NewChannels(cfg.Notification)...,
+	)
+	dispatcher.Broadcast(hub.HandleEvent)
+	dispatcher.Subscribe("notification", notifier.HandleEvent)
+
+	// Scheduler untuk job latar belakang (expire, pengingat, auto-complete, review, webhook)
+	sched := scheduler.New(storage.Jobs)
+
+	// Pemeriksaan untuk /readyz
+	checks := health.NewChecker()
+	if cfg.Storage == config.StorageDatabase {
+		migrator, err := migrate.New(config.DB)
+		if err != nil {
+			return fmt.Errorf("failed to load migrations: %w", err)
+		}
+		checks.Add("database", health.Database(config.DB))
+		checks.Add("migrations", migrator.Check)
+	}
+	checks.Add("outbox_dispatcher", dispatcher.Check)
+	checks.Add("scheduler", sched.Check)
+
+	routes.SetupRoutes(r, storage, hub, dispatcher, sched, checks, routes.Options{
+		RateLimits:     limits,
+		Legacy:         legacyRoutes(cfg.API),
+		TrustedProxies: cfg.Server.TrustedProxies,
+		MetricsToken:   cfg.Metrics.Token,
+		SwaggerUIDir:   cfg.Docs.SwaggerUIDir,
+	})
+
+	if err := dispatcher.Start(); err != nil {
+		return fmt.Errorf("failed to start event dispatcher: %w", err)
+	}
+	sched.Start()
+
+	srv := &http.Server{Addr: cfg.Server.Addr, Handler: r}
+	// Shutdown tidak menunggu koneksi WebSocket (hijacked) dan akan menunggu
+	// stream SSE sampai timeout, jadi keduanya ditutup lewat hub
+	srv.RegisterOnShutdown(hub.Close)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server is running", "addr", cfg.Server.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serveErr:
+		// Server gagal listen, mis. port sudah dipakai
+		slog.Error("server stopped", "error", err)
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining requests", "timeout", cfg.Server.ShutdownTimeout.String())
+	}
+	// Sinyal kedua menghentikan proses tanpa menunggu
+	stop()
+
+	shutdownErr := gracefulShutdown(time.Duration(cfg.Server.ShutdownTimeout), []shutdownStep{
+		{"http", func(ctx context.Context) error {
+			if err := srv.Shutdown(ctx); err != nil {
+				srv.Close()
+				return err
+			}
+			return nil
+		}},
+		{"scheduler", waitFor(sched.Stop)},
+		{"outbox_dispatcher", waitFor(dispatcher.Stop)},
+		{"tracing", shutdownTracing},
+		{"database", closeDatabase},
+	})
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return errors.Join(err, shutdownErr)
+}
+
+// rateLimits menyiapkan rate limit dengan store di memori. Untuk beberapa
+// replica, ganti store dengan implementasi ratelimit.Store yang dipakai
+// bersama (mis. Redis) agar batas berlaku untuk seluruh replica.
+func rateLimits(cfg config.RateLimitConfig) (routes.RateLimits, error) {
+	auth, authErr := ratePolicy("rate_limit.auth", cfg.Auth)
+	api, apiErr := ratePolicy("rate_limit.api", cfg.API)
+	if err := errors.Join(authErr, apiErr); err != nil {
+		return routes.RateLimits{}, err
+	}
+	if !cfg.Enabled {
+		return routes.RateLimits{}, nil
+	}
+
+	store := ratelimit.NewMemoryStore()
+	return routes.RateLimits{
+		Store:   store,
+		Auth:    auth,
+		API:     api,
+		Lockout: ratelimit.NewLockout(store, cfg.Lockout.MaxFailures, time.Duration(cfg.Lockout.Duration)),
+	}, nil
+}
+
+// ratePolicy mengurai limit "<requests>/<period>" dari konfigurasi.
+func ratePolicy(name string, cfg config.RateLimitPolicy) (ratelimit.Policy, error) {
+	ip, ipErr := ratelimit.ParseLimit(cfg.IP)
+	if ipErr != nil {
+		ipErr = fmt.Errorf("%s.ip: %w", name, ipErr)
+	}
+	account, accountErr := ratelimit.ParseLimit(cfg.Account)
+	if accountErr != nil {
+		accountErr = fmt.Errorf("%s.account: %w", name, accountErr)
+	}
+	return ratelimit.Policy{IP: ip, Account: account}, errors.Join(ipErr, accountErr)
+}
+
+// legacyRoutes mengubah konfigurasi api menjadi alias route tanpa versi.
+// Tanggal sunset sudah divalidasi saat konfigurasi dimuat.
+func legacyRoutes(cfg config.APIConfig) routes.LegacyRoutes {
+	sunset, _ := cfg.SunsetDate()
+	return routes.LegacyRoutes{Enabled: cfg.LegacyRoutes, Sunset: sunset}
+}
+
+type shutdownStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// gracefulShutdown menjalankan langkah shutdown berurutan dengan satu batas
+// waktu bersama: request HTTP yang sedang berjalan diselesaikan lebih dulu,
+// lalu worker latar belakang dihentikan, span tersisa dikirim dan pool
+// koneksi database ditutup. Langkah yang gagal atau melewati batas waktu
+// dicatat, dan langkah berikutnya tetap dijalankan.
+func gracefulShutdown(timeout time.Duration, steps []shutdownStep) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, step := range steps {
+		start := time.Now()
+		if err := step.run(ctx); err != nil {
+			slog.Error("shutdown step failed", "step", step.name, "error", err)
+			errs = append(errs, fmt.Errorf("shutdown %s: %w", step.name, err))
+			continue
+		}
+		slog.Info("shutdown step completed", "step", step.name, "duration_ms", time.Since(start).Milliseconds())
+	}
+	return errors.Join(errs...)
+}
+
+// waitFor menjalankan fungsi Stop yang blocking tanpa melewati batas waktu
+// shutdown. Jika waktu habis, fungsi tetap berjalan di latar belakang.
+func waitFor(stop func()) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			stop()
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// closeDatabase menutup pool koneksi database; tidak melakukan apa pun pada
+// storage memory.
+func closeDatabase(ctx context.Context) error {
+	if config.DB == nil {
+		return nil
+	}
+	sqlDB, err := config.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// openStorage menyiapkan backend penyimpanan sesuai cfg.Storage. Mode memory
+// tidak membutuhkan database dan langsung diisi data demo; semua data hilang
+// saat server berhenti.
+func openStorage(cfg config.Config) (repository.Storage, error) {
+	if cfg.Storage == config.StorageMemory {
+		storage := memory.NewStorage()
+		summary, err := seedDemoData(context.Background(), storage, demoPassword)
+		if err != nil {
+			return storage, fmt.Errorf("failed to seed in-memory storage: %w", err)
+		}
+		slog.Info("using in-memory storage, data is lost on shutdown", "seeded", summary.String())
+		slog.Info("demo accounts are ready", "admin_email", seedAdminEmail, "password", demoPassword)
+		return storage, nil
+	}
+
+	config.ConnectDatabase(cfg.Database, logging.NewGormLogger())
+	if err := metrics.InstrumentDB(config.DB, cfg.Database.Name); err != nil {
+		return repository.Storage{}, fmt.Errorf("failed to instrument database: %w", err)
+	}
+	if err := tracing.InstrumentDB(config.DB); err != nil {
+		return repository.Storage{}, fmt.Errorf("failed to trace database: %w", err)
+	}
+
+	// Skema database dikelola dengan migrasi SQL bernomor (lihat folder migrate/migrations)
+	if cfg.Database.AutoMigrate {
+		migrator, err := migrate.New(config.DB)
+		if err != nil {
+			return repository.Storage{}, fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			return repository.Storage{}, fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+	return repository.NewStorage(config.DB), nil
+}
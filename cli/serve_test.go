@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGracefulShutdown_RunsEveryStepWithinTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	var ran []string
+	err := gracefulShutdown(50*time.Millisecond, []shutdownStep{
+		{"http", func(ctx context.Context) error {
+			ran = append(ran, "http")
+			return nil
+		}},
+		// Worker yang macet tidak boleh menahan shutdown melewati batas waktu
+		{"scheduler", waitFor(func() { <-release })},
+		{"database", func(ctx context.Context) error {
+			ran = append(ran, "database")
+			return nil
+		}},
+	})
+
+	assert.Equal(t, []string{"http", "database"}, ran)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "shutdown scheduler")
+}
+
+func TestRateLimits_ParsesConfiguredLimits(t *testing.T) {
+	cfg := config.Default().RateLimit
+	limits, err := rateLimits(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Limit{Requests: 20, Period: time.Minute}, limits.Auth.IP)
+	assert.Equal(t, ratelimit.Limit{Requests: 300, Period: time.Minute}, limits.API.Account)
+	assert.NotNil(t, limits.Lockout)
+
+	// Limit yang salah tulis ditolak walaupun rate limit nonaktif
+	cfg.Enabled = false
+	cfg.API.IP = "lots"
+	cfg.Auth.Account = "5/soon"
+	_, err = rateLimits(cfg)
+	assert.ErrorContains(t, err, `rate_limit.api.ip: invalid rate limit "lots"`)
+	assert.ErrorContains(t, err, `rate_limit.auth.account: invalid rate limit "5/soon"`)
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+)
+
+func newUserService() service.UserService {
+	return service.NewUserService(repository.NewUserRepository(config.DB), repository.NewUnitOfWork(config.DB), nil)
+}
+
+func runCreateAdmin(args []string) error {
+	fs := newFlagSet("create-admin")
+	name := fs.String("name", "Administrator", "name of the admin")
+	email := fs.String("email", "", "email of the admin (required)")
+	password := fs.String("password", "", "password; a random one is generated and printed when empty")
+	if _, _, err := setup(fs, args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return errors.New("--email is required")
+	}
+
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+
+	admin, err := newUserService().RegisterAsAdmin(context.Background(), &entity.RegisterUserReq{
+		Name:     *name,
+		Email:    *email,
+		Password: *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created admin %s (id %d)\n", admin.Email, admin.ID)
+	if generated {
+		fmt.Println("Password:", *password)
+	}
+	return nil
+}
+
+func runResetPassword(args []string) error {
+	fs := newFlagSet("reset-password")
+	email := fs.String("email", "", "email of the user (required)")
+	password := fs.String("password", "", "new password; a random one is generated and printed when empty")
+	if _, _, err := setup(fs, args); err != nil {
+		return err
+	}
+
+	if *email == "" {
+		return errors.New("--email is required")
+	}
+
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+
+	if err := newUserService().ResetPassword(context.Background(), *email, *password); err != nil {
+		return err
+	}
+
+	fmt.Println("Password updated for", *email)
+	if generated {
+		fmt.Println("New password:", *password)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+)
+
+func runRotateSecrets(args []string) error {
+	fs := newFlagSet("rotate-secrets")
+	endpointID := fs.Int("endpoint", 0, "ID of the webhook endpoint to rotate; all endpoints when 0")
+	if _, _, err := setup(fs, args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	// Tanpa scheduler: rotasi secret tidak menjadwalkan pengiriman webhook
+	webhookService := service.NewWebhookService(repository.NewWebhookRepository(config.DB), nil, nil)
+
+	ids := []int{*endpointID}
+	if *endpointID == 0 {
+		ids = nil
+		q := listquery.New(service.WebhookEndpointListSpec, listquery.MaxLimit)
+		for more := true; more; {
+			page, err := webhookService.GetEndpoints(ctx, q)
+			if err != nil {
+				return err
+			}
+			for _, endpoint := range page.Items {
+				ids = append(ids, endpoint.ID)
+			}
+			q, more = listquery.Next(q, page)
+		}
+	}
+
+	// Secret baru hanya ditampilkan sekali; kirimkan ke partner sebelum menutup terminal
+	for _, id := range ids {
+		endpoint, err := webhookService.RotateSecret(ctx, id)
+		if err != nil {
+			return fmt.Errorf("endpoint %d: %w", id, err)
+		}
+		fmt.Printf("%d\t%s\t%s\n", endpoint.ID, endpoint.URL, endpoint.Secret)
+	}
+	fmt.Printf("Rotated %d webhook secret(s)\n", len(ids))
+	return nil
+}
@@ -0,0 +1,640 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "******"
+
+// Config berisi seluruh konfigurasi aplikasi. Nilai dimuat berurutan dari
+// default, file konfigurasi (YAML/TOML), environment variable lalu flag;
+// sumber yang belakangan menimpa sumber sebelumnya.
+type Config struct {
+	Storage      string             `yaml:"storage" toml:"storage"` // database atau memory
+	Server       ServerConfig       `yaml:"server" toml:"server"`
+	Database     DatabaseConfig     `yaml:"database" toml:"database"`
+	JWT          JWTConfig          `yaml:"jwt" toml:"jwt"`
+	Upload       UploadConfig       `yaml:"upload" toml:"upload"`
+	Notification NotificationConfig `yaml:"notification" toml:"notification"`
+	Log          LogConfig          `yaml:"log" toml:"log"`
+	Tracing      TracingConfig      `yaml:"tracing" toml:"tracing"`
+	Metrics      MetricsConfig      `yaml:"metrics" toml:"metrics"`
+	RateLimit    RateLimitConfig    `yaml:"rate_limit" toml:"rate_limit"`
+	API          APIConfig          `yaml:"api" toml:"api"`
+	Docs         DocsConfig         `yaml:"docs" toml:"docs"`
+}
+
+// Backend penyimpanan yang didukung. StorageMemory menyimpan data di memori
+// proses (tanpa database) dan hanya ditujukan untuk demo.
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+)
+
+type ServerConfig struct {
+	Addr         string   `yaml:"addr" toml:"addr"`
+	QueryTimeout Duration `yaml:"query_timeout" toml:"query_timeout"` // 0 menonaktifkan batas waktu
+	// ShutdownTimeout membatasi waktu menunggu request yang sedang berjalan
+	// dan worker latar belakang selesai setelah SIGTERM
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+	// TrustedProxies berisi IP atau CIDR reverse proxy yang header
+	// X-Forwarded-For-nya dipercaya untuk IP client. Kosong berarti tidak
+	// ada, sehingga IP client selalu alamat koneksi
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+}
+
+// Driver database yang didukung.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type DatabaseConfig struct {
+	Driver   string `yaml:"driver" toml:"driver"` // mysql, postgres atau sqlite
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"` // 0 memakai port bawaan driver
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	Name     string `yaml:"name" toml:"name"`       // Untuk sqlite berisi path file atau ":memory:"
+	SSLMode  string `yaml:"sslmode" toml:"sslmode"` // Hanya untuk postgres
+	// AutoMigrate menjalankan migrasi yang belum diterapkan saat server start
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate"`
+}
+
+// Format log yang didukung.
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level"`   // debug, info, warn atau error
+	Format string `yaml:"format" toml:"format"` // json atau text
+}
+
+// Exporter tracing yang didukung. TracingNone tetap meneruskan trace context
+// dari header request, tetapi span tidak dikirim ke mana pun.
+const (
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingOTLP   = "otlp"
+)
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter"`         // none, stdout atau otlp
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`         // host:port collector OTLP/HTTP, kosong memakai bawaan SDK
+	Insecure    bool    `yaml:"insecure" toml:"insecure"`         // Kirim OTLP tanpa TLS
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio"` // 0..1, dipakai untuk trace tanpa parent
+	ServiceName string  `yaml:"service_name" toml:"service_name"`
+}
+
+// MetricsConfig mengatur akses ke /metrics. Token wajib dikirim scraper
+// sebagai "Authorization: Bearer <token>"; kosong berarti /metrics tidak
+// didaftarkan sama sekali.
+type MetricsConfig struct {
+	Token string `yaml:"token" toml:"token"`
+}
+
+// RateLimitConfig mengatur rate limit per grup route dan lockout login.
+// Limit ditulis sebagai "<requests>/<period>", mis. "10/1m"; "0" berarti
+// tanpa batas. Limit baru diurai saat server start.
+type RateLimitConfig struct {
+	Enabled bool            `yaml:"enabled" toml:"enabled"`
+	Auth    RateLimitPolicy `yaml:"auth" toml:"auth"` // /register, /login dan /register-admin
+	API     RateLimitPolicy `yaml:"api" toml:"api"`   // Semua route lain kecuali health check dan metrics
+	Lockout LockoutConfig   `yaml:"lockout" toml:"lockout"`
+}
+
+// RateLimitPolicy berisi limit per alamat IP dan per akun untuk satu grup
+// route.
+type RateLimitPolicy struct {
+	IP      string `yaml:"ip" toml:"ip"`
+	Account string `yaml:"account" toml:"account"`
+}
+
+// APIConfig mengatur alias tanpa versi (/bookings, ...) untuk route /api/v1.
+// Alias hanya sementara untuk client lama; LegacySunset (YYYY-MM-DD)
+// diumumkan lewat header Sunset.
+type APIConfig struct {
+	LegacyRoutes bool   `yaml:"legacy_routes" toml:"legacy_routes"`
+	LegacySunset string `yaml:"legacy_sunset" toml:"legacy_sunset"`
+}
+
+// SunsetDate mengembalikan LegacySunset sebagai waktu UTC, atau waktu kosong
+// jika tidak diisi.
+func (c APIConfig) SunsetDate() (time.Time, error) {
+	if c.LegacySunset == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", c.LegacySunset)
+}
+
+// DocsConfig mengatur halaman Swagger UI di /docs. SwaggerUIDir berisi file
+// dari paket npm swagger-ui-dist; kosong berarti /docs nonaktif dan hanya
+// /openapi.json yang disajikan.
+type DocsConfig struct {
+	SwaggerUIDir string `yaml:"swagger_ui_dir" toml:"swagger_ui_dir"`
+}
+
+// LockoutConfig mengunci akun selama Duration setelah MaxFailures login
+// gagal. MaxFailures 0 menonaktifkan lockout.
+type LockoutConfig struct {
+	MaxFailures int      `yaml:"max_failures" toml:"max_failures"`
+	Duration    Duration `yaml:"duration" toml:"duration"`
+}
+
+type JWTConfig struct {
+	Secret string   `yaml:"secret" toml:"secret"`
+	TTL    Duration `yaml:"ttl" toml:"ttl"`
+}
+
+type UploadConfig struct {
+	Dir string `yaml:"dir" toml:"dir"`
+}
+
+type NotificationConfig struct {
+	LogFile string     `yaml:"log_file" toml:"log_file"`
+	Console bool       `yaml:"console" toml:"console"`
+	SMTP    SMTPConfig `yaml:"smtp" toml:"smtp"`
+	SMS     HTTPConfig `yaml:"sms" toml:"sms"`
+	Push    HTTPConfig `yaml:"push" toml:"push"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	From     string `yaml:"from" toml:"from"`
+}
+
+type HTTPConfig struct {
+	URL    string `yaml:"url" toml:"url"`
+	APIKey string `yaml:"api_key" toml:"api_key"`
+}
+
+// Duration adalah time.Duration yang ditulis sebagai string di file
+// konfigurasi, mis. "10s" atau "24h".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Default mengembalikan konfigurasi bawaan. Secret tidak punya nilai default.
+func Default() Config {
+	return Config{
+		Storage: StorageDatabase,
+		Server: ServerConfig{
+			Addr:            ":8080",
+			QueryTimeout:    Duration(10 * time.Second),
+			ShutdownTimeout: Duration(15 * time.Second),
+		},
+		Database: DatabaseConfig{
+			Driver:      DriverMySQL,
+			Host:        "127.0.0.1",
+			User:        "root",
+			Name:        "capstone",
+			SSLMode:     "disable",
+			AutoMigrate: true,
+		},
+		JWT: JWTConfig{
+			TTL: Duration(24 * time.Hour),
+		},
+		Upload: UploadConfig{
+			Dir: "uploads",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: LogFormatJSON,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingNone,
+			SampleRatio: 1,
+			ServiceName: "capstone",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Auth:    RateLimitPolicy{IP: "20/1m", Account: "10/1m"},
+			API:     RateLimitPolicy{IP: "600/1m", Account: "300/1m"},
+			Lockout: LockoutConfig{
+				MaxFailures: 5,
+				Duration:    Duration(15 * time.Minute),
+			},
+		},
+		API: APIConfig{
+			LegacyRoutes: true,
+		},
+	}
+}
+
+// Options mengatur perilaku Load selain nilai konfigurasi itu sendiri.
+type Options struct {
+	PrintConfig bool     // --print-config: tampilkan konfigurasi efektif lalu keluar
+	Args        []string // Argumen posisi setelah flag, mis. "up" pada "migrate up"
+}
+
+// Load memuat konfigurasi dari default, file, environment dan flag (args
+// tanpa nama program), lalu memvalidasinya.
+func Load(args []string) (Config, Options, error) {
+	return LoadFlagSet(flag.NewFlagSet("capstone", flag.ContinueOnError), args)
+}
+
+// LoadFlagSet sama seperti Load, tetapi flag konfigurasi didaftarkan ke fs
+// sehingga command CLI bisa menambahkan flag miliknya sendiri.
+func LoadFlagSet(fs *flag.FlagSet, args []string) (Config, Options, error) {
+	cfg := Default()
+	var opts Options
+
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	fs.BoolVar(&opts.PrintConfig, "print-config", false, "print the effective config (secrets redacted) and exit")
+	fs.String("storage", "", "storage backend: database, or memory for a seeded in-memory demo (serve only)")
+	fs.String("addr", "", "HTTP listen address")
+	fs.String("query-timeout", "", "per-request database query timeout, e.g. 10s (0 disables it)")
+	fs.String("shutdown-timeout", "", "how long to wait for in-flight requests and workers on shutdown, e.g. 15s")
+	fs.String("db-driver", "", "database driver: mysql, postgres or sqlite")
+	fs.String("db-host", "", "database host")
+	fs.String("db-port", "", "database port (0 uses the driver default)")
+	fs.String("db-user", "", "database user")
+	fs.String("db-password-file", "", "file containing the database password")
+	fs.String("db-name", "", "database name, or the file path for sqlite")
+	fs.String("db-sslmode", "", "postgres sslmode, e.g. disable or require")
+	fs.String("db-auto-migrate", "", "apply pending migrations on startup (true/false)")
+	fs.String("jwt-secret-file", "", "file containing the secret used to sign JWT tokens")
+	fs.String("jwt-ttl", "", "lifetime of issued JWT tokens, e.g. 24h")
+	fs.String("upload-dir", "", "directory for uploaded files")
+	fs.String("log-level", "", "minimum log level: debug, info, warn or error")
+	fs.String("log-format", "", "log output format: json or text")
+	fs.String("tracing-exporter", "", "trace exporter: none, stdout or otlp")
+	fs.String("tracing-endpoint", "", "OTLP/HTTP collector endpoint, e.g. localhost:4318")
+	fs.String("rate-limit", "", "enable rate limiting and login lockout (true/false)")
+	if err := fs.Parse(args); err != nil {
+		return cfg, opts, err
+	}
+	opts.Args = fs.Args()
+
+	if *configFile != "" {
+		if err := loadFile(&cfg, *configFile); err != nil {
+			return cfg, opts, err
+		}
+	}
+
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return cfg, opts, err
+	}
+
+	// Hanya flag yang diisi secara eksplisit yang menimpa nilai sebelumnya
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		if flagErr != nil {
+			return
+		}
+		if setter, ok := flagSetters[f.Name]; ok {
+			if err := setter(&cfg, f.Value.String()); err != nil {
+				flagErr = fmt.Errorf("invalid --%s: %w", f.Name, err)
+			}
+		}
+	})
+	if flagErr != nil {
+		return cfg, opts, flagErr
+	}
+
+	return cfg, opts, cfg.Validate()
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+type setter func(cfg *Config, value string) error
+
+func setString(field func(cfg *Config) *string) setter {
+	return func(cfg *Config, value string) error {
+		*field(cfg) = value
+		return nil
+	}
+}
+
+func setInt(field func(cfg *Config) *int) setter {
+	return func(cfg *Config, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*field(cfg) = n
+		return nil
+	}
+}
+
+func setBool(field func(cfg *Config) *bool) setter {
+	return func(cfg *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		*field(cfg) = b
+		return nil
+	}
+}
+
+func setFloat(field func(cfg *Config) *float64) setter {
+	return func(cfg *Config, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		*field(cfg) = f
+		return nil
+	}
+}
+
+// setStrings membaca daftar yang dipisahkan koma, mis. "10.0.0.0/8,192.168.1.1".
+func setStrings(field func(cfg *Config) *[]string) setter {
+	return func(cfg *Config, value string) error {
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		*field(cfg) = values
+		return nil
+	}
+}
+
+// setFromFile membaca nilai dari file, mis. secret yang di-mount oleh Docker
+// atau Kubernetes. Secret tidak diterima langsung sebagai flag karena nilai
+// flag terlihat oleh user lain lewat daftar proses.
+func setFromFile(field func(cfg *Config) *string) setter {
+	return func(cfg *Config, path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		*field(cfg) = strings.TrimRight(string(data), "\r\n")
+		return nil
+	}
+}
+
+func setDuration(field func(cfg *Config) *Duration) setter {
+	return func(cfg *Config, value string) error {
+		return field(cfg).UnmarshalText([]byte(value))
+	}
+}
+
+var (
+	setStorage         = setString(func(c *Config) *string { return &c.Storage })
+	setAddr            = setString(func(c *Config) *string { return &c.Server.Addr })
+	setQueryTimeout    = setDuration(func(c *Config) *Duration { return &c.Server.QueryTimeout })
+	setShutdownTimeout = setDuration(func(c *Config) *Duration { return &c.Server.ShutdownTimeout })
+	setDBDriver        = setString(func(c *Config) *string { return &c.Database.Driver })
+	setDBHost          = setString(func(c *Config) *string { return &c.Database.Host })
+	setDBPort          = setInt(func(c *Config) *int { return &c.Database.Port })
+	setDBUser          = setString(func(c *Config) *string { return &c.Database.User })
+	setDBName          = setString(func(c *Config) *string { return &c.Database.Name })
+	setDBSSLMode       = setString(func(c *Config) *string { return &c.Database.SSLMode })
+	setAutoMigrate     = setBool(func(c *Config) *bool { return &c.Database.AutoMigrate })
+	setJWTTTL          = setDuration(func(c *Config) *Duration { return &c.JWT.TTL })
+	setUploadDir       = setString(func(c *Config) *string { return &c.Upload.Dir })
+	setLogLevel        = setString(func(c *Config) *string { return &c.Log.Level })
+	setLogFormat       = setString(func(c *Config) *string { return &c.Log.Format })
+	setTracingExporter = setString(func(c *Config) *string { return &c.Tracing.Exporter })
+	setTracingEndpoint = setString(func(c *Config) *string { return &c.Tracing.Endpoint })
+	setRateLimit       = setBool(func(c *Config) *bool { return &c.RateLimit.Enabled })
+)
+
+// envSetters memetakan environment variable ke field konfigurasi.
+var envSetters = map[string]setter{
+	"STORAGE":                 setStorage,
+	"SERVER_ADDR":             setAddr,
+	"QUERY_TIMEOUT":           setQueryTimeout,
+	"SHUTDOWN_TIMEOUT":        setShutdownTimeout,
+	"TRUSTED_PROXIES":         setStrings(func(c *Config) *[]string { return &c.Server.TrustedProxies }),
+	"DB_DRIVER":               setDBDriver,
+	"DB_HOST":                 setDBHost,
+	"DB_PORT":                 setDBPort,
+	"DB_USER":                 setDBUser,
+	"DB_PASSWORD":             setString(func(c *Config) *string { return &c.Database.Password }),
+	"DB_NAME":                 setDBName,
+	"DB_SSLMODE":              setDBSSLMode,
+	"DB_AUTO_MIGRATE":         setAutoMigrate,
+	"JWT_SECRET_KEY":          setString(func(c *Config) *string { return &c.JWT.Secret }),
+	"JWT_TTL":                 setJWTTTL,
+	"UPLOAD_DIR":              setUploadDir,
+	"LOG_LEVEL":               setLogLevel,
+	"LOG_FORMAT":              setLogFormat,
+	"TRACING_EXPORTER":        setTracingExporter,
+	"TRACING_ENDPOINT":        setTracingEndpoint,
+	"TRACING_INSECURE":        setBool(func(c *Config) *bool { return &c.Tracing.Insecure }),
+	"TRACING_SAMPLE_RATIO":    setFloat(func(c *Config) *float64 { return &c.Tracing.SampleRatio }),
+	"TRACING_SERVICE_NAME":    setString(func(c *Config) *string { return &c.Tracing.ServiceName }),
+	"METRICS_TOKEN":           setString(func(c *Config) *string { return &c.Metrics.Token }),
+	"RATE_LIMIT_ENABLED":      setRateLimit,
+	"RATE_LIMIT_AUTH_IP":      setString(func(c *Config) *string { return &c.RateLimit.Auth.IP }),
+	"RATE_LIMIT_AUTH_ACCOUNT": setString(func(c *Config) *string { return &c.RateLimit.Auth.Account }),
+	"RATE_LIMIT_API_IP":       setString(func(c *Config) *string { return &c.RateLimit.API.IP }),
+	"RATE_LIMIT_API_ACCOUNT":  setString(func(c *Config) *string { return &c.RateLimit.API.Account }),
+	"LOGIN_MAX_FAILURES":      setInt(func(c *Config) *int { return &c.RateLimit.Lockout.MaxFailures }),
+	"LOGIN_LOCKOUT_DURATION":  setDuration(func(c *Config) *Duration { return &c.RateLimit.Lockout.Duration }),
+	"API_LEGACY_ROUTES":       setBool(func(c *Config) *bool { return &c.API.LegacyRoutes }),
+	"API_LEGACY_SUNSET":       setString(func(c *Config) *string { return &c.API.LegacySunset }),
+	"SWAGGER_UI_DIR":          setString(func(c *Config) *string { return &c.Docs.SwaggerUIDir }),
+	"NOTIFICATION_LOG_FILE":   setString(func(c *Config) *string { return &c.Notification.LogFile }),
+	"NOTIFICATION_CONSOLE":    setBool(func(c *Config) *bool { return &c.Notification.Console }),
+	"SMTP_HOST":               setString(func(c *Config) *string { return &c.Notification.SMTP.Host }),
+	"SMTP_PORT":               setInt(func(c *Config) *int { return &c.Notification.SMTP.Port }),
+	"SMTP_USERNAME":           setString(func(c *Config) *string { return &c.Notification.SMTP.Username }),
+	"SMTP_PASSWORD":           setString(func(c *Config) *string { return &c.Notification.SMTP.Password }),
+	"SMTP_FROM":               setString(func(c *Config) *string { return &c.Notification.SMTP.From }),
+	"SMS_GATEWAY_URL":         setString(func(c *Config) *string { return &c.Notification.SMS.URL }),
+	"SMS_GATEWAY_API_KEY":     setString(func(c *Config) *string { return &c.Notification.SMS.APIKey }),
+	"PUSH_GATEWAY_URL":        setString(func(c *Config) *string { return &c.Notification.Push.URL }),
+	"PUSH_GATEWAY_API_KEY":    setString(func(c *Config) *string { return &c.Notification.Push.APIKey }),
+}
+
+// flagSetters memetakan nama flag ke field konfigurasi.
+var flagSetters = map[string]setter{
+	"storage":          setStorage,
+	"addr":             setAddr,
+	"query-timeout":    setQueryTimeout,
+	"shutdown-timeout": setShutdownTimeout,
+	"db-driver":        setDBDriver,
+	"db-host":          setDBHost,
+	"db-port":          setDBPort,
+	"db-user":          setDBUser,
+	"db-password-file": setFromFile(func(c *Config) *string { return &c.Database.Password }),
+	"db-name":          setDBName,
+	"db-sslmode":       setDBSSLMode,
+	"db-auto-migrate":  setAutoMigrate,
+	"jwt-secret-file":  setFromFile(func(c *Config) *string { return &c.JWT.Secret }),
+	"jwt-ttl":          setJWTTTL,
+	"upload-dir":       setUploadDir,
+	"log-level":        setLogLevel,
+	"log-format":       setLogFormat,
+	"tracing-exporter": setTracingExporter,
+	"tracing-endpoint": setTracingEndpoint,
+	"rate-limit":       setRateLimit,
+}
+
+// applyEnv menerapkan environment variable yang diisi (nilai kosong diabaikan).
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	for name, set := range envSetters {
+		value, ok := lookup(name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := set(cfg, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Validate mengembalikan semua kesalahan konfigurasi sekaligus.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Addr != "", "server.addr is required")
+	check(c.Server.QueryTimeout >= 0, "server.query_timeout must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		check(err == nil || net.ParseIP(proxy) != nil, "server.trusted_proxies must contain IP addresses or CIDR ranges (got %q)", proxy)
+	}
+
+	switch c.Storage {
+	case StorageDatabase:
+		c.validateDatabase(check)
+	case StorageMemory:
+	default:
+		check(false, "storage must be one of database or memory (got %q)", c.Storage)
+	}
+
+	check(c.JWT.Secret != "", "jwt.secret is required (set JWT_SECRET_KEY)")
+	check(c.JWT.Secret == "" || len(c.JWT.Secret) >= 32, "jwt.secret must be at least 32 characters")
+	check(c.JWT.TTL > 0, "jwt.ttl must be positive")
+
+	check(c.Upload.Dir != "", "upload.dir is required")
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "log.level must be one of debug, info, warn or error (got %q)", c.Log.Level)
+	}
+	check(c.Log.Format == LogFormatJSON || c.Log.Format == LogFormatText, "log.format must be one of json or text (got %q)", c.Log.Format)
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout, TracingOTLP:
+	default:
+		check(false, "tracing.exporter must be one of none, stdout or otlp (got %q)", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+	check(c.Tracing.Exporter == TracingNone || c.Tracing.ServiceName != "", "tracing.service_name is required when tracing is enabled")
+	check(c.Metrics.Token == "" || len(c.Metrics.Token) >= 16, "metrics.token must be at least 16 characters")
+
+	check(c.RateLimit.Lockout.MaxFailures >= 0, "rate_limit.lockout.max_failures must not be negative")
+	check(c.RateLimit.Lockout.MaxFailures == 0 || c.RateLimit.Lockout.Duration > 0, "rate_limit.lockout.duration must be positive when max_failures is set")
+
+	_, err := c.API.SunsetDate()
+	check(err == nil, "api.legacy_sunset must be a date in YYYY-MM-DD format (got %q)", c.API.LegacySunset)
+
+	if dir := c.Docs.SwaggerUIDir; dir != "" {
+		_, err := os.Stat(filepath.Join(dir, "swagger-ui-bundle.js"))
+		check(err == nil, "docs.swagger_ui_dir must contain the swagger-ui-dist files (%v)", err)
+	}
+
+	if smtp := c.Notification.SMTP; smtp.Host != "" {
+		check(smtp.Port > 0 && smtp.Port < 65536, "notification.smtp.port must be between 1 and 65535")
+		check(smtp.From != "", "notification.smtp.from is required when smtp.host is set")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateDatabase memeriksa konfigurasi database; hanya dipakai jika
+// storage adalah database.
+func (c Config) validateDatabase(check func(ok bool, format string, args ...interface{})) {
+	switch c.Database.Driver {
+	case DriverMySQL, DriverPostgres:
+		check(c.Database.Host != "", "database.host is required")
+		check(c.Database.User != "", "database.user is required")
+	case DriverSQLite:
+	default:
+		check(false, "database.driver must be one of mysql, postgres or sqlite (got %q)", c.Database.Driver)
+	}
+	check(c.Database.Port >= 0 && c.Database.Port < 65536, "database.port must be between 0 and 65535 (0 uses the driver default)")
+	check(c.Database.Name != "", "database.name is required")
+}
+
+// Redacted mengembalikan salinan konfigurasi dengan secret disamarkan.
+func (c Config) Redacted() Config {
+	mask := func(value string) string {
+		if value == "" {
+			return ""
+		}
+		return redacted
+	}
+
+	c.Database.Password = mask(c.Database.Password)
+	c.JWT.Secret = mask(c.JWT.Secret)
+	c.Metrics.Token = mask(c.Metrics.Token)
+	c.Notification.SMTP.Password = mask(c.Notification.SMTP.Password)
+	c.Notification.SMS.APIKey = mask(c.Notification.SMS.APIKey)
+	c.Notification.Push.APIKey = mask(c.Notification.Push.APIKey)
+	return c
+}
+
+// String menampilkan konfigurasi efektif dalam format YAML tanpa secret.
+func (c Config) String() string {
+	out, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err.Error()
+	}
+	return string(out)
+}
@@ -0,0 +1,172 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// secretFile menulis secret ke file seperti secret Docker (dengan newline di
+// akhir) dan mengembalikan path-nya.
+func secretFile(t *testing.T, secret string) string {
+	t.Helper()
+	return writeFile(t, "secret", secret+"\n")
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+  query_timeout: 3s
+database:
+  host: db.internal
+  port: 3307
+  name: from_file
+jwt:
+  secret: `+testSecret+`
+rate_limit:
+  auth:
+    ip: 5/1m
+`)
+	t.Setenv("DB_NAME", "from_env")
+	t.Setenv("RATE_LIMIT_API_ACCOUNT", "100/1h")
+	t.Setenv("DB_PORT", "3308")
+
+	cfg, _, err := config.Load([]string{"--config", path, "--db-port", "3309"})
+	require.NoError(t, err)
+
+	assert.Equal(t, ":9000", cfg.Server.Addr)                              // file
+	assert.Equal(t, 3*time.Second, time.Duration(cfg.Server.QueryTimeout)) // file
+	assert.Equal(t, "db.internal", cfg.Database.Host)                      // file
+	assert.Equal(t, "from_env", cfg.Database.Name)                         // env menimpa file
+	assert.Equal(t, 3309, cfg.Database.Port)                               // flag menimpa env
+	assert.Equal(t, "root", cfg.Database.User)                             // default
+	assert.Equal(t, 24*time.Hour, time.Duration(cfg.JWT.TTL))              // default
+
+	assert.Equal(t, "5/1m", cfg.RateLimit.Auth.IP)       // file
+	assert.Equal(t, "100/1h", cfg.RateLimit.API.Account) // env
+	assert.Equal(t, "10/1m", cfg.RateLimit.Auth.Account) // default
+}
+
+func TestLoad_SecretsFromFiles(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "")
+	t.Setenv("DB_PASSWORD", "from-env")
+
+	cfg, _, err := config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--db-password-file", secretFile(t, "from-file")})
+	require.NoError(t, err)
+	assert.Equal(t, testSecret, cfg.JWT.Secret)
+	assert.Equal(t, "from-file", cfg.Database.Password) // flag menimpa env
+
+	_, _, err = config.Load([]string{"--jwt-secret-file", filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --jwt-secret-file")
+
+	// Secret tidak lagi diterima langsung sebagai flag
+	_, _, err = config.Load([]string{"--jwt-secret", testSecret})
+	assert.Error(t, err)
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "config.toml", `
+[server]
+query_timeout = "250ms"
+
+[jwt]
+secret = "`+testSecret+`"
+ttl = "1h"
+`)
+
+	cfg, _, err := config.Load([]string{"--config", path})
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, time.Duration(cfg.Server.QueryTimeout))
+	assert.Equal(t, time.Hour, time.Duration(cfg.JWT.TTL))
+}
+
+func TestLoad_FailsFastOnInvalidValues(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "")
+
+	_, _, err := config.Load([]string{"--db-port", "70000", "--db-driver", "oracle"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jwt.secret is required")
+	assert.Contains(t, err.Error(), "database.driver must be one of mysql, postgres or sqlite")
+	assert.Contains(t, err.Error(), "database.port must be between 0 and 65535")
+
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--log-level", "verbose", "--log-format", "xml"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "log.level must be one of debug, info, warn or error")
+	assert.Contains(t, err.Error(), "log.format must be one of json or text")
+
+	t.Setenv("TRACING_SAMPLE_RATIO", "1.5")
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--tracing-exporter", "jaeger"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tracing.exporter must be one of none, stdout or otlp")
+	assert.Contains(t, err.Error(), "tracing.sample_ratio must be between 0 and 1")
+	t.Setenv("TRACING_SAMPLE_RATIO", "")
+
+	t.Setenv("METRICS_TOKEN", "short")
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics.token must be at least 16 characters")
+	t.Setenv("METRICS_TOKEN", "")
+
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, "short")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at least 32 characters")
+
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--query-timeout", "soon"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --query-timeout")
+
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--shutdown-timeout", "0s"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.shutdown_timeout must be positive")
+
+	t.Setenv("API_LEGACY_SUNSET", "31-03-2027")
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "api.legacy_sunset must be a date in YYYY-MM-DD format")
+}
+
+func TestLoad_MemoryStorageSkipsDatabaseValidation(t *testing.T) {
+	t.Setenv("STORAGE", "memory")
+
+	cfg, _, err := config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--db-driver", "oracle"})
+	require.NoError(t, err)
+	assert.Equal(t, config.StorageMemory, cfg.Storage)
+
+	_, _, err = config.Load([]string{"--jwt-secret-file", secretFile(t, testSecret), "--storage", "redis"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "storage must be one of database or memory")
+}
+
+func TestConfig_StringRedactsSecrets(t *testing.T) {
+	cfg := config.Default()
+	cfg.JWT.Secret = testSecret
+	cfg.Database.Password = "db-password"
+	cfg.Notification.SMTP.Password = "smtp-password"
+	cfg.Metrics.Token = "metrics-token-0123456789"
+
+	out := cfg.String()
+	for _, secret := range []string{testSecret, "db-password", "smtp-password", "metrics-token-0123456789"} {
+		assert.False(t, strings.Contains(out, secret), "secret %q leaked", secret)
+	}
+	assert.Contains(t, out, "******")
+	assert.Contains(t, out, "query_timeout: 10s")
+	assert.Contains(t, out, "ip: 20/1m")
+	assert.Equal(t, testSecret, cfg.JWT.Secret) // konfigurasi asli tidak berubah
+}
@@ -2,45 +2,91 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-func ConnectDatabase() {
-	// Ambil variabel lingkungan dari .env
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	// String koneksi ke MYSQL with docker
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", dbUser, dbPassword, dbHost, dbPort, dbName)
-
-	// Konfigurasi koneksi database with xampp
-	// dsn := "root:@tcp(127.0.0.1:3306)/capstone?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// DSN membentuk string koneksi sesuai driver.
+func (c DatabaseConfig) DSN() string {
+	switch c.Driver {
+	case DriverPostgres:
+		port := c.Port
+		if port == 0 {
+			port = 5432
+		}
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+			c.Host, port, c.User, c.Password, c.Name, c.SSLMode)
+	case DriverSQLite:
+		// Waktu disimpan sebagai teks berformat tetap agar bisa dibandingkan,
+		// dan foreign key harus diaktifkan per koneksi
+		separator := "?"
+		if strings.Contains(c.Name, "?") {
+			separator = "&"
+		}
+		return c.Name + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+	default:
+		port := c.Port
+		if port == 0 {
+			port = 3306
+		}
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", c.User, c.Password, c.Host, port, c.Name)
+	}
+}
+
+// OpenDatabase membuka koneksi ke database sesuai cfg.Driver. log dipakai
+// untuk mencatat query, mis. logging.NewGormLogger agar request ID ikut
+// tercatat.
+func OpenDatabase(cfg DatabaseConfig, log logger.Interface) (*gorm.DB, error) {
+	gormConfig := &gorm.Config{Logger: log}
+
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case DriverMySQL, "":
+		dialector = mysql.Open(cfg.DSN())
+	case DriverPostgres:
+		dialector = postgres.Open(cfg.DSN())
+	case DriverSQLite:
+		dialector = sqlite.Open(cfg.DSN())
+		// SQLite menyimpan waktu sebagai teks, jadi semua waktu dicatat dalam UTC
+		gormConfig.NowFunc = func() time.Time { return time.Now().UTC() }
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return nil, err
+	}
+
+	if cfg.Driver == DriverSQLite {
+		// SQLite hanya mengizinkan satu penulis; satu koneksi juga membuat
+		// database ":memory:" dipakai bersama oleh seluruh aplikasi
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
 	}
+	return db, nil
+}
 
-	// Auto-migrasi semua entitas
-	err = db.AutoMigrate(
-		&entity.User{},
-		&entity.Service{},
-		&entity.Booking{},
-		&entity.Payment{},
-		&entity.Review{},
-	)
+func ConnectDatabase(cfg DatabaseConfig, log logger.Interface) {
+	db, err := OpenDatabase(cfg, log)
 	if err != nil {
-		log.Fatal("Failed to migrate database:", err)
+		slog.Error("failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 
 	DB = db
-	log.Println("Database connected and migrated successfully")
+	slog.Info("database connected", "driver", db.Dialector.Name())
 }
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
 	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
@@ -21,13 +22,13 @@ func NewBookingController(service service.BookingService) *BookingController {
 func (c *BookingController) CreateBooking(ctx *gin.Context) {
 	var req entity.CreateBookingReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	booking, err := c.service.CreateBooking(req)
+	booking, err := c.service.CreateBooking(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -38,13 +39,13 @@ func (c *BookingController) GetBookingByID(ctx *gin.Context) {
 	id := ctx.Param("id")
 	bookingID, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
 		return
 	}
 
-	booking, err := c.service.GetBookingByID(bookingID)
+	booking, err := c.service.GetBookingByID(ctx.Request.Context(), bookingID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		ctx.Error(err)
 		return
 	}
 
@@ -54,13 +55,13 @@ func (c *BookingController) GetBookingByID(ctx *gin.Context) {
 func (c *BookingController) UpdateBooking(ctx *gin.Context) {
 	var req entity.UpdateBookingReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	booking, err := c.service.UpdateBooking(req)
+	booking, err := c.service.UpdateBooking(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -71,13 +72,13 @@ func (c *BookingController) DeleteBooking(ctx *gin.Context) {
 	id := ctx.Param("id")
 	bookingID, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
 		return
 	}
 
-	err = c.service.DeleteBooking(bookingID)
+	err = c.service.DeleteBooking(ctx.Request.Context(), bookingID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -85,64 +86,77 @@ func (c *BookingController) DeleteBooking(ctx *gin.Context) {
 }
 
 func (c *BookingController) GetAllBookings(ctx *gin.Context) {
-	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	q, err := parseList(ctx, service.BookingListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	bookings, err := c.service.GetAllBookings(limit, offset)
+	bookings, err := c.service.GetAllBookings(ctx.Request.Context(), q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, bookings)
+	respondList(ctx, bookings)
 }
 
 func (c *BookingController) GetBookingsByUserID(ctx *gin.Context) {
 	userID, err := strconv.Atoi(ctx.Param("user_id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid user ID"))
+		return
+	}
+	q, err := parseList(ctx, service.BookingListSpec)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	bookings, err := c.service.GetBookingsByUserID(userID)
+	bookings, err := c.service.GetBookingsByUserID(ctx.Request.Context(), userID, q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, bookings)
+	respondList(ctx, bookings)
 }
 
 func (c *BookingController) GetBookingsByServiceID(ctx *gin.Context) {
 	serviceID, err := strconv.Atoi(ctx.Param("service_id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid service ID"))
+		return
+	}
+	q, err := parseList(ctx, service.BookingListSpec)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	bookings, err := c.service.GetBookingsByServiceID(serviceID)
+	bookings, err := c.service.GetBookingsByServiceID(ctx.Request.Context(), serviceID, q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, bookings)
+	respondList(ctx, bookings)
 }
 
 func (c *BookingController) UpdateBookingStatus(ctx *gin.Context) {
 	bookingID := ctx.Param("id")
 	var req struct {
-		Status string `json:"status"`
+		Status string `json:"status" validate:"required"`
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	err := c.service.UpdateBookingStatus(bookingID, req.Status)
+	err := c.service.UpdateBookingStatus(ctx.Request.Context(), bookingID, req.Status)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -161,7 +175,7 @@ func (c *BookingController) GetBookingReport(ctx *gin.Context) {
 	if startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format"})
+			ctx.Error(apperror.InvalidParameter("Invalid start_date format"))
 			return
 		}
 	}
@@ -169,15 +183,15 @@ func (c *BookingController) GetBookingReport(ctx *gin.Context) {
 	if endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format"})
+			ctx.Error(apperror.InvalidParameter("Invalid end_date format"))
 			return
 		}
 	}
 
 	// Panggil service untuk mendapatkan laporan booking
-	report, err := c.service.GetBookingReport(startDate, endDate)
+	report, err := c.service.GetBookingReport(ctx.Request.Context(), startDate, endDate)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -189,27 +203,27 @@ func (c *BookingController) GetAvailableDates(ctx *gin.Context) {
 	// Ambil service_id dari query parameter
 	serviceID, err := strconv.Atoi(ctx.Query("service_id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service_id"})
+		ctx.Error(apperror.InvalidParameter("Invalid service_id"))
 		return
 	}
 
 	// Ambil tahun dan bulan dari query parameter
 	year, err := strconv.Atoi(ctx.Query("year"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		ctx.Error(apperror.InvalidParameter("Invalid year"))
 		return
 	}
 
 	month, err := strconv.Atoi(ctx.Query("month"))
 	if err != nil || month < 1 || month > 12 {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month"})
+		ctx.Error(apperror.InvalidParameter("Invalid month"))
 		return
 	}
 
 	// Panggil service untuk mendapatkan tanggal yang tersedia
-	availableDates, err := c.service.GetAvailableDates(serviceID, year, month)
+	availableDates, err := c.service.GetAvailableDates(ctx.Request.Context(), serviceID, year, month)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -232,24 +246,29 @@ func (c *BookingController) GetConfirmedBookingsForTechnician(ctx *gin.Context)
 	// Ambil user ID dari JWT token (asumsi sudah ada middleware yang menambahkan user ID ke context)
 	userID, exists := ctx.Get("user_id")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
+		ctx.Error(apperror.Unauthorized("invalid_token", "User ID not found in token"))
 		return
 	}
 
 	// Konversi userID ke integer
 	technicianID, ok := userID.(int)
 	if !ok {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		ctx.Error(apperror.InvalidParameter("Invalid user ID format"))
+		return
+	}
+	q, err := parseList(ctx, service.BookingListSpec)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	// Panggil service untuk mendapatkan booking dengan status "Confirmed" untuk technician
-	bookings, err := c.service.GetConfirmedBookingsForTechnician(technicianID)
+	bookings, err := c.service.GetConfirmedBookingsForTechnician(ctx.Request.Context(), technicianID, q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	// Kembalikan response JSON
-	ctx.JSON(http.StatusOK, bookings)
+	respondList(ctx, bookings)
 }
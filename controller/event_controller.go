@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"io"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const streamHeartbeatInterval = 25 * time.Second
+
+type EventController struct {
+	hub *realtime.Hub
+}
+
+func NewEventController(hub *realtime.Hub) *EventController {
+	return &EventController{hub: hub}
+}
+
+// StreamEvents mengirim semua event milik user yang sedang login (status
+// booking, status payment, pesan baru dan review baru) via Server-Sent Events.
+func (c *EventController) StreamEvents(ctx *gin.Context) {
+	events, unsubscribe := c.hub.Subscribe(ctx.GetInt("user_id"))
+	defer unsubscribe()
+
+	streamSSE(ctx, events, nil)
+}
+
+// WebSocketEvents mengirim event yang sama dengan StreamEvents melalui
+// WebSocket. Setiap event dikirim sebagai satu frame JSON.
+func (c *EventController) WebSocketEvents(ctx *gin.Context) {
+	userID := ctx.GetInt("user_id")
+
+	// Handshake dibiarkan kosong agar client non-browser (tanpa header Origin)
+	// tetap bisa terhubung; autentikasi sudah dilakukan oleh JWTAuth.
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		events, unsubscribe := c.hub.Subscribe(userID)
+		defer unsubscribe()
+
+		// Baca frame dari client hanya untuk mendeteksi koneksi yang terputus
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard string
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+		}()
+
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case evt, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(ws, evt); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if err := websocket.JSON.Send(ws, realtime.Event{Type: "ping", Data: time.Now()}); err != nil {
+					return
+				}
+			}
+		}
+	}}
+
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// streamSSE meneruskan event dari hub ke client sampai koneksi ditutup.
+// Jika filter diberikan, hanya event yang lolos filter yang dikirim.
+func streamSSE(ctx *gin.Context, events <-chan realtime.Event, filter func(realtime.Event) bool) {
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// Header dikirim segera agar client tahu stream sudah terbuka tanpa
+	// menunggu event atau heartbeat pertama
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Writer.WriteHeaderNow()
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case evt, ok := <-events:
+			if !ok {
+				return false
+			}
+			if filter == nil || filter(evt) {
+				ctx.SSEvent(evt.Type, evt.Data)
+			}
+			return true
+		case <-heartbeat.C:
+			ctx.SSEvent("ping", time.Now())
+			return true
+		}
+	})
+}
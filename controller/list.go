@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// parseList membaca query string endpoint list. Route alias tanpa versi
+// masih menerima offset seperti sebelum ada cursor.
+func parseList(ctx *gin.Context, spec listquery.Spec) (listquery.Query, error) {
+	if middleware.IsLegacyList(ctx) {
+		return listquery.ParseOffset(ctx.Request.URL.Query(), spec)
+	}
+	return listquery.Parse(ctx.Request.URL.Query(), spec)
+}
+
+// respondList menulis page sebagai envelope, atau hanya array item untuk
+// route alias tanpa versi.
+func respondList[T any](ctx *gin.Context, page listquery.Page[T]) {
+	if middleware.IsLegacyList(ctx) {
+		ctx.JSON(http.StatusOK, page.Items)
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type MessageController struct {
+	messageService service.MessageService
+	hub            *realtime.Hub
+}
+
+func NewMessageController(messageService service.MessageService, hub *realtime.Hub) *MessageController {
+	return &MessageController{messageService: messageService, hub: hub}
+}
+
+func (c *MessageController) SendMessage(ctx *gin.Context) {
+	bookingID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
+		return
+	}
+
+	userID, role := ctx.GetInt("user_id"), ctx.GetString("role")
+	if err := c.messageService.CheckAccess(ctx.Request.Context(), bookingID, userID, role); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var req entity.CreateMessageReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	// Lampiran bersifat opsional dan hanya tersedia untuk request multipart
+	var attachment string
+	if _, err := ctx.FormFile("attachment"); err == nil {
+		attachment, err = utils.SaveUpload(ctx, "attachment", fmt.Sprintf("booking%d", bookingID))
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+	}
+
+	messageRes, err := c.messageService.SendMessage(ctx.Request.Context(), bookingID, userID, role, &req, attachment)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, messageRes)
+}
+
+func (c *MessageController) GetMessages(ctx *gin.Context) {
+	bookingID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
+		return
+	}
+
+	legacy := middleware.IsLegacyList(ctx)
+	var q listquery.Query
+	if legacy {
+		q, err = legacyMessageQuery(ctx)
+	} else {
+		q, err = listquery.Parse(ctx.Request.URL.Query(), service.MessageListSpec)
+	}
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	page, err := c.messageService.GetMessages(ctx.Request.Context(), bookingID, ctx.GetInt("user_id"), ctx.GetString("role"), q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if legacy {
+		ctx.JSON(http.StatusOK, toLegacyMessagePage(page))
+		return
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// legacyMessagePage adalah response GetMessages di route alias tanpa versi,
+// sama seperti sebelum /api/v1: cursor berupa ID pesan terakhir.
+type legacyMessagePage struct {
+	Messages   []entity.MessageRes `json:"messages"`
+	NextCursor *int                `json:"next_cursor"` // ID pesan untuk halaman berikutnya, null jika sudah habis
+}
+
+// legacyMessageQuery membaca limit dan cursor ID pesan dari route alias.
+// Limit di luar 1-100 menjadi 20 seperti sebelumnya.
+func legacyMessageQuery(ctx *gin.Context) (listquery.Query, error) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > listquery.MaxLimit {
+		limit = 20
+	}
+	cursor, err := strconv.Atoi(ctx.DefaultQuery("cursor", "0"))
+	if err != nil {
+		return listquery.Query{}, apperror.InvalidParameter("Invalid cursor")
+	}
+
+	q := listquery.New(service.MessageListSpec, limit)
+	if cursor > 0 {
+		q.After = &listquery.Cursor{Sort: q.Sort.String(), ID: cursor}
+	}
+	return q, nil
+}
+
+func toLegacyMessagePage(page listquery.Page[entity.MessageRes]) legacyMessagePage {
+	legacy := legacyMessagePage{Messages: page.Items}
+	if page.NextCursor != nil && len(page.Items) > 0 {
+		last := page.Items[len(page.Items)-1].ID
+		legacy.NextCursor = &last
+	}
+	return legacy
+}
+
+func (c *MessageController) MarkAsRead(ctx *gin.Context) {
+	bookingID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
+		return
+	}
+
+	updated, err := c.messageService.MarkAsRead(ctx.Request.Context(), bookingID, ctx.GetInt("user_id"), ctx.GetString("role"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"marked_as_read": updated})
+}
+
+func (c *MessageController) GetAttachment(ctx *gin.Context) {
+	bookingID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
+		return
+	}
+
+	messageID, err := strconv.Atoi(ctx.Param("message_id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("Invalid message ID"))
+		return
+	}
+
+	name, err := c.messageService.GetAttachment(ctx.Request.Context(), bookingID, messageID, ctx.GetInt("user_id"), ctx.GetString("role"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	path, err := utils.UploadPath(name)
+	if err != nil {
+		ctx.Error(service.ErrAttachmentNotFound.Wrap(err))
+		return
+	}
+
+	ctx.File(path)
+}
+
+func (c *MessageController) GetUnreadCounts(ctx *gin.Context) {
+	counts, err := c.messageService.GetUnreadCounts(ctx.Request.Context(), ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, counts)
+}
+
+// StreamMessages mengirim pesan baru dan read receipt dari sebuah booking
+// secara realtime menggunakan Server-Sent Events.
+func (c *MessageController) StreamMessages(ctx *gin.Context) {
+	bookingID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("Invalid booking ID"))
+		return
+	}
+
+	userID := ctx.GetInt("user_id")
+	if err := c.messageService.CheckAccess(ctx.Request.Context(), bookingID, userID, ctx.GetString("role")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	events, unsubscribe := c.hub.Subscribe(userID)
+	defer unsubscribe()
+
+	streamSSE(ctx, events, func(evt realtime.Event) bool {
+		return strings.HasPrefix(evt.Type, "message.") && eventBookingID(evt) == bookingID
+	})
+}
+
+// eventBookingID membaca field booking_id dari payload event.
+func eventBookingID(evt realtime.Event) int {
+	raw, err := json.Marshal(evt.Data)
+	if err != nil {
+		return 0
+	}
+
+	var data struct {
+		BookingID int `json:"booking_id"`
+	}
+	_ = json.Unmarshal(raw, &data)
+	return data.BookingID
+}
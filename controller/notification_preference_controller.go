@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationPreferenceController struct {
+	preferenceService service.NotificationPreferenceService
+}
+
+func NewNotificationPreferenceController(preferenceService service.NotificationPreferenceService) *NotificationPreferenceController {
+	return &NotificationPreferenceController{preferenceService: preferenceService}
+}
+
+func (c *NotificationPreferenceController) GetPreferences(ctx *gin.Context) {
+	preferences, err := c.preferenceService.GetPreferences(ctx.Request.Context(), ctx.GetInt("user_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
+
+func (c *NotificationPreferenceController) UpdatePreferences(ctx *gin.Context) {
+	var req entity.UpdateNotificationPreferencesReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	preferences, err := c.preferenceService.UpdatePreferences(ctx.Request.Context(), ctx.GetInt("user_id"), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"preferences": preferences})
+}
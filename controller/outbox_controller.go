@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxController struct {
+	outboxService service.OutboxService
+}
+
+func NewOutboxController(outboxService service.OutboxService) *OutboxController {
+	return &OutboxController{outboxService: outboxService}
+}
+
+func (c *OutboxController) GetEvents(ctx *gin.Context) {
+	q, err := parseList(ctx, service.OutboxEventListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	events, err := c.outboxService.GetEvents(ctx.Request.Context(), q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondList(ctx, events)
+}
+
+func (c *OutboxController) GetEventByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid event ID"))
+		return
+	}
+
+	evt, err := c.outboxService.GetEventByID(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, evt)
+}
+
+func (c *OutboxController) ReplayEvent(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid event ID"))
+		return
+	}
+
+	evt, err := c.outboxService.ReplayEvent(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "event queued for replay", "event": evt})
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
 	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
@@ -21,13 +22,13 @@ func NewPaymentController(service service.PaymentService) *PaymentController {
 func (c *PaymentController) CreatePayment(ctx *gin.Context) {
 	var req entity.CreatePaymentReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	payment, err := c.service.CreatePayment(req)
+	payment, err := c.service.CreatePayment(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -38,13 +39,13 @@ func (c *PaymentController) GetPaymentByID(ctx *gin.Context) {
 	id := ctx.Param("id")
 	paymentID, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid payment ID"))
 		return
 	}
 
-	payment, err := c.service.GetPaymentByID(paymentID)
+	payment, err := c.service.GetPaymentByID(ctx.Request.Context(), paymentID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		ctx.Error(err)
 		return
 	}
 
@@ -54,13 +55,13 @@ func (c *PaymentController) GetPaymentByID(ctx *gin.Context) {
 func (c *PaymentController) UpdatePayment(ctx *gin.Context) {
 	var req entity.UpdatePaymentReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	payment, err := c.service.UpdatePayment(req)
+	payment, err := c.service.UpdatePayment(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -71,13 +72,13 @@ func (c *PaymentController) DeletePayment(ctx *gin.Context) {
 	id := ctx.Param("id")
 	paymentID, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid payment ID"))
 		return
 	}
 
-	err = c.service.DeletePayment(paymentID)
+	err = c.service.DeletePayment(ctx.Request.Context(), paymentID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -85,32 +86,35 @@ func (c *PaymentController) DeletePayment(ctx *gin.Context) {
 }
 
 func (c *PaymentController) GetAllPayments(ctx *gin.Context) {
-	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	q, err := parseList(ctx, service.PaymentListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	payments, err := c.service.GetAllPayments(limit, offset)
+	payments, err := c.service.GetAllPayments(ctx.Request.Context(), q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, payments)
+	respondList(ctx, payments)
 }
 
 func (c *PaymentController) UpdatePaymentStatus(ctx *gin.Context) {
 	paymentID := ctx.Param("id")
 	var req struct {
-		Status string `json:"status"`
+		Status string `json:"status" validate:"required"`
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	err := c.service.UpdatePaymentStatus(paymentID, req.Status)
+	err := c.service.UpdatePaymentStatus(ctx.Request.Context(), paymentID, req.Status)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -133,7 +137,7 @@ func (c *PaymentController) GetPaymentReport(ctx *gin.Context) {
 	if startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format"})
+			ctx.Error(apperror.InvalidParameter("Invalid start_date format"))
 			return
 		}
 	}
@@ -141,15 +145,15 @@ func (c *PaymentController) GetPaymentReport(ctx *gin.Context) {
 	if endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format"})
+			ctx.Error(apperror.InvalidParameter("Invalid end_date format"))
 			return
 		}
 	}
 
 	// Panggil service untuk mendapatkan laporan pembayaran
-	report, err := c.service.GetPaymentReport(startDate, endDate, serviceID)
+	report, err := c.service.GetPaymentReport(ctx.Request.Context(), startDate, endDate, serviceID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
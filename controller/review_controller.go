@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
 	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
@@ -21,13 +22,13 @@ func NewReviewController(service service.ReviewService) *ReviewController {
 func (c *ReviewController) CreateReview(ctx *gin.Context) {
 	var req entity.CreateReviewReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	review, err := c.service.CreateReview(req)
+	review, err := c.service.CreateReview(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -38,13 +39,13 @@ func (c *ReviewController) GetReviewByID(ctx *gin.Context) {
 	id := ctx.Param("id")
 	reviewID, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid review ID"))
 		return
 	}
 
-	review, err := c.service.GetReviewByID(reviewID)
+	review, err := c.service.GetReviewByID(ctx.Request.Context(), reviewID)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		ctx.Error(err)
 		return
 	}
 
@@ -54,13 +55,13 @@ func (c *ReviewController) GetReviewByID(ctx *gin.Context) {
 func (c *ReviewController) UpdateReview(ctx *gin.Context) {
 	var req entity.UpdateReviewReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	review, err := c.service.UpdateReview(req)
+	review, err := c.service.UpdateReview(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -71,13 +72,13 @@ func (c *ReviewController) DeleteReview(ctx *gin.Context) {
 	id := ctx.Param("id")
 	reviewID, err := strconv.Atoi(id)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid review ID"))
 		return
 	}
 
-	err = c.service.DeleteReview(reviewID)
+	err = c.service.DeleteReview(ctx.Request.Context(), reviewID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -85,16 +86,19 @@ func (c *ReviewController) DeleteReview(ctx *gin.Context) {
 }
 
 func (c *ReviewController) GetAllReviews(ctx *gin.Context) {
-	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	q, err := parseList(ctx, service.ReviewListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	reviews, err := c.service.GetAllReviews(limit, offset)
+	reviews, err := c.service.GetAllReviews(ctx.Request.Context(), q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, reviews)
+	respondList(ctx, reviews)
 }
 
 func (c *ReviewController) GetReviewReport(ctx *gin.Context) {
@@ -113,7 +117,7 @@ func (c *ReviewController) GetReviewReport(ctx *gin.Context) {
 	if startDateStr != "" {
 		startDate, err = time.Parse("2006-01-02", startDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format"})
+			ctx.Error(apperror.InvalidParameter("Invalid start_date format"))
 			return
 		}
 	}
@@ -121,15 +125,15 @@ func (c *ReviewController) GetReviewReport(ctx *gin.Context) {
 	if endDateStr != "" {
 		endDate, err = time.Parse("2006-01-02", endDateStr)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format"})
+			ctx.Error(apperror.InvalidParameter("Invalid end_date format"))
 			return
 		}
 	}
 
 	// Panggil service untuk mendapatkan laporan review
-	report, err := c.service.GetReviewReport(startDate, endDate, serviceID)
+	report, err := c.service.GetReviewReport(ctx.Request.Context(), startDate, endDate, serviceID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
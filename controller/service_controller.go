@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
 	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
@@ -30,25 +31,33 @@ func NewServiceController(serviceService service.ServiceService) *ServiceControl
 func (c *ServiceController) CreateService(ctx *gin.Context) {
 	var req entity.CreateServiceReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	service, err := c.serviceService.CreateService(req)
+	// Service selalu dibuat atas nama technician yang sedang login
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.Error(apperror.Unauthorized("invalid_token", "User ID not found in token"))
+		return
+	}
+	req.UserID = userID.(int)
+
+	newService, err := c.serviceService.CreateService(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	// Gunakan ServiceRes untuk menghilangkan User dari response
 	serviceRes := entity.ServiceRes{
-		ID:          service.ID,
-		UserID:      service.UserID,
-		Name:        service.Name,
-		Description: service.Description,
-		Cost:        service.Cost,
-		CreatedAt:   service.CreatedAt,
-		UpdatedAt:   service.UpdatedAt,
+		ID:          newService.ID,
+		UserID:      newService.UserID,
+		Name:        newService.Name,
+		Description: newService.Description,
+		Cost:        newService.Cost,
+		CreatedAt:   newService.CreatedAt,
+		UpdatedAt:   newService.UpdatedAt,
 	}
 
 	ctx.JSON(http.StatusCreated, serviceRes)
@@ -57,13 +66,13 @@ func (c *ServiceController) CreateService(ctx *gin.Context) {
 func (c *ServiceController) GetServiceByID(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		ctx.Error(apperror.InvalidParameter("invalid ID"))
 		return
 	}
 
-	service, err := c.serviceService.GetServiceByID(id)
+	service, err := c.serviceService.GetServiceByID(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -73,13 +82,13 @@ func (c *ServiceController) GetServiceByID(ctx *gin.Context) {
 func (c *ServiceController) UpdateService(ctx *gin.Context) {
 	var req entity.UpdateServiceReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	service, err := c.serviceService.UpdateService(req)
+	service, err := c.serviceService.UpdateService(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -100,13 +109,13 @@ func (c *ServiceController) UpdateService(ctx *gin.Context) {
 func (c *ServiceController) DeleteService(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		ctx.Error(apperror.InvalidParameter("invalid ID"))
 		return
 	}
 
-	err = c.serviceService.DeleteService(id)
+	err = c.serviceService.DeleteService(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -114,85 +123,77 @@ func (c *ServiceController) DeleteService(ctx *gin.Context) {
 }
 
 func (c *ServiceController) GetAllServices(ctx *gin.Context) {
-	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	q, err := parseList(ctx, service.ServiceListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
 
-	services, err := c.serviceService.GetAllServices(limit, offset)
+	services, err := c.serviceService.GetAllServices(ctx.Request.Context(), q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, services)
+	respondList(ctx, services)
 }
 
 func (c *ServiceController) GetServicesByUserID(ctx *gin.Context) {
 	userID, err := strconv.Atoi(ctx.Param("user_id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		ctx.Error(apperror.InvalidParameter("Invalid user ID"))
+		return
+	}
+	q, err := parseList(ctx, service.ServiceListSpec)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	services, err := c.serviceService.GetServicesByUserID(userID)
+	services, err := c.serviceService.GetServicesByUserID(ctx.Request.Context(), userID, q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, services)
+	respondList(ctx, services)
 }
 
+// SearchServices mencari berdasarkan parameter search; filter harga
+// (min_price, max_price), sort dan cursor sama dengan GetAllServices.
 func (c *ServiceController) SearchServices(ctx *gin.Context) {
-	searchQuery := ctx.Query("search") // Ambil parameter query string "search"
-	minPriceStr := ctx.Query("min_price")
-	maxPriceStr := ctx.Query("max_price")
-
-	var minPrice, maxPrice int
-	var err error
-
-	// Jika min_price tidak disebutkan, set ke 0
-	if minPriceStr == "" {
-		minPrice = 0
-	} else {
-		minPrice, err = strconv.Atoi(minPriceStr)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_price"})
-			return
-		}
+	q, err := parseList(ctx, service.ServiceListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
 
-	// Jika max_price tidak disebutkan, set ke 100 juta
-	if maxPriceStr == "" {
-		maxPrice = 100000000
-	} else {
-		maxPrice, err = strconv.Atoi(maxPriceStr)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid max_price"})
+	// Nilai harga sudah divalidasi Parse, tinggal cek urutannya
+	if minPriceStr, maxPriceStr := ctx.Query("min_price"), ctx.Query("max_price"); minPriceStr != "" && maxPriceStr != "" {
+		minPrice, _ := strconv.Atoi(minPriceStr)
+		maxPrice, _ := strconv.Atoi(maxPriceStr)
+		if minPrice > maxPrice {
+			ctx.Error(apperror.InvalidParameter("min_price must be less than or equal to max_price"))
 			return
 		}
 	}
 
-	if minPrice > maxPrice {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "min_price must be less than or equal to max_price"})
-		return
-	}
-
-	services, err := c.serviceService.SearchServices(searchQuery, minPrice, maxPrice)
+	services, err := c.serviceService.SearchServices(ctx.Request.Context(), ctx.Query("search"), q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, services)
+	respondList(ctx, services)
 }
 
 func (c *ServiceController) GetServiceCostReport(ctx *gin.Context) {
 	startDate := ctx.Query("start_date")
 	endDate := ctx.Query("end_date")
 
-	report, err := c.serviceService.GetServiceCostReport(startDate, endDate)
+	report, err := c.serviceService.GetServiceCostReport(ctx.Request.Context(), startDate, endDate)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
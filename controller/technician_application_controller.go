@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TechnicianApplicationController struct {
+	applicationService service.TechnicianApplicationService
+}
+
+func NewTechnicianApplicationController(applicationService service.TechnicianApplicationService) *TechnicianApplicationController {
+	return &TechnicianApplicationController{applicationService: applicationService}
+}
+
+func (c *TechnicianApplicationController) SubmitApplication(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.Error(apperror.Unauthorized("invalid_token", "User ID not found in token"))
+		return
+	}
+
+	var req entity.RegisterAsTechnicianReq
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	// Simpan dokumen KTP dan sertifikat
+	prefix := fmt.Sprintf("user%d", userID.(int))
+	idDocument, err := utils.SaveUpload(ctx, "id_document", prefix)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	certificate, err := utils.SaveUpload(ctx, "certificate", prefix)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	applicationRes, err := c.applicationService.SubmitApplication(ctx.Request.Context(), userID.(int), &req, idDocument, certificate)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, applicationRes)
+}
+
+func (c *TechnicianApplicationController) GetMyApplication(ctx *gin.Context) {
+	userID, exists := ctx.Get("user_id")
+	if !exists {
+		ctx.Error(apperror.Unauthorized("invalid_token", "User ID not found in token"))
+		return
+	}
+
+	applicationRes, err := c.applicationService.GetMyApplication(ctx.Request.Context(), userID.(int))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, applicationRes)
+}
+
+func (c *TechnicianApplicationController) GetApplicationByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid application ID"))
+		return
+	}
+
+	application, err := c.applicationService.GetApplicationByID(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, application)
+}
+
+func (c *TechnicianApplicationController) GetAllApplications(ctx *gin.Context) {
+	q, err := parseList(ctx, service.ApplicationListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	applications, err := c.applicationService.GetAllApplications(ctx.Request.Context(), q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondList(ctx, applications)
+}
+
+func (c *TechnicianApplicationController) GetApplicationDocument(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid application ID"))
+		return
+	}
+
+	application, err := c.applicationService.GetApplicationByID(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var name string
+	switch ctx.Param("document") {
+	case "id-document":
+		name = application.IDDocument
+	case "certificate":
+		name = application.Certificate
+	default:
+		ctx.Error(apperror.InvalidParameter("document must be id-document or certificate"))
+		return
+	}
+
+	path, err := utils.UploadPath(name)
+	if err != nil {
+		ctx.Error(apperror.NotFound("document_not_found", "document not found").Wrap(err))
+		return
+	}
+
+	ctx.File(path)
+}
+
+func (c *TechnicianApplicationController) StartReview(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid application ID"))
+		return
+	}
+
+	reviewerID, _ := ctx.Get("user_id")
+
+	applicationRes, err := c.applicationService.StartReview(ctx.Request.Context(), id, reviewerID.(int))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, applicationRes)
+}
+
+func (c *TechnicianApplicationController) ApproveApplication(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid application ID"))
+		return
+	}
+
+	// Alasan persetujuan bersifat opsional, body boleh kosong
+	var req entity.ReviewTechnicianApplicationReq
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	reviewerID, _ := ctx.Get("user_id")
+
+	applicationRes, err := c.applicationService.ApproveApplication(ctx.Request.Context(), id, reviewerID.(int), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, applicationRes)
+}
+
+func (c *TechnicianApplicationController) RejectApplication(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid application ID"))
+		return
+	}
+
+	var req entity.ReviewTechnicianApplicationReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	reviewerID, _ := ctx.Get("user_id")
+
+	applicationRes, err := c.applicationService.RejectApplication(ctx.Request.Context(), id, reviewerID.(int), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, applicationRes)
+}
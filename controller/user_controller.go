@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
 	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
 )
@@ -20,13 +23,13 @@ func NewUserController(userService service.UserService) *UserController {
 func (c *UserController) Register(ctx *gin.Context) {
 	var req entity.RegisterUserReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	userRes, err := c.userService.Register(&req)
+	userRes, err := c.userService.Register(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -36,13 +39,17 @@ func (c *UserController) Register(ctx *gin.Context) {
 func (c *UserController) Login(ctx *gin.Context) {
 	var req entity.LoginUserReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	userRes, token, err := c.userService.Login(&req)
+	userRes, token, err := c.userService.Login(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		var locked *service.AccountLockedError
+		if errors.As(err, &locked) {
+			ctx.Header("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(locked.RetryAfter)))
+		}
+		ctx.Error(err)
 		return
 	}
 
@@ -56,13 +63,13 @@ func (c *UserController) Login(ctx *gin.Context) {
 func (c *UserController) GetUserByID(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		ctx.Error(apperror.InvalidParameter("invalid user ID"))
 		return
 	}
 
-	userRes, err := c.userService.GetUserByID(id)
+	userRes, err := c.userService.GetUserByID(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		ctx.Error(err)
 		return
 	}
 
@@ -70,60 +77,47 @@ func (c *UserController) GetUserByID(ctx *gin.Context) {
 }
 
 func (c *UserController) GetAllUsers(ctx *gin.Context) {
-	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
-
-	users, err := c.userService.GetAllUsers(limit, offset)
+	q, err := parseList(ctx, service.UserListSpec)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, users)
-}
-
-func (c *UserController) UpdateUser(ctx *gin.Context) {
-	var req entity.UpdateUserReq
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	userRes, err := c.userService.UpdateUser(&req)
+	users, err := c.userService.GetAllUsers(ctx.Request.Context(), q)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, userRes)
+	respondList(ctx, users)
 }
 
-func (c *UserController) RegisterAsTechnician(ctx *gin.Context) {
-	var req entity.RegisterAsTechnicianReq
+func (c *UserController) UpdateUser(ctx *gin.Context) {
+	var req entity.UpdateUserReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	technicianRes, err := c.userService.RegisterAsTechnician(&req)
+	userRes, err := c.userService.UpdateUser(ctx.Request.Context(), &req, ctx.GetInt("user_id"), ctx.GetString("role"))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, technicianRes)
+	ctx.JSON(http.StatusOK, userRes)
 }
 
 func (c *UserController) UpdateTechnician(ctx *gin.Context) {
 	var req entity.UpdateTechnicianReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
-	technicianRes, err := c.userService.UpdateTechnician(&req)
+	technicianRes, err := c.userService.UpdateTechnician(ctx.Request.Context(), &req, ctx.GetInt("user_id"), ctx.GetString("role"))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -133,13 +127,13 @@ func (c *UserController) UpdateTechnician(ctx *gin.Context) {
 func (c *UserController) DeleteUser(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		ctx.Error(apperror.InvalidParameter("invalid user ID"))
 		return
 	}
 
-	err = c.userService.DeleteUser(id)
+	err = c.userService.DeleteUser(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -149,14 +143,14 @@ func (c *UserController) DeleteUser(ctx *gin.Context) {
 func (c *UserController) RegisterAsAdmin(ctx *gin.Context) {
 	var req entity.RegisterUserReq
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	// Panggil service untuk register sebagai admin
-	userRes, err := c.userService.RegisterAsAdmin(&req)
+	userRes, err := c.userService.RegisterAsAdmin(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -168,9 +162,9 @@ func (c *UserController) GetUserRoleReport(ctx *gin.Context) {
 	startDate := ctx.Query("start_date")
 	endDate := ctx.Query("end_date")
 
-	report, err := c.userService.GetUserRoleReport(startDate, endDate)
+	report, err := c.userService.GetUserRoleReport(ctx.Request.Context(), startDate, endDate)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
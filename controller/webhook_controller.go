@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+func (c *WebhookController) CreateEndpoint(ctx *gin.Context) {
+	var req entity.CreateWebhookEndpointReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	endpoint, err := c.webhookService.CreateEndpoint(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Webhook endpoint created successfully", "endpoint": endpoint})
+}
+
+func (c *WebhookController) GetEndpoints(ctx *gin.Context) {
+	q, err := parseList(ctx, service.WebhookEndpointListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	endpoints, err := c.webhookService.GetEndpoints(ctx.Request.Context(), q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondList(ctx, endpoints)
+}
+
+func (c *WebhookController) GetEndpointByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid webhook ID"))
+		return
+	}
+
+	endpoint, err := c.webhookService.GetEndpointByID(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, endpoint)
+}
+
+func (c *WebhookController) UpdateEndpoint(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid webhook ID"))
+		return
+	}
+
+	var req entity.UpdateWebhookEndpointReq
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	endpoint, err := c.webhookService.UpdateEndpoint(ctx.Request.Context(), id, &req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint updated successfully", "endpoint": endpoint})
+}
+
+func (c *WebhookController) DeleteEndpoint(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid webhook ID"))
+		return
+	}
+
+	err = c.webhookService.DeleteEndpoint(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint deleted successfully"})
+}
+
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid webhook ID"))
+		return
+	}
+	q, err := parseList(ctx, service.WebhookDeliveryListSpec)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	deliveries, err := c.webhookService.GetDeliveries(ctx.Request.Context(), id, q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	respondList(ctx, deliveries)
+}
+
+func (c *WebhookController) Redeliver(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid webhook ID"))
+		return
+	}
+	deliveryID, err := strconv.Atoi(ctx.Param("delivery_id"))
+	if err != nil {
+		ctx.Error(apperror.InvalidParameter("invalid delivery ID"))
+		return
+	}
+
+	delivery, err := c.webhookService.Redeliver(ctx.Request.Context(), id, deliveryID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "Webhook delivery queued", "delivery": delivery})
+}
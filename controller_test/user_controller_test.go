@@ -11,7 +11,9 @@ import (
 
 	"github.com/Ayyasy123/dibimbing-capstone.git/controller"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
 	"github.com/Ayyasy123/dibimbing-capstone.git/mocks"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -24,6 +26,11 @@ func TestUserController_Register(t *testing.T) {
 	mockUserService := mocks.NewMockUserService(ctrl)
 	userController := controller.NewUserController(mockUserService)
 
+	// Error dari controller diubah menjadi response oleh middleware ErrorHandler
+	router := gin.New()
+	router.Use(middleware.ErrorHandler())
+	router.POST("/register", userController.Register)
+
 	testCases := []struct {
 		name           string
 		requestBody    interface{}
@@ -39,7 +46,7 @@ func TestUserController_Register(t *testing.T) {
 				Password: "password123",
 			},
 			mockSetup: func() {
-				mockUserService.EXPECT().Register(gomock.Any()).Return(&entity.UserRes{
+				mockUserService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(&entity.UserRes{
 					ID:        1,
 					Name:      "John Doe",
 					Email:     "john@example.com",
@@ -66,7 +73,20 @@ func TestUserController_Register(t *testing.T) {
 				Password: "password123",
 			},
 			mockSetup: func() {
-				mockUserService.EXPECT().Register(gomock.Any()).Return(nil, errors.New("email already registered"))
+				mockUserService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, service.ErrEmailTaken)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   nil,
+		},
+		{
+			name: "Failure - Unexpected database error",
+			requestBody: entity.RegisterUserReq{
+				Name:     "Jane Doe",
+				Email:    "jane@example.com",
+				Password: "password123",
+			},
+			mockSetup: func() {
+				mockUserService.EXPECT().Register(gomock.Any(), gomock.Any()).Return(nil, errors.New("database is locked"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   nil,
@@ -85,10 +105,7 @@ func TestUserController_Register(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 
 			res := httptest.NewRecorder()
-			ctx, _ := gin.CreateTestContext(res)
-			ctx.Request = req
-
-			userController.Register(ctx)
+			router.ServeHTTP(res, req)
 
 			assert.Equal(t, tc.expectedStatus, res.Code)
 
@@ -21,7 +21,7 @@ type Booking struct {
 type CreateBookingReq struct {
 	UserID    int       `json:"user_id" validate:"required"`
 	ServiceID int       `json:"service_id" validate:"required"`
-	Date      time.Time `json:"date" validate:"required"`
+	Date      time.Time `json:"date" validate:"required,future_date"`
 	// Time        time.Time `json:"time" validate:"required"`
 	Status      string `json:"status"`
 	Description string `json:"description"`
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// Job adalah pekerjaan latar belakang yang disimpan di database agar tetap
+// dijalankan meskipun server di-restart, dan hanya dijalankan oleh satu
+// replica meskipun ada beberapa API server.
+type Job struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type        string     `json:"type" gorm:"size:100;not null;index"`
+	UniqueKey   *string    `json:"unique_key,omitempty" gorm:"size:191;uniqueIndex"` // Mencegah job yang sama dijadwalkan dua kali
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Status      string     `json:"status" gorm:"size:20;not null;index:idx_job_due"` // Pending, Running, Completed, Failed
+	RunAt       time.Time  `json:"run_at" gorm:"index:idx_job_due"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	LockedBy    string     `json:"locked_by,omitempty" gorm:"size:100"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type Message struct {
+	ID         int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	BookingID  int        `json:"booking_id" gorm:"not null;index"`
+	SenderID   int        `json:"sender_id" gorm:"not null"`
+	Body       string     `json:"body" gorm:"type:text"`
+	Attachment string     `json:"-"`       // Nama file lampiran di upload dir
+	ReadAt     *time.Time `json:"read_at"` // Diisi saat pihak lain membaca pesan
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Booking    Booking    `json:"booking,omitempty" gorm:"foreignKey:BookingID"` // Relasi: Message belongs to Booking
+}
+
+// CreateMessageReq bisa dikirim sebagai JSON atau multipart/form-data
+// (dengan file opsional attachment).
+type CreateMessageReq struct {
+	Body string `json:"body" form:"body"`
+}
+
+type MessageRes struct {
+	ID            int        `json:"id"`
+	BookingID     int        `json:"booking_id"`
+	SenderID      int        `json:"sender_id"`
+	Body          string     `json:"body"`
+	HasAttachment bool       `json:"has_attachment"`
+	ReadAt        *time.Time `json:"read_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type UnreadCount struct {
+	BookingID   int `json:"booking_id"`
+	UnreadCount int `json:"unread_count"`
+}
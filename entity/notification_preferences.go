@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+type NotificationPreference struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int       `json:"user_id" gorm:"not null;uniqueIndex:idx_notification_preference"`
+	EventType string    `json:"event_type" gorm:"size:50;not null;uniqueIndex:idx_notification_preference"`
+	Channel   string    `json:"channel" gorm:"size:20;not null;uniqueIndex:idx_notification_preference"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type NotificationPreferenceRes struct {
+	EventType string `json:"event_type" validate:"required"`
+	Channel   string `json:"channel" validate:"required"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesReq struct {
+	Preferences []NotificationPreferenceRes `json:"preferences" validate:"required,dive"`
+}
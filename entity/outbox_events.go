@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent adalah domain event yang disimpan dalam transaksi yang sama dengan
+// perubahan state, lalu dikirim ke subscriber oleh dispatcher.
+type OutboxEvent struct {
+	ID          int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type        string     `json:"type" gorm:"size:100;not null;index"`
+	Recipients  string     `json:"recipients" gorm:"type:text"` // JSON: peran -> user ID
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Status      string     `json:"status" gorm:"size:20;not null;index:idx_outbox_due"` // Pending, Processing, Delivered, Failed
+	AvailableAt time.Time  `json:"available_at" gorm:"index:idx_outbox_due"`
+	Attempts    int        `json:"attempts"`
+	LockedAt    *time.Time `json:"locked_at"`
+	LastError   string     `json:"last_error" gorm:"type:text"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ProcessedEvent mencatat event yang sudah berhasil diproses oleh subscriber
+// tertentu, sehingga pengiriman ulang tidak diproses dua kali.
+type ProcessedEvent struct {
+	EventID   int       `gorm:"primaryKey;autoIncrement:false" json:"event_id"`
+	Handler   string    `gorm:"primaryKey;size:100" json:"handler"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OutboxEventRes struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	Recipients  map[string]int  `json:"recipients"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	AvailableAt time.Time       `json:"available_at"`
+	DeliveredAt *time.Time      `json:"delivered_at,omitempty"`
+}
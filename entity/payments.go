@@ -14,8 +14,8 @@ type Payment struct {
 
 type CreatePaymentReq struct {
 	BookingID int       `json:"booking_id" validate:"required"`
-	Amount    string    `json:"amount" validate:"required"`
-	Status    string    `json:"status" validate:"required"`
+	Amount    string    `json:"amount" validate:"required,money"`
+	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -23,7 +23,7 @@ type CreatePaymentReq struct {
 type UpdatePaymentReq struct {
 	ID        int       `json:"id" validate:"required"`
 	BookingID int       `json:"booking_id" validate:"required"`
-	Amount    string    `json:"amount" validate:"required"`
+	Amount    string    `json:"amount" validate:"required,money"`
 	Status    string    `json:"status" validate:"required"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
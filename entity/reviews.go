@@ -14,7 +14,7 @@ type Review struct {
 
 type CreateReviewReq struct {
 	BookingID int       `json:"booking_id" validate:"required"`
-	Rating    int       `json:"rating" validate:"required"`
+	Rating    int       `json:"rating" validate:"required,rating"`
 	Comment   string    `json:"comment" validate:"required"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -23,7 +23,7 @@ type CreateReviewReq struct {
 type UpdateReviewReq struct {
 	ID        int       `json:"id" validate:"required"`
 	BookingID int       `json:"booking_id" validate:"required"`
-	Rating    int       `json:"rating" validate:"required"`
+	Rating    int       `json:"rating" validate:"required,rating"`
 	Comment   string    `json:"comment" validate:"required"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
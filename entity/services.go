@@ -15,18 +15,18 @@ type Service struct {
 }
 
 type CreateServiceReq struct {
-	UserID      int    `json:"user_id" validate:"required"` // Foreign key ke User
-	Name        string `json:"name" validate:"required"`
+	UserID      int    `json:"user_id"` // Foreign key ke User, diisi dari token
+	Name        string `json:"name" validate:"required,max=100"`
 	Description string `json:"description"`
-	Cost        int    `json:"cost" validate:"required"`
+	Cost        int    `json:"cost" validate:"required,money"`
 }
 
 type UpdateServiceReq struct {
 	ID          int    `json:"id" validate:"required"`
 	UserID      int    `json:"user_id" validate:"required"` // Foreign key ke User
-	Name        string `json:"name" validate:"required"`
+	Name        string `json:"name" validate:"required,max=100"`
 	Description string `json:"description" validate:"required"`
-	Cost        int    `json:"cost" validate:"required"`
+	Cost        int    `json:"cost" validate:"required,money"`
 }
 
 type ServiceRes struct {
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type TechnicianApplication struct {
+	ID                     int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID                 int        `json:"user_id" gorm:"not null;index"`
+	Status                 string     `json:"status"` // Submitted, Under Review, Approved, Rejected
+	Address                string     `json:"address"`
+	Phone                  string     `json:"phone"`
+	Expertise              string     `json:"expertise"`
+	Availability           string     `json:"availability"`
+	IDDocument             string     `json:"-"` // Path file KTP di upload dir
+	Certificate            string     `json:"-"` // Path file sertifikat di upload dir
+	CertificationExpiresAt time.Time  `json:"certification_expires_at" gorm:"type:date"`
+	ReviewerID             *int       `json:"reviewer_id"`
+	ReviewReason           string     `json:"review_reason"`
+	ReviewedAt             *time.Time `json:"reviewed_at"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+	User                   User       `json:"user,omitempty" gorm:"foreignKey:UserID"` // Relasi: TechnicianApplication belongs to User
+}
+
+type ReviewTechnicianApplicationReq struct {
+	Reason string `json:"reason"`
+}
+
+type TechnicianApplicationRes struct {
+	ID                     int        `json:"id"`
+	UserID                 int        `json:"user_id"`
+	Status                 string     `json:"status"`
+	Address                string     `json:"address"`
+	Phone                  string     `json:"phone"`
+	Expertise              string     `json:"expertise"`
+	Availability           string     `json:"availability"`
+	CertificationExpiresAt time.Time  `json:"certification_expires_at"`
+	ReviewerID             *int       `json:"reviewer_id"`
+	ReviewReason           string     `json:"review_reason"`
+	ReviewedAt             *time.Time `json:"reviewed_at"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
@@ -7,11 +7,12 @@ type User struct {
 	Name         string    `json:"name"`
 	Email        string    `json:"email"`
 	Password     string    `json:"password"`
-	Role         string    `gorm:"type:ENUM('admin', 'user', 'technician');default:'user'" json:"role"`
+	Role         string    `gorm:"size:20;default:'user';check:chk_users_role,role IN ('admin', 'user', 'technician')" json:"role"` // ENUM hanya ada di MySQL, jadi dibatasi dengan CHECK
 	Address      string    `json:"address"`
 	Phone        string    `json:"phone"`
 	Expertise    string    `json:"expertise"`
 	Availability string    `json:"availability"`
+	Language     string    `gorm:"size:5;default:'id'" json:"language"` // Bahasa notifikasi: id atau en
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	Services     []Service `json:"services,omitempty" gorm:"foreignKey:UserID"` // Relasi: User has many Services
@@ -19,42 +20,45 @@ type User struct {
 }
 
 type RegisterUserReq struct {
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email_addr"`
 	Password string `json:"password" validate:"required"`
 }
 
 type LoginUserReq struct {
-	Email    string `json:"email" validate:"required"`
+	Email    string `json:"email" validate:"required,email_addr"`
 	Password string `json:"password" validate:"required"`
 }
 
+// RegisterAsTechnicianReq dikirim sebagai multipart/form-data bersama file
+// id_document dan certificate.
 type RegisterAsTechnicianReq struct {
-	ID           int    `json:"id" validate:"required"`
-	Address      string `json:"address" validate:"required"`
-	Phone        string `json:"phone" validate:"required"`
-	Expertise    string `json:"expertise" validate:"required"`
-	Availability string `json:"availability" validate:"required"`
+	Address                string    `form:"address" validate:"required"`
+	Phone                  string    `form:"phone" validate:"required,phone_id"`
+	Expertise              string    `form:"expertise" validate:"required"`
+	Availability           string    `form:"availability" validate:"required"`
+	CertificationExpiresAt time.Time `form:"certification_expires_at" time_format:"2006-01-02" validate:"required,future_date"`
 }
 
 type UpdateUserReq struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
+	ID       int    `json:"id" validate:"required"`
+	Name     string `json:"name" validate:"omitempty,max=100"`
+	Email    string `json:"email" validate:"omitempty,email_addr"`
 	Password string `json:"password"`
-	Role     string `json:"role"`
+	Role     string `json:"role" validate:"omitempty,oneof=admin user technician"`
 	Address  string `json:"address"`
-	Phone    string `json:"phone"`
+	Phone    string `json:"phone" validate:"omitempty,phone_id"`
+	Language string `json:"language" validate:"omitempty,oneof=id en"`
 }
 
 type UpdateTechnicianReq struct {
-	ID           int    `json:"id"`
-	Name         string `json:"name"`
-	Email        string `json:"email"`
+	ID           int    `json:"id" validate:"required"`
+	Name         string `json:"name" validate:"omitempty,max=100"`
+	Email        string `json:"email" validate:"omitempty,email_addr"`
 	Password     string `json:"password"`
-	Role         string `json:"role"`
+	Role         string `json:"role" validate:"omitempty,oneof=admin user technician"`
 	Address      string `json:"address"`
-	Phone        string `json:"phone"`
+	Phone        string `json:"phone" validate:"omitempty,phone_id"`
 	Expertise    string `json:"expertise"`
 	Availability string `json:"availability"`
 }
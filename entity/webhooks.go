@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// WebhookEndpoint adalah URL milik partner yang menerima event tertentu.
+type WebhookEndpoint struct {
+	ID                  int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL                 string     `json:"url" gorm:"size:500;not null"`
+	Secret              string     `json:"-" gorm:"size:100;not null"`
+	EventTypes          string     `json:"event_types" gorm:"size:1000"` // Dipisah koma, mis. "booking.confirmed,payment.paid"
+	Description         string     `json:"description"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery mencatat pengiriman satu event ke satu endpoint.
+type WebhookDelivery struct {
+	ID             int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	EndpointID     int             `json:"endpoint_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        int             `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventType      string          `json:"event_type" gorm:"size:100"`
+	Payload        string          `json:"-" gorm:"type:text"`
+	Status         string          `json:"status" gorm:"size:20"` // Pending, Succeeded, Failed
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status"`
+	ResponseBody   string          `json:"response_body" gorm:"type:text"`
+	LastError      string          `json:"last_error" gorm:"type:text"`
+	DurationMs     int64           `json:"duration_ms"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Endpoint       WebhookEndpoint `json:"-" gorm:"foreignKey:EndpointID"`
+}
+
+type CreateWebhookEndpointReq struct {
+	URL         string   `json:"url" validate:"required,url"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Description string   `json:"description"`
+}
+
+type UpdateWebhookEndpointReq struct {
+	URL         string   `json:"url" validate:"omitempty,url"`
+	EventTypes  []string `json:"event_types" validate:"omitempty,dive,required"`
+	Description *string  `json:"description"`
+	Active      *bool    `json:"active"` // true mengaktifkan kembali endpoint yang dinonaktifkan otomatis
+}
+
+type WebhookEndpointRes struct {
+	ID                  int        `json:"id"`
+	URL                 string     `json:"url"`
+	Secret              string     `json:"secret,omitempty"` // Hanya ditampilkan saat endpoint dibuat
+	EventTypes          []string   `json:"event_types"`
+	Description         string     `json:"description"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
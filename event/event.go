@@ -0,0 +1,37 @@
+package event
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Event adalah domain event yang dicatat oleh service saat state berubah.
+type Event struct {
+	ID         int            `json:"id"`
+	Type       string         `json:"type"`
+	Recipients map[string]int `json:"recipients"` // Peran -> user ID, mis. {"customer": 3, "technician": 9}
+	Data       interface{}    `json:"data"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+// UserIDs mengembalikan semua user yang terkait dengan event (tanpa duplikat).
+func (e Event) UserIDs() []int {
+	seen := make(map[int]bool, len(e.Recipients))
+	var ids []int
+	for _, id := range e.Recipients {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// StatusType membentuk tipe event dari status, mis. ("booking", "In Progress") -> "booking.in_progress".
+func StatusType(prefix, status string) string {
+	return prefix + "." + strings.ReplaceAll(strings.ToLower(status), " ", "_")
+}
+
+// Handler memproses satu event. Error membuat event dikirim ulang.
+type Handler func(ctx context.Context, evt Event) error
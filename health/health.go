@@ -0,0 +1,97 @@
+// Package health menjalankan pemeriksaan kesiapan (readiness) aplikasi:
+// koneksi database, migrasi dan worker latar belakang. Hasilnya dipakai oleh
+// endpoint /readyz sehingga load balancer atau orchestrator hanya mengirim
+// trafik ke replica yang benar-benar siap.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CheckTimeout adalah batas waktu satu pemeriksaan.
+const CheckTimeout = 2 * time.Second
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check mengembalikan error jika komponen belum atau tidak lagi siap.
+type Check func(ctx context.Context) error
+
+// Report adalah hasil semua pemeriksaan. Checks berisi "ok" atau pesan error
+// per komponen.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Checker menyimpan daftar pemeriksaan yang dijalankan oleh Run.
+type Checker struct {
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// Add mendaftarkan pemeriksaan dengan nama unik; nama yang sama menimpa
+// pemeriksaan sebelumnya.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Run menjalankan semua pemeriksaan secara paralel, masing-masing dengan
+// batas waktu CheckTimeout. Status "ok" hanya jika semua pemeriksaan lolos.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, CheckTimeout)
+			defer cancel()
+
+			result := StatusOK
+			if err := check(checkCtx); err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+// Database memeriksa bahwa database bisa dihubungi.
+func Database(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
@@ -0,0 +1,39 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Run(t *testing.T) {
+	checker := health.NewChecker()
+	assert.Equal(t, health.Report{Status: health.StatusOK, Checks: map[string]string{}}, checker.Run(context.Background()))
+
+	checker.Add("database", func(context.Context) error { return nil })
+	checker.Add("scheduler", func(context.Context) error { return errors.New("scheduler is not running") })
+
+	report := checker.Run(context.Background())
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, map[string]string{
+		"database":  health.StatusOK,
+		"scheduler": "scheduler is not running",
+	}, report.Checks)
+}
+
+func TestChecker_RunTimesOut(t *testing.T) {
+	checker := health.NewChecker()
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := checker.Run(ctx)
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, context.Canceled.Error(), report.Checks["slow"])
+}
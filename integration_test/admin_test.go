@@ -0,0 +1,159 @@
+package integration_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminEvents_InspectAndReplay(t *testing.T) {
+	f := newFixture(t)
+	f.booking(f.customer, f.service.ID, day(2))
+
+	assert.Equal(t, http.StatusForbidden, f.do(http.MethodGet, "/api/v1/admin/events", f.customer.Token, nil).Code)
+
+	var pending listquery.Page[entity.OutboxEventRes]
+	expect(t, f.do(http.MethodGet, "/api/v1/admin/events?status=Pending&sort=id", f.admin.Token, nil), http.StatusOK, &pending)
+	require.NotEmpty(t, pending.Items)
+	created := pending.Items[0]
+	assert.Equal(t, "booking.created", created.Type)
+
+	f.processEvents()
+
+	var evt entity.OutboxEventRes
+	path := fmt.Sprintf("/api/v1/admin/events/%d", created.ID)
+	expect(t, f.do(http.MethodGet, path, f.admin.Token, nil), http.StatusOK, &evt)
+	assert.Equal(t, "Delivered", evt.Status)
+	assert.Equal(t, f.customer.ID, evt.Recipients["customer"])
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, "/api/v1/admin/events/9999", f.admin.Token, nil).Code)
+
+	// Hanya event Failed yang bisa di-replay
+	assert.Equal(t, http.StatusConflict, f.do(http.MethodPost, path+"/replay", f.admin.Token, nil).Code)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodPost, "/api/v1/admin/events/9999/replay", f.admin.Token, nil).Code)
+
+	require.NoError(t, f.db.Model(&entity.OutboxEvent{}).Where("id = ?", created.ID).Updates(map[string]interface{}{"status": "Failed", "last_error": "boom"}).Error)
+	var replayed struct {
+		Event entity.OutboxEventRes `json:"event"`
+	}
+	expect(t, f.do(http.MethodPost, path+"/replay", f.admin.Token, nil), http.StatusOK, &replayed)
+	assert.Equal(t, "Pending", replayed.Event.Status)
+	assert.Zero(t, replayed.Event.Attempts)
+}
+
+// receiver adalah endpoint webhook partner palsu yang mencatat request masuk.
+type receiver struct {
+	mu       sync.Mutex
+	requests []receivedWebhook
+	status   int
+}
+
+type receivedWebhook struct {
+	Header http.Header
+	Body   []byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, receivedWebhook{Header: req.Header.Clone(), Body: body})
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+func TestWebhooks_EndpointLifecycle(t *testing.T) {
+	f := newFixture(t)
+
+	rec := f.do(http.MethodPost, "/api/v1/admin/webhooks", f.admin.Token, entity.CreateWebhookEndpointReq{URL: "ftp://partner.example.com", EventTypes: []string{"booking.created"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = f.do(http.MethodPost, "/api/v1/admin/webhooks", f.admin.Token, entity.CreateWebhookEndpointReq{URL: "https://partner.example.com", EventTypes: []string{"booking.exploded"}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, http.StatusForbidden, f.do(http.MethodGet, "/api/v1/admin/webhooks", f.technician.Token, nil).Code)
+
+	var created struct {
+		Endpoint entity.WebhookEndpointRes `json:"endpoint"`
+	}
+	req := entity.CreateWebhookEndpointReq{URL: "https://partner.example.com/hooks", EventTypes: []string{"booking.created"}, Description: "Partner"}
+	expect(t, f.do(http.MethodPost, "/api/v1/admin/webhooks", f.admin.Token, req), http.StatusCreated, &created)
+	assert.NotEmpty(t, created.Endpoint.Secret)
+	path := fmt.Sprintf("/api/v1/admin/webhooks/%d", created.Endpoint.ID)
+
+	var endpoint entity.WebhookEndpointRes
+	expect(t, f.do(http.MethodGet, path, f.admin.Token, nil), http.StatusOK, &endpoint)
+	assert.Empty(t, endpoint.Secret, "secret is only shown once")
+
+	var updated struct {
+		Endpoint entity.WebhookEndpointRes `json:"endpoint"`
+	}
+	expect(t, f.do(http.MethodPut, path, f.admin.Token, entity.UpdateWebhookEndpointReq{EventTypes: []string{"booking.created", "payment.paid"}}), http.StatusOK, &updated)
+	assert.Equal(t, []string{"booking.created", "payment.paid"}, updated.Endpoint.EventTypes)
+
+	var endpoints listquery.Page[entity.WebhookEndpointRes]
+	expect(t, f.do(http.MethodGet, "/api/v1/admin/webhooks", f.admin.Token, nil), http.StatusOK, &endpoints)
+	assert.Len(t, endpoints.Items, 1)
+	assert.Equal(t, int64(1), endpoints.Total)
+
+	expect(t, f.do(http.MethodDelete, path, f.admin.Token, nil), http.StatusOK, nil)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, path, f.admin.Token, nil).Code)
+}
+
+func TestWebhooks_DeliveryAndRedeliver(t *testing.T) {
+	f := newFixture(t)
+	partner := &receiver{status: http.StatusOK}
+	srv := httptest.NewServer(partner)
+	t.Cleanup(srv.Close)
+
+	var created struct {
+		Endpoint entity.WebhookEndpointRes `json:"endpoint"`
+	}
+	req := entity.CreateWebhookEndpointReq{URL: srv.URL, EventTypes: []string{"booking.created"}}
+	expect(t, f.do(http.MethodPost, "/api/v1/admin/webhooks", f.admin.Token, req), http.StatusCreated, &created)
+	secret := created.Endpoint.Secret
+
+	f.booking(f.customer, f.service.ID, day(2))
+	f.processEvents()
+
+	// Partner menerima event yang ditandatangani dengan secret endpoint
+	requests := partner.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "booking.created", requests[0].Header.Get(webhook.HeaderEvent))
+	timestamp, err := strconv.ParseInt(requests[0].Header.Get(webhook.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, webhook.Verify(secret, timestamp, requests[0].Body, requests[0].Header.Get(webhook.HeaderSignature)))
+
+	var page listquery.Page[entity.WebhookDelivery]
+	path := fmt.Sprintf("/api/v1/admin/webhooks/%d/deliveries", created.Endpoint.ID)
+	expect(t, f.do(http.MethodGet, path, f.admin.Token, nil), http.StatusOK, &page)
+	deliveries := page.Items
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "Succeeded", deliveries[0].Status)
+	assert.Equal(t, http.StatusOK, deliveries[0].ResponseStatus)
+
+	// Redeliver mengirim ulang delivery yang sama dengan ID delivery yang sama
+	rec := f.do(http.MethodPost, fmt.Sprintf("%s/%d/redeliver", path, deliveries[0].ID), f.admin.Token, nil)
+	expect(t, rec, http.StatusAccepted, nil)
+	f.processEvents()
+
+	requests = partner.received()
+	require.Len(t, requests, 2)
+	assert.Equal(t, requests[0].Header.Get(webhook.HeaderDelivery), requests[1].Header.Get(webhook.HeaderDelivery))
+	assert.Equal(t, requests[0].Body, requests[1].Body)
+
+	expectProblem(t, f.do(http.MethodPost, fmt.Sprintf("%s/9999/redeliver", path), f.admin.Token, nil), http.StatusNotFound, "webhook_delivery_not_found")
+}
@@ -0,0 +1,104 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTechnicianApplications_ApproveFlow(t *testing.T) {
+	a := newApp(t)
+	admin := a.admin()
+	user := a.register("Gilang", "gilang@example.com")
+
+	assert.Equal(t, http.StatusNotFound, a.do(http.MethodGet, "/api/v1/technician-applications/me", user.Token, nil).Code)
+
+	application := a.submitApplication(user)
+	assert.Equal(t, "Submitted", application.Status)
+
+	// Pengajuan kedua ditolak selama yang pertama masih diproses
+	form := map[string]string{
+		"address":                  "Jl. Sudirman No. 2",
+		"phone":                    "+62 812-3456-7890",
+		"expertise":                "Kulkas",
+		"availability":             "Sabtu",
+		"certification_expires_at": day(30).Format("2006-01-02"),
+	}
+	documents := map[string]string{"id_document": "ktp.pdf", "certificate": "sertifikat.pdf"}
+	rec := a.doMultipart(http.MethodPost, "/api/v1/technician-applications", user.Token, form, documents)
+	expectProblem(t, rec, http.StatusConflict, "application_in_progress")
+
+	// Field yang kosong atau tidak valid dilaporkan satu per satu
+	rec = a.doMultipart(http.MethodPost, "/api/v1/technician-applications", user.Token, map[string]string{"address": "x", "phone": "12345", "certification_expires_at": day(0).Format("2006-01-02")}, documents)
+	problem := expectProblem(t, rec, http.StatusBadRequest, "validation_failed")
+	rules := map[string]string{}
+	for _, field := range problem.Errors {
+		rules[field.Field] = field.Rule
+	}
+	assert.Equal(t, map[string]string{"phone": "phone_id", "expertise": "required", "availability": "required", "certification_expires_at": "future_date"}, rules)
+
+	var mine entity.TechnicianApplicationRes
+	expect(t, a.do(http.MethodGet, "/api/v1/technician-applications/me", user.Token, nil), http.StatusOK, &mine)
+	assert.Equal(t, application.ID, mine.ID)
+
+	// Endpoint review hanya untuk admin
+	path := fmt.Sprintf("/api/v1/technician-applications/%d", application.ID)
+	assert.Equal(t, http.StatusForbidden, a.do(http.MethodGet, path, user.Token, nil).Code)
+	assert.Equal(t, http.StatusForbidden, a.do(http.MethodGet, "/api/v1/technician-applications", user.Token, nil).Code)
+
+	var applications listquery.Page[entity.TechnicianApplicationRes]
+	expect(t, a.do(http.MethodGet, "/api/v1/technician-applications?status=Submitted", admin.Token, nil), http.StatusOK, &applications)
+	assert.Len(t, applications.Items, 1)
+
+	var fetched entity.TechnicianApplicationRes
+	expect(t, a.do(http.MethodGet, path, admin.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, user.ID, fetched.UserID)
+
+	rec = a.do(http.MethodGet, path+"/documents/id-document", admin.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "%PDF-1.4 id_document", rec.Body.String())
+	rec = a.do(http.MethodGet, path+"/documents/certificate", admin.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusBadRequest, a.do(http.MethodGet, path+"/documents/foto", admin.Token, nil).Code)
+
+	// Tidak bisa langsung disetujui sebelum direview
+	expectProblem(t, a.do(http.MethodPut, path+"/approve", admin.Token, nil), http.StatusConflict, "invalid_status_transition")
+
+	expect(t, a.do(http.MethodPut, path+"/review", admin.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, "Under Review", fetched.Status)
+	expect(t, a.do(http.MethodPut, path+"/approve", admin.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, "Approved", fetched.Status)
+	assert.Equal(t, admin.ID, *fetched.ReviewerID)
+
+	// Role technician baru berlaku pada token yang diambil setelah disetujui
+	assert.Equal(t, http.StatusForbidden, a.do(http.MethodPost, "/api/v1/services", user.Token, entity.CreateServiceReq{Name: "Servis", Cost: 1}).Code)
+	user.Token = a.login(user.Email)
+	a.service(user, "Servis Kulkas", 90000)
+}
+
+func TestTechnicianApplications_Reject(t *testing.T) {
+	a := newApp(t)
+	admin := a.admin()
+	user := a.register("Hana", "hana@example.com")
+	application := a.submitApplication(user)
+	path := fmt.Sprintf("/api/v1/technician-applications/%d", application.ID)
+
+	expect(t, a.do(http.MethodPut, path+"/review", admin.Token, nil), http.StatusOK, nil)
+	assert.Equal(t, http.StatusBadRequest, a.do(http.MethodPut, path+"/reject", admin.Token, entity.ReviewTechnicianApplicationReq{}).Code)
+
+	var rejected entity.TechnicianApplicationRes
+	expect(t, a.do(http.MethodPut, path+"/reject", admin.Token, entity.ReviewTechnicianApplicationReq{Reason: "sertifikat tidak terbaca"}), http.StatusOK, &rejected)
+	assert.Equal(t, "Rejected", rejected.Status)
+	assert.Equal(t, "sertifikat tidak terbaca", rejected.ReviewReason)
+
+	// Technician yang ditolak belum bisa membuat service
+	user.Token = a.login(user.Email)
+	assert.Equal(t, http.StatusForbidden, a.do(http.MethodPost, "/api/v1/services", user.Token, entity.CreateServiceReq{Name: "Servis", Cost: 1}).Code)
+
+	// Setelah ditolak, user boleh mengajukan ulang
+	a.submitApplication(user)
+}
@@ -0,0 +1,168 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookings_CRUD(t *testing.T) {
+	f := newFixture(t)
+
+	// Booking untuk hari ini ditolak, begitu juga tanggal yang sudah terisi
+	rec := f.do(http.MethodPost, "/api/v1/bookings", f.customer.Token, entity.CreateBookingReq{UserID: f.customer.ID, ServiceID: f.service.ID, Date: day(0)})
+	problem := expectProblem(t, rec, http.StatusBadRequest, "validation_failed")
+	assert.Equal(t, []apperror.FieldError{{Field: "date", Rule: "future_date", Message: "date must be a date after today"}}, problem.Errors)
+
+	booking := f.booking(f.customer, f.service.ID, day(3))
+	assert.Equal(t, "Pending", booking.Status)
+
+	rec = f.do(http.MethodPost, "/api/v1/bookings", f.customer.Token, entity.CreateBookingReq{UserID: f.customer.ID, ServiceID: f.service.ID, Date: day(3)})
+	expectProblem(t, rec, http.StatusConflict, "service_already_booked")
+
+	var fetched entity.Booking
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/bookings/%d", booking.ID), f.customer.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, f.service.ID, fetched.ServiceID)
+	assert.True(t, day(3).Equal(fetched.Date), "date %s", fetched.Date)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, "/api/v1/bookings/9999", f.customer.Token, nil).Code)
+
+	var updated entity.Booking
+	req := entity.UpdateBookingReq{ID: booking.ID, UserID: f.customer.ID, ServiceID: f.service.ID, Date: day(4), Status: "Pending", Description: "Sore saja"}
+	expect(t, f.do(http.MethodPut, "/api/v1/bookings", f.customer.Token, req), http.StatusOK, &updated)
+	assert.Equal(t, "Sore saja", updated.Description)
+
+	var bookings listquery.Page[entity.Booking]
+	expect(t, f.do(http.MethodGet, "/api/v1/bookings", f.admin.Token, nil), http.StatusOK, &bookings)
+	assert.Len(t, bookings.Items, 1)
+
+	var byUser, byService listquery.Page[entity.BookingRes]
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/bookings/user/%d", f.customer.ID), f.customer.Token, nil), http.StatusOK, &byUser)
+	assert.Len(t, byUser.Items, 1)
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/bookings/service/%d", f.service.ID), f.technician.Token, nil), http.StatusOK, &byService)
+	assert.Len(t, byService.Items, 1)
+
+	expect(t, f.do(http.MethodDelete, fmt.Sprintf("/api/v1/bookings/%d", booking.ID), f.customer.Token, nil), http.StatusOK, nil)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, fmt.Sprintf("/api/v1/bookings/%d", booking.ID), f.customer.Token, nil).Code)
+}
+
+func TestBookings_StatusAndTechnicianView(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	path := fmt.Sprintf("/api/v1/bookings/%d/status", booking.ID)
+
+	expectProblem(t, f.do(http.MethodPut, path, f.technician.Token, map[string]string{"status": "Dibatalkan"}), http.StatusBadRequest, "invalid_booking_status")
+	expect(t, f.do(http.MethodPut, path, f.technician.Token, map[string]string{"status": "Confirmed"}), http.StatusOK, nil)
+
+	var confirmed listquery.Page[entity.BookingRes]
+	expect(t, f.do(http.MethodGet, "/api/v1/bookings/technician/confirmed", f.technician.Token, nil), http.StatusOK, &confirmed)
+	require.Len(t, confirmed.Items, 1)
+	assert.Equal(t, booking.ID, confirmed.Items[0].ID)
+	assert.Equal(t, http.StatusForbidden, f.do(http.MethodGet, "/api/v1/bookings/technician/confirmed", f.customer.Token, nil).Code)
+
+	// Perubahan status tercatat sebagai event di outbox
+	var types []string
+	require.NoError(t, f.db.Table("outbox_events").Order("id").Pluck("type", &types).Error)
+	assert.Contains(t, types, "booking.created")
+	assert.Contains(t, types, "booking.confirmed")
+}
+
+func TestBookings_AvailableDates(t *testing.T) {
+	f := newFixture(t)
+	booked := day(2)
+	f.booking(f.customer, f.service.ID, booked)
+
+	var res struct {
+		ServiceID      int      `json:"service_id"`
+		AvailableDates []string `json:"available_dates"`
+	}
+	path := fmt.Sprintf("/api/v1/bookings/available-dates?service_id=%d&year=%d&month=%d", f.service.ID, booked.Year(), booked.Month())
+	expect(t, f.do(http.MethodGet, path, f.customer.Token, nil), http.StatusOK, &res)
+	assert.Equal(t, f.service.ID, res.ServiceID)
+	assert.NotContains(t, res.AvailableDates, booked.Format("2006-01-02"))
+	assert.NotContains(t, res.AvailableDates, day(0).Format("2006-01-02"))
+	if next := day(3); next.Month() == booked.Month() {
+		assert.Contains(t, res.AvailableDates, next.Format("2006-01-02"))
+	}
+
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/api/v1/bookings/available-dates?service_id=1&year=2030&month=13", f.customer.Token, nil).Code)
+}
+
+func TestBookings_Report(t *testing.T) {
+	f := newFixture(t)
+	paid := f.booking(f.customer, f.service.ID, day(2))
+	f.booking(f.customer, f.service.ID, day(5))
+	payment := f.payment(f.customer.Token, paid.ID, "75000")
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/payments/%d/status", payment.ID), f.admin.Token, map[string]string{"status": "Paid"}), http.StatusOK, nil)
+
+	var report entity.BookingReport
+	expect(t, f.do(http.MethodGet, "/api/v1/bookings/reports", f.admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 2, report.TotalBooking)
+	assert.Equal(t, float64(75000), report.TotalRevenue)
+
+	// Rentang tanggal hanya mencakup booking pertama
+	query := fmt.Sprintf("/api/v1/bookings/reports?start_date=%s&end_date=%s", day(1).Format("2006-01-02"), day(3).Format("2006-01-02"))
+	expect(t, f.do(http.MethodGet, query, f.admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 1, report.TotalBooking)
+
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/api/v1/bookings/reports?start_date=kemarin", f.admin.Token, nil).Code)
+}
+
+func TestBookings_ListQuery(t *testing.T) {
+	f := newFixture(t)
+	other := f.app.service(f.technician, "Servis Kulkas", 150000)
+	b1 := f.booking(f.customer, f.service.ID, day(4))
+	b2 := f.booking(f.customer, other.ID, day(2))
+	b3 := f.booking(f.customer, f.service.ID, day(3))
+
+	list := func(query string) listquery.Page[entity.BookingRes] {
+		var page listquery.Page[entity.BookingRes]
+		expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/bookings/user/%d?%s", f.customer.ID, query), f.customer.Token, nil), http.StatusOK, &page)
+		return page
+	}
+	ids := func(page listquery.Page[entity.BookingRes]) []int {
+		result := []int{}
+		for _, b := range page.Items {
+			result = append(result, b.ID)
+		}
+		return result
+	}
+
+	// Halaman pertama berisi total dan cursor ke halaman berikutnya
+	first := list("limit=2&sort=date")
+	assert.Equal(t, []int{b2.ID, b3.ID}, ids(first))
+	assert.Equal(t, int64(3), first.Total)
+	require.NotNil(t, first.NextCursor)
+	last := list("limit=2&sort=date&cursor=" + *first.NextCursor)
+	assert.Equal(t, []int{b1.ID}, ids(last))
+	assert.Equal(t, int64(3), last.Total)
+	assert.Nil(t, last.NextCursor)
+
+	assert.Equal(t, []int{b1.ID, b3.ID, b2.ID}, ids(list("sort=-date")))
+	assert.Equal(t, []int{b1.ID, b3.ID}, ids(list(fmt.Sprintf("service_id=%d", f.service.ID))))
+	assert.Equal(t, []int{b1.ID, b3.ID}, ids(list("date_from="+day(3).Format(listquery.DateLayout))))
+	assert.Equal(t, []int{b2.ID, b3.ID}, ids(list("date_to="+day(3).Format(listquery.DateLayout))))
+	assert.Empty(t, list("status=Confirmed").Items)
+	assert.Len(t, list("limit=1000").Items, 3)
+
+	rules := func(query string) []string {
+		rec := f.do(http.MethodGet, "/api/v1/bookings?"+query, f.admin.Token, nil)
+		problem := expectProblem(t, rec, http.StatusBadRequest, "validation_failed")
+		var result []string
+		for _, field := range problem.Errors {
+			result = append(result, field.Field+":"+field.Rule)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"limit:min"}, rules("limit=0"))
+	assert.Equal(t, []string{"offset:unsupported"}, rules("offset=10"))
+	assert.Equal(t, []string{"sort:oneof"}, rules("sort=password"))
+	assert.Equal(t, []string{"cursor:cursor"}, rules("cursor=abc"))
+	assert.Equal(t, []string{"cursor:cursor"}, rules("sort=-date&cursor="+*first.NextCursor))
+	assert.Equal(t, []string{"date_from:type"}, rules("date_from=besok"))
+}
@@ -0,0 +1,94 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestEvents_StreamDeliversOwnEvents(t *testing.T) {
+	f := newFixture(t)
+	srv := f.serve()
+	events := f.openStream(srv, "/api/v1/events/stream", f.customer)
+
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	f.processEvents()
+
+	evt := nextEvent(t, events)
+	assert.Equal(t, "booking.created", evt.Type)
+	var created entity.BookingRes
+	require.NoError(t, json.Unmarshal([]byte(evt.Data), &created))
+	assert.Equal(t, booking.ID, created.ID)
+
+	payment := f.payment(f.customer.Token, booking.ID, "75000")
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/payments/%d/status", payment.ID), f.admin.Token, map[string]string{"status": "Paid"}), http.StatusOK, nil)
+	f.processEvents()
+
+	var types []string
+	for i := 0; i < 3; i++ {
+		types = append(types, nextEvent(t, events).Type)
+	}
+	assert.ElementsMatch(t, []string{"payment.created", "payment.paid", "booking.confirmed"}, types)
+}
+
+// EventSource tidak bisa mengirim header, jadi route streaming menerima
+// token lewat query parameter access_token.
+func TestEvents_StreamAcceptsQueryToken(t *testing.T) {
+	f := newFixture(t)
+	srv := f.serve()
+
+	for _, path := range []string{"/api/v1/events/stream", fmt.Sprintf("/api/v1/bookings/%d/messages/stream", f.booking(f.customer, f.service.ID, day(2)).ID)} {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path+"?access_token="+f.customer.Token, nil)
+		require.NoError(t, err)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode, path)
+		assert.Contains(t, res.Header.Get("Content-Type"), "text/event-stream", path)
+		cancel()
+		res.Body.Close()
+	}
+}
+
+func TestEvents_WebSocket(t *testing.T) {
+	f := newFixture(t)
+	srv := f.serve()
+
+	// Tanpa token handshake ditolak oleh JWTAuth
+	_, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/events/ws", "", srv.URL)
+	require.Error(t, err)
+
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/events/ws", srv.URL)
+	require.NoError(t, err)
+	config.Header.Set("Authorization", "Bearer "+f.technician.Token)
+	ws, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return f.hub.Subscribers(f.technician.ID) > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	f.processEvents()
+
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var evt realtime.Event
+	require.NoError(t, websocket.JSON.Receive(ws, &evt))
+	assert.Equal(t, "booking.created", evt.Type)
+	assert.EqualValues(t, booking.ID, evt.Data.(map[string]interface{})["id"])
+
+	// Handler selesai (dan route tercatat) setelah client menutup koneksi
+	require.NoError(t, ws.Close())
+	require.Eventually(t, func() bool {
+		_, ok := coveredRoutes.Load("GET /api/v1/events/ws")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Zero(t, f.hub.Subscribers(f.technician.ID))
+}
@@ -0,0 +1,435 @@
+package integration_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/health"
+	"github.com/Ayyasy123/dibimbing-capstone.git/metrics"
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/Ayyasy123/dibimbing-capstone.git/migrate"
+	"github.com/Ayyasy123/dibimbing-capstone.git/outbox"
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/routes"
+	"github.com/Ayyasy123/dibimbing-capstone.git/scheduler"
+	"github.com/Ayyasy123/dibimbing-capstone.git/tracing"
+	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
+	"github.com/Ayyasy123/dibimbing-capstone.git/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testSecret   = "integration-test-secret-0123456789"
+	testPassword = "password123"
+)
+
+// coveredRoutes mencatat route ("GET /bookings/:id") yang handler-nya
+// benar-benar dijalankan, yaitu tidak dihentikan oleh middleware auth.
+var coveredRoutes sync.Map
+
+// spans menampung semua span yang selesai selama test. Tracer provider
+// global hanya bisa dipasang sekali, jadi test memfilter span per trace ID.
+var spans = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	flag.Parse()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	code := m.Run()
+
+	// Cek cakupan route hanya jika seluruh test dijalankan
+	if code == 0 && flag.Lookup("test.run").Value.String() == "" && flag.Lookup("test.skip").Value.String() == "" {
+		if missing := uncoveredRoutes(); len(missing) > 0 {
+			fmt.Fprintf(os.Stderr, "routes without an integration test:\n  %s\n", strings.Join(missing, "\n  "))
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+func uncoveredRoutes() []string {
+	router := gin.New()
+	routes.SetupRoutes(router, repository.Storage{}, realtime.NewHub(), outbox.NewDispatcher(nil), scheduler.New(nil), health.NewChecker(), routes.Options{})
+
+	var missing []string
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Path
+		if _, ok := coveredRoutes.Load(key); !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func recordRoute(c *gin.Context) {
+	c.Next()
+	if c.FullPath() != "" && !c.IsAborted() {
+		coveredRoutes.Store(c.Request.Method+" "+c.FullPath(), true)
+	}
+}
+
+// app adalah satu instance API lengkap dengan database SQLite sendiri.
+type app struct {
+	t          *testing.T
+	db         *gorm.DB
+	router     *gin.Engine
+	hub        *realtime.Hub
+	dispatcher *outbox.Dispatcher
+	scheduler  *scheduler.Scheduler
+}
+
+// metricsToken adalah bearer token /metrics untuk app dari newApp.
+const metricsToken = "test-metrics-token"
+
+// newApp menyiapkan aplikasi tanpa rate limit agar test bebas memanggil
+// /login berulang kali.
+func newApp(t *testing.T) *app {
+	t.Helper()
+	return newAppWithOptions(t, routes.Options{MetricsToken: metricsToken})
+}
+
+func newAppWithLimits(t *testing.T, limits routes.RateLimits) *app {
+	t.Helper()
+	return newAppWithOptions(t, routes.Options{RateLimits: limits})
+}
+
+func newAppWithOptions(t *testing.T, opts routes.Options) *app {
+	t.Helper()
+
+	db, err := config.OpenDatabase(config.DatabaseConfig{
+		Driver: config.DriverSQLite,
+		Name:   filepath.Join(t.TempDir(), "test.db"),
+	}, logger.Discard)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := migrate.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	utils.ConfigureJWT(testSecret, time.Hour)
+	utils.SetUploadDir(t.TempDir())
+	require.NoError(t, validation.Setup())
+
+	storage := repository.NewStorage(db)
+	a := &app{
+		t:          t,
+		db:         db,
+		router:     gin.New(),
+		hub:        realtime.NewHub(),
+		dispatcher: outbox.NewDispatcher(storage.Outbox),
+		scheduler:  scheduler.New(storage.Jobs),
+	}
+	a.dispatcher.Broadcast(a.hub.HandleEvent)
+	require.NoError(t, metrics.InstrumentDB(db, "test"))
+	require.NoError(t, tracing.InstrumentDB(db))
+	a.router.Use(recordRoute, middleware.Tracing("capstone-test"), middleware.Metrics(), middleware.ErrorHandler())
+
+	// Dispatcher dan scheduler tidak dijalankan (lihat processEvents), jadi
+	// /readyz baru siap setelah test memanggil Start
+	checks := health.NewChecker()
+	checks.Add("database", health.Database(db))
+	checks.Add("migrations", migrator.Check)
+	checks.Add("outbox_dispatcher", a.dispatcher.Check)
+	checks.Add("scheduler", a.scheduler.Check)
+	routes.SetupRoutes(a.router, storage, a.hub, a.dispatcher, a.scheduler, checks, opts)
+	return a
+}
+
+// processEvents mengirim event outbox dan menjalankan job yang jatuh tempo,
+// menggantikan goroutine dispatcher dan scheduler pada server sungguhan.
+func (a *app) processEvents() {
+	ctx := context.Background()
+	a.dispatcher.Tick(ctx)
+	a.scheduler.Tick(ctx)
+}
+
+func (a *app) request(method, path, token string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	a.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// do mengirim request JSON; body nil berarti tanpa body.
+func (a *app) do(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	a.t.Helper()
+	if body == nil {
+		return a.request(method, path, token, nil, "")
+	}
+
+	raw, err := json.Marshal(body)
+	require.NoError(a.t, err)
+	return a.request(method, path, token, bytes.NewReader(raw), "application/json")
+}
+
+// doMultipart mengirim form multipart; files berisi nama field dan nama file.
+func (a *app) doMultipart(method, path, token string, fields, files map[string]string) *httptest.ResponseRecorder {
+	a.t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		require.NoError(a.t, writer.WriteField(name, value))
+	}
+	for field, fileName := range files {
+		part, err := writer.CreateFormFile(field, fileName)
+		require.NoError(a.t, err)
+		_, err = part.Write([]byte("%PDF-1.4 " + field))
+		require.NoError(a.t, err)
+	}
+	require.NoError(a.t, writer.Close())
+
+	return a.request(method, path, token, &buf, writer.FormDataContentType())
+}
+
+// serve menjalankan router pada server HTTP sungguhan, dibutuhkan oleh
+// endpoint streaming (SSE dan WebSocket).
+func (a *app) serve() *httptest.Server {
+	srv := httptest.NewServer(a.router)
+	a.t.Cleanup(srv.Close)
+	return srv
+}
+
+type sseEvent struct {
+	Type string
+	Data string
+}
+
+// openStream membuka koneksi SSE dan menunggu sampai koneksi terdaftar di hub
+// sehingga event berikutnya pasti diterima. Koneksi ditutup saat test selesai.
+func (a *app) openStream(srv *httptest.Server, path string, user account) <-chan sseEvent {
+	a.t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+path, nil)
+	require.NoError(a.t, err)
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+
+	before := a.hub.Subscribers(user.ID)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(a.t, err)
+	require.Equal(a.t, http.StatusOK, res.StatusCode)
+	require.Eventually(a.t, func() bool { return a.hub.Subscribers(user.ID) > before }, 5*time.Second, 10*time.Millisecond)
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer res.Body.Close()
+		defer close(events)
+
+		var evt sseEvent
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event:"):
+				evt.Type = strings.TrimPrefix(line, "event:")
+			case strings.HasPrefix(line, "data:"):
+				evt.Data = strings.TrimPrefix(line, "data:")
+			case line == "" && evt.Type != "":
+				events <- evt
+				evt = sseEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+// nextEvent menunggu event berikutnya selain heartbeat.
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case evt, ok := <-events:
+			require.True(t, ok, "stream closed")
+			if evt.Type != "ping" {
+				return evt
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for event")
+		}
+	}
+}
+
+// expect memastikan status response dan men-decode body JSON ke out (jika tidak nil).
+func expect(t *testing.T, rec *httptest.ResponseRecorder, status int, out interface{}) {
+	t.Helper()
+	require.Equal(t, status, rec.Code, rec.Body.String())
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out), rec.Body.String())
+	}
+}
+
+// expectProblem memastikan response berupa problem+json dengan status dan
+// kode error yang diharapkan, lalu mengembalikan body-nya.
+func expectProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) apperror.Problem {
+	t.Helper()
+	var problem apperror.Problem
+	expect(t, rec, status, &problem)
+	require.Equal(t, apperror.ProblemContentType, rec.Header().Get("Content-Type"))
+	require.Equal(t, status, problem.Status)
+	require.Equal(t, code, problem.Code, rec.Body.String())
+	return problem
+}
+
+type account struct {
+	ID    int
+	Email string
+	Token string
+}
+
+// login mengambil JWT asli melalui endpoint /login.
+func (a *app) login(email string) string {
+	a.t.Helper()
+
+	var res struct {
+		Token string `json:"token"`
+	}
+	expect(a.t, a.do(http.MethodPost, "/api/v1/login", "", entity.LoginUserReq{Email: email, Password: testPassword}), http.StatusOK, &res)
+	require.NotEmpty(a.t, res.Token)
+	return res.Token
+}
+
+func (a *app) register(name, email string) account {
+	a.t.Helper()
+
+	var user entity.UserRes
+	expect(a.t, a.do(http.MethodPost, "/api/v1/register", "", entity.RegisterUserReq{Name: name, Email: email, Password: testPassword}), http.StatusCreated, &user)
+	return account{ID: user.ID, Email: email, Token: a.login(email)}
+}
+
+func (a *app) admin() account {
+	a.t.Helper()
+
+	var user entity.UserRes
+	expect(a.t, a.do(http.MethodPost, "/api/v1/register-admin", "", entity.RegisterUserReq{Name: "Admin", Email: "admin@example.com", Password: testPassword}), http.StatusCreated, &user)
+	return account{ID: user.ID, Email: user.Email, Token: a.login(user.Email)}
+}
+
+// submitApplication mengirim pengajuan technician dengan dokumen PDF.
+func (a *app) submitApplication(user account) entity.TechnicianApplicationRes {
+	a.t.Helper()
+
+	var application entity.TechnicianApplicationRes
+	rec := a.doMultipart(http.MethodPost, "/api/v1/technician-applications", user.Token, map[string]string{
+		"address":                  "Jl. Merdeka No. 1",
+		"phone":                    "08123456789",
+		"expertise":                "AC",
+		"availability":             "Senin - Jumat",
+		"certification_expires_at": time.Now().AddDate(1, 0, 0).Format("2006-01-02"),
+	}, map[string]string{"id_document": "ktp.pdf", "certificate": "sertifikat.pdf"})
+	expect(a.t, rec, http.StatusCreated, &application)
+	return application
+}
+
+// technician mendaftarkan user lalu menyetujui pengajuannya lewat API admin.
+// Token diambil ulang setelah disetujui karena role tersimpan di JWT.
+func (a *app) technician(admin account, name, email string) account {
+	a.t.Helper()
+
+	user := a.register(name, email)
+	application := a.submitApplication(user)
+	expect(a.t, a.do(http.MethodPut, fmt.Sprintf("/api/v1/technician-applications/%d/review", application.ID), admin.Token, nil), http.StatusOK, nil)
+	expect(a.t, a.do(http.MethodPut, fmt.Sprintf("/api/v1/technician-applications/%d/approve", application.ID), admin.Token, entity.ReviewTechnicianApplicationReq{Reason: "lengkap"}), http.StatusOK, nil)
+
+	user.Token = a.login(email)
+	return user
+}
+
+func (a *app) service(technician account, name string, cost int) entity.ServiceRes {
+	a.t.Helper()
+
+	var service entity.ServiceRes
+	rec := a.do(http.MethodPost, "/api/v1/services", technician.Token, entity.CreateServiceReq{Name: name, Description: name + " profesional", Cost: cost})
+	expect(a.t, rec, http.StatusCreated, &service)
+	return service
+}
+
+func (a *app) booking(customer account, serviceID int, date time.Time) entity.Booking {
+	a.t.Helper()
+
+	var booking entity.Booking
+	rec := a.do(http.MethodPost, "/api/v1/bookings", customer.Token, entity.CreateBookingReq{UserID: customer.ID, ServiceID: serviceID, Date: date, Description: "Tolong datang pagi"})
+	expect(a.t, rec, http.StatusCreated, &booking)
+	return booking
+}
+
+func (a *app) payment(token string, bookingID int, amount string) entity.Payment {
+	a.t.Helper()
+
+	var payment entity.Payment
+	expect(a.t, a.do(http.MethodPost, "/api/v1/payments", token, entity.CreatePaymentReq{BookingID: bookingID, Amount: amount, Status: "Pending"}), http.StatusCreated, &payment)
+	return payment
+}
+
+// fixture adalah marketplace kecil: admin, satu technician dengan satu service
+// dan satu customer.
+type fixture struct {
+	*app
+	admin      account
+	technician account
+	customer   account
+	service    entity.ServiceRes
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	a := newApp(t)
+	f := &fixture{app: a, admin: a.admin()}
+	f.technician = a.technician(f.admin, "Budi Teknisi", "budi@example.com")
+	f.customer = a.register("Citra Customer", "citra@example.com")
+	f.service = a.service(f.technician, "Cuci AC", 75000)
+	return f
+}
+
+// day mengembalikan tanggal (UTC, tengah malam) n hari dari sekarang.
+func day(n int) time.Time {
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, n)
+}
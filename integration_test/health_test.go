@@ -0,0 +1,47 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_LivenessAndReadiness(t *testing.T) {
+	a := newApp(t)
+
+	var live map[string]string
+	expect(t, a.do(http.MethodGet, "/healthz", "", nil), http.StatusOK, &live)
+	assert.Equal(t, health.StatusOK, live["status"])
+
+	// Worker latar belakang belum berjalan
+	var report health.Report
+	expect(t, a.do(http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable, &report)
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, map[string]string{
+		"database":          health.StatusOK,
+		"migrations":        health.StatusOK,
+		"outbox_dispatcher": "outbox dispatcher is not running",
+		"scheduler":         "scheduler is not running",
+	}, report.Checks)
+
+	require.NoError(t, a.dispatcher.Start())
+	a.scheduler.Start()
+	expect(t, a.do(http.MethodGet, "/readyz", "", nil), http.StatusOK, &report)
+	assert.Equal(t, health.StatusOK, report.Status)
+
+	// Database yang tidak bisa dihubungi membuat replica tidak siap, tetapi
+	// liveness tetap ok agar proses tidak di-restart
+	a.scheduler.Stop()
+	a.dispatcher.Stop()
+	sqlDB, err := a.db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	report = health.Report{}
+	expect(t, a.do(http.MethodGet, "/readyz", "", nil), http.StatusServiceUnavailable, &report)
+	assert.Contains(t, report.Checks["database"], "database is closed")
+	expect(t, a.do(http.MethodGet, "/healthz", "", nil), http.StatusOK, nil)
+}
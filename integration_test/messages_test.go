@@ -0,0 +1,108 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessages_ConversationBetweenParticipants(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	path := fmt.Sprintf("/api/v1/bookings/%d/messages", booking.ID)
+
+	// User lain tidak boleh membaca atau mengirim pesan pada booking ini
+	stranger := f.register("Intan", "intan@example.com")
+	assert.Equal(t, http.StatusForbidden, f.do(http.MethodGet, path, stranger.Token, nil).Code)
+	assert.Equal(t, http.StatusForbidden, f.do(http.MethodPost, path, stranger.Token, entity.CreateMessageReq{Body: "Halo"}).Code)
+
+	var first, second entity.MessageRes
+	expect(t, f.do(http.MethodPost, path, f.customer.Token, entity.CreateMessageReq{Body: "Bisa datang jam 9?"}), http.StatusCreated, &first)
+	expect(t, f.do(http.MethodPost, path, f.technician.Token, entity.CreateMessageReq{Body: "Bisa"}), http.StatusCreated, &second)
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodPost, path, f.customer.Token, entity.CreateMessageReq{}).Code)
+
+	var page listquery.Page[entity.MessageRes]
+	expect(t, f.do(http.MethodGet, path+"?limit=1", f.customer.Token, nil), http.StatusOK, &page)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, second.ID, page.Items[0].ID)
+	assert.Equal(t, int64(2), page.Total)
+	require.NotNil(t, page.NextCursor)
+
+	var older listquery.Page[entity.MessageRes]
+	expect(t, f.do(http.MethodGet, path+"?limit=1&cursor="+*page.NextCursor, f.customer.Token, nil), http.StatusOK, &older)
+	require.Len(t, older.Items, 1)
+	assert.Equal(t, first.ID, older.Items[0].ID)
+	assert.Nil(t, older.NextCursor)
+
+	var unread []entity.UnreadCount
+	expect(t, f.do(http.MethodGet, "/api/v1/messages/unread", f.technician.Token, nil), http.StatusOK, &unread)
+	require.Len(t, unread, 1)
+	assert.Equal(t, entity.UnreadCount{BookingID: booking.ID, UnreadCount: 1}, unread[0])
+
+	var read struct {
+		MarkedAsRead int `json:"marked_as_read"`
+	}
+	expect(t, f.do(http.MethodPut, path+"/read", f.technician.Token, nil), http.StatusOK, &read)
+	assert.Equal(t, 1, read.MarkedAsRead)
+	expect(t, f.do(http.MethodGet, "/api/v1/messages/unread", f.technician.Token, nil), http.StatusOK, &unread)
+	assert.Empty(t, unread)
+
+	// Admin boleh membaca semua percakapan, tetapi tidak menandai pesan
+	// peserta sebagai sudah dibaca
+	expect(t, f.do(http.MethodGet, path, f.admin.Token, nil), http.StatusOK, &page)
+	assert.Len(t, page.Items, 2)
+	expect(t, f.do(http.MethodPut, path+"/read", f.admin.Token, nil), http.StatusOK, &read)
+	assert.Equal(t, 0, read.MarkedAsRead)
+	expect(t, f.do(http.MethodGet, "/api/v1/messages/unread", f.customer.Token, nil), http.StatusOK, &unread)
+	assert.Equal(t, []entity.UnreadCount{{BookingID: booking.ID, UnreadCount: 1}}, unread)
+}
+
+func TestMessages_Attachment(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	path := fmt.Sprintf("/api/v1/bookings/%d/messages", booking.ID)
+
+	var message entity.MessageRes
+	rec := f.doMultipart(http.MethodPost, path, f.customer.Token, map[string]string{"body": "Foto unit"}, map[string]string{"attachment": "unit.pdf"})
+	expect(t, rec, http.StatusCreated, &message)
+	assert.True(t, message.HasAttachment)
+
+	rec = f.do(http.MethodGet, fmt.Sprintf("%s/%d/attachment", path, message.ID), f.technician.Token, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "%PDF-1.4 attachment", rec.Body.String())
+
+	var plain entity.MessageRes
+	expect(t, f.do(http.MethodPost, path, f.technician.Token, entity.CreateMessageReq{Body: "Oke"}), http.StatusCreated, &plain)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, fmt.Sprintf("%s/%d/attachment", path, plain.ID), f.customer.Token, nil).Code)
+}
+
+func TestMessages_Stream(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	other := f.booking(f.customer, f.service.ID, day(3))
+	srv := f.serve()
+
+	events := f.openStream(srv, fmt.Sprintf("/api/v1/bookings/%d/messages/stream", booking.ID), f.technician)
+
+	// Pesan dari booking lain tidak ikut dikirim ke stream ini
+	expect(t, f.do(http.MethodPost, fmt.Sprintf("/api/v1/bookings/%d/messages", other.ID), f.customer.Token, entity.CreateMessageReq{Body: "Booking lain"}), http.StatusCreated, nil)
+	expect(t, f.do(http.MethodPost, fmt.Sprintf("/api/v1/bookings/%d/messages", booking.ID), f.customer.Token, entity.CreateMessageReq{Body: "Sudah di jalan?"}), http.StatusCreated, nil)
+	f.processEvents()
+
+	evt := nextEvent(t, events)
+	assert.Equal(t, "message.created", evt.Type)
+	var message entity.MessageRes
+	require.NoError(t, json.Unmarshal([]byte(evt.Data), &message))
+	assert.Equal(t, booking.ID, message.BookingID)
+	assert.Equal(t, "Sudah di jalan?", message.Body)
+
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/bookings/%d/messages/read", booking.ID), f.technician.Token, nil), http.StatusOK, nil)
+	f.processEvents()
+	assert.Equal(t, "message.read", nextEvent(t, events).Type)
+}
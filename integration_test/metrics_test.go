@@ -0,0 +1,64 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/metrics"
+	"github.com/Ayyasy123/dibimbing-capstone.git/routes"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_BusinessAndTechnical(t *testing.T) {
+	f := newFixture(t)
+
+	// Counter bersifat global untuk seluruh package, jadi yang diperiksa selisihnya
+	created := testutil.ToFloat64(metrics.BookingsCreated.WithLabelValues("Pending"))
+	confirmed := testutil.ToFloat64(metrics.BookingStatusChanges.WithLabelValues("Confirmed"))
+	paid := testutil.ToFloat64(metrics.PaymentStatusChanges.WithLabelValues("Paid"))
+	paidAmount := testutil.ToFloat64(metrics.PaymentAmount.WithLabelValues("Paid"))
+	fiveStars := testutil.ToFloat64(metrics.ReviewsCreated.WithLabelValues("5"))
+
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	payment := f.payment(f.customer.Token, booking.ID, "75000")
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/payments/%d/status", payment.ID), f.admin.Token, map[string]string{"status": "Paid"}), http.StatusOK, nil)
+	expect(t, f.do(http.MethodPost, "/api/v1/reviews", f.customer.Token, entity.CreateReviewReq{BookingID: booking.ID, Rating: 5, Comment: "Mantap"}), http.StatusCreated, nil)
+
+	assert.Equal(t, created+1, testutil.ToFloat64(metrics.BookingsCreated.WithLabelValues("Pending")))
+	assert.Equal(t, confirmed+1, testutil.ToFloat64(metrics.BookingStatusChanges.WithLabelValues("Confirmed")))
+	assert.Equal(t, paid+1, testutil.ToFloat64(metrics.PaymentStatusChanges.WithLabelValues("Paid")))
+	assert.Equal(t, paidAmount+75000, testutil.ToFloat64(metrics.PaymentAmount.WithLabelValues("Paid")))
+	assert.Equal(t, fiveStars+1, testutil.ToFloat64(metrics.ReviewsCreated.WithLabelValues("5")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ActiveTechnicians))
+
+	// Status yang ditolak tidak dihitung
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodPut, fmt.Sprintf("/api/v1/payments/%d/status", payment.ID), f.admin.Token, map[string]string{"status": "Lunas"}).Code)
+	assert.Equal(t, paid+1, testutil.ToFloat64(metrics.PaymentStatusChanges.WithLabelValues("Paid")))
+
+	expectProblem(t, f.do(http.MethodGet, "/metrics", "", nil), http.StatusUnauthorized, "invalid_token")
+	expectProblem(t, f.do(http.MethodGet, "/metrics", f.admin.Token, nil), http.StatusUnauthorized, "invalid_token")
+
+	rec := f.do(http.MethodGet, "/metrics", metricsToken, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	for _, metric := range []string{
+		`capstone_http_request_duration_seconds_count{method="POST",route="/api/v1/bookings",status="201"}`,
+		`capstone_db_query_duration_seconds_bucket{operation="create",table="bookings"`,
+		`capstone_bookings_created_total{status="Pending"}`,
+		`capstone_payment_amount_rupiah_total{status="Paid"}`,
+		`capstone_reviews_created_total{rating="5"}`,
+		`capstone_technicians_active 1`,
+		`go_sql_open_connections{db_name="test"}`,
+	} {
+		assert.Contains(t, body, metric)
+	}
+}
+
+func TestMetrics_DisabledWithoutToken(t *testing.T) {
+	a := newAppWithOptions(t, routes.Options{})
+	expectProblem(t, a.do(http.MethodGet, "/metrics", "", nil), http.StatusNotFound, "route_not_found")
+}
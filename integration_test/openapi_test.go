@@ -0,0 +1,154 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/openapi"
+	"github.com/Ayyasy123/dibimbing-capstone.git/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// servedDocument mengambil dokumen dari /openapi.json.
+func (a *app) servedDocument() openapi.Document {
+	a.t.Helper()
+
+	var doc openapi.Document
+	expect(a.t, a.do(http.MethodGet, routes.OpenAPIPath, "", nil), http.StatusOK, &doc)
+	return doc
+}
+
+// concretePath mengisi parameter path dengan nilai yang tidak ada di
+// database, sehingga handler yang lolos auth tidak mengubah data.
+func concretePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "{document}":
+			segments[i] = "certificate"
+		case strings.HasPrefix(segment, "{"):
+			segments[i] = "999"
+		}
+	}
+	return routes.V1Prefix + strings.Join(segments, "/")
+}
+
+func problemCode(body []byte) string {
+	var problem apperror.Problem
+	if json.Unmarshal(body, &problem) != nil {
+		return ""
+	}
+	return problem.Code
+}
+
+// streaming melaporkan apakah operasi berupa SSE atau WebSocket, yang
+// koneksinya tetap terbuka dan tidak bisa diuji dengan ResponseRecorder.
+func streaming(op *openapi.Operation) bool {
+	if _, ok := op.Responses["101"]; ok {
+		return true
+	}
+	success, ok := op.Responses["200"]
+	if !ok {
+		return false
+	}
+	_, ok = success.Content["text/event-stream"]
+	return ok
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	a := newApp(t)
+	doc := a.servedDocument()
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Equal(t, []openapi.Server{{URL: routes.V1Prefix}}, doc.Servers)
+
+	var registered []string
+	for _, route := range a.router.Routes() {
+		if strings.HasPrefix(route.Path, routes.V1Prefix+"/") {
+			registered = append(registered, route.Method+" "+strings.TrimPrefix(route.Path, routes.V1Prefix))
+		}
+	}
+	var documented []string
+	for _, op := range doc.Operations() {
+		method, path, _ := strings.Cut(op, " ")
+		documented = append(documented, method+" "+openapi.GinPath(path))
+	}
+	assert.ElementsMatch(t, registered, documented, "routes.APIDocs harus sama dengan route di SetupV1Routes")
+
+	// Setiap schema yang direferensikan ada di components
+	body := a.do(http.MethodGet, routes.OpenAPIPath, "", nil).Body.String()
+	for _, ref := range strings.Split(body, `"$ref":"#/components/schemas/`)[1:] {
+		name := ref[:strings.Index(ref, `"`)]
+		assert.Contains(t, doc.Components.Schemas, name)
+	}
+
+	// Tanpa file swagger-ui-dist halaman docs tidak didaftarkan
+	expectProblem(t, a.do(http.MethodGet, routes.DocsPath, "", nil), http.StatusNotFound, "route_not_found")
+}
+
+func TestOpenAPI_DocsServesSwaggerUIFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range openapi.SwaggerUIFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("/* "+name+" */"), 0o644))
+	}
+	a := newAppWithOptions(t, routes.Options{SwaggerUIDir: dir})
+
+	rec := a.do(http.MethodGet, routes.DocsPath, "", nil)
+	expect(t, rec, http.StatusOK, nil)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), routes.OpenAPIPath)
+	assert.NotContains(t, rec.Body.String(), "unpkg.com")
+
+	for _, name := range openapi.SwaggerUIFiles {
+		rec := a.do(http.MethodGet, routes.DocsAssetsPath+"/"+name, "", nil)
+		expect(t, rec, http.StatusOK, nil)
+		assert.Equal(t, "/* "+name+" */", rec.Body.String())
+	}
+}
+
+// Auth dan role di dokumen dicocokkan dengan perilaku middleware yang
+// sebenarnya terpasang di setiap route.
+func TestOpenAPI_AuthAndRolesMatchMiddleware(t *testing.T) {
+	a := newApp(t)
+	admin := a.admin()
+	tokens := map[string]string{
+		"user":       a.register("Budi", "budi@example.com").Token,
+		"technician": a.technician(admin, "Tono", "tono@example.com").Token,
+		"admin":      admin.Token,
+	}
+
+	doc := a.servedDocument()
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			method, target := strings.ToUpper(method), concretePath(path)
+			name := method + " " + path
+
+			rec := a.do(method, target, "", nil)
+			if len(op.Security) == 0 {
+				assert.NotEqual(t, http.StatusUnauthorized, rec.Code, "%s didokumentasikan tanpa auth", name)
+				continue
+			}
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, "%s didokumentasikan dengan auth", name)
+
+			for role, token := range tokens {
+				allowed := len(op.Roles) == 0 || slices.Contains(op.Roles, role)
+				if allowed && streaming(op) {
+					continue
+				}
+				rec := a.do(method, target, token, nil)
+				if allowed {
+					assert.NotEqual(t, "insufficient_role", problemCode(rec.Body.Bytes()), "%s mengizinkan %s", name, role)
+				} else {
+					require.Equal(t, http.StatusForbidden, rec.Code, "%s menolak %s", name, role)
+					assert.Equal(t, "insufficient_role", problemCode(rec.Body.Bytes()), name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayments_UpdateStatusAndReport(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+
+	payment := f.payment(f.customer.Token, booking.ID, "75000")
+	assert.Equal(t, "Pending", payment.Status)
+
+	// Booking Pending dikonfirmasi dalam transaksi yang sama dengan payment
+	var fetchedBooking entity.Booking
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/bookings/%d", booking.ID), f.customer.Token, nil), http.StatusOK, &fetchedBooking)
+	assert.Equal(t, "Confirmed", fetchedBooking.Status)
+
+	var fetched entity.Payment
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/payments/%d", payment.ID), f.customer.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, "75000", fetched.Amount)
+
+	expectProblem(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/payments/%d/status", payment.ID), f.admin.Token, map[string]string{"status": "Lunas"}), http.StatusBadRequest, "invalid_payment_status")
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/payments/%d/status", payment.ID), f.admin.Token, map[string]string{"status": "Paid"}), http.StatusOK, nil)
+
+	var report entity.PaymentReport
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/payments/reports?service_id=%d", f.service.ID), f.admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 1, report.TotalPayment)
+	assert.Equal(t, float64(75000), report.TotalAmount)
+	for _, status := range report.Status {
+		if status.PaymentStatus == "Paid" {
+			assert.Equal(t, 1, status.PaymentCount)
+		}
+	}
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/api/v1/payments/reports?end_date=besok", f.admin.Token, nil).Code)
+}
+
+func TestPayments_CRUDAndCancelledBooking(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	payment := f.payment(f.customer.Token, booking.ID, "75000")
+
+	var updated entity.Payment
+	req := entity.UpdatePaymentReq{ID: payment.ID, BookingID: booking.ID, Amount: "80000", Status: "Pending"}
+	expect(t, f.do(http.MethodPut, "/api/v1/payments", f.admin.Token, req), http.StatusOK, &updated)
+	assert.Equal(t, "80000", updated.Amount)
+
+	var payments listquery.Page[entity.Payment]
+	expect(t, f.do(http.MethodGet, "/api/v1/payments", f.admin.Token, nil), http.StatusOK, &payments)
+	assert.Len(t, payments.Items, 1)
+
+	expect(t, f.do(http.MethodDelete, fmt.Sprintf("/api/v1/payments/%d", payment.ID), f.admin.Token, nil), http.StatusOK, nil)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, fmt.Sprintf("/api/v1/payments/%d", payment.ID), f.admin.Token, nil).Code)
+
+	// Booking yang dibatalkan tidak bisa dibayar
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/bookings/%d/status", booking.ID), f.customer.Token, map[string]string{"status": "Cancelled"}), http.StatusOK, nil)
+	rec := f.do(http.MethodPost, "/api/v1/payments", f.customer.Token, entity.CreatePaymentReq{BookingID: booking.ID, Amount: "75000", Status: "Pending"})
+	expectProblem(t, rec, http.StatusUnprocessableEntity, "booking_not_payable")
+}
+
+func TestReviews_CRUDAndReport(t *testing.T) {
+	f := newFixture(t)
+	booking := f.booking(f.customer, f.service.ID, day(2))
+	expect(t, f.do(http.MethodPut, fmt.Sprintf("/api/v1/bookings/%d/status", booking.ID), f.technician.Token, map[string]string{"status": "Completed"}), http.StatusOK, nil)
+
+	var review entity.Review
+	expect(t, f.do(http.MethodPost, "/api/v1/reviews", f.customer.Token, entity.CreateReviewReq{BookingID: booking.ID, Rating: 4, Comment: "Rapi"}), http.StatusCreated, &review)
+	assert.Equal(t, 4, review.Rating)
+
+	var fetched entity.Review
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/reviews/%d", review.ID), f.technician.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, "Rapi", fetched.Comment)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, "/api/v1/reviews/9999", f.technician.Token, nil).Code)
+
+	var updated entity.Review
+	expect(t, f.do(http.MethodPut, "/api/v1/reviews", f.customer.Token, entity.UpdateReviewReq{ID: review.ID, BookingID: booking.ID, Rating: 5, Comment: "Rapi dan cepat"}), http.StatusOK, &updated)
+	assert.Equal(t, 5, updated.Rating)
+
+	var reviews listquery.Page[entity.Review]
+	expect(t, f.do(http.MethodGet, "/api/v1/reviews", f.admin.Token, nil), http.StatusOK, &reviews)
+	assert.Len(t, reviews.Items, 1)
+
+	var report entity.ReviewReport
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/reviews/reports?service_id=%d", f.service.ID), f.admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 1, report.TotalReviews)
+	assert.Equal(t, float64(5), report.AverageRating)
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/api/v1/reviews/reports?start_date=31-12-2024", f.admin.Token, nil).Code)
+
+	expect(t, f.do(http.MethodDelete, fmt.Sprintf("/api/v1/reviews/%d", review.ID), f.admin.Token, nil), http.StatusOK, nil)
+	expect(t, f.do(http.MethodGet, "/api/v1/reviews", f.admin.Token, nil), http.StatusOK, &reviews)
+	require.Empty(t, reviews.Items)
+}
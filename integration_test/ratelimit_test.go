@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
+	"github.com/Ayyasy123/dibimbing-capstone.git/routes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit_AuthEndpointsPerIPAndAccount(t *testing.T) {
+	a := newAppWithLimits(t, routes.RateLimits{
+		Store: ratelimit.NewMemoryStore(),
+		Auth: ratelimit.Policy{
+			IP:      ratelimit.Limit{Requests: 5, Period: time.Minute},
+			Account: ratelimit.Limit{Requests: 2, Period: time.Minute},
+		},
+	})
+
+	login := func(email string) *http.Response {
+		return a.do(http.MethodPost, "/api/v1/login", "", entity.LoginUserReq{Email: email, Password: "wrong-password"}).Result()
+	}
+
+	// Batas per akun (email) lebih ketat daripada batas per IP
+	res := login("victim@example.com")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, "2", res.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", res.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", res.Header.Get("X-RateLimit-Reset"))
+
+	// Email dengan spasi ditolak validasi, tetapi tetap dihitung untuk akun yang sama
+	assert.Equal(t, http.StatusBadRequest, login("Victim@Example.com ").StatusCode)
+
+	res = login("victim@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "0", res.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", res.Header.Get("Retry-After"))
+
+	// Akun lain masih bisa mencoba sampai batas per IP habis
+	assert.Equal(t, http.StatusUnauthorized, login("other@example.com").StatusCode)
+	res = login("another@example.com")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, "5", res.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", res.Header.Get("X-RateLimit-Remaining"))
+
+	res = login("third@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "12", res.Header.Get("Retry-After"))
+
+	// Endpoint operasional tidak ikut dibatasi
+	expect(t, a.do(http.MethodGet, "/healthz", "", nil), http.StatusOK, nil)
+}
+
+func TestRateLimit_APIPerAccount(t *testing.T) {
+	a := newAppWithLimits(t, routes.RateLimits{
+		Store: ratelimit.NewMemoryStore(),
+		API:   ratelimit.Policy{Account: ratelimit.Limit{Requests: 2, Period: time.Minute}},
+	})
+	alice := a.register("Alice", "alice@example.com")
+	bob := a.register("Bob", "bob@example.com")
+
+	expect(t, a.do(http.MethodGet, "/api/v1/users/"+strconv.Itoa(alice.ID), alice.Token, nil), http.StatusOK, nil)
+	expect(t, a.do(http.MethodGet, "/api/v1/users/"+strconv.Itoa(alice.ID), alice.Token, nil), http.StatusOK, nil)
+	rec := a.do(http.MethodGet, "/api/v1/users/"+strconv.Itoa(alice.ID), alice.Token, nil)
+	expect(t, rec, http.StatusTooManyRequests, nil)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Bucket dipisah per user, bukan per IP
+	expect(t, a.do(http.MethodGet, "/api/v1/users/"+strconv.Itoa(bob.ID), bob.Token, nil), http.StatusOK, nil)
+}
+
+func TestRateLimit_LockoutAfterFailedLogins(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	a := newAppWithLimits(t, routes.RateLimits{
+		Store:   store,
+		Lockout: ratelimit.NewLockout(store, 3, 15*time.Minute),
+	})
+	a.register("Alice", "alice@example.com")
+	a.register("Bob", "bob@example.com")
+
+	wrong := entity.LoginUserReq{Email: "alice@example.com", Password: "wrong-password"}
+	for i := 0; i < 3; i++ {
+		expect(t, a.do(http.MethodPost, "/api/v1/login", "", wrong), http.StatusUnauthorized, nil)
+	}
+
+	// Akun terkunci: password yang benar pun ditolak sampai kunci berakhir
+	rec := a.do(http.MethodPost, "/api/v1/login", "", entity.LoginUserReq{Email: "alice@example.com", Password: testPassword})
+	problem := expectProblem(t, rec, http.StatusTooManyRequests, "account_locked")
+	assert.Equal(t, "too many failed login attempts, account is temporarily locked", problem.Detail)
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 15*60, retryAfter, 1)
+
+	// Akun lain tidak terpengaruh, dan login berhasil mereset hitungan gagal
+	a.login("bob@example.com")
+	expect(t, a.do(http.MethodPost, "/api/v1/login", "", entity.LoginUserReq{Email: "bob@example.com", Password: "wrong-password"}), http.StatusUnauthorized, nil)
+	a.login("bob@example.com")
+}
+
+// X-Forwarded-For dari client biasa diabaikan, jadi header palsu tidak
+// memberi bucket baru. Header hanya dipakai jika koneksi berasal dari proxy
+// di server.trusted_proxies.
+func TestRateLimit_IgnoresSpoofedForwardedFor(t *testing.T) {
+	limits := routes.RateLimits{
+		Store: ratelimit.NewMemoryStore(),
+		Auth:  ratelimit.Policy{IP: ratelimit.Limit{Requests: 2, Period: time.Minute}},
+	}
+	login := func(a *app, forwardedFor string) int {
+		body, err := json.Marshal(entity.LoginUserReq{Email: "victim@example.com", Password: "wrong-password"})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		a.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	a := newAppWithLimits(t, limits)
+	assert.Equal(t, http.StatusUnauthorized, login(a, "203.0.113.1"))
+	assert.Equal(t, http.StatusUnauthorized, login(a, "203.0.113.2"))
+	assert.Equal(t, http.StatusTooManyRequests, login(a, "203.0.113.3"))
+
+	// httptest.NewRequest memakai alamat 192.0.2.1 sebagai proxy
+	limits.Store = ratelimit.NewMemoryStore()
+	proxied := newAppWithOptions(t, routes.Options{RateLimits: limits, TrustedProxies: []string{"192.0.2.1"}})
+	assert.Equal(t, http.StatusUnauthorized, login(proxied, "203.0.113.1"))
+	assert.Equal(t, http.StatusUnauthorized, login(proxied, "203.0.113.1"))
+	assert.Equal(t, http.StatusUnauthorized, login(proxied, "203.0.113.2"))
+	assert.Equal(t, http.StatusTooManyRequests, login(proxied, "203.0.113.1"))
+}
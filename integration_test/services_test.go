@@ -0,0 +1,73 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServices_CRUD(t *testing.T) {
+	f := newFixture(t)
+
+	// Customer tidak boleh membuat service
+	rec := f.do(http.MethodPost, "/api/v1/services", f.customer.Token, entity.CreateServiceReq{Name: "Servis", Cost: 1})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var fetched entity.Service
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/services/%d", f.service.ID), f.customer.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, "Cuci AC", fetched.Name)
+	assert.Equal(t, f.technician.ID, fetched.UserID)
+
+	var updated entity.ServiceRes
+	req := entity.UpdateServiceReq{ID: f.service.ID, UserID: f.technician.ID, Name: "Cuci AC Split", Description: "Termasuk freon", Cost: 85000}
+	expect(t, f.do(http.MethodPut, "/api/v1/services", f.technician.Token, req), http.StatusOK, &updated)
+	assert.Equal(t, "Cuci AC Split", updated.Name)
+	assert.Equal(t, 85000, updated.Cost)
+
+	var services listquery.Page[entity.Service]
+	expect(t, f.do(http.MethodGet, "/api/v1/services", f.customer.Token, nil), http.StatusOK, &services)
+	assert.Len(t, services.Items, 1)
+
+	var owned listquery.Page[entity.ServiceRes]
+	expect(t, f.do(http.MethodGet, fmt.Sprintf("/api/v1/services/user/%d", f.technician.ID), f.customer.Token, nil), http.StatusOK, &owned)
+	assert.Len(t, owned.Items, 1)
+
+	assert.Equal(t, http.StatusForbidden, f.do(http.MethodDelete, fmt.Sprintf("/api/v1/services/%d", f.service.ID), f.customer.Token, nil).Code)
+	expect(t, f.do(http.MethodDelete, fmt.Sprintf("/api/v1/services/%d", f.service.ID), f.technician.Token, nil), http.StatusOK, nil)
+	expect(t, f.do(http.MethodGet, "/api/v1/services", f.customer.Token, nil), http.StatusOK, &services)
+	assert.Empty(t, services.Items)
+	assert.Equal(t, int64(0), services.Total)
+}
+
+func TestServices_SearchAndReport(t *testing.T) {
+	f := newFixture(t)
+	f.app.service(f.technician, "Servis Kulkas", 150000)
+	f.app.service(f.technician, "Pasang AC", 300000)
+
+	search := func(query string) []string {
+		var services listquery.Page[entity.ServiceRes]
+		expect(t, f.do(http.MethodGet, "/api/v1/services/search?"+query, f.customer.Token, nil), http.StatusOK, &services)
+		names := make([]string, 0, len(services.Items))
+		for _, s := range services.Items {
+			names = append(names, s.Name)
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"Cuci AC", "Pasang AC"}, search("search=AC"))
+	assert.ElementsMatch(t, []string{"Servis Kulkas", "Pasang AC"}, search("min_price=100000"))
+	assert.ElementsMatch(t, []string{"Cuci AC"}, search("search=AC&max_price=100000"))
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/api/v1/services/search?min_price=5&max_price=1", f.customer.Token, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, f.do(http.MethodGet, "/api/v1/services/search?min_price=murah", f.customer.Token, nil).Code)
+
+	var report struct {
+		TotalServices    int            `json:"total_services"`
+		CostDistribution map[string]int `json:"cost_distribution"`
+	}
+	expect(t, f.do(http.MethodGet, "/api/v1/services/reports", f.admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 3, report.TotalServices)
+}
@@ -0,0 +1,85 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_PropagatesTraceContext(t *testing.T) {
+	f := newFixture(t)
+	f.booking(f.customer, f.service.ID, day(2))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/reports", nil)
+	req.Header.Set("Authorization", "Bearer "+f.admin.Token)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var traced []sdktrace.ReadOnlySpan
+	for _, span := range spans.Ended() {
+		if span.SpanContext().TraceID().String() == traceID {
+			traced = append(traced, span)
+		}
+	}
+	find := func(name string) sdktrace.ReadOnlySpan {
+		for _, span := range traced {
+			if span.Name() == name {
+				return span
+			}
+		}
+		t.Fatalf("span %q not recorded", name)
+		return nil
+	}
+
+	// Span HTTP melanjutkan trace dari client
+	server := find("/api/v1/bookings/reports")
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.True(t, server.Parent().IsRemote())
+
+	// Span service berada di bawah span HTTP, dan query SQL di bawah span service
+	svc := find("BookingService.GetBookingReport")
+	assert.Equal(t, server.SpanContext().SpanID(), svc.Parent().SpanID())
+
+	var queries int
+	for _, span := range traced {
+		if span.Parent().SpanID() == svc.SpanContext().SpanID() {
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+			queries++
+		}
+	}
+	assert.Greater(t, queries, 1)
+}
+
+func TestTracing_ServiceSpanRecordsError(t *testing.T) {
+	f := newFixture(t)
+
+	const traceID = "0af7651916cd43dd8448eb211c80319c"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/999", nil)
+	req.Header.Set("Authorization", "Bearer "+f.admin.Token)
+	req.Header.Set("traceparent", "00-"+traceID+"-b7ad6b7169203331-01")
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+
+	for _, span := range spans.Ended() {
+		if span.SpanContext().TraceID().String() != traceID || span.Name() != "BookingService.GetBookingByID" {
+			continue
+		}
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, "booking not found", span.Status().Description)
+		require.Len(t, span.Events(), 1)
+		assert.Equal(t, "exception", span.Events()[0].Name)
+		return
+	}
+	t.Fatal("span BookingService.GetBookingByID not recorded")
+}
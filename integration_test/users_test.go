@@ -0,0 +1,154 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPing(t *testing.T) {
+	a := newApp(t)
+
+	var res map[string]string
+	expect(t, a.do(http.MethodGet, "/ping", "", nil), http.StatusOK, &res)
+	assert.Equal(t, "pong", res["message"])
+
+	// Path yang tidak dikenal juga dijawab dengan problem+json
+	problem := expectProblem(t, a.do(http.MethodGet, "/tidak-ada", "", nil), http.StatusNotFound, "route_not_found")
+	assert.Equal(t, "/tidak-ada", problem.Instance)
+}
+
+func TestAuth_RegisterLoginAndTokenChecks(t *testing.T) {
+	a := newApp(t)
+	user := a.register("Dewi", "dewi@example.com")
+
+	// Email yang sama tidak boleh didaftarkan dua kali
+	rec := a.do(http.MethodPost, "/api/v1/register", "", entity.RegisterUserReq{Name: "Dewi", Email: "dewi@example.com", Password: testPassword})
+	expectProblem(t, rec, http.StatusConflict, "email_taken")
+
+	// Email tidak terdaftar dan password salah menghasilkan error yang sama
+	rec = a.do(http.MethodPost, "/api/v1/login", "", entity.LoginUserReq{Email: "dewi@example.com", Password: "salah"})
+	expectProblem(t, rec, http.StatusUnauthorized, "invalid_credentials")
+	rec = a.do(http.MethodPost, "/api/v1/login", "", entity.LoginUserReq{Email: "tidakada@example.com", Password: testPassword})
+	expectProblem(t, rec, http.StatusUnauthorized, "invalid_credentials")
+
+	expectProblem(t, a.do(http.MethodGet, "/api/v1/users", "", nil), http.StatusUnauthorized, "missing_token")
+	expectProblem(t, a.do(http.MethodGet, "/api/v1/users", "not-a-jwt", nil), http.StatusUnauthorized, "invalid_token")
+
+	// Token lewat query parameter hanya diterima route streaming
+	rec = a.request(http.MethodGet, fmt.Sprintf("/api/v1/users/%d?access_token=%s", user.ID, user.Token), "", nil, "")
+	expectProblem(t, rec, http.StatusUnauthorized, "missing_token")
+}
+
+func TestUsers_CRUDAndReport(t *testing.T) {
+	a := newApp(t)
+	admin := a.admin()
+	user := a.register("Eko", "eko@example.com")
+
+	var fetched entity.UserRes
+	expect(t, a.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", user.ID), user.Token, nil), http.StatusOK, &fetched)
+	assert.Equal(t, "eko@example.com", fetched.Email)
+	assert.Equal(t, "user", fetched.Role)
+	assert.Equal(t, http.StatusNotFound, a.do(http.MethodGet, "/api/v1/users/9999", user.Token, nil).Code)
+
+	var users listquery.Page[entity.UserRes]
+	expect(t, a.do(http.MethodGet, "/api/v1/users?limit=10", admin.Token, nil), http.StatusOK, &users)
+	assert.Len(t, users.Items, 2)
+	assert.Equal(t, int64(2), users.Total)
+
+	var updated entity.UserRes
+	expect(t, a.do(http.MethodPut, "/api/v1/users", user.Token, entity.UpdateUserReq{ID: user.ID, Name: "Eko Prasetyo", Language: "en"}), http.StatusOK, &updated)
+	assert.Equal(t, "Eko Prasetyo", updated.Name)
+
+	var report struct {
+		TotalUsers       int            `json:"total_users"`
+		RoleDistribution map[string]int `json:"role_distribution"`
+	}
+	expect(t, a.do(http.MethodGet, "/api/v1/users/reports", admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 2, report.TotalUsers)
+	assert.Equal(t, map[string]int{"admin": 1, "user": 1}, report.RoleDistribution)
+
+	expect(t, a.do(http.MethodGet, "/api/v1/users/reports?start_date=2000-01-01&end_date=2000-12-31", admin.Token, nil), http.StatusOK, &report)
+	assert.Equal(t, 0, report.TotalUsers)
+
+	expect(t, a.do(http.MethodDelete, fmt.Sprintf("/api/v1/users/%d", user.ID), admin.Token, nil), http.StatusOK, nil)
+	assert.Equal(t, http.StatusNotFound, a.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", user.ID), admin.Token, nil).Code)
+}
+
+func TestUsers_UpdateTechnicianRequiresTechnicianRole(t *testing.T) {
+	f := newFixture(t)
+
+	rec := f.do(http.MethodPut, "/api/v1/users/update-technician", f.customer.Token, entity.UpdateTechnicianReq{ID: f.customer.ID, Expertise: "Listrik"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	var technician entity.TechnicianRes
+	rec = f.do(http.MethodPut, "/api/v1/users/update-technician", f.technician.Token, entity.UpdateTechnicianReq{ID: f.technician.ID, Expertise: "AC & Kulkas", Availability: "Setiap hari"})
+	expect(t, rec, http.StatusOK, &technician)
+	assert.Equal(t, "AC & Kulkas", technician.Expertise)
+	assert.Equal(t, "Setiap hari", technician.Availability)
+}
+
+func TestUsers_UpdateOnlyOwnAccountAndRoleByAdmin(t *testing.T) {
+	f := newFixture(t)
+	update := func(token string, req entity.UpdateUserReq) *httptest.ResponseRecorder {
+		return f.do(http.MethodPut, "/api/v1/users", token, req)
+	}
+
+	// User lain dan role sendiri tidak boleh diubah oleh non-admin
+	expectProblem(t, update(f.customer.Token, entity.UpdateUserReq{ID: f.technician.ID, Name: "Diganti"}), http.StatusForbidden, "user_update_forbidden")
+	expectProblem(t, update(f.customer.Token, entity.UpdateUserReq{ID: f.customer.ID, Role: "admin"}), http.StatusForbidden, "role_change_forbidden")
+	expectProblem(t, f.do(http.MethodPut, "/api/v1/users/update-technician", f.technician.Token, entity.UpdateTechnicianReq{ID: f.technician.ID, Role: "admin"}), http.StatusForbidden, "role_change_forbidden")
+	expect(t, update(f.customer.Token, entity.UpdateUserReq{ID: f.customer.ID, Role: "user", Name: "Citra"}), http.StatusOK, nil)
+
+	// Admin boleh mengubah user lain, tetapi role technician tetap lewat pengajuan
+	expectProblem(t, update(f.admin.Token, entity.UpdateUserReq{ID: f.customer.ID, Role: "technician"}), http.StatusUnprocessableEntity, "technician_role_requires_application")
+	var updated entity.UserRes
+	expect(t, update(f.admin.Token, entity.UpdateUserReq{ID: f.customer.ID, Role: "admin"}), http.StatusOK, &updated)
+	assert.Equal(t, "admin", updated.Role)
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	a := newApp(t)
+	user := a.register("Fajar", "fajar@example.com")
+
+	enabled := func(preferences []entity.NotificationPreferenceRes, eventType, channel string) bool {
+		for _, p := range preferences {
+			if p.EventType == eventType && p.Channel == channel {
+				return p.Enabled
+			}
+		}
+		t.Fatalf("preference %s/%s not found", eventType, channel)
+		return false
+	}
+
+	var res struct {
+		Preferences []entity.NotificationPreferenceRes `json:"preferences"`
+	}
+	expect(t, a.do(http.MethodGet, "/api/v1/users/me/notification-preferences", user.Token, nil), http.StatusOK, &res)
+	require.NotEmpty(t, res.Preferences)
+	assert.True(t, enabled(res.Preferences, "booking.created", "email"))
+	assert.False(t, enabled(res.Preferences, "booking.created", "sms"))
+
+	req := entity.UpdateNotificationPreferencesReq{Preferences: []entity.NotificationPreferenceRes{
+		{EventType: "booking.created", Channel: "email", Enabled: false},
+		{EventType: "booking.created", Channel: "sms", Enabled: true},
+	}}
+	expect(t, a.do(http.MethodPut, "/api/v1/users/me/notification-preferences", user.Token, req), http.StatusOK, &res)
+	assert.False(t, enabled(res.Preferences, "booking.created", "email"))
+	assert.True(t, enabled(res.Preferences, "booking.created", "sms"))
+
+	// Upsert kedua mengubah baris yang sama, bukan menambah baris baru
+	expect(t, a.do(http.MethodPut, "/api/v1/users/me/notification-preferences", user.Token, req), http.StatusOK, &res)
+	var count int64
+	require.NoError(t, a.db.Table("notification_preferences").Where("user_id = ?", user.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	req.Preferences[0].Channel = "fax"
+	assert.Equal(t, http.StatusBadRequest, a.do(http.MethodPut, "/api/v1/users/me/notification-preferences", user.Token, req).Code)
+}
@@ -0,0 +1,90 @@
+package integration_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/routes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersioning_LegacyAliasesAreDeprecated(t *testing.T) {
+	sunset := time.Date(2027, time.March, 31, 0, 0, 0, 0, time.UTC)
+	a := newAppWithOptions(t, routes.Options{Legacy: routes.LegacyRoutes{Enabled: true, Sunset: sunset}})
+	user := a.register("Dewi", "dewi@example.com")
+
+	// Route v1 tidak membawa header deprecation
+	rec := a.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", user.ID), user.Token, nil)
+	expect(t, rec, http.StatusOK, nil)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+
+	// Alias tanpa versi menjalankan handler yang sama
+	var res entity.UserRes
+	rec = a.do(http.MethodGet, fmt.Sprintf("/users/%d", user.ID), user.Token, nil)
+	expect(t, rec, http.StatusOK, &res)
+	assert.Equal(t, user.ID, res.ID)
+	assert.Regexp(t, `^@\d+$`, rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Wed, 31 Mar 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, fmt.Sprintf(`</api/v1/users/%d>; rel="successor-version"`, user.ID), rec.Header().Get("Link"))
+
+	// Header juga ada di response error
+	rec = a.do(http.MethodPost, "/login", "", entity.LoginUserReq{Email: "dewi@example.com", Password: "salah"})
+	expectProblem(t, rec, http.StatusUnauthorized, "invalid_credentials")
+	assert.Equal(t, `</api/v1/login>; rel="successor-version"`, rec.Header().Get("Link"))
+
+	// Route infrastruktur tetap di root dan tidak diberi versi
+	rec = a.do(http.MethodGet, "/healthz", "", nil)
+	expect(t, rec, http.StatusOK, nil)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+}
+
+func TestVersioning_LegacyAliasesDisabled(t *testing.T) {
+	a := newApp(t)
+	user := a.register("Dewi", "dewi@example.com")
+
+	expectProblem(t, a.do(http.MethodGet, fmt.Sprintf("/users/%d", user.ID), user.Token, nil), http.StatusNotFound, "route_not_found")
+	expect(t, a.do(http.MethodGet, fmt.Sprintf("/api/v1/users/%d", user.ID), user.Token, nil), http.StatusOK, nil)
+}
+
+func TestVersioning_LegacyListsKeepOldContract(t *testing.T) {
+	a := newAppWithOptions(t, routes.Options{Legacy: routes.LegacyRoutes{Enabled: true}})
+	admin := a.admin()
+	technician := a.technician(admin, "Budi Teknisi", "budi@example.com")
+	customer := a.register("Citra Customer", "citra@example.com")
+	for _, name := range []string{"Cuci AC", "Isi Freon", "Bongkar Pasang AC"} {
+		a.service(technician, name, 75000)
+	}
+
+	// Alias mengembalikan array dan menerima offset
+	var services []entity.ServiceRes
+	expect(t, a.do(http.MethodGet, "/services?limit=2&offset=1", customer.Token, nil), http.StatusOK, &services)
+	assert.Equal(t, []string{"Isi Freon", "Bongkar Pasang AC"}, []string{services[0].Name, services[1].Name})
+	expectProblem(t, a.do(http.MethodGet, "/services?offset=-1", customer.Token, nil), http.StatusBadRequest, "validation_failed")
+
+	// v1 memakai envelope dan menolak offset
+	expectProblem(t, a.do(http.MethodGet, "/api/v1/services?offset=1", customer.Token, nil), http.StatusBadRequest, "validation_failed")
+
+	// Pesan memakai bentuk lama dengan cursor berupa ID pesan
+	booking := a.booking(customer, services[0].ID, day(3))
+	path := fmt.Sprintf("/bookings/%d/messages", booking.ID)
+	for _, body := range []string{"Satu", "Dua", "Tiga"} {
+		expect(t, a.do(http.MethodPost, path, customer.Token, entity.CreateMessageReq{Body: body}), http.StatusCreated, nil)
+	}
+	var page struct {
+		Messages   []entity.MessageRes `json:"messages"`
+		NextCursor *int                `json:"next_cursor"`
+	}
+	expect(t, a.do(http.MethodGet, path+"?limit=2", customer.Token, nil), http.StatusOK, &page)
+	if assert.Len(t, page.Messages, 2) && assert.NotNil(t, page.NextCursor) {
+		assert.Equal(t, "Tiga", page.Messages[0].Body)
+		assert.Equal(t, page.Messages[1].ID, *page.NextCursor)
+	}
+	expect(t, a.do(http.MethodGet, fmt.Sprintf("%s?limit=2&cursor=%d", path, *page.NextCursor), customer.Token, nil), http.StatusOK, &page)
+	if assert.Len(t, page.Messages, 1) {
+		assert.Equal(t, "Satu", page.Messages[0].Body)
+	}
+	assert.Nil(t, page.NextCursor)
+}
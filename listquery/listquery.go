@@ -0,0 +1,260 @@
+// Package listquery adalah lapisan query bersama untuk endpoint list:
+// membaca limit, sort, filter dan cursor dari query string sesuai Spec
+// endpoint, lalu membentuk response Page berisi items, total dan next_cursor.
+//
+// Pagination memakai keyset (cursor) dan bukan offset: cursor menyimpan
+// nilai kolom sort dan ID baris terakhir, sehingga halaman berikutnya tetap
+// konsisten meskipun ada baris baru yang disisipkan di antaranya.
+package listquery
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/validation"
+)
+
+const (
+	// DefaultLimit dipakai jika Spec tidak menentukan limit default.
+	DefaultLimit = 10
+	// MaxLimit adalah batas atas limit; nilai yang lebih besar dipotong.
+	MaxLimit = 100
+	// DateLayout adalah format nilai filter tanggal, mis. date_from.
+	DateLayout = "2006-01-02"
+)
+
+// Kind menentukan cara nilai filter dan cursor dibaca dan dibandingkan.
+type Kind int
+
+const (
+	Int Kind = iota
+	String
+	Time
+)
+
+// Field adalah kolom yang boleh dipakai untuk sort atau filter. Name sama
+// dengan nama kolom database dan nama field JSON entity-nya.
+type Field struct {
+	Name string
+	Kind Kind
+}
+
+// ID adalah primary key yang dipakai sebagai pemecah urutan di setiap sort.
+var ID = Field{Name: "id", Kind: Int}
+
+// Op adalah operator perbandingan kondisi. Nilainya dipakai langsung di SQL,
+// jadi hanya konstanta di bawah yang boleh dipakai.
+type Op string
+
+const (
+	Eq  Op = "="
+	Gte Op = ">="
+	Lte Op = "<="
+	Lt  Op = "<"
+)
+
+// Filter memetakan parameter query string ke kondisi pada Field. Untuk
+// Field bertipe Time nilainya berupa tanggal (DateLayout, UTC) dan Lte
+// berarti sampai akhir hari tersebut.
+type Filter struct {
+	Param string
+	Field Field
+	Op    Op
+}
+
+// Spec mendaftar sort dan filter yang diizinkan sebuah endpoint list.
+// DefaultSort ditulis seperti parameter sort, mis. "-id" untuk terbaru dulu.
+type Spec struct {
+	Sorts        []Field
+	DefaultSort  string
+	Filters      []Filter
+	DefaultLimit int
+}
+
+// Limit mengembalikan limit default Spec.
+func (s Spec) Limit() int {
+	if s.DefaultLimit > 0 {
+		return s.DefaultLimit
+	}
+	return DefaultLimit
+}
+
+// SortValues mengembalikan semua nilai parameter sort yang valid.
+func (s Spec) SortValues() []string {
+	var values []string
+	for _, field := range s.Sorts {
+		values = append(values, field.Name, "-"+field.Name)
+	}
+	return values
+}
+
+func (s Spec) sort(value string) (Sort, bool) {
+	name, desc := strings.CutPrefix(value, "-")
+	for _, field := range s.Sorts {
+		if field.Name == name {
+			return Sort{Field: field, Desc: desc}, true
+		}
+	}
+	return Sort{}, false
+}
+
+// Sort adalah urutan list. Baris dengan nilai yang sama diurutkan lagi
+// berdasarkan ID dengan arah yang sama.
+type Sort struct {
+	Field Field
+	Desc  bool
+}
+
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field.Name
+	}
+	return s.Field.Name
+}
+
+// Condition membatasi baris dengan Field Op Value.
+type Condition struct {
+	Field Field
+	Op    Op
+	Value interface{}
+}
+
+// Query adalah hasil Parse yang dijalankan oleh repository. After berisi
+// posisi baris terakhir halaman sebelumnya. Offset hanya diisi ParseOffset
+// untuk route lama dan dilewati setelah cursor.
+type Query struct {
+	Limit      int
+	Sort       Sort
+	Conditions []Condition
+	After      *Cursor
+	Offset     int
+}
+
+// Where mengembalikan salinan q dengan kondisi tambahan, dipakai service
+// untuk membatasi list, mis. hanya booking milik user tertentu.
+func (q Query) Where(field Field, op Op, value interface{}) Query {
+	q.Conditions = append(slices.Clip(q.Conditions), Condition{Field: field, Op: op, Value: value})
+	return q
+}
+
+// New membuat Query halaman pertama dengan sort default Spec untuk
+// pemanggil di luar HTTP seperti CLI. limit tidak dibatasi MaxLimit.
+func New(spec Spec, limit int) Query {
+	sort, ok := spec.sort(spec.DefaultSort)
+	if !ok {
+		panic(fmt.Sprintf("listquery: unknown default sort %q", spec.DefaultSort))
+	}
+	return Query{Limit: limit, Sort: sort}
+}
+
+// Parse membaca limit, sort, cursor dan filter dari query string. Semua
+// parameter yang tidak valid dilaporkan sekaligus sebagai error
+// validation_failed. Parameter offset ditolak karena sudah diganti cursor.
+func Parse(values url.Values, spec Spec) (Query, error) {
+	return parse(values, spec, false)
+}
+
+// ParseOffset sama dengan Parse tetapi menerima offset seperti sebelum ada
+// cursor. Hanya untuk route alias tanpa versi yang masih memakai kontrak lama.
+func ParseOffset(values url.Values, spec Spec) (Query, error) {
+	return parse(values, spec, true)
+}
+
+func parse(values url.Values, spec Spec, allowOffset bool) (Query, error) {
+	var fields []apperror.FieldError
+	invalid := func(param, rule, message string) {
+		fields = append(fields, apperror.FieldError{Field: param, Rule: rule, Message: message})
+	}
+
+	q := Query{Limit: spec.Limit()}
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			invalid("limit", "type", "limit must be an integer")
+		case limit < 1:
+			invalid("limit", "min", "limit must be at least 1")
+		default:
+			q.Limit = min(limit, MaxLimit)
+		}
+	}
+	if raw := values.Get("offset"); values.Has("offset") && !allowOffset {
+		invalid("offset", "unsupported", "offset is not supported; pass next_cursor from the previous page as cursor")
+	} else if raw != "" {
+		offset, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			invalid("offset", "type", "offset must be an integer")
+		case offset < 0:
+			invalid("offset", "min", "offset must not be negative")
+		default:
+			q.Offset = offset
+		}
+	}
+
+	sortValue := spec.DefaultSort
+	if raw := values.Get("sort"); raw != "" {
+		sortValue = raw
+	}
+	sort, ok := spec.sort(sortValue)
+	if !ok {
+		invalid("sort", "oneof", "sort must be one of: "+strings.Join(spec.SortValues(), ", "))
+	}
+	q.Sort = sort
+
+	if raw := values.Get("cursor"); raw != "" && ok {
+		cursor, err := decodeCursor(raw, sort)
+		if err != nil {
+			invalid("cursor", "cursor", "cursor is invalid or was issued for a different sort")
+		}
+		q.After = cursor
+	}
+
+	for _, filter := range spec.Filters {
+		raw := values.Get(filter.Param)
+		if raw == "" {
+			continue
+		}
+		condition, err := filter.condition(raw)
+		if err != nil {
+			invalid(filter.Param, "type", err.Error())
+			continue
+		}
+		q.Conditions = append(q.Conditions, condition)
+	}
+
+	if len(fields) > 0 {
+		return Query{}, validation.ErrValidationFailed.WithFields(fields)
+	}
+	return q, nil
+}
+
+func (f Filter) condition(raw string) (Condition, error) {
+	condition := Condition{Field: f.Field, Op: f.Op}
+	switch f.Field.Kind {
+	case Int:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return Condition{}, fmt.Errorf("%s must be an integer", f.Param)
+		}
+		condition.Value = value
+	case Time:
+		date, err := time.Parse(DateLayout, raw)
+		if err != nil {
+			return Condition{}, fmt.Errorf("%s must be a date in YYYY-MM-DD format", f.Param)
+		}
+		// Tanggal akhir inklusif: semua waktu sebelum hari berikutnya
+		if f.Op == Lte {
+			condition.Op, date = Lt, date.AddDate(0, 0, 1)
+		}
+		condition.Value = date
+	default:
+		condition.Value = raw
+	}
+	return condition, nil
+}
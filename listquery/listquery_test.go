@@ -0,0 +1,186 @@
+package listquery_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type row struct {
+	ID   int       `json:"id"`
+	Name string    `json:"name"`
+	Cost int       `json:"cost"`
+	Date time.Time `json:"date"`
+}
+
+var (
+	name = listquery.Field{Name: "name", Kind: listquery.String}
+	cost = listquery.Field{Name: "cost", Kind: listquery.Int}
+	date = listquery.Field{Name: "date", Kind: listquery.Time}
+
+	spec = listquery.Spec{
+		Sorts:       []listquery.Field{listquery.ID, name, cost, date},
+		DefaultSort: "id",
+		Filters: []listquery.Filter{
+			{Param: "name", Field: name, Op: listquery.Eq},
+			{Param: "min_cost", Field: cost, Op: listquery.Gte},
+			{Param: "date_from", Field: date, Op: listquery.Gte},
+			{Param: "date_to", Field: date, Op: listquery.Lte},
+		},
+	}
+)
+
+func parse(t *testing.T, rawQuery string) listquery.Query {
+	t.Helper()
+	values, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	q, err := listquery.Parse(values, spec)
+	require.NoError(t, err)
+	return q
+}
+
+// fieldErrors mengembalikan rule per parameter dari error validation_failed.
+func fieldErrors(t *testing.T, rawQuery string) map[string]string {
+	t.Helper()
+	values, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	_, err = listquery.Parse(values, spec)
+	var appErr *apperror.Error
+	require.True(t, errors.As(err, &appErr), "error %v", err)
+	rules := map[string]string{}
+	for _, field := range appErr.Fields {
+		rules[field.Field] = field.Rule
+	}
+	return rules
+}
+
+func TestParse_Defaults(t *testing.T) {
+	q := parse(t, "")
+	assert.Equal(t, listquery.DefaultLimit, q.Limit)
+	assert.Equal(t, "id", q.Sort.String())
+	assert.Empty(t, q.Conditions)
+	assert.Nil(t, q.After)
+
+	assert.Equal(t, 20, listquery.Spec{DefaultLimit: 20}.Limit())
+	assert.Equal(t, listquery.MaxLimit, parse(t, "limit=1000").Limit)
+	assert.Equal(t, "-cost", parse(t, "sort=-cost").Sort.String())
+}
+
+func TestParse_Filters(t *testing.T) {
+	q := parse(t, "name=AC&min_cost=5000&date_from=2030-07-01&date_to=2030-07-31&unknown=1")
+	require.Len(t, q.Conditions, 4)
+	assert.Equal(t, listquery.Condition{Field: name, Op: listquery.Eq, Value: "AC"}, q.Conditions[0])
+	assert.Equal(t, listquery.Condition{Field: cost, Op: listquery.Gte, Value: 5000}, q.Conditions[1])
+	assert.Equal(t, listquery.Condition{Field: date, Op: listquery.Gte, Value: time.Date(2030, 7, 1, 0, 0, 0, 0, time.UTC)}, q.Conditions[2])
+	// Tanggal akhir inklusif menjadi "sebelum hari berikutnya"
+	assert.Equal(t, listquery.Condition{Field: date, Op: listquery.Lt, Value: time.Date(2030, 8, 1, 0, 0, 0, 0, time.UTC)}, q.Conditions[3])
+}
+
+func TestParse_Errors(t *testing.T) {
+	rules := fieldErrors(t, "limit=0&offset=10&sort=secret&min_cost=murah&date_from=01-07-2030")
+	assert.Equal(t, map[string]string{
+		"limit":     "min",
+		"offset":    "unsupported",
+		"sort":      "oneof",
+		"min_cost":  "type",
+		"date_from": "type",
+	}, rules)
+
+	assert.Equal(t, map[string]string{"limit": "type"}, fieldErrors(t, "limit=ten"))
+	assert.Equal(t, map[string]string{"cursor": "cursor"}, fieldErrors(t, "cursor=not-a-cursor"))
+}
+
+func TestSlice_CursorWalk(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2030, 7, d, 9, 0, 0, 0, time.UTC) }
+	rows := []row{
+		{ID: 1, Name: "c", Cost: 300, Date: day(3)},
+		{ID: 2, Name: "a", Cost: 100, Date: day(1)},
+		{ID: 3, Name: "b", Cost: 200, Date: day(2)},
+		{ID: 4, Name: "d", Cost: 100, Date: day(1)},
+	}
+	walk := func(q listquery.Query) [][]int {
+		var pages [][]int
+		for more := true; more; {
+			page := listquery.Slice(rows, q)
+			assert.Equal(t, int64(len(rows)), page.Total)
+			ids := []int{}
+			for _, r := range page.Items {
+				ids = append(ids, r.ID)
+			}
+			pages = append(pages, ids)
+			q, more = listquery.Next(q, page)
+		}
+		return pages
+	}
+
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, walk(parse(t, "limit=2")))
+	assert.Equal(t, [][]int{{2, 4, 3}, {1}}, walk(parse(t, "limit=3&sort=cost")))
+	assert.Equal(t, [][]int{{1, 3}, {4, 2}}, walk(parse(t, "limit=2&sort=-date")))
+	assert.Equal(t, [][]int{{4, 1, 3, 2}}, walk(parse(t, "sort=-name")))
+
+	page := listquery.Slice(rows, parse(t, "min_cost=200&date_to=2030-07-02"))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, 3, page.Items[0].ID)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Nil(t, page.NextCursor)
+
+	empty := listquery.Slice([]row{}, parse(t, ""))
+	assert.NotNil(t, empty.Items)
+}
+
+func TestCursor_QueryString(t *testing.T) {
+	rows := []row{{ID: 1, Cost: 1}, {ID: 2, Cost: 2}}
+	page := listquery.Slice(rows, parse(t, "limit=1&sort=cost"))
+	require.NotNil(t, page.NextCursor)
+
+	// Cursor dari response dipakai lagi lewat query string dengan sort yang sama
+	next := listquery.Slice(rows, parse(t, "limit=1&sort=cost&cursor="+*page.NextCursor))
+	assert.Equal(t, []row{{ID: 2, Cost: 2}}, next.Items)
+	assert.Nil(t, next.NextCursor)
+
+	assert.Equal(t, map[string]string{"cursor": "cursor"}, fieldErrors(t, "sort=-cost&cursor="+*page.NextCursor))
+	_, more := listquery.Next(parse(t, "sort=id"), page)
+	assert.False(t, more)
+}
+
+func TestMap(t *testing.T) {
+	next := "abc"
+	page := listquery.Page[row]{Items: []row{{ID: 1, Name: "a"}}, Total: 5, NextCursor: &next}
+	names := listquery.Map(page, func(r row) string { return r.Name })
+	assert.Equal(t, listquery.Page[string]{Items: []string{"a"}, Total: 5, NextCursor: &next}, names)
+}
+
+func TestQuery_WhereCopies(t *testing.T) {
+	base := listquery.New(spec, 500)
+	assert.Equal(t, 500, base.Limit)
+
+	a := base.Where(cost, listquery.Eq, 1)
+	b := base.Where(cost, listquery.Eq, 2)
+	assert.Empty(t, base.Conditions)
+	assert.Equal(t, 1, a.Conditions[0].Value)
+	assert.Equal(t, 2, b.Conditions[0].Value)
+}
+
+func TestParseOffset(t *testing.T) {
+	values, err := url.ParseQuery("limit=2&offset=1&sort=cost")
+	require.NoError(t, err)
+	q, err := listquery.ParseOffset(values, spec)
+	require.NoError(t, err)
+	assert.Equal(t, 1, q.Offset)
+
+	rows := []row{{ID: 1, Cost: 3}, {ID: 2, Cost: 1}, {ID: 3, Cost: 2}}
+	page := listquery.Slice(rows, q)
+	assert.Equal(t, []row{{ID: 3, Cost: 2}, {ID: 1, Cost: 3}}, page.Items)
+	assert.Equal(t, int64(3), page.Total)
+
+	values, err = url.ParseQuery("offset=-1")
+	require.NoError(t, err)
+	_, err = listquery.ParseOffset(values, spec)
+	assert.Error(t, err)
+}
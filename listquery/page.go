@@ -0,0 +1,217 @@
+package listquery
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Page adalah envelope response semua endpoint list. Total adalah jumlah
+// baris yang cocok dengan filter (tanpa cursor); NextCursor null jika sudah
+// halaman terakhir.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	Total      int64   `json:"total"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// NewPage membentuk Page dari rows yang diambil dengan limit q.Limit+1.
+// Baris tambahan hanya menandakan masih ada halaman berikutnya.
+func NewPage[T any](rows []T, total int64, q Query) Page[T] {
+	page := Page[T]{Items: make([]T, 0, min(len(rows), q.Limit)), Total: total}
+	if len(rows) > q.Limit {
+		rows = rows[:q.Limit]
+		next := q.cursorOf(rows[len(rows)-1]).encode()
+		page.NextCursor = &next
+	}
+	page.Items = append(page.Items, rows...)
+	return page
+}
+
+// Map mengubah item Page, mis. entity menjadi DTO response.
+func Map[T, R any](page Page[T], fn func(T) R) Page[R] {
+	mapped := Page[R]{Items: make([]R, 0, len(page.Items)), Total: page.Total, NextCursor: page.NextCursor}
+	for _, item := range page.Items {
+		mapped.Items = append(mapped.Items, fn(item))
+	}
+	return mapped
+}
+
+// Next mengembalikan Query untuk halaman setelah page, atau false jika
+// page adalah halaman terakhir.
+func Next[T any](q Query, page Page[T]) (Query, bool) {
+	if page.NextCursor == nil {
+		return q, false
+	}
+	cursor, err := decodeCursor(*page.NextCursor, q.Sort)
+	if err != nil {
+		return q, false
+	}
+	q.After = cursor
+	return q, true
+}
+
+// Slice menjalankan q pada rows di memori dengan hasil yang sama seperti
+// repository database: filter, hitung total, urutkan, lalu ambil halaman
+// setelah cursor dan offset. Field dibaca dari tag json struct baris.
+func Slice[T any](rows []T, q Query) Page[T] {
+	matched := make([]T, 0)
+	for _, row := range rows {
+		if q.matches(row) {
+			matched = append(matched, row)
+		}
+	}
+	total := int64(len(matched))
+
+	slices.SortStableFunc(matched, func(a, b T) int { return q.compare(a, q.cursorOf(b)) })
+	if q.After != nil {
+		start := slices.IndexFunc(matched, func(row T) bool { return q.compare(row, *q.After) > 0 })
+		if start < 0 {
+			start = len(matched)
+		}
+		matched = matched[start:]
+	}
+	matched = matched[min(q.Offset, len(matched)):]
+	return NewPage(matched[:min(len(matched), q.Limit+1)], total, q)
+}
+
+func (q Query) matches(row interface{}) bool {
+	for _, condition := range q.Conditions {
+		c := compareValues(fieldValue(row, condition.Field.Name), condition.Value)
+		var ok bool
+		switch condition.Op {
+		case Eq:
+			ok = c == 0
+		case Gte:
+			ok = c >= 0
+		case Lte:
+			ok = c <= 0
+		case Lt:
+			ok = c < 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compare membandingkan posisi row dengan cursor sesuai arah sort.
+func (q Query) compare(row interface{}, cursor Cursor) int {
+	c := 0
+	if q.Sort.Field != ID {
+		c = compareValues(fieldValue(row, q.Sort.Field.Name), cursor.Value)
+	}
+	if c == 0 {
+		c = cmp.Compare(fieldValue(row, ID.Name).(int64), int64(cursor.ID))
+	}
+	if q.Sort.Desc {
+		return -c
+	}
+	return c
+}
+
+func (q Query) cursorOf(row interface{}) Cursor {
+	cursor := Cursor{Sort: q.Sort.String(), ID: int(fieldValue(row, ID.Name).(int64))}
+	if q.Sort.Field != ID {
+		cursor.Value = fieldValue(row, q.Sort.Field.Name)
+	}
+	return cursor
+}
+
+// fieldValue membaca field struct berdasarkan nama di tag json. Angka
+// bulat dikembalikan sebagai int64 agar bisa dibandingkan langsung.
+func fieldValue(row interface{}, name string) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(row))
+	for i := 0; i < v.NumField(); i++ {
+		if strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0] == name {
+			return normalize(v.Field(i).Interface())
+		}
+	}
+	panic(fmt.Sprintf("listquery: %s has no field %q", v.Type(), name))
+}
+
+func normalize(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	}
+	return value
+}
+
+func compareValues(a, b interface{}) int {
+	switch a := normalize(a).(type) {
+	case int64:
+		return cmp.Compare(a, normalize(b).(int64))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(a, b.(string))
+	}
+	panic(fmt.Sprintf("listquery: cannot compare %T", a))
+}
+
+// Cursor menandai baris terakhir sebuah halaman: nilai kolom sort dan
+// ID-nya. Sort disimpan agar cursor tidak dipakai dengan urutan lain.
+type Cursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v,omitempty"`
+	ID    int         `json:"id"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+func (c Cursor) encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		panic(fmt.Sprintf("listquery: encode cursor: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor membaca cursor opaque dan mengembalikan nilainya dengan tipe
+// sesuai Kind field sort.
+func decodeCursor(raw string, sort Sort) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var encoded struct {
+		Sort  string          `json:"s"`
+		Value json.RawMessage `json:"v"`
+		ID    int             `json:"id"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil || encoded.Sort != sort.String() {
+		return nil, errInvalidCursor
+	}
+
+	cursor := &Cursor{Sort: encoded.Sort, ID: encoded.ID}
+	if sort.Field == ID {
+		return cursor, nil
+	}
+	switch sort.Field.Kind {
+	case Int:
+		var value int64
+		err = json.Unmarshal(encoded.Value, &value)
+		cursor.Value = value
+	case Time:
+		var value time.Time
+		err = json.Unmarshal(encoded.Value, &value)
+		cursor.Value = value
+	default:
+		var value string
+		err = json.Unmarshal(encoded.Value, &value)
+		cursor.Value = value
+	}
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SlowQueryThreshold adalah batas durasi query yang dicatat sebagai slow query.
+const SlowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger meneruskan log GORM ke slog. Query yang gagal dicatat sebagai
+// error dan query lambat sebagai warning; semua query lain hanya dicatat
+// pada level debug. Karena GORM memakai context dari db.WithContext, request
+// ID dan user dari request ikut tercatat di log repository. SQL dicatat
+// tanpa nilai parameter (lihat ParamsFilter).
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+func NewGormLogger() GormLogger {
+	return GormLogger{SlowThreshold: SlowQueryThreshold}
+}
+
+// LogMode diabaikan; level log diatur oleh logger slog.
+func (l GormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	slog.InfoContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+}
+
+func (l GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	slog.WarnContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+}
+
+func (l GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	slog.ErrorContext(ctx, fmt.Sprintf(msg, args...), "component", "gorm")
+}
+
+// ParamsFilter membuang nilai parameter query sebelum SQL dirangkai untuk
+// Trace, sehingga log hanya berisi template statement dengan placeholder dan
+// tidak memuat email, hash password atau data lain dari query.
+func (l GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	level := slog.LevelDebug
+	msg := "query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, context.Canceled):
+		level, msg = slog.LevelError, "query failed"
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		level, msg = slog.LevelWarn, "slow query"
+	}
+
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("component", "gorm"),
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	if level == slog.LevelError {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,93 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+func TestNew_AddsContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", logging.FormatJSON)
+	require.NoError(t, err)
+
+	ctx := logging.With(context.Background(), "request_id", "abc-123")
+	ctx = logging.With(ctx, "user_id", 7, "role", "admin")
+	logger.InfoContext(ctx, "booking created", "booking_id", 42)
+	logger.DebugContext(ctx, "not written")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "booking created", line["msg"])
+	assert.Equal(t, "abc-123", line["request_id"])
+	assert.EqualValues(t, 7, line["user_id"])
+	assert.Equal(t, "admin", line["role"])
+	assert.EqualValues(t, 42, line["booking_id"])
+
+	// Context induk tidak ikut berubah
+	assert.Len(t, logging.Attrs(logging.With(context.Background(), "a", 1)), 1)
+}
+
+func TestNew_AddsTraceContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", logging.FormatJSON)
+	require.NoError(t, err)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	logger.InfoContext(ctx, "payment paid")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", line["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", line["span_id"])
+}
+
+func TestNew_RejectsUnknownSettings(t *testing.T) {
+	_, err := logging.New(&bytes.Buffer{}, "verbose", logging.FormatJSON)
+	assert.ErrorContains(t, err, "unknown log level")
+
+	_, err = logging.New(&bytes.Buffer{}, "info", "xml")
+	assert.ErrorContains(t, err, "unknown log format")
+
+	level, err := logging.ParseLevel("warn")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+}
+
+func TestGormLogger_OmitsQueryParameters(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "debug", logging.FormatJSON)
+	require.NoError(t, err)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logging.NewGormLogger()})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("CREATE TABLE users (email text, password text)").Error)
+	buf.Reset()
+
+	require.NoError(t, db.Exec("INSERT INTO users (email, password) VALUES (?, ?)", "budi@example.com", "$2a$10$hash").Error)
+	require.Error(t, db.Exec("INSERT INTO missing (email) VALUES (?)", "citra@example.com").Error)
+
+	logs := buf.String()
+	assert.Contains(t, logs, "INSERT INTO users (email, password) VALUES (?, ?)")
+	assert.Contains(t, logs, "query failed")
+	assert.NotContains(t, logs, "budi@example.com")
+	assert.NotContains(t, logs, "$2a$10$hash")
+	assert.NotContains(t, logs, "citra@example.com")
+}
@@ -0,0 +1,125 @@
+// Package metrics mendefinisikan metrik Prometheus aplikasi: latency HTTP per
+// route, durasi query GORM, statistik pool koneksi database, serta metrik
+// bisnis (booking, payment, review dan technician aktif) yang diperbarui
+// oleh service.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "capstone"
+
+// Registry menampung semua metrik aplikasi. Registry sendiri dipakai (bukan
+// prometheus.DefaultRegisterer) agar /metrics hanya berisi metrik yang
+// didaftarkan di sini.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of GORM operations by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	BookingsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bookings_created_total",
+		Help:      "Bookings created, by initial status.",
+	}, []string{"status"})
+
+	BookingStatusChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "booking_status_changes_total",
+		Help:      "Booking status changes, by new status.",
+	}, []string{"status"})
+
+	PaymentsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payments_created_total",
+		Help:      "Payments created, by initial status.",
+	}, []string{"status"})
+
+	PaymentStatusChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payment_status_changes_total",
+		Help:      "Payment status changes, by new status.",
+	}, []string{"status"})
+
+	PaymentAmount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payment_amount_rupiah_total",
+		Help:      "Sum of payment amounts in rupiah, by the status the payments entered.",
+	}, []string{"status"})
+
+	ReviewsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_created_total",
+		Help:      "Reviews created, by rating.",
+	}, []string{"rating"})
+
+	ActiveTechnicians = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "technicians_active",
+		Help:      "Number of users with the technician role.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		DBQueryDuration,
+		BookingsCreated,
+		BookingStatusChanges,
+		PaymentsCreated,
+		PaymentStatusChanges,
+		PaymentAmount,
+		ReviewsCreated,
+		ActiveTechnicians,
+	)
+}
+
+// Handler mengembalikan handler HTTP untuk endpoint /metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// AddPaymentAmount menambahkan jumlah payment yang masuk ke status tertentu.
+// Amount disimpan sebagai teks; nilai yang tidak valid diabaikan.
+func AddPaymentAmount(status, amount string) {
+	if value, err := strconv.ParseFloat(amount, 64); err == nil && value > 0 {
+		PaymentAmount.WithLabelValues(status).Add(value)
+	}
+}
+
+// RoleCounter dipenuhi oleh repository.UserRepository.
+type RoleCounter interface {
+	GetUserRoleDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error)
+}
+
+// RefreshActiveTechnicians menghitung ulang jumlah technician dari database.
+// Dipanggil saat start dan setiap kali role technician diberikan atau dicabut.
+func RefreshActiveTechnicians(ctx context.Context, users RoleCounter) error {
+	distribution, err := users.GetUserRoleDistribution(ctx, "", "")
+	if err != nil {
+		return err
+	}
+	ActiveTechnicians.Set(float64(distribution["technician"]))
+	return nil
+}
@@ -1,27 +1,50 @@
 package middleware
 
 import (
-	"net/http"
+	"crypto/subtle"
 	"strings"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
 	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// JWTAuth hanya menerima token dari header Authorization. Token di URL
+// mudah bocor lewat log proxy, access log dan header Referer.
 func JWTAuth() gin.HandlerFunc {
+	return authenticate(false)
+}
+
+// StreamAuth seperti JWTAuth, tetapi juga menerima token lewat query
+// parameter access_token karena EventSource di browser dan sebagian client
+// WebSocket tidak bisa mengirim header. Hanya dipakai di route streaming.
+func StreamAuth() gin.HandlerFunc {
+	return authenticate(true)
+}
+
+func authenticate(allowQueryToken bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if allowQueryToken && authHeader == "" && c.Query("access_token") != "" {
+			authHeader = "Bearer " + c.Query("access_token")
+		}
+
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			c.Abort()
+			abortWithError(c, apperror.Unauthorized("missing_token", "Authorization header is required"))
+			return
+		}
+
+		parts := strings.SplitN(authHeader, " ", 2) // Format: Bearer <token>
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			abortWithError(c, apperror.Unauthorized("invalid_auth_scheme", "Authorization header must use the Bearer scheme"))
 			return
 		}
 
-		tokenString := strings.Split(authHeader, " ")[1] // Format: Bearer <token>
+		tokenString := parts[1]
 		claims, err := utils.ValidateJWT(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			abortWithError(c, apperror.Unauthorized("invalid_token", "Invalid or expired token"))
 			return
 		}
 
@@ -29,6 +52,23 @@ func JWTAuth() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("role", claims.Role)
 
+		// User dan role ikut tercatat di log service dan repository
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "user_id", claims.UserID, "role", claims.Role))
+
+		c.Next()
+	}
+}
+
+// BearerToken hanya meneruskan request dengan header "Authorization: Bearer
+// <token>" yang sama persis dengan token. Dipakai untuk endpoint internal
+// seperti /metrics yang diakses mesin, bukan user.
+func BearerToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			abortWithError(c, apperror.Unauthorized("invalid_token", "Invalid or missing token"))
+			return
+		}
 		c.Next()
 	}
 }
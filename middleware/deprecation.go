@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation berisi informasi versi API yang sudah usang.
+type Deprecation struct {
+	Since     time.Time                // tanggal versi dinyatakan usang
+	Sunset    time.Time                // tanggal versi dihapus, kosong jika belum ditentukan
+	Successor func(path string) string // path pengganti untuk path request, boleh nil
+}
+
+// Deprecated menandai response dari route yang sudah usang dengan header
+// Deprecation (RFC 9745), Sunset (RFC 8594) dan Link rel="successor-version"
+// yang menunjuk ke route penggantinya. Header ditulis sebelum handler
+// berjalan sehingga juga ada di response error.
+func Deprecated(d Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+		if !d.Sunset.IsZero() {
+			c.Header("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Successor != nil {
+			c.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor(c.Request.URL.Path)))
+		}
+		c.Next()
+	}
+}
+
+const legacyListsKey = "legacy_lists"
+
+// LegacyLists menandai request ke route alias tanpa versi agar endpoint list
+// memakai kontrak sebelum /api/v1: offset diterima dan response berupa array
+// tanpa envelope. Dibaca controller lewat IsLegacyList.
+func LegacyLists() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(legacyListsKey, true)
+		c.Next()
+	}
+}
+
+// IsLegacyList melaporkan apakah request melewati LegacyLists.
+func IsLegacyList(c *gin.Context) bool {
+	return c.GetBool(legacyListsKey)
+}
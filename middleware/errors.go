@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler mengubah error yang dicatat handler dengan c.Error menjadi
+// response application/problem+json (RFC 7807). Handler cukup memanggil
+// c.Error(err) lalu return: status dan kode diambil dari error domain
+// (apperror), sedangkan error lain dijawab 500 tanpa membocorkan pesannya.
+// Jika handler sudah menulis response, error hanya dicatat di log.
+//
+// Pasang setelah Logger dan Metrics agar status yang dicatat adalah status
+// response error.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// abortWithError dipakai middleware lain untuk menolak request: error
+// dicatat, response problem ditulis langsung dan handler berikutnya tidak
+// dijalankan.
+func abortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	writeProblem(c, err)
+	c.Abort()
+}
+
+func writeProblem(c *gin.Context, err error) {
+	appErr := apperror.From(err)
+
+	// Error validasi dari binding dijawab dengan daftar field yang tidak valid
+	if fields := validation.Fields(err, c.GetHeader("Accept-Language")); len(fields) > 0 {
+		appErr = validation.ErrValidationFailed.WithFields(fields).Wrap(err)
+	}
+
+	problem := appErr.Problem(c.Request.URL.Path)
+	problem.RequestID = c.GetString("request_id")
+
+	c.Header("Content-Type", apperror.ProblemContentType)
+	c.JSON(problem.Status, problem)
+}
@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs mengganti logger bawaan slog selama test dan mengembalikan
+// buffer berisi log JSON yang ditulis.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "debug", logging.FormatJSON)
+	require.NoError(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(raw), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Logger(), middleware.Recovery())
+	router.GET("/bookings/:id", middleware.JWTAuth(), func(c *gin.Context) {
+		// Log dari lapisan service/repository memakai context request
+		slog.InfoContext(c.Request.Context(), "loading booking")
+		err := errors.New("booking not found")
+		c.Error(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	})
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	return router
+}
+
+func TestRequestID_AcceptsOrGeneratesID(t *testing.T) {
+	captureLogs(t)
+	router := newRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(middleware.RequestIDHeader, "trace-42")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, "trace-42", rec.Header().Get(middleware.RequestIDHeader))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"internal server error","instance":"/panic","code":"internal_error","request_id":"trace-42"}`, rec.Body.String())
+
+	// ID yang terlalu panjang atau berisi karakter aneh diganti dengan ID baru
+	for _, id := range []string{"", "bad id\n", strings.Repeat("a", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		req.Header.Set(middleware.RequestIDHeader, id)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Regexp(t, `^[0-9a-f]{32}$`, rec.Header().Get(middleware.RequestIDHeader))
+	}
+}
+
+func TestLogger_CorrelatesRequestAndUser(t *testing.T) {
+	buf := captureLogs(t)
+	utils.ConfigureJWT("0123456789abcdef0123456789abcdef", time.Hour)
+	token, err := utils.GenerateJWT(5, "technician")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/bookings/9", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	newRouter().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, "req-1", line["request_id"])
+		assert.EqualValues(t, 5, line["user_id"])
+		assert.Equal(t, "technician", line["role"])
+	}
+
+	access := lines[1]
+	assert.Equal(t, "request", access["msg"])
+	assert.Equal(t, "WARN", access["level"])
+	assert.Equal(t, "/bookings/:id", access["route"])
+	assert.EqualValues(t, http.StatusNotFound, access["status"])
+	assert.Contains(t, access, "latency_ms")
+	assert.Equal(t, []interface{}{"booking not found"}, access["errors"])
+}
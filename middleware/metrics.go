@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics mencatat latency dan status code setiap request ke histogram
+// Prometheus. Label route memakai pola route (mis. /bookings/:id), bukan path
+// asli, agar jumlah seri tetap terbatas; request ke route yang tidak
+// terdaftar dicatat dengan route "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequestDuration.
+			WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
+	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxAccountBody membatasi body yang dibaca AccountFromJSON.
+const maxAccountBody = 1 << 20
+
+// RateLimit membatasi request per alamat IP dan per akun dengan token bucket
+// di store. name memisahkan bucket antar grup route, dan account mengambil
+// kunci akun dari request (kosong berarti hanya batas IP yang berlaku).
+//
+// Setiap response membawa X-RateLimit-Limit, X-RateLimit-Remaining dan
+// X-RateLimit-Reset (detik sampai bucket penuh) dari bucket yang paling
+// ketat. Request yang melebihi batas ditolak dengan 429 dan Retry-After.
+// Jika store gagal, request tetap dilayani agar gangguan store tidak
+// menghentikan API. Store nil menonaktifkan rate limit.
+func RateLimit(store ratelimit.Store, name string, policy ratelimit.Policy, account func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		var results []ratelimit.Result
+		take := func(key string, limit ratelimit.Limit) {
+			if !limit.Enabled() {
+				return
+			}
+			result, err := store.Take(ctx, name+":"+key, limit)
+			if err != nil {
+				slog.WarnContext(ctx, "rate limit store failed", "limiter", name, "error", err)
+				return
+			}
+			results = append(results, result)
+		}
+
+		take("ip:"+c.ClientIP(), policy.IP)
+		if account != nil && policy.Account.Enabled() {
+			if key := account(c); key != "" {
+				take("account:"+key, policy.Account)
+			}
+		}
+		if len(results) == 0 {
+			c.Next()
+			return
+		}
+
+		result := strictest(results)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ratelimit.RetryAfterSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(result.RetryAfter)))
+			abortWithError(c, apperror.TooManyRequests("rate_limited", "Too many requests, please try again later"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// strictest memilih bucket yang menolak request dengan waktu tunggu terlama,
+// atau jika semua mengizinkan, bucket dengan sisa token paling sedikit.
+func strictest(results []ratelimit.Result) ratelimit.Result {
+	best := results[0]
+	for _, r := range results[1:] {
+		switch {
+		case best.Allowed && !r.Allowed:
+			best = r
+		case !best.Allowed && !r.Allowed && r.RetryAfter > best.RetryAfter:
+			best = r
+		case best.Allowed && r.Allowed && r.Remaining < best.Remaining:
+			best = r
+		}
+	}
+	return best
+}
+
+// AccountFromJSON mengambil akun dari field body JSON, mis. "email" pada
+// /login. Body dikembalikan utuh sehingga handler tetap bisa membacanya.
+func AccountFromJSON(field string) func(c *gin.Context) string {
+	return func(c *gin.Context) string {
+		if c.Request.Body == nil {
+			return ""
+		}
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAccountBody))
+		c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+		if err != nil {
+			return ""
+		}
+
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return ""
+		}
+		value, _ := fields[field].(string)
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+}
+
+// AccountFromToken mengambil user dari JWT di header Authorization (atau
+// query access_token). Token yang tidak valid diabaikan; JWTAuth yang akan
+// menolaknya.
+func AccountFromToken(c *gin.Context) string {
+	token := c.Query("access_token")
+	if scheme, value, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = value
+	}
+	if token == "" {
+		return ""
+	}
+
+	claims, err := utils.ValidateJWT(token)
+	if err != nil {
+		return ""
+	}
+	return "user:" + strconv.Itoa(claims.UserID)
+}
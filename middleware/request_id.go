@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader adalah header yang membawa request ID dari client atau
+// proxy, dan dikembalikan di response.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID menerima X-Request-ID dari client atau membuat ID baru jika
+// kosong atau tidak valid. ID disimpan di gin context ("request_id"),
+// dikirim balik di header response, dan ditambahkan ke context request
+// sehingga ikut tercatat di semua log selama request diproses.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.With(c.Request.Context(), "request_id", id))
+		c.Next()
+	}
+}
+
+// validRequestID hanya menerima huruf, angka, '-', '_' dan '.' agar ID dari
+// luar tidak bisa menyisipkan karakter aneh ke log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -1,8 +1,7 @@
 package middleware
 
 import (
-	"net/http"
-
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -10,8 +9,7 @@ func RoleAuth(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Role not found in token"})
-			c.Abort()
+			abortWithError(c, apperror.Unauthorized("missing_role", "Role not found in token"))
 			return
 		}
 
@@ -23,7 +21,6 @@ func RoleAuth(allowedRoles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this resource"})
-		c.Abort()
+		abortWithError(c, apperror.Forbidden("insufficient_role", "You don't have permission to access this resource"))
 	}
 }
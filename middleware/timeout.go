@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryTimeout membatasi waktu eksekusi query database untuk setiap request.
+// Context request diteruskan sampai ke repository (db.WithContext), sehingga
+// query dibatalkan jika melewati batas waktu atau client menutup koneksi.
+// Route streaming (SSE dan WebSocket) tidak dibatasi karena berumur panjang.
+func QueryTimeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || isStreamingRequest(c) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// streamRoutes adalah route streaming yang terdaftar, tanpa prefix versi agar
+// berlaku untuk /api/v1 maupun alias legacy.
+var streamRoutes = []string{
+	"/bookings/:id/messages/stream",
+	"/events/stream",
+	"/events/ws",
+}
+
+// isStreamingRequest hanya melihat route yang cocok (c.FullPath), bukan header
+// seperti Accept atau Upgrade, agar client tidak bisa melewati batas waktu
+// pada endpoint biasa.
+func isStreamingRequest(c *gin.Context) bool {
+	path := c.FullPath()
+	for _, route := range streamRoutes {
+		if strings.HasSuffix(path, route) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTimeout_ExemptsOnlyStreamRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.QueryTimeout(time.Second))
+	hasDeadline := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, ok)
+	}
+	router.GET("/api/v1/bookings", hasDeadline)
+	router.GET("/api/v1/events/stream", hasDeadline)
+	router.GET("/events/ws", hasDeadline)
+
+	tests := []struct {
+		path     string
+		header   string
+		value    string
+		deadline string
+	}{
+		{path: "/api/v1/bookings", deadline: "true"},
+		// Header dari client tidak boleh mematikan batas waktu
+		{path: "/api/v1/bookings", header: "Accept", value: "text/event-stream", deadline: "true"},
+		{path: "/api/v1/bookings", header: "Upgrade", value: "websocket", deadline: "true"},
+		{path: "/api/v1/events/stream", deadline: "false"},
+		{path: "/events/ws", deadline: "false"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, tt.deadline, rec.Body.String(), "%s %s=%s", tt.path, tt.header, tt.value)
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Tracing membuat span server untuk setiap request, melanjutkan trace dari
+// header traceparent jika ada. Span dinamai dengan route Gin sehingga
+// request ke /bookings/1 dan /bookings/2 dikelompokkan bersama. /ping,
+// /healthz, /readyz dan /metrics tidak ditrace karena dipanggil
+// terus-menerus oleh monitoring.
+func Tracing(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		switch c.FullPath() {
+		case "/ping", "/healthz", "/readyz", "/metrics":
+			return false
+		}
+		return true
+	}))
+}
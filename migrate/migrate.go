@@ -0,0 +1,474 @@
+package migrate
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations
+var embedded embed.FS
+
+// Dir adalah lokasi file migrasi di source tree, dipakai oleh Create. Setiap
+// dialek memiliki subdirektori sendiri dengan nomor versi yang sama.
+const Dir = "migrate/migrations"
+
+// Dialects adalah nama dialek GORM yang memiliki migrasi.
+var Dialects = []string{"mysql", "postgres", "sqlite"}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration adalah satu perubahan skema bernomor beserta SQL untuk membatalkannya.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status menunjukkan apakah sebuah migrasi sudah dijalankan.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator menjalankan migrasi dan mencatatnya di tabel schema_migrations.
+// Hanya satu proses yang boleh menjalankan migrasi dalam satu waktu; proses
+// lain menunggu lock di tabel schema_migration_lock.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	owner      string
+
+	lockTimeout time.Duration // Lock yang lebih tua dari ini dianggap milik proses yang mati
+	lockWait    time.Duration // Batas waktu menunggu lock
+	heartbeat   time.Duration // Interval memperbarui locked_at selama migrasi berjalan
+	now         func() time.Time
+}
+
+// New membuat Migrator dengan migrasi yang di-embed di binary untuk dialek db.
+func New(db *gorm.DB) (*Migrator, error) {
+	sub, err := Embedded(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return NewFromFS(db, sub)
+}
+
+// Embedded mengembalikan file migrasi yang di-embed untuk dialek tertentu.
+func Embedded(dialect string) (fs.FS, error) {
+	for _, d := range Dialects {
+		if d == dialect {
+			return fs.Sub(embedded, "migrations/"+dialect)
+		}
+	}
+	return nil, fmt.Errorf("no migrations for database dialect %q", dialect)
+}
+
+// NewFromFS membuat Migrator dengan migrasi dari fsys.
+func NewFromFS(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		db:          db,
+		migrations:  migrations,
+		owner:       fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		lockTimeout: 15 * time.Minute,
+		lockWait:    5 * time.Minute,
+		heartbeat:   time.Minute,
+		now:         time.Now,
+	}, nil
+}
+
+// Load membaca pasangan file <versi>_<nama>.up.sql dan .down.sql dari fsys,
+// diurutkan berdasarkan versi.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s (expected 0001_name.up.sql)", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up menjalankan semua migrasi yang belum dijalankan dan mengembalikan
+// migrasi yang baru diterapkan.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		done, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, migration, migration.Up, true); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down membatalkan steps migrasi terakhir, dimulai dari versi tertinggi.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(ctx context.Context) error {
+		done, err := m.appliedVersions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("migration %04d_%s has no down script", migration.Version, migration.Name)
+			}
+			if err := m.apply(ctx, migration, migration.Down, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status mengembalikan semua migrasi beserta waktu dijalankannya.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := done[migration.Version]; ok {
+			appliedAt := appliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check mengembalikan error jika masih ada migrasi yang belum dijalankan,
+// mis. replica versi baru start sebelum migrasi selesai. Tabel tidak dibuat
+// di sini, jadi database yang belum pernah dimigrasi juga dianggap belum siap.
+func (m *Migrator) Check(ctx context.Context) error {
+	done, err := m.appliedVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, ok := done[migration.Version]; !ok {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d pending migrations", pending)
+	}
+	return nil
+}
+
+// apply menjalankan script migrasi lalu mencatat (atau menghapus) versinya.
+// PostgreSQL dan SQLite me-rollback DDL yang gagal, tetapi pada MySQL perintah
+// DDL tidak bisa di-rollback, jadi migrasi yang gagal di tengah jalan harus
+// diperbaiki secara manual.
+func (m *Migrator) apply(ctx context.Context, migration Migration, script string, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+	slog.InfoContext(ctx, "migrate: applying migration", "direction", direction, "version", migration.Version, "name", migration.Name)
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, statement := range SplitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("migration %04d_%s (%s) failed: %w", migration.Version, migration.Name, direction, err)
+			}
+		}
+
+		if up {
+			return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+				migration.Version, migration.Name, m.now().UTC()).Error
+		}
+		return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+	})
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]time.Time, error) {
+	var rows []struct {
+		Version   int
+		AppliedAt time.Time
+	}
+	err := m.db.WithContext(ctx).Raw("SELECT version, applied_at FROM schema_migrations").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		done[row.Version] = row.AppliedAt
+	}
+	return done, nil
+}
+
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL
+	)`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migration_lock (
+		id INT NOT NULL PRIMARY KEY,
+		locked_by VARCHAR(100) NOT NULL,
+		locked_at TIMESTAMP NOT NULL
+	)`).Error
+}
+
+// errLockLost dikembalikan jika lock migrasi diambil alih proses lain selama
+// migrasi berjalan.
+var errLockLost = errors.New("lost the migration lock to another process")
+
+// withLock menjalankan fn setelah mendapatkan lock migrasi. Lock berupa satu
+// baris di schema_migration_lock sehingga bekerja di semua replica. Selama fn
+// berjalan locked_at diperbarui setiap heartbeat agar migrasi yang lama tidak
+// dianggap milik proses yang mati; jika lock tetap hilang, ctx untuk fn
+// dibatalkan.
+func (m *Migrator) withLock(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		// Lock tetap dilepas walaupun ctx sudah dibatalkan
+		err := m.db.WithContext(context.WithoutCancel(ctx)).
+			Exec("DELETE FROM schema_migration_lock WHERE id = 1 AND locked_by = ?", m.owner).Error
+		if err != nil {
+			slog.ErrorContext(ctx, "migrate: failed to release lock", "error", err)
+		}
+	}()
+
+	lockCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		m.keepLock(lockCtx, cancel, stop)
+	}()
+
+	err := fn(lockCtx)
+	close(stop)
+	<-stopped
+	if cause := context.Cause(lockCtx); errors.Is(cause, errLockLost) {
+		return cause
+	}
+	return err
+}
+
+// keepLock memperbarui locked_at setiap heartbeat sampai stop ditutup, dan
+// membatalkan ctx dengan errLockLost jika baris lock bukan milik proses ini lagi.
+func (m *Migrator) keepLock(ctx context.Context, cancel context.CancelCauseFunc, stop <-chan struct{}) {
+	ticker := time.NewTicker(m.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		result := m.db.WithContext(ctx).
+			Exec("UPDATE schema_migration_lock SET locked_at = ? WHERE id = 1 AND locked_by = ?", m.now().UTC(), m.owner)
+		if result.Error != nil {
+			// Gagal sesaat tidak melepas lock; dicoba lagi pada heartbeat berikutnya
+			slog.WarnContext(ctx, "migrate: failed to refresh lock", "error", result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			slog.ErrorContext(ctx, "migrate: migration lock was taken over by another process")
+			cancel(errLockLost)
+			return
+		}
+	}
+}
+
+func (m *Migrator) acquireLock(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	deadline := m.now().Add(m.lockWait)
+
+	for {
+		now := m.now().UTC()
+
+		// Lock yang ditinggalkan proses yang mati diambil alih setelah lockTimeout
+		if err := db.Exec("DELETE FROM schema_migration_lock WHERE id = 1 AND locked_at < ?", now.Add(-m.lockTimeout)).Error; err != nil {
+			return err
+		}
+
+		// Hanya satu proses yang berhasil menyisipkan baris dengan id 1; proses
+		// lain mendapat error primary key ganda
+		err := db.Exec("INSERT INTO schema_migration_lock (id, locked_by, locked_at) VALUES (1, ?, ?)", m.owner, now).Error
+		if err == nil {
+			return nil
+		}
+		if !isDuplicate(err) {
+			return err
+		}
+
+		if m.now().After(deadline) {
+			return errors.New("timed out waiting for the migration lock; another process is migrating")
+		}
+		slog.InfoContext(ctx, "migrate: waiting for another process to finish migrating")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// isDuplicate mengenali error primary key ganda saat dua proses berebut lock.
+func isDuplicate(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "duplicate") || strings.Contains(msg, "unique")
+}
+
+// SplitStatements memecah script SQL menjadi perintah-perintah terpisah
+// (dipisahkan ";" di akhir baris) dan membuang baris komentar "--".
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, statement)
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// Create membuat pasangan file migrasi baru di dir dengan versi berikutnya.
+func Create(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", errors.New("migration name is required")
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	version := 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%04d_%s", version, name)
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(up, []byte("-- "+base+": tulis perubahan skema di sini\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- "+base+": batalkan perubahan dari file .up.sql\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
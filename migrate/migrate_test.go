@@ -0,0 +1,287 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLoad_SortsAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.up.sql":        {Data: []byte("CREATE INDEX idx ON t (c);")},
+		"0002_add_index.down.sql":      {Data: []byte("DROP INDEX idx ON t;")},
+		"0001_initial_schema.up.sql":   {Data: []byte("CREATE TABLE t (c int);")},
+		"0001_initial_schema.down.sql": {Data: []byte("DROP TABLE t;")},
+		"README.md":                    {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "initial_schema", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE t;", migrations[0].Down)
+	assert.Equal(t, 2, migrations[1].Version)
+}
+
+func TestLoad_RejectsInvalidSets(t *testing.T) {
+	_, err := Load(fstest.MapFS{"0001_only_down.down.sql": {Data: []byte("DROP TABLE t;")}})
+	assert.ErrorContains(t, err, "has no up script")
+
+	_, err = Load(fstest.MapFS{
+		"0001_first.up.sql":  {Data: []byte("SELECT 1;")},
+		"0001_second.up.sql": {Data: []byte("SELECT 2;")},
+	})
+	assert.ErrorContains(t, err, "is used by both")
+
+	_, err = Load(fstest.MapFS{"create_table.sql": {Data: []byte("SELECT 1;")}})
+	assert.ErrorContains(t, err, "invalid migration file name")
+}
+
+func TestEmbeddedMigrationsAreReversible(t *testing.T) {
+	for _, dialect := range Dialects {
+		fsys, err := Embedded(dialect)
+		require.NoError(t, err)
+		migrations, err := Load(fsys)
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations, dialect)
+
+		for i, m := range migrations {
+			assert.Equal(t, i+1, m.Version, "%s: migration versions must be consecutive", dialect)
+			assert.NotEmpty(t, SplitStatements(m.Down), "%s: migration %04d_%s has no down script", dialect, m.Version, m.Name)
+		}
+	}
+}
+
+func TestEmbeddedMigrationsMatchAcrossDialects(t *testing.T) {
+	names := func(dialect string) []string {
+		fsys, err := Embedded(dialect)
+		require.NoError(t, err)
+		migrations, err := Load(fsys)
+		require.NoError(t, err)
+
+		var names []string
+		for _, m := range migrations {
+			names = append(names, fmt.Sprintf("%04d_%s", m.Version, m.Name))
+		}
+		return names
+	}
+
+	mysql := names("mysql")
+	for _, dialect := range Dialects[1:] {
+		assert.Equal(t, mysql, names(dialect), "%s migrations differ from mysql", dialect)
+	}
+
+	_, err := Embedded("oracle")
+	assert.ErrorContains(t, err, "no migrations for database dialect")
+}
+
+func TestUpAndDownOnSQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_pragma=foreign_keys(1)"), &gorm.Config{})
+	require.NoError(t, err)
+	migrator, err := New(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+	assert.ErrorContains(t, migrator.Check(ctx), "failed to read applied migrations")
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrator.migrations))
+	assert.True(t, db.Migrator().HasTable("bookings"))
+	assert.NoError(t, migrator.Check(ctx))
+
+	// Role di luar daftar ditolak oleh CHECK constraint pengganti ENUM
+	err = db.Exec("INSERT INTO users (name, role) VALUES ('x', 'superuser')").Error
+	assert.ErrorContains(t, err, "CHECK constraint failed")
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	_, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.EqualError(t, migrator.Check(ctx), "1 pending migrations")
+
+	_, err = migrator.Down(ctx, len(migrator.migrations))
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("bookings"))
+}
+
+// Struct di bawah adalah entity sebelum migrasi dipakai, saat skema dibuat
+// dengan AutoMigrate. Disalin agar tidak ikut berubah bersama package
+// entity; hanya tipe ENUM pada role yang dihapus karena SQLite tidak
+// mengenalnya.
+type (
+	baselineUser struct {
+		ID           int `gorm:"primaryKey;autoIncrement"`
+		Name         string
+		Email        string
+		Password     string
+		Role         string `gorm:"default:'user'"`
+		Address      string
+		Phone        string
+		Expertise    string
+		Availability string
+		CreatedAt    time.Time
+		UpdatedAt    time.Time
+	}
+	baselineService struct {
+		ID          int `gorm:"primaryKey;autoIncrement"`
+		UserID      int
+		Name        string
+		Description string
+		Cost        int
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		User        baselineUser `gorm:"foreignKey:UserID"`
+	}
+	baselineBooking struct {
+		ID          int       `gorm:"primaryKey;autoIncrement"`
+		UserID      int       `gorm:"not null"`
+		ServiceID   int       `gorm:"not null"`
+		Date        time.Time `gorm:"type:date"`
+		Status      string
+		Description string
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+		User        baselineUser    `gorm:"foreignKey:UserID"`
+		Service     baselineService `gorm:"foreignKey:ServiceID"`
+	}
+	baselinePayment struct {
+		ID        int `gorm:"primaryKey;autoIncrement"`
+		BookingID int `gorm:"not null"`
+		Amount    string
+		Status    string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		Booking   baselineBooking `gorm:"foreignKey:BookingID"`
+	}
+	baselineReview struct {
+		ID        int `gorm:"primaryKey;autoIncrement"`
+		BookingID int `gorm:"not null"`
+		Rating    int
+		Comment   string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		Booking   baselineBooking `gorm:"foreignKey:BookingID"`
+	}
+)
+
+func (baselineUser) TableName() string    { return "users" }
+func (baselineService) TableName() string { return "services" }
+func (baselineBooking) TableName() string { return "bookings" }
+func (baselinePayment) TableName() string { return "payments" }
+func (baselineReview) TableName() string  { return "reviews" }
+
+// Database yang dibuat AutoMigrate lama diadopsi oleh 0001 lalu dilengkapi
+// migrasi berikutnya, termasuk kolom baru di tabel yang sudah ada.
+func TestUpAdoptsBaselineAutoMigrate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_pragma=foreign_keys(1)"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&baselineUser{}, &baselineService{}, &baselineBooking{}, &baselinePayment{}, &baselineReview{}))
+	existing := baselineUser{Name: "Lama", Email: "lama@example.com", Role: "technician"}
+	require.NoError(t, db.Create(&existing).Error)
+
+	migrator, err := New(db)
+	require.NoError(t, err)
+	ctx := context.Background()
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Check(ctx))
+
+	// Kolom language ada dan baris lama mendapat nilai default
+	var language string
+	require.NoError(t, db.Raw("SELECT language FROM users WHERE id = ?", existing.ID).Scan(&language).Error)
+	assert.Equal(t, "id", language)
+	user := entity.User{Name: "Baru", Email: "baru@example.com", Role: "user", Language: "en"}
+	require.NoError(t, db.Create(&user).Error)
+	for _, table := range []string{"technician_applications", "messages", "notification_preferences", "jobs", "outbox_events", "webhook_deliveries"} {
+		assert.True(t, db.Migrator().HasTable(table), table)
+	}
+
+	// Technician lama mendapat pengajuan Approved agar tetap bisa membuat service
+	var application entity.TechnicianApplication
+	require.NoError(t, db.Where("user_id = ?", existing.ID).First(&application).Error)
+	assert.Equal(t, "Approved", application.Status)
+	assert.True(t, application.CertificationExpiresAt.After(time.Now().AddDate(0, 11, 0)))
+
+	_, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, db.Model(&entity.TechnicianApplication{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+// Selama migrasi berjalan locked_at terus diperbarui sehingga proses lain
+// tidak mengambil alih lock; jika lock tetap hilang, migrasi dibatalkan.
+func TestWithLock_HeartbeatKeepsLock(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")+"?_pragma=busy_timeout(5000)"), &gorm.Config{})
+	require.NoError(t, err)
+	newMigrator := func(owner string) *Migrator {
+		m, err := New(db)
+		require.NoError(t, err)
+		m.owner, m.lockTimeout, m.lockWait, m.heartbeat = owner, 50*time.Millisecond, 0, 10*time.Millisecond
+		return m
+	}
+	first, second := newMigrator("first"), newMigrator("second")
+	ctx := context.Background()
+
+	err = first.withLock(ctx, func(ctx context.Context) error {
+		time.Sleep(150 * time.Millisecond)
+		assert.ErrorContains(t, second.acquireLock(ctx), "timed out waiting for the migration lock")
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = first.withLock(ctx, func(ctx context.Context) error {
+		// Proses lain mengambil alih baris lock
+		require.NoError(t, db.Exec("UPDATE schema_migration_lock SET locked_by = ? WHERE id = 1", "second").Error)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, errLockLost)
+	var owner string
+	require.NoError(t, db.Raw("SELECT locked_by FROM schema_migration_lock WHERE id = 1").Scan(&owner).Error)
+	assert.Equal(t, "second", owner)
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- komentar
+CREATE TABLE a (
+  id int
+);
+
+INSERT INTO a VALUES (1);
+UPDATE a SET id = 2`
+
+	assert.Equal(t, []string{
+		"CREATE TABLE a (\n  id int\n)",
+		"INSERT INTO a VALUES (1)",
+		"UPDATE a SET id = 2",
+	}, SplitStatements(script))
+}
+
+func TestCreate_UsesNextVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0001_initial_schema.up.sql"), []byte("SELECT 1;"), 0o644))
+
+	up, down, err := Create(dir, "Add payment amount index")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0002_add_payment_amount_index.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0002_add_payment_amount_index.down.sql"), down)
+
+	migrations, err := Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+}
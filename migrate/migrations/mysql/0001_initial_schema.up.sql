@@ -0,0 +1,67 @@
+-- Skema awal, sama persis dengan hasil AutoMigrate sebelum migrasi
+-- dipakai (users, services, bookings, payments, reviews). IF NOT EXISTS
+-- membuat migrasi ini aman dijalankan pada database yang sudah ada;
+-- perubahan setelahnya ada di migrasi berikutnya.
+
+CREATE TABLE IF NOT EXISTS users (
+  id bigint NOT NULL AUTO_INCREMENT,
+  name longtext,
+  email longtext,
+  password longtext,
+  role ENUM('admin', 'user', 'technician') DEFAULT 'user',
+  address longtext,
+  phone longtext,
+  expertise longtext,
+  availability longtext,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS services (
+  id bigint NOT NULL AUTO_INCREMENT,
+  user_id bigint,
+  name longtext,
+  description longtext,
+  cost bigint,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_services_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS bookings (
+  id bigint NOT NULL AUTO_INCREMENT,
+  user_id bigint NOT NULL,
+  service_id bigint NOT NULL,
+  date date,
+  status longtext,
+  description longtext,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_bookings_user FOREIGN KEY (user_id) REFERENCES users (id),
+  CONSTRAINT fk_bookings_service FOREIGN KEY (service_id) REFERENCES services (id)
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+  id bigint NOT NULL AUTO_INCREMENT,
+  booking_id bigint NOT NULL,
+  amount longtext,
+  status longtext,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_payments_booking FOREIGN KEY (booking_id) REFERENCES bookings (id)
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+  id bigint NOT NULL AUTO_INCREMENT,
+  booking_id bigint NOT NULL,
+  rating bigint,
+  comment longtext,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT fk_reviews_booking FOREIGN KEY (booking_id) REFERENCES bookings (id)
+);
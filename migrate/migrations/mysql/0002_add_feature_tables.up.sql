@@ -0,0 +1,132 @@
+-- Kolom dan tabel yang ditambahkan setelah skema awal: bahasa user,
+-- pengajuan technician, pesan, preferensi notifikasi, job, outbox dan
+-- webhook. Database lama yang dibuat AutoMigrate belum memilikinya.
+
+ALTER TABLE users ADD COLUMN language varchar(5) DEFAULT 'id';
+
+CREATE TABLE IF NOT EXISTS technician_applications (
+  id bigint NOT NULL AUTO_INCREMENT,
+  user_id bigint NOT NULL,
+  status longtext,
+  address longtext,
+  phone longtext,
+  expertise longtext,
+  availability longtext,
+  id_document longtext,
+  certificate longtext,
+  certification_expires_at date,
+  reviewer_id bigint NULL,
+  review_reason longtext,
+  reviewed_at datetime(3) NULL,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  INDEX idx_technician_applications_user_id (user_id),
+  CONSTRAINT fk_technician_applications_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+  id bigint NOT NULL AUTO_INCREMENT,
+  booking_id bigint NOT NULL,
+  sender_id bigint NOT NULL,
+  body text,
+  attachment longtext,
+  read_at datetime(3) NULL,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  INDEX idx_messages_booking_id (booking_id),
+  CONSTRAINT fk_messages_booking FOREIGN KEY (booking_id) REFERENCES bookings (id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+  id bigint NOT NULL AUTO_INCREMENT,
+  user_id bigint NOT NULL,
+  event_type varchar(50) NOT NULL,
+  channel varchar(20) NOT NULL,
+  enabled boolean,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX idx_notification_preference (user_id, event_type, channel)
+);
+
+CREATE TABLE IF NOT EXISTS jobs (
+  id bigint NOT NULL AUTO_INCREMENT,
+  type varchar(100) NOT NULL,
+  unique_key varchar(191) NULL,
+  payload text,
+  status varchar(20) NOT NULL,
+  run_at datetime(3) NULL,
+  attempts bigint,
+  max_attempts bigint,
+  locked_by varchar(100),
+  locked_at datetime(3) NULL,
+  last_error text,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  INDEX idx_jobs_type (type),
+  UNIQUE INDEX idx_jobs_unique_key (unique_key),
+  INDEX idx_job_due (status, run_at)
+);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id bigint NOT NULL AUTO_INCREMENT,
+  type varchar(100) NOT NULL,
+  recipients text,
+  payload text,
+  status varchar(20) NOT NULL,
+  available_at datetime(3) NULL,
+  attempts bigint,
+  locked_at datetime(3) NULL,
+  last_error text,
+  occurred_at datetime(3) NULL,
+  delivered_at datetime(3) NULL,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  INDEX idx_outbox_events_type (type),
+  INDEX idx_outbox_due (status, available_at)
+);
+
+CREATE TABLE IF NOT EXISTS processed_events (
+  event_id bigint NOT NULL,
+  handler varchar(100) NOT NULL,
+  created_at datetime(3) NULL,
+  PRIMARY KEY (event_id, handler)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id bigint NOT NULL AUTO_INCREMENT,
+  url varchar(500) NOT NULL,
+  secret varchar(100) NOT NULL,
+  event_types varchar(1000),
+  description longtext,
+  active boolean,
+  consecutive_failures bigint,
+  disabled_at datetime(3) NULL,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id bigint NOT NULL AUTO_INCREMENT,
+  endpoint_id bigint NOT NULL,
+  event_id bigint NOT NULL,
+  event_type varchar(100),
+  payload text,
+  status varchar(20),
+  attempts bigint,
+  response_status bigint,
+  response_body text,
+  last_error text,
+  duration_ms bigint,
+  delivered_at datetime(3) NULL,
+  created_at datetime(3) NULL,
+  updated_at datetime(3) NULL,
+  PRIMARY KEY (id),
+  UNIQUE INDEX idx_webhook_delivery_event (endpoint_id, event_id),
+  CONSTRAINT fk_webhook_deliveries_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints (id)
+);
@@ -0,0 +1,60 @@
+-- Skema awal untuk PostgreSQL, setara dengan versi MySQL. Role dibatasi
+-- dengan CHECK karena PostgreSQL tidak memiliki ENUM inline.
+
+CREATE TABLE IF NOT EXISTS users (
+  id bigserial PRIMARY KEY,
+  name text,
+  email text,
+  password text,
+  role varchar(20) DEFAULT 'user' CONSTRAINT chk_users_role CHECK (role IN ('admin', 'user', 'technician')),
+  address text,
+  phone text,
+  expertise text,
+  availability text,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL
+);
+
+CREATE TABLE IF NOT EXISTS services (
+  id bigserial PRIMARY KEY,
+  user_id bigint,
+  name text,
+  description text,
+  cost bigint,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_services_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS bookings (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  service_id bigint NOT NULL,
+  date date,
+  status text,
+  description text,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_bookings_user FOREIGN KEY (user_id) REFERENCES users (id),
+  CONSTRAINT fk_bookings_service FOREIGN KEY (service_id) REFERENCES services (id)
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+  id bigserial PRIMARY KEY,
+  booking_id bigint NOT NULL,
+  amount text,
+  status text,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_payments_booking FOREIGN KEY (booking_id) REFERENCES bookings (id)
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+  id bigserial PRIMARY KEY,
+  booking_id bigint NOT NULL,
+  rating bigint,
+  comment text,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_reviews_booking FOREIGN KEY (booking_id) REFERENCES bookings (id)
+);
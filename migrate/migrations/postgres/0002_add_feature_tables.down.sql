@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS outbox_events;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS messages;
+DROP TABLE IF EXISTS technician_applications;
+ALTER TABLE users DROP COLUMN language;
@@ -0,0 +1,134 @@
+-- Kolom dan tabel yang ditambahkan setelah skema awal: bahasa user,
+-- pengajuan technician, pesan, preferensi notifikasi, job, outbox dan
+-- webhook. Database lama yang dibuat AutoMigrate belum memilikinya.
+
+ALTER TABLE users ADD COLUMN language varchar(5) DEFAULT 'id';
+
+CREATE TABLE IF NOT EXISTS technician_applications (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  status text,
+  address text,
+  phone text,
+  expertise text,
+  availability text,
+  id_document text,
+  certificate text,
+  certification_expires_at date,
+  reviewer_id bigint NULL,
+  review_reason text,
+  reviewed_at timestamptz NULL,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_technician_applications_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_technician_applications_user_id ON technician_applications (user_id);
+
+CREATE TABLE IF NOT EXISTS messages (
+  id bigserial PRIMARY KEY,
+  booking_id bigint NOT NULL,
+  sender_id bigint NOT NULL,
+  body text,
+  attachment text,
+  read_at timestamptz NULL,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_messages_booking FOREIGN KEY (booking_id) REFERENCES bookings (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_messages_booking_id ON messages (booking_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL,
+  event_type varchar(50) NOT NULL,
+  channel varchar(20) NOT NULL,
+  enabled boolean,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preference ON notification_preferences (user_id, event_type, channel);
+
+CREATE TABLE IF NOT EXISTS jobs (
+  id bigserial PRIMARY KEY,
+  type varchar(100) NOT NULL,
+  unique_key varchar(191) NULL,
+  payload text,
+  status varchar(20) NOT NULL,
+  run_at timestamptz NULL,
+  attempts bigint,
+  max_attempts bigint,
+  locked_by varchar(100),
+  locked_at timestamptz NULL,
+  last_error text,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_type ON jobs (type);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_key ON jobs (unique_key);
+
+CREATE INDEX IF NOT EXISTS idx_job_due ON jobs (status, run_at);
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id bigserial PRIMARY KEY,
+  type varchar(100) NOT NULL,
+  recipients text,
+  payload text,
+  status varchar(20) NOT NULL,
+  available_at timestamptz NULL,
+  attempts bigint,
+  locked_at timestamptz NULL,
+  last_error text,
+  occurred_at timestamptz NULL,
+  delivered_at timestamptz NULL,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_type ON outbox_events (type);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox_events (status, available_at);
+
+CREATE TABLE IF NOT EXISTS processed_events (
+  event_id bigint NOT NULL,
+  handler varchar(100) NOT NULL,
+  created_at timestamptz NULL,
+  PRIMARY KEY (event_id, handler)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+  id bigserial PRIMARY KEY,
+  url varchar(500) NOT NULL,
+  secret varchar(100) NOT NULL,
+  event_types varchar(1000),
+  description text,
+  active boolean,
+  consecutive_failures bigint,
+  disabled_at timestamptz NULL,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id bigserial PRIMARY KEY,
+  endpoint_id bigint NOT NULL,
+  event_id bigint NOT NULL,
+  event_type varchar(100),
+  payload text,
+  status varchar(20),
+  attempts bigint,
+  response_status bigint,
+  response_body text,
+  last_error text,
+  duration_ms bigint,
+  delivered_at timestamptz NULL,
+  created_at timestamptz NULL,
+  updated_at timestamptz NULL,
+  CONSTRAINT fk_webhook_deliveries_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_delivery_event ON webhook_deliveries (endpoint_id, event_id);
@@ -0,0 +1,2 @@
+DELETE FROM technician_applications
+WHERE reviewer_id IS NULL AND review_reason = 'Backfilled: registered before technician verification';
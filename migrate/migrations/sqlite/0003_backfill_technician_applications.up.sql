@@ -0,0 +1,9 @@
+-- Technician yang terdaftar sebelum verifikasi KYC tidak punya pengajuan,
+-- sehingga tidak bisa membuat service baru. Beri mereka pengajuan Approved
+-- dengan masa berlaku satu tahun sebagai masa tenggang untuk mengajukan
+-- dokumen asli.
+INSERT INTO technician_applications (user_id, status, address, phone, expertise, availability, certification_expires_at, review_reason, reviewed_at, created_at, updated_at)
+SELECT u.id, 'Approved', u.address, u.phone, u.expertise, u.availability, DATE('now', '+1 year'), 'Backfilled: registered before technician verification', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM users u
+WHERE u.role = 'technician'
+  AND NOT EXISTS (SELECT 1 FROM technician_applications a WHERE a.user_id = u.id);
@@ -10,9 +10,11 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	listquery "github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,53 +43,68 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // DeleteUser mocks base method.
-func (m *MockUserService) DeleteUser(id int) error {
+func (m *MockUserService) DeleteUser(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", id)
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockUserServiceMockRecorder) DeleteUser(id any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, id)
 }
 
 // GetAllUsers mocks base method.
-func (m *MockUserService) GetAllUsers() ([]*entity.UserRes, error) {
+func (m *MockUserService) GetAllUsers(ctx context.Context, q listquery.Query) (listquery.Page[*entity.UserRes], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUsers")
-	ret0, _ := ret[0].([]*entity.UserRes)
+	ret := m.ctrl.Call(m, "GetAllUsers", ctx, q)
+	ret0, _ := ret[0].(listquery.Page[*entity.UserRes])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllUsers indicates an expected call of GetAllUsers.
-func (mr *MockUserServiceMockRecorder) GetAllUsers() *gomock.Call {
+func (mr *MockUserServiceMockRecorder) GetAllUsers(ctx, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserService)(nil).GetAllUsers))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockUserService)(nil).GetAllUsers), ctx, q)
 }
 
 // GetUserByID mocks base method.
-func (m *MockUserService) GetUserByID(id int) (*entity.UserRes, error) {
+func (m *MockUserService) GetUserByID(ctx context.Context, id int) (*entity.UserRes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", id)
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
 	ret0, _ := ret[0].(*entity.UserRes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockUserServiceMockRecorder) GetUserByID(id any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserService)(nil).GetUserByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockUserService)(nil).GetUserByID), ctx, id)
+}
+
+// GetUserRoleReport mocks base method.
+func (m *MockUserService) GetUserRoleReport(ctx context.Context, startDate, endDate string) (map[string]any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoleReport", ctx, startDate, endDate)
+	ret0, _ := ret[0].(map[string]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoleReport indicates an expected call of GetUserRoleReport.
+func (mr *MockUserServiceMockRecorder) GetUserRoleReport(ctx, startDate, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoleReport", reflect.TypeOf((*MockUserService)(nil).GetUserRoleReport), ctx, startDate, endDate)
 }
 
 // Login mocks base method.
-func (m *MockUserService) Login(req *entity.LoginUserReq) (*entity.UserRes, string, error) {
+func (m *MockUserService) Login(ctx context.Context, req *entity.LoginUserReq) (*entity.UserRes, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", req)
+	ret := m.ctrl.Call(m, "Login", ctx, req)
 	ret0, _ := ret[0].(*entity.UserRes)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -95,82 +112,81 @@ func (m *MockUserService) Login(req *entity.LoginUserReq) (*entity.UserRes, stri
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserServiceMockRecorder) Login(req any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) Login(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, req)
 }
 
 // Register mocks base method.
-func (m *MockUserService) Register(req *entity.RegisterUserReq) (*entity.UserRes, error) {
+func (m *MockUserService) Register(ctx context.Context, req *entity.RegisterUserReq) (*entity.UserRes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", req)
+	ret := m.ctrl.Call(m, "Register", ctx, req)
 	ret0, _ := ret[0].(*entity.UserRes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockUserServiceMockRecorder) Register(req any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) Register(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserService)(nil).Register), ctx, req)
 }
 
 // RegisterAsAdmin mocks base method.
-func (m *MockUserService) RegisterAsAdmin(req *entity.RegisterUserReq) (*entity.UserRes, error) {
+func (m *MockUserService) RegisterAsAdmin(ctx context.Context, req *entity.RegisterUserReq) (*entity.UserRes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterAsAdmin", req)
+	ret := m.ctrl.Call(m, "RegisterAsAdmin", ctx, req)
 	ret0, _ := ret[0].(*entity.UserRes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RegisterAsAdmin indicates an expected call of RegisterAsAdmin.
-func (mr *MockUserServiceMockRecorder) RegisterAsAdmin(req any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) RegisterAsAdmin(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAsAdmin", reflect.TypeOf((*MockUserService)(nil).RegisterAsAdmin), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAsAdmin", reflect.TypeOf((*MockUserService)(nil).RegisterAsAdmin), ctx, req)
 }
 
-// RegisterAsTechnician mocks base method.
-func (m *MockUserService) RegisterAsTechnician(req *entity.RegisterAsTechnicianReq) (*entity.TechnicianRes, error) {
+// ResetPassword mocks base method.
+func (m *MockUserService) ResetPassword(ctx context.Context, email, password string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterAsTechnician", req)
-	ret0, _ := ret[0].(*entity.TechnicianRes)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, email, password)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// RegisterAsTechnician indicates an expected call of RegisterAsTechnician.
-func (mr *MockUserServiceMockRecorder) RegisterAsTechnician(req any) *gomock.Call {
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserServiceMockRecorder) ResetPassword(ctx, email, password any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAsTechnician", reflect.TypeOf((*MockUserService)(nil).RegisterAsTechnician), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserService)(nil).ResetPassword), ctx, email, password)
 }
 
 // UpdateTechnician mocks base method.
-func (m *MockUserService) UpdateTechnician(req *entity.UpdateTechnicianReq) (*entity.TechnicianRes, error) {
+func (m *MockUserService) UpdateTechnician(ctx context.Context, req *entity.UpdateTechnicianReq, callerID int, callerRole string) (*entity.TechnicianRes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTechnician", req)
+	ret := m.ctrl.Call(m, "UpdateTechnician", ctx, req, callerID, callerRole)
 	ret0, _ := ret[0].(*entity.TechnicianRes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateTechnician indicates an expected call of UpdateTechnician.
-func (mr *MockUserServiceMockRecorder) UpdateTechnician(req any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) UpdateTechnician(ctx, req, callerID, callerRole any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTechnician", reflect.TypeOf((*MockUserService)(nil).UpdateTechnician), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTechnician", reflect.TypeOf((*MockUserService)(nil).UpdateTechnician), ctx, req, callerID, callerRole)
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserService) UpdateUser(req *entity.UpdateUserReq) (*entity.UserRes, error) {
+func (m *MockUserService) UpdateUser(ctx context.Context, req *entity.UpdateUserReq, callerID int, callerRole string) (*entity.UserRes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", req)
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, req, callerID, callerRole)
 	ret0, _ := ret[0].(*entity.UserRes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserServiceMockRecorder) UpdateUser(req any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) UpdateUser(ctx, req, callerID, callerRole any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), ctx, req, callerID, callerRole)
 }
@@ -0,0 +1,216 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+)
+
+// Recipient adalah penerima notifikasi beserta alamat untuk setiap channel.
+type Recipient struct {
+	UserID   int    `json:"user_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Language string `json:"language"`
+}
+
+// Message adalah notifikasi yang sudah dirender dan siap dikirim.
+type Message struct {
+	EventType string    `json:"event_type"`
+	To        Recipient `json:"to"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+}
+
+// Channel mengirim notifikasi melalui satu media (email, sms, push).
+type Channel interface {
+	Name() string
+	Send(msg Message) error
+}
+
+// EmailChannel mengirim notifikasi lewat SMTP.
+type EmailChannel struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (c *EmailChannel) Name() string { return "email" }
+
+func (c *EmailChannel) Send(msg Message) error {
+	if msg.To.Email == "" {
+		return nil
+	}
+
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	body := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		c.From, msg.To.Email, msg.Subject, msg.Body)
+	return smtp.SendMail(c.Host+":"+c.Port, auth, c.From, []string{msg.To.Email}, []byte(body))
+}
+
+// HTTPChannel mengirim notifikasi ke gateway HTTP (SMS gateway atau push
+// notification service) sebagai JSON.
+type HTTPChannel struct {
+	ChannelName string
+	URL         string
+	APIKey      string
+	Client      *http.Client
+}
+
+func (c *HTTPChannel) Name() string { return c.ChannelName }
+
+func (c *HTTPChannel) Send(msg Message) error {
+	// SMS membutuhkan nomor telepon; push dikirim berdasarkan user ID
+	if c.ChannelName == "sms" && msg.To.Phone == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"user_id": msg.To.UserID,
+		"phone":   msg.To.Phone,
+		"title":   msg.Subject,
+		"body":    msg.Body,
+		"event":   msg.EventType,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("%s gateway responded with status %d", c.ChannelName, res.StatusCode)
+	}
+	return nil
+}
+
+// ConsoleChannel menulis notifikasi ke writer (mis. stdout). Dipakai sebagai
+// pengganti channel asli saat development dan testing; isi notifikasi ikut
+// tercetak sehingga hanya aktif jika notification.console diisi true.
+type ConsoleChannel struct {
+	ChannelName string
+	Out         io.Writer
+	mu          sync.Mutex
+}
+
+func (c *ConsoleChannel) Name() string { return c.ChannelName }
+
+func (c *ConsoleChannel) Send(msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintf(c.Out, "[%s] to user %d (%s): %s - %s\n", c.ChannelName, msg.To.UserID, msg.EventType, msg.Subject, msg.Body)
+	return err
+}
+
+// DiscardChannel membuang notifikasi. Dipakai untuk channel yang belum
+// dikonfigurasi agar isi notifikasi tidak bocor ke log.
+type DiscardChannel struct {
+	ChannelName string
+}
+
+func (c DiscardChannel) Name() string { return c.ChannelName }
+
+func (c DiscardChannel) Send(Message) error { return nil }
+
+// FileChannel menambahkan notifikasi sebagai satu baris JSON ke sebuah file.
+type FileChannel struct {
+	ChannelName string
+	Path        string
+	mu          sync.Mutex
+}
+
+func (c *FileChannel) Name() string { return c.ChannelName }
+
+func (c *FileChannel) Send(msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := os.OpenFile(c.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(struct {
+		Channel string `json:"channel"`
+		Message
+	}{c.ChannelName, msg})
+}
+
+// NewChannels membuat channel berdasarkan konfigurasi. Channel yang belum
+// dikonfigurasi diganti dengan FileChannel (jika notification.log_file diisi),
+// ConsoleChannel (jika notification.console true) atau DiscardChannel.
+func NewChannels(cfg config.NotificationConfig) []Channel {
+	standIn := func(name string) Channel {
+		switch {
+		case cfg.LogFile != "":
+			return &FileChannel{ChannelName: name, Path: cfg.LogFile}
+		case cfg.Console:
+			return &ConsoleChannel{ChannelName: name, Out: os.Stdout}
+		default:
+			return DiscardChannel{ChannelName: name}
+		}
+	}
+
+	var channels []Channel
+
+	if smtp := cfg.SMTP; smtp.Host != "" {
+		channels = append(channels, &EmailChannel{
+			Host:     smtp.Host,
+			Port:     strconv.Itoa(smtp.Port),
+			Username: smtp.Username,
+			Password: smtp.Password,
+			From:     smtp.From,
+		})
+	} else {
+		channels = append(channels, standIn("email"))
+	}
+
+	if cfg.SMS.URL != "" {
+		channels = append(channels, &HTTPChannel{ChannelName: "sms", URL: cfg.SMS.URL, APIKey: cfg.SMS.APIKey})
+	} else {
+		channels = append(channels, standIn("sms"))
+	}
+
+	if cfg.Push.URL != "" {
+		channels = append(channels, &HTTPChannel{ChannelName: "push", URL: cfg.Push.URL, APIKey: cfg.Push.APIKey})
+	} else {
+		channels = append(channels, standIn("push"))
+	}
+
+	return channels
+}
@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+)
+
+type UserFinder interface {
+	FindByID(ctx context.Context, id int) (*entity.User, error)
+}
+
+type PreferenceFinder interface {
+	FindByUserID(ctx context.Context, userID int) ([]entity.NotificationPreference, error)
+}
+
+// ChannelNames mengembalikan nama channel yang bisa diatur oleh user.
+func ChannelNames() []string {
+	return []string{"email", "sms", "push"}
+}
+
+// DefaultEnabled menentukan apakah channel aktif jika user belum pernah
+// mengatur preferensinya. SMS dimatikan secara default karena berbayar.
+func DefaultEnabled(channel string) bool {
+	return channel != "sms"
+}
+
+// IsEnabled mengecek preferensi user untuk kombinasi event dan channel.
+func IsEnabled(preferences []entity.NotificationPreference, eventType, channel string) bool {
+	for _, preference := range preferences {
+		if preference.EventType == eventType && preference.Channel == channel {
+			return preference.Enabled
+		}
+	}
+	return DefaultEnabled(channel)
+}
+
+// Notifier mengubah domain event menjadi notifikasi untuk setiap penerima
+// sesuai template, bahasa dan preferensi channel milik user.
+type Notifier struct {
+	users       UserFinder
+	preferences PreferenceFinder
+	channels    []Channel
+}
+
+func NewNotifier(users UserFinder, preferences PreferenceFinder, channels ...Channel) *Notifier {
+	return &Notifier{users: users, preferences: preferences, channels: channels}
+}
+
+// HandleEvent dipasang sebagai subscriber outbox. Error hanya dikembalikan jika
+// data user atau template gagal dimuat; kegagalan channel cukup dicatat di log
+// agar channel lain tidak menerima notifikasi ganda saat event dikirim ulang.
+func (n *Notifier) HandleEvent(ctx context.Context, evt event.Event) error {
+	if _, ok := templates[evt.Type]; !ok {
+		return nil
+	}
+
+	data, err := toTemplateData(evt.Data)
+	if err != nil {
+		return fmt.Errorf("failed to read %s payload: %w", evt.Type, err)
+	}
+
+	var failures []error
+	for role, userID := range evt.Recipients {
+		if err := n.notify(ctx, evt.Type, role, userID, data); err != nil {
+			failures = append(failures, fmt.Errorf("user %d: %w", userID, err))
+		}
+	}
+	return errors.Join(failures...)
+}
+
+func (n *Notifier) notify(ctx context.Context, eventType, role string, userID int, data map[string]interface{}) error {
+	if _, ok := templates[eventType][role]; !ok || userID == 0 {
+		return nil
+	}
+
+	user, err := n.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := n.preferences.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	language := user.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+
+	subject, body, _, err := render(eventType, role, language, data)
+	if err != nil {
+		return err
+	}
+
+	msg := Message{
+		EventType: eventType,
+		To: Recipient{
+			UserID:   user.ID,
+			Name:     user.Name,
+			Email:    user.Email,
+			Phone:    user.Phone,
+			Language: language,
+		},
+		Subject: subject,
+		Body:    body,
+	}
+
+	for _, channel := range n.channels {
+		if !IsEnabled(preferences, eventType, channel.Name()) {
+			continue
+		}
+		if err := channel.Send(msg); err != nil {
+			slog.WarnContext(ctx, "notification: channel failed", "channel", channel.Name(), "user_id", userID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// toTemplateData mengubah payload event menjadi map dengan key sesuai nama
+// field JSON agar bisa dipakai langsung di template.
+func toTemplateData(payload interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	data := map[string]interface{}{}
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
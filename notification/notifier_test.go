@@ -0,0 +1,112 @@
+package notification_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/notification"
+)
+
+type fakeUsers map[int]*entity.User
+
+func (f fakeUsers) FindByID(ctx context.Context, id int) (*entity.User, error) {
+	user, ok := f[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+type fakePreferences map[int][]entity.NotificationPreference
+
+func (f fakePreferences) FindByUserID(ctx context.Context, userID int) ([]entity.NotificationPreference, error) {
+	return f[userID], nil
+}
+
+func TestNotifierRendersTemplatePerRecipientLanguage(t *testing.T) {
+	var email, sms bytes.Buffer
+	users := fakeUsers{
+		3: {ID: 3, Name: "Budi", Language: "id"},
+		9: {ID: 9, Name: "Alice", Language: "en"},
+	}
+	notifier := notification.NewNotifier(users, fakePreferences{},
+		&notification.ConsoleChannel{ChannelName: "email", Out: &email},
+		&notification.ConsoleChannel{ChannelName: "sms", Out: &sms},
+	)
+
+	notifier.HandleEvent(context.Background(), event.Event{
+		Type:       "booking.created",
+		Recipients: map[string]int{"customer": 3, "technician": 9},
+		Data:       map[string]interface{}{"id": 12, "date": "2026-10-20T00:00:00Z", "description": "AC bocor"},
+	})
+
+	out := email.String()
+	if !strings.Contains(out, "Booking #12 diterima") {
+		t.Errorf("expected Indonesian customer notification, got %q", out)
+	}
+	if !strings.Contains(out, "New booking #12") || !strings.Contains(out, "20 Oct 2026") {
+		t.Errorf("expected English technician notification, got %q", out)
+	}
+	// SMS tidak aktif secara default
+	if sms.Len() != 0 {
+		t.Errorf("expected no sms by default, got %q", sms.String())
+	}
+}
+
+func TestNotifierRespectsPreferences(t *testing.T) {
+	var email, push bytes.Buffer
+	users := fakeUsers{3: {ID: 3, Language: "id"}}
+	preferences := fakePreferences{3: {
+		{UserID: 3, EventType: "payment.paid", Channel: "email", Enabled: false},
+	}}
+	notifier := notification.NewNotifier(users, preferences,
+		&notification.ConsoleChannel{ChannelName: "email", Out: &email},
+		&notification.ConsoleChannel{ChannelName: "push", Out: &push},
+	)
+
+	notifier.HandleEvent(context.Background(), event.Event{
+		Type:       "payment.paid",
+		Recipients: map[string]int{"customer": 3},
+		Data:       map[string]interface{}{"id": 5, "booking_id": 12, "amount": 150000},
+	})
+
+	if email.Len() != 0 {
+		t.Errorf("expected email to be opted out, got %q", email.String())
+	}
+	if !strings.Contains(push.String(), "Rp150000") {
+		t.Errorf("expected push notification with amount, got %q", push.String())
+	}
+}
+
+func TestNotifierIgnoresEventsWithoutTemplate(t *testing.T) {
+	var email bytes.Buffer
+	notifier := notification.NewNotifier(fakeUsers{}, fakePreferences{},
+		&notification.ConsoleChannel{ChannelName: "email", Out: &email},
+	)
+
+	notifier.HandleEvent(context.Background(), event.Event{Type: "message.read", Recipients: map[string]int{"customer": 3}})
+
+	if email.Len() != 0 {
+		t.Errorf("expected no notification, got %q", email.String())
+	}
+}
+
+func TestNewChannelsDiscardsUnconfiguredChannels(t *testing.T) {
+	for _, channel := range notification.NewChannels(config.NotificationConfig{}) {
+		if _, ok := channel.(notification.DiscardChannel); !ok {
+			t.Errorf("channel %s: expected DiscardChannel, got %T", channel.Name(), channel)
+		}
+	}
+
+	for _, channel := range notification.NewChannels(config.NotificationConfig{Console: true}) {
+		if _, ok := channel.(*notification.ConsoleChannel); !ok {
+			t.Errorf("channel %s: expected ConsoleChannel when opted in, got %T", channel.Name(), channel)
+		}
+	}
+}
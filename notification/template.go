@@ -0,0 +1,149 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// Bahasa default jika user belum memilih bahasa atau template untuk bahasa
+// tersebut tidak tersedia.
+const DefaultLanguage = "id"
+
+type messageTemplate struct {
+	Subject string
+	Body    string
+}
+
+// templates dikelompokkan per tipe event, lalu per peran penerima, lalu per bahasa.
+// Data template adalah field JSON dari event, mis. {{.id}}, {{.status}}, {{.rating}}.
+var templates = map[string]map[string]map[string]messageTemplate{
+	"booking.created": {
+		"technician": {
+			"id": {"Booking baru #{{.id}}", "Ada booking baru untuk tanggal {{date .date}}. Catatan pelanggan: {{.description}}"},
+			"en": {"New booking #{{.id}}", "You have a new booking on {{date .date}}. Customer note: {{.description}}"},
+		},
+		"customer": {
+			"id": {"Booking #{{.id}} diterima", "Booking Anda untuk tanggal {{date .date}} sudah kami terima dan menunggu konfirmasi teknisi."},
+			"en": {"Booking #{{.id}} received", "Your booking on {{date .date}} has been received and is waiting for the technician's confirmation."},
+		},
+	},
+	"booking.confirmed": {
+		"customer": {
+			"id": {"Booking #{{.id}} dikonfirmasi", "Teknisi telah mengonfirmasi booking Anda untuk tanggal {{date .date}}."},
+			"en": {"Booking #{{.id}} confirmed", "The technician has confirmed your booking on {{date .date}}."},
+		},
+	},
+	"booking.cancelled": {
+		"customer": {
+			"id": {"Booking #{{.id}} dibatalkan", "Booking Anda untuk tanggal {{date .date}} telah dibatalkan."},
+			"en": {"Booking #{{.id}} cancelled", "Your booking on {{date .date}} has been cancelled."},
+		},
+		"technician": {
+			"id": {"Booking #{{.id}} dibatalkan", "Booking untuk tanggal {{date .date}} telah dibatalkan."},
+			"en": {"Booking #{{.id}} cancelled", "The booking on {{date .date}} has been cancelled."},
+		},
+	},
+	"booking.expired": {
+		"customer": {
+			"id": {"Booking #{{.id}} kedaluwarsa", "Booking Anda untuk tanggal {{date .date}} tidak dikonfirmasi teknisi dan telah kedaluwarsa. Silakan pilih jadwal lain."},
+			"en": {"Booking #{{.id}} expired", "Your booking on {{date .date}} was not confirmed by the technician and has expired. Please pick another date."},
+		},
+	},
+	"booking.reminder": {
+		"customer": {
+			"id": {"Pengingat kunjungan besok", "Teknisi akan berkunjung besok, {{date .date}}, untuk booking #{{.id}}."},
+			"en": {"Visit reminder for tomorrow", "The technician will visit tomorrow, {{date .date}}, for booking #{{.id}}."},
+		},
+		"technician": {
+			"id": {"Pengingat jadwal besok", "Anda dijadwalkan berkunjung besok, {{date .date}}, untuk booking #{{.id}}."},
+			"en": {"Schedule reminder for tomorrow", "You are scheduled to visit tomorrow, {{date .date}}, for booking #{{.id}}."},
+		},
+	},
+	"payment.paid": {
+		"customer": {
+			"id": {"Pembayaran #{{.id}} berhasil", "Pembayaran sebesar Rp{{.amount}} untuk booking #{{.booking_id}} telah kami terima."},
+			"en": {"Payment #{{.id}} successful", "We have received your payment of Rp{{.amount}} for booking #{{.booking_id}}."},
+		},
+		"technician": {
+			"id": {"Booking #{{.booking_id}} sudah dibayar", "Pelanggan telah membayar Rp{{.amount}} untuk booking #{{.booking_id}}."},
+			"en": {"Booking #{{.booking_id}} has been paid", "The customer has paid Rp{{.amount}} for booking #{{.booking_id}}."},
+		},
+	},
+	"payment.expired": {
+		"customer": {
+			"id": {"Pembayaran #{{.id}} kedaluwarsa", "Pembayaran sebesar Rp{{.amount}} untuk booking #{{.booking_id}} tidak diselesaikan dan telah kedaluwarsa."},
+			"en": {"Payment #{{.id}} expired", "The payment of Rp{{.amount}} for booking #{{.booking_id}} was not completed and has expired."},
+		},
+	},
+	"review.requested": {
+		"customer": {
+			"id": {"Bagaimana layanan booking #{{.id}}?", "Booking Anda pada {{date .date}} telah selesai. Beri rating dan ulasan untuk membantu pengguna lain."},
+			"en": {"How was booking #{{.id}}?", "Your booking on {{date .date}} is complete. Leave a rating and review to help other users."},
+		},
+	},
+	"review.created": {
+		"technician": {
+			"id": {"Review baru untuk booking #{{.booking_id}}", "Anda mendapat rating {{.rating}}/5: \"{{.comment}}\""},
+			"en": {"New review for booking #{{.booking_id}}", "You received a {{.rating}}/5 rating: \"{{.comment}}\""},
+		},
+	},
+}
+
+var templateFuncs = template.FuncMap{
+	// date memformat tanggal RFC3339 dari JSON menjadi "02 Jan 2006"
+	"date": func(v interface{}) string {
+		s := fmt.Sprint(v)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return t.Format("02 Jan 2006")
+	},
+}
+
+// EventTypes mengembalikan tipe event yang memiliki template notifikasi.
+func EventTypes() []string {
+	return []string{
+		"booking.created", "booking.confirmed", "booking.cancelled", "booking.expired", "booking.reminder",
+		"payment.paid", "payment.expired", "review.requested", "review.created",
+	}
+}
+
+// render mengembalikan subject dan body untuk event, peran dan bahasa tertentu.
+// ok bernilai false jika tidak ada template untuk kombinasi event dan peran.
+func render(eventType, role, language string, data map[string]interface{}) (subject, body string, ok bool, err error) {
+	byLanguage, found := templates[eventType][role]
+	if !found {
+		return "", "", false, nil
+	}
+
+	tmpl, found := byLanguage[language]
+	if !found {
+		tmpl = byLanguage[DefaultLanguage]
+	}
+
+	subject, err = execute(tmpl.Subject, data)
+	if err != nil {
+		return "", "", true, err
+	}
+	body, err = execute(tmpl.Body, data)
+	if err != nil {
+		return "", "", true, err
+	}
+	return subject, body, true, nil
+}
+
+func execute(text string, data map[string]interface{}) (string, error) {
+	tmpl, err := template.New("notification").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
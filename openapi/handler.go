@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+)
+
+// SwaggerUIVersion adalah versi swagger-ui-dist yang dipakai halaman docs.
+// File-nya (swagger-ui.css dan swagger-ui-bundle.js) disajikan sendiri oleh
+// server, bukan dari CDN, agar browser tidak menjalankan script pihak ketiga.
+const SwaggerUIVersion = "5.17.14"
+
+// SwaggerUIFiles adalah file swagger-ui-dist yang dibutuhkan halaman docs.
+var SwaggerUIFiles = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// Handler menyajikan dokumen sebagai JSON. Dokumen di-encode sekali saat
+// handler dibuat.
+func Handler(doc *Document) http.Handler {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		// Dokumen hanya berisi string, angka dan map, jadi ini bug
+		panic(fmt.Sprintf("openapi: encode document: %v", err))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(body)
+	})
+}
+
+// DocsHandler menyajikan halaman Swagger UI yang membaca dokumen dari
+// specURL, mis. /openapi.json, dan memuat SwaggerUIFiles dari assetsURL.
+func DocsHandler(title, specURL, assetsURL string) http.Handler {
+	assetsURL = html.EscapeString(assetsURL)
+	page := fmt.Sprintf(docsPage, html.EscapeString(title), assetsURL, assetsURL, specURL)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	})
+}
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%s/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: %q, dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`
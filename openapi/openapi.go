@@ -0,0 +1,291 @@
+// Package openapi membentuk dokumen OpenAPI 3.0 dari daftar route. Schema
+// request dan response dibuat dari struct DTO lewat reflection: nama field
+// dari tag json (atau form untuk multipart/form-data), field wajib dan
+// batasannya dari tag validate yang juga dipakai oleh package validation.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+)
+
+// Version adalah versi spesifikasi OpenAPI yang dihasilkan Build.
+const Version = "3.0.3"
+
+// BearerAuth adalah nama security scheme untuk token JWT.
+const BearerAuth = "bearerAuth"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem berisi operasi per method HTTP dalam huruf kecil, mis. "get".
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Roles       []string              `json:"x-roles,omitempty"` // role yang diizinkan, kosong berarti semua user
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path atau query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Route menjelaskan satu endpoint untuk Build. Path memakai format gin
+// (/bookings/:id); parameter path diambil dari segmen :param.
+type Route struct {
+	Method       string
+	Path         string
+	ID           string // operationId, biasanya nama method controller
+	Tag          string
+	Summary      string
+	Description  string
+	Public       bool     // tanpa token JWT
+	Roles        []string // role yang diizinkan, kosong berarti semua user yang login
+	Query        []Param
+	Body         interface{} // DTO request JSON, nil jika tanpa body
+	OptionalBody bool        // body boleh kosong
+	Form         interface{} // DTO request multipart/form-data, nil jika tidak ada
+	Files        []Param     // file di request multipart/form-data
+	Status       int         // status response sukses, default 200
+	Response     interface{} // DTO response JSON, nil jika response bukan JSON
+	Content      string      // media type response selain JSON, mis. text/event-stream
+}
+
+// Param adalah query parameter atau file multipart. Type berisi tipe
+// schema (string, integer, number), Format opsional (mis. date) dan Enum
+// daftar nilai yang diizinkan.
+type Param struct {
+	Name        string
+	Type        string
+	Format      string
+	Enum        []string
+	Required    bool
+	Description string
+}
+
+// Build membentuk dokumen dari daftar route. Path di dokumen relatif
+// terhadap server, mis. prefix versi /api/v1. Route yang sama didaftarkan
+// dua kali menyebabkan panic, seperti router gin.
+func Build(info Info, server string, tags []Tag, routes []Route) *Document {
+	gen := newGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: []Server{{URL: server}},
+		Tags:    tags,
+		Paths:   map[string]PathItem{},
+	}
+
+	for _, route := range routes {
+		path, params := convertPath(route.Path)
+		method := strings.ToLower(route.Method)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		if _, exists := item[method]; exists {
+			panic(fmt.Sprintf("openapi: duplicate operation %s %s", route.Method, route.Path))
+		}
+		item[method] = buildOperation(gen, route, params)
+	}
+
+	gen.ref(typeOf(apperror.Problem{}))
+	doc.Components = Components{
+		Schemas: gen.schemas,
+		SecuritySchemes: map[string]SecurityScheme{
+			BearerAuth: {
+				Type:         "http",
+				Scheme:       "bearer",
+				BearerFormat: "JWT",
+				Description:  "Token from POST /login. Streaming endpoints also accept it as the access_token query parameter.",
+			},
+		},
+	}
+	return doc
+}
+
+func buildOperation(gen *generator, route Route, pathParams []string) *Operation {
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: route.ID,
+		Responses:   map[string]*Response{},
+		Roles:       route.Roles,
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+
+	for _, name := range pathParams {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: pathParamSchema(name)})
+	}
+	for _, param := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: param.Type, Format: param.Format, Enum: param.Enum},
+		})
+	}
+
+	if route.Body != nil || route.Form != nil {
+		op.RequestBody = &RequestBody{Required: !route.OptionalBody, Content: map[string]MediaType{}}
+		if route.Body != nil {
+			op.RequestBody.Content["application/json"] = MediaType{Schema: gen.ref(typeOf(route.Body))}
+		}
+		if route.Form != nil {
+			op.RequestBody.Content["multipart/form-data"] = MediaType{Schema: gen.formSchema(typeOf(route.Form), route.Files)}
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case route.Response != nil:
+		success.Content = map[string]MediaType{"application/json": {Schema: gen.ref(typeOf(route.Response))}}
+	case route.Content == "application/octet-stream":
+		success.Content = map[string]MediaType{route.Content: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	case route.Content != "":
+		success.Content = map[string]MediaType{route.Content: {Schema: &Schema{Type: "string"}}}
+	}
+	op.Responses[fmt.Sprint(status)] = success
+
+	// Error mengikuti format problem+json dari middleware.ErrorHandler
+	problem := func(status int) {
+		op.Responses[fmt.Sprint(status)] = &Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{apperror.ProblemContentType: {Schema: &Schema{Ref: schemaRef("Problem")}}},
+		}
+	}
+	if route.Body != nil || route.Form != nil || len(route.Query) > 0 || len(pathParams) > 0 {
+		problem(http.StatusBadRequest)
+	}
+	if !route.Public {
+		op.Security = []map[string][]string{{BearerAuth: {}}}
+		problem(http.StatusUnauthorized)
+	}
+	if len(route.Roles) > 0 {
+		problem(http.StatusForbidden)
+	}
+	if len(pathParams) > 0 {
+		problem(http.StatusNotFound)
+	}
+	problem(http.StatusTooManyRequests)
+	problem(http.StatusInternalServerError)
+	return op
+}
+
+// convertPath mengubah /bookings/:id menjadi /bookings/{id} dan
+// mengembalikan nama parameter path sesuai urutan.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// GinPath adalah kebalikan convertPath: /bookings/{id} menjadi /bookings/:id.
+func GinPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + strings.Trim(segment, "{}")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// pathParamSchema menganggap id dan *_id sebagai angka, sisanya string
+// (mis. :document).
+func pathParamSchema(name string) *Schema {
+	if name == "id" || strings.HasSuffix(name, "_id") {
+		return &Schema{Type: "integer"}
+	}
+	return &Schema{Type: "string"}
+}
+
+// Operations mengembalikan semua operasi dokumen sebagai "METHOD /path"
+// (path format dokumen), terurut.
+func (d *Document) Operations() []string {
+	var ops []string
+	for path, item := range d.Paths {
+		for method := range item {
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
@@ -0,0 +1,152 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type node struct {
+	ID       int        `json:"id"`
+	Parent   *node      `json:"parent"`
+	Children []node     `json:"children,omitempty"`
+	Secret   string     `json:"-"`
+	DoneAt   *time.Time `json:"done_at"`
+}
+
+type createNodeReq struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Kind   string   `json:"kind" validate:"omitempty,oneof=leaf branch"`
+	Email  string   `json:"email" validate:"omitempty,email_addr"`
+	Rating int      `json:"rating" validate:"required,rating"`
+	Tags   []string `json:"tags" validate:"required,min=1,dive,required"`
+	Cost   float64  `json:"cost" validate:"required,money"`
+}
+
+type uploadReq struct {
+	Note string    `form:"note" validate:"required"`
+	Due  time.Time `form:"due" time_format:"2006-01-02" validate:"required,future_date"`
+}
+
+type page[T any] struct {
+	Items []T `json:"items"`
+}
+
+func build(routes ...openapi.Route) *openapi.Document {
+	return openapi.Build(openapi.Info{Title: "Test", Version: "1"}, "/api/v1", nil, routes)
+}
+
+func TestBuild_Operations(t *testing.T) {
+	doc := build(
+		openapi.Route{Method: http.MethodPost, Path: "/login", Public: true, Body: createNodeReq{}, Response: node{}},
+		openapi.Route{Method: http.MethodGet, Path: "/nodes/:id/files/:name", Roles: []string{"admin"}, Content: "application/octet-stream"},
+		openapi.Route{Method: http.MethodPost, Path: "/nodes", Status: http.StatusCreated, Form: uploadReq{}, Files: []openapi.Param{{Name: "file", Required: true}}, Response: []node{}},
+	)
+
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Equal(t, []openapi.Server{{URL: "/api/v1"}}, doc.Servers)
+	assert.Equal(t, []string{"GET /nodes/{id}/files/{name}", "POST /login", "POST /nodes"}, doc.Operations())
+
+	login := doc.Paths["/login"]["post"]
+	assert.Empty(t, login.Security)
+	assert.NotContains(t, login.Responses, "401")
+	assert.Equal(t, "#/components/schemas/CreateNodeReq", login.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Node", login.Responses["200"].Content["application/json"].Schema.Ref)
+
+	file := doc.Paths["/nodes/{id}/files/{name}"]["get"]
+	assert.Equal(t, []map[string][]string{{openapi.BearerAuth: {}}}, file.Security)
+	assert.Equal(t, []string{"admin"}, file.Roles)
+	assert.Equal(t, []openapi.Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}},
+		{Name: "name", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+	}, file.Parameters)
+	assert.Equal(t, "binary", file.Responses["200"].Content["application/octet-stream"].Schema.Format)
+	for _, status := range []string{"401", "403", "404"} {
+		assert.Equal(t, "#/components/schemas/Problem", file.Responses[status].Content["application/problem+json"].Schema.Ref, status)
+	}
+
+	upload := doc.Paths["/nodes"]["post"]
+	form := upload.RequestBody.Content["multipart/form-data"].Schema
+	assert.Equal(t, []string{"note", "due", "file"}, form.Required)
+	assert.Equal(t, "date", form.Properties["due"].Format)
+	assert.NotEmpty(t, form.Properties["due"].Description)
+	assert.Equal(t, "binary", form.Properties["file"].Format)
+	created := upload.Responses["201"].Content["application/json"].Schema
+	assert.Equal(t, "array", created.Type)
+	assert.Equal(t, "#/components/schemas/Node", created.Items.Ref)
+
+	assert.Panics(t, func() {
+		build(openapi.Route{Method: http.MethodGet, Path: "/nodes/:id"}, openapi.Route{Method: http.MethodGet, Path: "/nodes/:id"})
+	})
+}
+
+func TestBuild_SchemasFromTags(t *testing.T) {
+	doc := build(openapi.Route{Method: http.MethodPost, Path: "/nodes", Body: createNodeReq{}, Response: node{}})
+	schemas := doc.Components.Schemas
+
+	req := schemas["CreateNodeReq"]
+	require.NotNil(t, req)
+	assert.Equal(t, []string{"name", "rating", "tags", "cost"}, req.Required)
+	assert.Equal(t, 50, *req.Properties["name"].MaxLength)
+	assert.Equal(t, []string{"leaf", "branch"}, req.Properties["kind"].Enum)
+	assert.Equal(t, "email", req.Properties["email"].Format)
+	assert.Equal(t, 1.0, *req.Properties["rating"].Minimum)
+	assert.Equal(t, 5.0, *req.Properties["rating"].Maximum)
+	assert.Equal(t, 1, *req.Properties["tags"].MinItems)
+	assert.True(t, req.Properties["cost"].ExclusiveMinimum)
+
+	// Struct yang mereferensikan dirinya sendiri memakai $ref
+	n := schemas["Node"]
+	require.NotNil(t, n)
+	assert.NotContains(t, n.Properties, "Secret")
+	assert.Equal(t, &openapi.Schema{AllOf: []*openapi.Schema{{Ref: "#/components/schemas/Node"}}, Nullable: true}, n.Properties["parent"])
+	assert.Equal(t, "#/components/schemas/Node", n.Properties["children"].Items.Ref)
+	assert.Equal(t, &openapi.Schema{Type: "string", Format: "date-time", Nullable: true}, n.Properties["done_at"])
+
+	// Schema error problem+json selalu tersedia
+	assert.Contains(t, schemas["Problem"].Properties, "code")
+	assert.Contains(t, schemas, "FieldError")
+}
+
+func TestBuild_GenericSchemaNames(t *testing.T) {
+	doc := build(
+		openapi.Route{Method: http.MethodGet, Path: "/nodes", Query: []openapi.Param{{Name: "sort", Type: "string", Enum: []string{"id", "-id"}}}, Response: page[node]{}},
+		openapi.Route{Method: http.MethodGet, Path: "/requests", Response: page[*createNodeReq]{}},
+	)
+
+	// Nama komponen tidak memuat path package atau kurung siku
+	assert.Contains(t, doc.Components.Schemas, "PageNode")
+	assert.Contains(t, doc.Components.Schemas, "PageCreateNodeReq")
+	assert.Equal(t, "#/components/schemas/Node", doc.Components.Schemas["PageNode"].Properties["items"].Items.Ref)
+	assert.Equal(t, []string{"id", "-id"}, doc.Paths["/nodes"]["get"].Parameters[0].Schema.Enum)
+}
+
+func TestHandlers(t *testing.T) {
+	doc := build(openapi.Route{Method: http.MethodGet, Path: "/nodes/:id", Response: node{}})
+
+	rec := httptest.NewRecorder()
+	openapi.Handler(doc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	var served openapi.Document
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, doc.Operations(), served.Operations())
+
+	rec = httptest.NewRecorder()
+	openapi.DocsHandler("Test <API>", "/openapi.json", "/docs/assets").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "<title>Test &lt;API&gt;</title>")
+	assert.Contains(t, rec.Body.String(), `url: "/openapi.json"`)
+	assert.Contains(t, rec.Body.String(), `<script src="/docs/assets/swagger-ui-bundle.js">`)
+	assert.NotContains(t, rec.Body.String(), "https://")
+}
+
+func TestGinPath(t *testing.T) {
+	assert.Equal(t, "/bookings/:id/messages/:message_id/attachment", openapi.GinPath("/bookings/{id}/messages/{message_id}/attachment"))
+	assert.Equal(t, "/bookings", openapi.GinPath("/bookings"))
+}
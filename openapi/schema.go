@@ -0,0 +1,267 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/validation"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// ruleDescriptions menjelaskan aturan validasi khusus yang tidak punya
+// padanan di JSON Schema.
+var ruleDescriptions = map[string]string{
+	"phone_id":    "Indonesian mobile number (08xx, 628xx or +628xx); spaces and dashes are allowed.",
+	"future_date": "Must be a date after today (UTC).",
+	"money":       "Amount greater than 0.",
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// generator menyimpan schema struct bernama di components/schemas.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+func typeOf(v interface{}) reflect.Type {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func schemaRef(name string) string {
+	return "#/components/schemas/" + name
+}
+
+// ref mengembalikan $ref untuk struct bernama dan mendaftarkannya di
+// components; tipe lain dibuat inline.
+func (g *generator) ref(t reflect.Type) *Schema {
+	if t.Kind() != reflect.Struct || t.Name() == "" || t == timeType {
+		return g.schemaOf(t, "json")
+	}
+
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: schemaRef(name)}
+	}
+	name := componentName(t)
+	if _, taken := g.schemas[name]; taken {
+		name = componentName(t) + "_" + strings.ReplaceAll(t.PkgPath(), "/", "_")
+	}
+	g.names[t] = name
+	// Didaftarkan dulu sebelum field dibuat agar struct yang saling
+	// mereferensikan (User -> Booking -> User) tidak berulang tanpa akhir
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.objectSchema(t, "json")
+	return &Schema{Ref: schemaRef(name)}
+}
+
+// componentName memakai nama tipe dengan huruf awal kapital, sehingga DTO
+// response yang tidak diekspor (loginRes) tetap tampil sebagai LoginRes.
+// Tipe generic digabung dengan nama argumennya tanpa path package, mis.
+// Page[*entity.UserRes] menjadi PageUserRes.
+func componentName(t reflect.Type) string {
+	name, args, generic := strings.Cut(t.Name(), "[")
+	name = capitalize(name)
+	if generic {
+		for _, arg := range strings.Split(strings.TrimSuffix(args, "]"), ",") {
+			name += capitalize(arg[strings.LastIndex(arg, ".")+1:])
+		}
+	}
+	return name
+}
+
+func capitalize(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func (g *generator) schemaOf(t reflect.Type, tag string) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		inner := g.schemaOf(t.Elem(), tag)
+		if inner.Ref != "" {
+			return &Schema{AllOf: []*Schema{inner}, Nullable: true}
+		}
+		inner.Nullable = true
+		return inner
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		if t.Name() == "" {
+			return g.objectSchema(t, tag)
+		}
+		return g.ref(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem(), tag)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem(), tag)}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	default:
+		return &Schema{Type: "string"}
+	}
+}
+
+// objectSchema membuat schema object dari field struct. tag adalah tag
+// nama field: json untuk body JSON, form untuk multipart/form-data.
+func (g *generator) objectSchema(t reflect.Type, tag string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t, tag)
+	return schema
+}
+
+func (g *generator) addFields(schema *Schema, t reflect.Type, tag string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			continue
+		}
+		// Struct embedded tanpa nama ikut diratakan seperti encoding/json
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(schema, field.Type, tag)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := g.schemaOf(field.Type, tag)
+		if field.Type == timeType && field.Tag.Get("time_format") == "2006-01-02" {
+			property.Format = "date"
+		}
+		if applyRules(property, field.Type, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+// formSchema membuat schema multipart/form-data: field dari tag form dan
+// file sebagai string binary.
+func (g *generator) formSchema(t reflect.Type, files []Param) *Schema {
+	schema := g.objectSchema(t, "form")
+	for _, file := range files {
+		schema.Properties[file.Name] = &Schema{Type: "string", Format: "binary", Description: file.Description}
+		if file.Required {
+			schema.Required = append(schema.Required, file.Name)
+		}
+	}
+	return schema
+}
+
+// applyRules menerjemahkan tag validate ke batasan schema dan melaporkan
+// apakah field wajib. Aturan setelah dive berlaku untuk elemen slice dan
+// diabaikan.
+func applyRules(schema *Schema, t reflect.Type, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "email_addr":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "rating":
+			schema.Minimum, schema.Maximum = float(validation.MinRating), float(validation.MaxRating)
+		case "min", "max":
+			limit(schema, t, name, param)
+		}
+		if description, ok := ruleDescriptions[name]; ok {
+			schema.Description = description
+		}
+		if name == "money" && t.Kind() != reflect.String {
+			schema.Minimum, schema.ExclusiveMinimum = float(0), true
+		}
+	}
+	return required
+}
+
+// limit memasang min/max sesuai tipe: panjang string, jumlah elemen slice
+// atau nilai angka, sama seperti validator.
+func limit(schema *Schema, t reflect.Type, rule, param string) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if rule == "min" {
+			schema.MinLength = &n
+		} else {
+			schema.MaxLength = &n
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if rule == "min" {
+			schema.MinItems = &n
+		} else {
+			schema.MaxItems = &n
+		}
+	default:
+		if rule == "min" {
+			schema.Minimum = float(n)
+		} else {
+			schema.Maximum = float(n)
+		}
+	}
+}
+
+func float(n int) *float64 {
+	f := float64(n)
+	return &f
+}
@@ -0,0 +1,316 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/scheduler"
+)
+
+const (
+	defaultMaxAttempts = 10
+	tailBatchSize      = 100
+	// tailLookback adalah jumlah ID di bawah cursor yang dibaca ulang setiap
+	// tick. ID outbox dibagikan saat insert, bukan saat commit, sehingga
+	// transaksi yang lebih lama bisa commit dengan ID di bawah cursor.
+	tailLookback = 100
+)
+
+type subscriber struct {
+	name    string
+	handler event.Handler
+}
+
+// Dispatcher mengirim event dari tabel outbox ke subscriber in-process.
+//
+// Subscriber yang didaftarkan dengan Subscribe menerima setiap event minimal
+// sekali di seluruh replica; event yang sudah diproses dicatat di tabel
+// processed_events sehingga pengiriman ulang dilewati. Subscriber Broadcast
+// (mis. hub realtime) menerima event di setiap replica tanpa retry; event yang
+// commit terlambat dengan ID hingga tailLookback di bawah event terbaru tetap
+// dikirim, masing-masing sekali.
+type Dispatcher struct {
+	repo        repository.OutboxRepository
+	interval    time.Duration
+	lockTimeout time.Duration
+	batchSize   int
+	maxAttempts int
+
+	mu          sync.RWMutex
+	subscribers []subscriber
+	broadcast   []event.Handler
+	floor       int
+	cursor      int
+	seen        map[int]struct{}
+	running     bool
+	lastTick    time.Time
+
+	now    func() time.Time
+	stop   chan struct{}
+	done   chan struct{}
+	closer sync.Once
+}
+
+func NewDispatcher(repo repository.OutboxRepository) *Dispatcher {
+	return &Dispatcher{
+		repo:        repo,
+		interval:    500 * time.Millisecond,
+		lockTimeout: 2 * time.Minute,
+		batchSize:   50,
+		maxAttempts: defaultMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// Subscribe mendaftarkan subscriber dengan nama unik. Nama dipakai sebagai
+// kunci idempotensi, jadi jangan diubah setelah ada event yang diproses.
+func (d *Dispatcher) Subscribe(name string, handler event.Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers = append(d.subscribers, subscriber{name: name, handler: handler})
+}
+
+// Broadcast mendaftarkan handler yang dijalankan di setiap replica untuk
+// setiap event baru, mis. untuk meneruskan event ke koneksi realtime lokal.
+func (d *Dispatcher) Broadcast(handler event.Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.broadcast = append(d.broadcast, handler)
+}
+
+// Start menjalankan dispatcher. Broadcast dimulai dari event terbaru saat start.
+func (d *Dispatcher) Start() error {
+	ctx := context.Background()
+	cursor, err := d.repo.LatestID(ctx)
+	if err != nil {
+		return err
+	}
+	d.floor = cursor
+	d.cursor = cursor
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+
+	d.heartbeat(true)
+	go func() {
+		defer close(d.done)
+		defer d.heartbeat(false)
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			d.heartbeat(true)
+			d.Tick(ctx)
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop menghentikan dispatcher setelah batch yang sedang berjalan selesai.
+func (d *Dispatcher) Stop() {
+	if d.stop == nil {
+		return
+	}
+	d.closer.Do(func() {
+		close(d.stop)
+		<-d.done
+	})
+}
+
+// Check mengembalikan error jika loop dispatcher tidak berjalan, atau tidak
+// memulai tick baru selama lebih dari lockTimeout (mis. tertahan subscriber
+// yang macet). Dipakai oleh pemeriksaan readiness.
+func (d *Dispatcher) Check(ctx context.Context) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if !d.running {
+		return errors.New("outbox dispatcher is not running")
+	}
+	if idle := d.now().Sub(d.lastTick); idle > d.lockTimeout {
+		return fmt.Errorf("outbox dispatcher has not ticked for %s", idle.Round(time.Second))
+	}
+	return nil
+}
+
+func (d *Dispatcher) heartbeat(running bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.running = running
+	d.lastTick = d.now()
+}
+
+// Tick meneruskan event baru ke subscriber Broadcast lalu mengirim event yang
+// jatuh tempo ke subscriber biasa.
+func (d *Dispatcher) Tick(ctx context.Context) {
+	d.tail(ctx)
+	d.deliverDue(ctx)
+}
+
+func (d *Dispatcher) tail(ctx context.Context) {
+	d.mu.RLock()
+	handlers := d.broadcast
+	d.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		return
+	}
+
+	// Baca ulang jendela tailLookback ID di bawah cursor agar event yang commit
+	// terlambat tetap terkirim; event yang sudah dikirim dilewati lewat seen.
+	from := max(d.cursor-tailLookback, d.floor)
+	rows, err := d.repo.FindAfter(ctx, from, tailLookback+tailBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to read new events", "error", err)
+		return
+	}
+	if d.seen == nil {
+		d.seen = make(map[int]struct{})
+	}
+
+	for _, row := range rows {
+		if _, ok := d.seen[row.ID]; ok {
+			continue
+		}
+		d.seen[row.ID] = struct{}{}
+		d.cursor = max(d.cursor, row.ID)
+
+		evt, err := ToEvent(row)
+		if err != nil {
+			slog.ErrorContext(ctx, "outbox: failed to decode event", "event_id", row.ID, "error", err)
+			continue
+		}
+		for _, handler := range handlers {
+			if err := safeHandle(ctx, handler, evt); err != nil {
+				slog.WarnContext(ctx, "outbox: broadcast failed", "event_id", row.ID, "event_type", row.Type, "error", err)
+			}
+		}
+	}
+
+	for id := range d.seen {
+		if id <= d.cursor-tailLookback {
+			delete(d.seen, id)
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	now := d.now()
+	staleBefore := now.Add(-d.lockTimeout)
+
+	rows, err := d.repo.FindDue(ctx, now, staleBefore, d.batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to load pending events", "error", err)
+		return
+	}
+
+	for _, row := range rows {
+		claimed, err := d.repo.Claim(ctx, row.ID, now, staleBefore)
+		if err != nil {
+			slog.ErrorContext(ctx, "outbox: failed to claim event", "event_id", row.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			// Sudah diklaim oleh replica lain
+			continue
+		}
+
+		row.Attempts++
+		eventCtx := logging.With(ctx, "event_id", row.ID, "event_type", row.Type)
+		d.finish(eventCtx, row, d.Deliver(eventCtx, row))
+	}
+}
+
+// Deliver mengirim satu event ke semua subscriber yang belum memprosesnya.
+func (d *Dispatcher) Deliver(ctx context.Context, row entity.OutboxEvent) error {
+	evt, err := ToEvent(row)
+	if err != nil {
+		return err
+	}
+
+	d.mu.RLock()
+	subscribers := d.subscribers
+	d.mu.RUnlock()
+
+	var failures []string
+	for _, sub := range subscribers {
+		processed, err := d.repo.IsProcessed(ctx, row.ID, sub.name)
+		if err != nil {
+			return err
+		}
+		if processed {
+			continue
+		}
+
+		if err := safeHandle(ctx, sub.handler, evt); err != nil {
+			failures = append(failures, sub.name+": "+err.Error())
+			continue
+		}
+
+		if err := d.repo.MarkProcessed(ctx, row.ID, sub.name); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func (d *Dispatcher) finish(ctx context.Context, row entity.OutboxEvent, deliverErr error) {
+	var err error
+	switch {
+	case deliverErr == nil:
+		err = d.repo.MarkDelivered(ctx, row.ID, d.now())
+	case row.Attempts >= d.maxAttempts:
+		slog.ErrorContext(ctx, "outbox: event failed permanently", "attempts", row.Attempts, "error", deliverErr)
+		err = d.repo.Fail(ctx, row.ID, deliverErr.Error())
+	default:
+		err = d.repo.Retry(ctx, row.ID, d.now().Add(scheduler.Backoff(row.Attempts)), deliverErr.Error())
+	}
+
+	if err != nil {
+		slog.ErrorContext(ctx, "outbox: failed to update event", "error", err)
+	}
+}
+
+// ToEvent mengubah baris outbox menjadi event. Data berisi JSON asli payload.
+func ToEvent(row entity.OutboxEvent) (event.Event, error) {
+	evt := event.Event{
+		ID:         row.ID,
+		Type:       row.Type,
+		Data:       json.RawMessage(row.Payload),
+		OccurredAt: row.OccurredAt,
+	}
+	if row.Recipients != "" {
+		if err := json.Unmarshal([]byte(row.Recipients), &evt.Recipients); err != nil {
+			return evt, err
+		}
+	}
+	return evt, nil
+}
+
+// safeHandle mengubah panic di handler menjadi error.
+func safeHandle(ctx context.Context, handler event.Handler, evt event.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, evt)
+}
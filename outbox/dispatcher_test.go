@@ -0,0 +1,316 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+)
+
+type memoryOutboxRepository struct {
+	mu        sync.Mutex
+	events    []*entity.OutboxEvent
+	processed map[string]bool
+	// uncommitted menyembunyikan event dari FindAfter untuk meniru transaksi
+	// yang belum commit
+	uncommitted map[int]bool
+}
+
+func newMemoryOutboxRepository() *memoryOutboxRepository {
+	return &memoryOutboxRepository{processed: make(map[string]bool)}
+}
+
+func (r *memoryOutboxRepository) Append(ctx context.Context, events ...event.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, evt := range events {
+		recipients, _ := json.Marshal(evt.Recipients)
+		payload, _ := json.Marshal(evt.Data)
+		r.events = append(r.events, &entity.OutboxEvent{
+			ID:         len(r.events) + 1,
+			Type:       evt.Type,
+			Recipients: string(recipients),
+			Payload:    string(payload),
+			Status:     "Pending",
+		})
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) due(evt *entity.OutboxEvent, now, staleBefore time.Time) bool {
+	return (evt.Status == "Pending" && !evt.AvailableAt.After(now)) ||
+		(evt.Status == "Processing" && evt.LockedAt != nil && evt.LockedAt.Before(staleBefore))
+}
+
+func (r *memoryOutboxRepository) FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []entity.OutboxEvent
+	for _, evt := range r.events {
+		if r.due(evt, now, staleBefore) && len(events) < limit {
+			events = append(events, *evt)
+		}
+	}
+	return events, nil
+}
+
+func (r *memoryOutboxRepository) Claim(ctx context.Context, id int, now, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt := r.events[id-1]
+	if !r.due(evt, now, staleBefore) {
+		return false, nil
+	}
+	evt.Status = "Processing"
+	evt.LockedAt = &now
+	evt.Attempts++
+	return true, nil
+}
+
+func (r *memoryOutboxRepository) MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error {
+	return r.update(id, func(evt *entity.OutboxEvent) {
+		evt.Status = "Delivered"
+		evt.DeliveredAt = &deliveredAt
+	})
+}
+
+func (r *memoryOutboxRepository) Retry(ctx context.Context, id int, availableAt time.Time, lastError string) error {
+	return r.update(id, func(evt *entity.OutboxEvent) {
+		evt.Status = "Pending"
+		evt.AvailableAt = availableAt
+		evt.LastError = lastError
+	})
+}
+
+func (r *memoryOutboxRepository) Fail(ctx context.Context, id int, lastError string) error {
+	return r.update(id, func(evt *entity.OutboxEvent) {
+		evt.Status = "Failed"
+		evt.LastError = lastError
+	})
+}
+
+func (r *memoryOutboxRepository) update(id int, fn func(evt *entity.OutboxEvent)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.events[id-1])
+	r.events[id-1].LockedAt = nil
+	return nil
+}
+
+func (r *memoryOutboxRepository) FindAfter(ctx context.Context, id int, limit int) ([]entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []entity.OutboxEvent
+	for _, evt := range r.events {
+		if evt.ID > id && !r.uncommitted[evt.ID] && len(events) < limit {
+			events = append(events, *evt)
+		}
+	}
+	return events, nil
+}
+
+func (r *memoryOutboxRepository) LatestID(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events), nil
+}
+
+func (r *memoryOutboxRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.OutboxEvent], error) {
+	return listquery.Page[entity.OutboxEvent]{}, nil
+}
+
+func (r *memoryOutboxRepository) FindByID(ctx context.Context, id int) (entity.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.events[id-1], nil
+}
+
+func (r *memoryOutboxRepository) Replay(ctx context.Context, id int, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evt := r.events[id-1]
+	if evt.Status != "Failed" {
+		return false, nil
+	}
+	evt.Status = "Pending"
+	evt.Attempts = 0
+	evt.AvailableAt = now
+	return true, nil
+}
+
+func (r *memoryOutboxRepository) IsProcessed(ctx context.Context, eventID int, handler string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.processed[fmt.Sprintf("%s:%d", handler, eventID)], nil
+}
+
+func (r *memoryOutboxRepository) MarkProcessed(ctx context.Context, eventID int, handler string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.processed[fmt.Sprintf("%s:%d", handler, eventID)] = true
+	return nil
+}
+
+func newTestDispatcher(repo *memoryOutboxRepository, now *time.Time) *Dispatcher {
+	d := NewDispatcher(repo)
+	d.now = func() time.Time { return *now }
+	return d
+}
+
+func TestDispatcherDeliversEventToSubscribersAndBroadcast(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	d := newTestDispatcher(repo, &now)
+
+	var received, broadcast []event.Event
+	d.Subscribe("notification", func(ctx context.Context, evt event.Event) error {
+		received = append(received, evt)
+		return nil
+	})
+	d.Broadcast(func(ctx context.Context, evt event.Event) error {
+		broadcast = append(broadcast, evt)
+		return nil
+	})
+
+	_ = repo.Append(context.Background(), event.Event{Type: "booking.created", Recipients: map[string]int{"customer": 3}, Data: map[string]int{"id": 12}})
+	d.Tick(context.Background())
+
+	if len(received) != 1 || len(broadcast) != 1 {
+		t.Fatalf("expected event to be delivered once to each handler, got %d and %d", len(received), len(broadcast))
+	}
+	if received[0].ID != 1 || received[0].Recipients["customer"] != 3 || string(received[0].Data.(json.RawMessage)) != `{"id":12}` {
+		t.Errorf("unexpected event: %+v", received[0])
+	}
+	if repo.events[0].Status != "Delivered" {
+		t.Errorf("expected event to be Delivered, got %s", repo.events[0].Status)
+	}
+
+	d.Tick(context.Background())
+	if len(received) != 1 || len(broadcast) != 1 {
+		t.Fatalf("expected delivered event not to be sent again")
+	}
+}
+
+func TestDispatcherBroadcastsLateCommittedEventsOnce(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	d := newTestDispatcher(repo, &now)
+
+	var ids []int
+	d.Broadcast(func(ctx context.Context, evt event.Event) error {
+		ids = append(ids, evt.ID)
+		return nil
+	})
+
+	// Event 1 mendapat ID lebih dulu tetapi commit setelah event 2
+	_ = repo.Append(context.Background(), event.Event{Type: "booking.created"}, event.Event{Type: "payment.paid"})
+	repo.uncommitted = map[int]bool{1: true}
+	d.Tick(context.Background())
+
+	repo.uncommitted = nil
+	d.Tick(context.Background())
+	d.Tick(context.Background())
+
+	if fmt.Sprint(ids) != "[2 1]" {
+		t.Fatalf("expected each event to be broadcast once, got %v", ids)
+	}
+}
+
+func TestDispatcherRetriesOnlyFailedSubscribers(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	d := newTestDispatcher(repo, &now)
+
+	notified, webhookCalls := 0, 0
+	d.Subscribe("notification", func(ctx context.Context, evt event.Event) error {
+		notified++
+		return nil
+	})
+	d.Subscribe("webhook", func(ctx context.Context, evt event.Event) error {
+		webhookCalls++
+		if webhookCalls < 3 {
+			return errors.New("endpoint unavailable")
+		}
+		return nil
+	})
+
+	_ = repo.Append(context.Background(), event.Event{Type: "payment.paid"})
+
+	for i := 0; i < 3; i++ {
+		d.Tick(context.Background())
+		now = now.Add(time.Hour)
+	}
+
+	if notified != 1 {
+		t.Errorf("expected notification subscriber to process the event once, got %d", notified)
+	}
+	if webhookCalls != 3 || repo.events[0].Status != "Delivered" || repo.events[0].Attempts != 3 {
+		t.Errorf("expected webhook to succeed on third attempt, got %d calls, status %s", webhookCalls, repo.events[0].Status)
+	}
+}
+
+func TestDispatcherMarksEventFailedAndReplays(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	d := newTestDispatcher(repo, &now)
+	d.maxAttempts = 2
+
+	healthy := false
+	d.Subscribe("webhook", func(ctx context.Context, evt event.Event) error {
+		if !healthy {
+			return errors.New("endpoint unavailable")
+		}
+		return nil
+	})
+
+	_ = repo.Append(context.Background(), event.Event{Type: "review.created"})
+	d.Tick(context.Background())
+	now = now.Add(time.Hour)
+	d.Tick(context.Background())
+
+	if repo.events[0].Status != "Failed" || repo.events[0].LastError != "webhook: endpoint unavailable" {
+		t.Fatalf("expected event to fail after max attempts, got %s (%s)", repo.events[0].Status, repo.events[0].LastError)
+	}
+
+	healthy = true
+	if ok, _ := repo.Replay(context.Background(), 1, now); !ok {
+		t.Fatal("expected failed event to be replayable")
+	}
+	d.Tick(context.Background())
+
+	if repo.events[0].Status != "Delivered" {
+		t.Errorf("expected replayed event to be delivered, got %s", repo.events[0].Status)
+	}
+}
+
+func TestDispatcherCheckReportsStoppedOrStuckLoop(t *testing.T) {
+	repo := newMemoryOutboxRepository()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	d := newTestDispatcher(repo, &now)
+
+	if err := d.Check(context.Background()); err == nil {
+		t.Fatal("expected dispatcher that was never started to be unhealthy")
+	}
+
+	d.heartbeat(true)
+	if err := d.Check(context.Background()); err != nil {
+		t.Fatalf("expected running dispatcher to be healthy, got %v", err)
+	}
+
+	now = now.Add(d.lockTimeout + time.Second)
+	if err := d.Check(context.Background()); err == nil || err.Error() != "outbox dispatcher has not ticked for 2m1s" {
+		t.Fatalf("expected stuck dispatcher to be unhealthy, got %v", err)
+	}
+
+	d.heartbeat(false)
+	if err := d.Check(context.Background()); err == nil || err.Error() != "outbox dispatcher is not running" {
+		t.Fatalf("expected stopped dispatcher to be unhealthy, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Lockout mengunci akun sementara setelah login gagal berulang. Setiap login
+// gagal mengambil satu token dari bucket akun; setelah MaxFailures kegagalan
+// akun terkunci selama Duration, dan setiap kegagalan berikutnya mengunci
+// lagi selama Duration. Login yang berhasil menghapus hitungan kegagalan.
+type Lockout struct {
+	store Store
+	limit Limit
+}
+
+// NewLockout mengembalikan nil jika maxFailures atau duration tidak positif,
+// yang berarti lockout dinonaktifkan.
+func NewLockout(store Store, maxFailures int, duration time.Duration) *Lockout {
+	if maxFailures <= 0 || duration <= 0 {
+		return nil
+	}
+	// Satu token kembali setiap duration, sehingga bucket yang kosong
+	// terkunci tepat selama duration
+	return &Lockout{store: store, limit: Limit{Requests: maxFailures, Period: duration * time.Duration(maxFailures)}}
+}
+
+func lockoutKey(account string) string {
+	return "login_failures:" + account
+}
+
+// Check mengembalikan sisa waktu kunci, atau 0 jika akun boleh mencoba login.
+// Semua method Lockout aman dipanggil pada nil (lockout dinonaktifkan).
+func (l *Lockout) Check(ctx context.Context, account string) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+	result, err := l.store.Peek(ctx, lockoutKey(account), l.limit)
+	if err != nil || result.Allowed {
+		return 0, err
+	}
+	return result.RetryAfter, nil
+}
+
+// Failed mencatat satu login gagal.
+func (l *Lockout) Failed(ctx context.Context, account string) error {
+	if l == nil {
+		return nil
+	}
+	_, err := l.store.Take(ctx, lockoutKey(account), l.limit)
+	return err
+}
+
+// Succeeded menghapus hitungan kegagalan setelah login berhasil.
+func (l *Lockout) Succeeded(ctx context.Context, account string) error {
+	if l == nil {
+		return nil
+	}
+	return l.store.Reset(ctx, lockoutKey(account))
+}
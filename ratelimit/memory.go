@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval adalah jarak minimum antara dua pembersihan bucket yang
+// sudah penuh kembali.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // Saat bucket terisi penuh dan boleh dihapus
+}
+
+// MemoryStore menyimpan bucket di memori proses. Setiap replica punya
+// hitungan sendiri, jadi batas efektif dikalikan jumlah replica.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return s.apply(key, limit, true), nil
+}
+
+func (s *MemoryStore) Peek(ctx context.Context, key string, limit Limit) (Result, error) {
+	return s.apply(key, limit, false), nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.buckets, key)
+	return nil
+}
+
+func (s *MemoryStore) apply(key string, limit Limit, take bool) Result {
+	if !limit.Enabled() {
+		return Result{Allowed: true}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Requests)
+	interval := limit.interval()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+	}
+	// Isi ulang token sesuai waktu yang berlalu sejak akses terakhir
+	b.tokens += float64(now.Sub(b.updated)) / float64(interval)
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.updated = now
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		result.Allowed = true
+		if take {
+			b.tokens--
+		}
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+	result.Remaining = int(b.tokens)
+	result.ResetAfter = time.Duration((capacity - b.tokens) * float64(interval))
+	b.full = now.Add(result.ResetAfter)
+
+	if take || ok {
+		s.buckets[key] = b
+	}
+	return result
+}
+
+// sweep menghapus bucket yang sudah penuh kembali; bucket baru akan dibuat
+// penuh sehingga hasilnya sama.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
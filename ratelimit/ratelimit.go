@@ -0,0 +1,117 @@
+// Package ratelimit membatasi jumlah request dengan token bucket per kunci
+// (mis. alamat IP atau akun) dan mengunci akun sementara setelah login gagal
+// berulang. State bucket disimpan di Store; MemoryStore cukup untuk satu
+// replica, sedangkan deployment dengan banyak replica bisa memasang Store
+// terdistribusi (mis. Redis) tanpa mengubah middleware maupun service.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit adalah kapasitas token bucket: Requests token yang terisi penuh
+// kembali dalam Period. Ditulis di konfigurasi sebagai "10/1m"; Limit kosong
+// ("0") berarti tanpa batas.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled melaporkan apakah limit membatasi request.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// interval adalah waktu untuk mengisi satu token.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "0"
+	}
+	// Tulis "1m" dan "1h" alih-alih "1m0s" dan "1h0m0s"
+	period := l.Period.String()
+	if strings.HasSuffix(period, "m0s") {
+		period = strings.TrimSuffix(period, "0s")
+	}
+	if strings.HasSuffix(period, "h0m") {
+		period = strings.TrimSuffix(period, "0m")
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, period)
+}
+
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Limit) UnmarshalText(text []byte) error {
+	parsed, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// ParseLimit membaca limit berformat "<requests>/<period>", mis. "10/1m"
+// atau "300/1h". "0" atau string kosong menonaktifkan limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q (use <requests>/<period>, e.g. 10/1m)", value)
+	}
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: requests must be a positive number", value)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", value)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// Result adalah keadaan bucket setelah Take atau Peek.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // Waktu sampai token berikutnya tersedia jika Allowed false
+	ResetAfter time.Duration // Waktu sampai bucket terisi penuh
+}
+
+// RetryAfterSeconds membulatkan durasi ke atas dalam detik untuk header
+// Retry-After.
+func RetryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Store menyimpan token bucket. Implementasi harus aman dipakai bersamaan
+// dan Take harus atomik per kunci.
+type Store interface {
+	// Take mengambil satu token dari bucket key jika tersedia.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Peek mengembalikan keadaan bucket tanpa mengambil token.
+	Peek(ctx context.Context, key string, limit Limit) (Result, error)
+	// Reset mengisi penuh bucket key.
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy adalah batas untuk satu grup route: per alamat IP dan per akun.
+// Limit yang kosong tidak diterapkan.
+type Policy struct {
+	IP      Limit
+	Account Limit
+}
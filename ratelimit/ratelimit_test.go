@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(now *time.Time) *MemoryStore {
+	s := NewMemoryStore()
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("10/1m")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 10, Period: time.Minute}, limit)
+	assert.Equal(t, "10/1m", limit.String())
+	assert.Equal(t, "300/1h", Limit{Requests: 300, Period: time.Hour}.String())
+	assert.Equal(t, "5/1m30s", Limit{Requests: 5, Period: 90 * time.Second}.String())
+
+	limit, err = ParseLimit("0")
+	require.NoError(t, err)
+	assert.False(t, limit.Enabled())
+
+	for _, invalid := range []string{"10", "x/1m", "-1/1m", "10/soon", "10/0s"} {
+		_, err := ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	store := newTestStore(&now)
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	for remaining := 2; remaining >= 0; remaining-- {
+		result, err := store.Take(ctx, "ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, remaining, result.Remaining)
+	}
+
+	result, _ := store.Take(ctx, "ip:10.0.0.1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// Bucket lain tidak terpengaruh
+	result, _ = store.Take(ctx, "ip:10.0.0.2", limit)
+	assert.True(t, result.Allowed)
+
+	// Satu token kembali setiap detik
+	now = now.Add(1500 * time.Millisecond)
+	result, _ = store.Peek(ctx, "ip:10.0.0.1", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+	result, _ = store.Take(ctx, "ip:10.0.0.1", limit)
+	assert.True(t, result.Allowed)
+	result, _ = store.Take(ctx, "ip:10.0.0.1", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	require.NoError(t, store.Reset(ctx, "ip:10.0.0.1"))
+	result, _ = store.Peek(ctx, "ip:10.0.0.1", limit)
+	assert.Equal(t, 3, result.Remaining)
+
+	// Bucket yang sudah penuh kembali dibersihkan
+	now = now.Add(time.Hour)
+	store.Take(ctx, "ip:10.0.0.3", limit)
+	assert.Len(t, store.buckets, 1)
+}
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	lockout := NewLockout(newTestStore(&now), 3, 15*time.Minute)
+
+	for i := 0; i < 3; i++ {
+		locked, err := lockout.Check(ctx, "dewi@example.com")
+		require.NoError(t, err)
+		assert.Zero(t, locked)
+		require.NoError(t, lockout.Failed(ctx, "dewi@example.com"))
+	}
+
+	locked, _ := lockout.Check(ctx, "dewi@example.com")
+	assert.Equal(t, 15*time.Minute, locked)
+	locked, _ = lockout.Check(ctx, "budi@example.com")
+	assert.Zero(t, locked)
+
+	// Setelah kunci habis satu percobaan diizinkan; gagal lagi mengunci lagi
+	now = now.Add(15 * time.Minute)
+	locked, _ = lockout.Check(ctx, "dewi@example.com")
+	assert.Zero(t, locked)
+	require.NoError(t, lockout.Failed(ctx, "dewi@example.com"))
+	locked, _ = lockout.Check(ctx, "dewi@example.com")
+	assert.Equal(t, 15*time.Minute, locked)
+
+	// Login berhasil menghapus hitungan
+	require.NoError(t, lockout.Succeeded(ctx, "dewi@example.com"))
+	locked, _ = lockout.Check(ctx, "dewi@example.com")
+	assert.Zero(t, locked)
+
+	assert.Nil(t, NewLockout(NewMemoryStore(), 0, time.Minute))
+}
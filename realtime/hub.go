@@ -0,0 +1,113 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+)
+
+// Event adalah pesan yang dikirim ke client yang sedang terhubung.
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Hub menyimpan subscriber per user dan meneruskan event ke semua koneksi
+// milik user tersebut. Hub aman dipakai oleh banyak publisher sekaligus.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[int]map[chan Event]struct{}
+	closed      bool
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[int]map[chan Event]struct{})}
+}
+
+// Subscribe mendaftarkan koneksi baru untuk user. Fungsi yang dikembalikan
+// harus dipanggil saat koneksi ditutup; fungsi tersebut menghapus subscriber
+// dan menutup channel-nya, dan aman dipanggil lebih dari sekali.
+func (h *Hub) Subscribe(userID int) (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+
+	h.mu.Lock()
+	if h.closed {
+		// Server sedang berhenti: koneksi baru langsung ditutup
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan Event]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			// Channel sudah ditutup oleh Close
+			if _, ok := h.subscribers[userID][ch]; !ok {
+				return
+			}
+			delete(h.subscribers[userID], ch)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish mengirim event ke semua koneksi milik userIDs. Koneksi yang lambat
+// (buffer penuh) dilewati agar publisher tidak ikut tertahan.
+func (h *Hub) Publish(userIDs []int, evt Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sent := make(map[int]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if sent[userID] {
+			continue
+		}
+		sent[userID] = true
+
+		for ch := range h.subscribers[userID] {
+			select {
+			case ch <- evt:
+			default:
+			}
+		}
+	}
+}
+
+// Close menutup semua koneksi yang sedang terbuka sehingga handler SSE dan
+// WebSocket selesai, lalu menolak subscriber baru. Dipanggil saat server
+// berhenti agar koneksi streaming tidak menahan graceful shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for userID, channels := range h.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(h.subscribers, userID)
+	}
+}
+
+// Subscribers mengembalikan jumlah koneksi aktif milik user.
+func (h *Hub) Subscribers(userID int) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers[userID])
+}
+
+// HandleEvent meneruskan domain event dari outbox ke user yang terkait.
+func (h *Hub) HandleEvent(ctx context.Context, evt event.Event) error {
+	h.Publish(evt.UserIDs(), Event{Type: evt.Type, Data: evt.Data})
+	return nil
+}
@@ -0,0 +1,84 @@
+package realtime_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_PublishToSubscribedUsers(t *testing.T) {
+	hub := realtime.NewHub()
+
+	customer, unsubscribeCustomer := hub.Subscribe(1)
+	defer unsubscribeCustomer()
+	other, unsubscribeOther := hub.Subscribe(2)
+	defer unsubscribeOther()
+
+	hub.Publish([]int{1, 1}, realtime.Event{Type: "booking.status_changed", Data: "Confirmed"})
+
+	assert.Equal(t, realtime.Event{Type: "booking.status_changed", Data: "Confirmed"}, <-customer)
+	assert.Len(t, customer, 0, "duplicate recipients must receive the event once")
+	assert.Len(t, other, 0)
+}
+
+func TestHub_ConcurrentPublishAndUnsubscribe(t *testing.T) {
+	hub := realtime.NewHub()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			events, unsubscribe := hub.Subscribe(7)
+			for j := 0; j < 5; j++ {
+				select {
+				case <-events:
+				default:
+				}
+			}
+			unsubscribe()
+			unsubscribe() // aman dipanggil lebih dari sekali
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				hub.Publish([]int{7}, realtime.Event{Type: "message.created"})
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, hub.Subscribers(7), "disconnected subscribers must be removed")
+}
+
+func TestHub_UnsubscribeClosesChannel(t *testing.T) {
+	hub := realtime.NewHub()
+
+	events, unsubscribe := hub.Subscribe(3)
+	unsubscribe()
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.NotPanics(t, func() { hub.Publish([]int{3}, realtime.Event{Type: "review.created"}) })
+}
+
+func TestHub_CloseEndsAllStreams(t *testing.T) {
+	hub := realtime.NewHub()
+
+	events, unsubscribe := hub.Subscribe(3)
+	hub.Close()
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.Equal(t, 0, hub.Subscribers(3))
+	assert.NotPanics(t, unsubscribe)
+
+	// Subscriber baru setelah Close langsung ditutup
+	late, _ := hub.Subscribe(4)
+	_, ok = <-late
+	assert.False(t, ok)
+}
@@ -1,27 +1,40 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"gorm.io/gorm"
 )
 
+// BookingEvents membentuk domain event dari booking yang baru disimpan
+// (termasuk relasi Service). Event disimpan ke outbox dalam transaksi yang sama.
+type BookingEvents func(booking entity.Booking) []event.Event
+
 type BookingRepository interface {
-	Create(booking entity.Booking) (entity.Booking, error)
-	FindByID(id int) (entity.Booking, error)
-	FindAll(limit, offset int) ([]entity.Booking, error)
-	Update(booking entity.Booking) (entity.Booking, error)
-	Delete(id int) error
-	GetBookingsByUserID(userID int) ([]entity.Booking, error)
-	GetBookingsByServiceID(serviceID int) ([]entity.Booking, error)
-	UpdateBookingStatus(bookingID string, status string) error
-	GetTotalBookings(startDate, endDate time.Time) (int64, error)
-	GetTotalRevenue(startDate, endDate time.Time) (float64, error)
-	GetBookingsByStatus(status string, startDate, endDate time.Time) (int64, float64, error)
-	CheckServiceAvailability(serviceID int, date time.Time) (bool, error)
-	GetBookedDates(serviceID int, year int, month int) ([]time.Time, error)
-	GetConfirmedBookingsByTechnicianID(technicianID int) ([]entity.Booking, error)
+	Create(ctx context.Context, booking entity.Booking, events BookingEvents) (entity.Booking, error)
+	FindByID(ctx context.Context, id int) (entity.Booking, error)
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Booking], error)
+	Update(ctx context.Context, booking entity.Booking, events BookingEvents) (entity.Booking, error)
+	Delete(ctx context.Context, id int) error
+	UpdateBookingStatus(ctx context.Context, bookingID string, status string, events BookingEvents) error
+	GetTotalBookings(ctx context.Context, startDate, endDate time.Time) (int64, error)
+	GetTotalRevenue(ctx context.Context, startDate, endDate time.Time) (float64, error)
+	GetBookingsByStatus(ctx context.Context, status string, startDate, endDate time.Time) (int64, float64, error)
+	CheckServiceAvailability(ctx context.Context, serviceID int, date time.Time) (bool, error)
+	GetBookedDates(ctx context.Context, serviceID int, year int, month int) ([]time.Time, error)
+	GetConfirmedBookingsByTechnicianID(ctx context.Context, technicianID int, q listquery.Query) (listquery.Page[entity.Booking], error)
+	FindStalePending(ctx context.Context, createdBefore, dateBefore time.Time) ([]entity.Booking, error)
+	FindByStatusAndDate(ctx context.Context, status string, date time.Time) ([]entity.Booking, error)
+	FindByStatusBeforeDate(ctx context.Context, status string, date time.Time) ([]entity.Booking, error)
+	FindCompletedWithoutReview(ctx context.Context, updatedAfter, updatedBefore time.Time) ([]entity.Booking, error)
+	TransitionStatus(ctx context.Context, bookingID int, from, to string, events BookingEvents) (bool, error)
+	FindIDsByUserID(ctx context.Context, userID int) ([]int, error)
+	DeleteByIDs(ctx context.Context, ids []int) error
+	FindIDsByStatusUpdatedBefore(ctx context.Context, status string, before time.Time) ([]int, error)
 }
 
 type bookingRepository struct {
@@ -32,56 +45,57 @@ func NewBookingRepository(db *gorm.DB) BookingRepository {
 	return &bookingRepository{db}
 }
 
-func (r *bookingRepository) Create(booking entity.Booking) (entity.Booking, error) {
-	err := r.db.Create(&booking).Error
+func (r *bookingRepository) Create(ctx context.Context, booking entity.Booking, events BookingEvents) (entity.Booking, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&booking).Error; err != nil {
+			return err
+		}
+		return appendBookingEvents(tx, booking.ID, events)
+	})
 	return booking, err
 }
 
-func (r *bookingRepository) FindByID(id int) (entity.Booking, error) {
+func (r *bookingRepository) FindByID(ctx context.Context, id int) (entity.Booking, error) {
 	var booking entity.Booking
-	err := r.db.Preload("User").Preload("Service").First(&booking, id).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Service").First(&booking, id).Error
 	return booking, err
 }
 
-func (r *bookingRepository) FindAll(limit, offset int) ([]entity.Booking, error) {
-	var bookings []entity.Booking
-	err := r.db.Limit(limit).Offset(offset).Find(&bookings).Error
-	return bookings, err
+func (r *bookingRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Booking], error) {
+	return findPage[entity.Booking](r.db.WithContext(ctx), "bookings", q)
 }
 
-func (r *bookingRepository) Update(booking entity.Booking) (entity.Booking, error) {
-	err := r.db.Save(&booking).Error
+func (r *bookingRepository) Update(ctx context.Context, booking entity.Booking, events BookingEvents) (entity.Booking, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&booking).Error; err != nil {
+			return err
+		}
+		return appendBookingEvents(tx, booking.ID, events)
+	})
 	return booking, err
 }
 
-func (r *bookingRepository) Delete(id int) error {
-	err := r.db.Delete(&entity.Booking{}, id).Error
+func (r *bookingRepository) Delete(ctx context.Context, id int) error {
+	err := r.db.WithContext(ctx).Delete(&entity.Booking{}, id).Error
 	return err
 }
 
-func (r *bookingRepository) GetBookingsByUserID(userID int) ([]entity.Booking, error) {
-	var bookings []entity.Booking
-	err := r.db.Where("user_id = ?", userID).Find(&bookings).Error
-	return bookings, err
-}
-
-func (r *bookingRepository) GetBookingsByServiceID(serviceID int) ([]entity.Booking, error) {
-	var bookings []entity.Booking
-	err := r.db.Where("service_id = ?", serviceID).Find(&bookings).Error
-	return bookings, err
-}
-
-func (r *bookingRepository) UpdateBookingStatus(bookingID string, status string) error {
-	return r.db.Model(&entity.Booking{}).Where("id = ?", bookingID).Update("status", status).Error
+func (r *bookingRepository) UpdateBookingStatus(ctx context.Context, bookingID string, status string, events BookingEvents) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Booking{}).Where("id = ?", bookingID).Update("status", status).Error; err != nil {
+			return err
+		}
+		return appendBookingEvents(tx, bookingID, events)
+	})
 }
 
-func (r *bookingRepository) CancelBooking(bookingID string) error {
-	return r.db.Model(&entity.Booking{}).Where("id = ?", bookingID).Update("status", "Cancelled").Error
+func (r *bookingRepository) CancelBooking(ctx context.Context, bookingID string) error {
+	return r.db.WithContext(ctx).Model(&entity.Booking{}).Where("id = ?", bookingID).Update("status", "Cancelled").Error
 }
 
-func (r *bookingRepository) GetTotalBookings(startDate, endDate time.Time) (int64, error) {
+func (r *bookingRepository) GetTotalBookings(ctx context.Context, startDate, endDate time.Time) (int64, error) {
 	var total int64
-	query := r.db.Model(&entity.Booking{})
+	query := r.db.WithContext(ctx).Model(&entity.Booking{})
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
@@ -92,10 +106,10 @@ func (r *bookingRepository) GetTotalBookings(startDate, endDate time.Time) (int6
 	return total, err
 }
 
-func (r *bookingRepository) GetTotalRevenue(startDate, endDate time.Time) (float64, error) {
+func (r *bookingRepository) GetTotalRevenue(ctx context.Context, startDate, endDate time.Time) (float64, error) {
 	var totalRevenue float64
-	query := r.db.Model(&entity.Booking{}).Joins("JOIN payments ON payments.booking_id = bookings.id").
-		Select("COALESCE(SUM(payments.amount), 0)")
+	query := r.db.WithContext(ctx).Model(&entity.Booking{}).Joins("JOIN payments ON payments.booking_id = bookings.id").
+		Select(sumPaymentAmount)
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
@@ -106,12 +120,12 @@ func (r *bookingRepository) GetTotalRevenue(startDate, endDate time.Time) (float
 	return totalRevenue, err
 }
 
-func (r *bookingRepository) GetBookingsByStatus(status string, startDate, endDate time.Time) (int64, float64, error) {
+func (r *bookingRepository) GetBookingsByStatus(ctx context.Context, status string, startDate, endDate time.Time) (int64, float64, error) {
 	var count int64
 	var totalRevenue float64
 
 	// Query to count bookings by status
-	query := r.db.Model(&entity.Booking{}).Where("bookings.status = ?", status)
+	query := r.db.WithContext(ctx).Model(&entity.Booking{}).Where("bookings.status = ?", status)
 
 	// Add date filter if provided
 	if !startDate.IsZero() && !endDate.IsZero() {
@@ -125,7 +139,7 @@ func (r *bookingRepository) GetBookingsByStatus(status string, startDate, endDat
 	}
 
 	// Query to calculate total revenue for the given status
-	revenueQuery := r.db.Model(&entity.Booking{}).
+	revenueQuery := r.db.WithContext(ctx).Model(&entity.Booking{}).
 		Joins("JOIN payments ON payments.booking_id = bookings.id").
 		Where("bookings.status = ?", status)
 
@@ -135,7 +149,7 @@ func (r *bookingRepository) GetBookingsByStatus(status string, startDate, endDat
 	}
 
 	// Sum the payment amounts
-	err = revenueQuery.Select("COALESCE(SUM(payments.amount), 0)").Scan(&totalRevenue).Error
+	err = revenueQuery.Select(sumPaymentAmount).Scan(&totalRevenue).Error
 	if err != nil {
 		return 0, 0, err
 	}
@@ -143,10 +157,11 @@ func (r *bookingRepository) GetBookingsByStatus(status string, startDate, endDat
 	return count, totalRevenue, nil
 }
 
-func (r *bookingRepository) CheckServiceAvailability(serviceID int, date time.Time) (bool, error) {
+func (r *bookingRepository) CheckServiceAvailability(ctx context.Context, serviceID int, date time.Time) (bool, error) {
 	var count int64
-	err := r.db.Model(&entity.Booking{}).
-		Where("service_id = ? AND DATE(date) = ?", serviceID, date.Format("2006-01-02")).
+	start, end := dayRange(date)
+	err := r.db.WithContext(ctx).Model(&entity.Booking{}).
+		Where("service_id = ? AND date >= ? AND date < ?", serviceID, start, end).
 		Count(&count).Error
 	if err != nil {
 		return false, err
@@ -154,12 +169,13 @@ func (r *bookingRepository) CheckServiceAvailability(serviceID int, date time.Ti
 	return count == 0, nil
 }
 
-func (r *bookingRepository) GetBookedDates(serviceID int, year int, month int) ([]time.Time, error) {
+func (r *bookingRepository) GetBookedDates(ctx context.Context, serviceID int, year int, month int) ([]time.Time, error) {
 	var bookedDates []time.Time
 
 	// Query untuk mendapatkan tanggal-tanggal yang sudah dipesan
-	err := r.db.Model(&entity.Booking{}).
-		Where("service_id = ? AND YEAR(date) = ? AND MONTH(date) = ?", serviceID, year, month).
+	start, end := monthRange(year, month, time.Local)
+	err := r.db.WithContext(ctx).Model(&entity.Booking{}).
+		Where("service_id = ? AND date >= ? AND date < ?", serviceID, start, end).
 		Pluck("date", &bookedDates).Error
 
 	if err != nil {
@@ -169,10 +185,94 @@ func (r *bookingRepository) GetBookedDates(serviceID int, year int, month int) (
 	return bookedDates, nil
 }
 
-func (r *bookingRepository) GetConfirmedBookingsByTechnicianID(technicianID int) ([]entity.Booking, error) {
+func (r *bookingRepository) GetConfirmedBookingsByTechnicianID(ctx context.Context, technicianID int, q listquery.Query) (listquery.Page[entity.Booking], error) {
+	query := r.db.WithContext(ctx).Joins("JOIN services ON services.id = bookings.service_id").
+		Where("services.user_id = ? AND bookings.status = ?", technicianID, "Confirmed")
+	return findPage[entity.Booking](query, "bookings", q)
+}
+
+// FindStalePending mengambil booking Pending yang terlalu lama belum dikonfirmasi
+// atau tanggal kunjungannya sudah lewat.
+func (r *bookingRepository) FindStalePending(ctx context.Context, createdBefore, dateBefore time.Time) ([]entity.Booking, error) {
 	var bookings []entity.Booking
-	err := r.db.Joins("JOIN services ON services.id = bookings.service_id").
-		Where("services.user_id = ? AND bookings.status = ?", technicianID, "Confirmed").
+	err := r.db.WithContext(ctx).Where("status = ? AND (created_at < ? OR date < ?)", "Pending", createdBefore, dateBefore.Format("2006-01-02")).
 		Find(&bookings).Error
 	return bookings, err
 }
+
+func (r *bookingRepository) FindByStatusAndDate(ctx context.Context, status string, date time.Time) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	start, end := dayRange(date)
+	err := r.db.WithContext(ctx).Where("status = ? AND date >= ? AND date < ?", status, start, end).Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) FindByStatusBeforeDate(ctx context.Context, status string, date time.Time) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := r.db.WithContext(ctx).Where("status = ? AND date < ?", status, date.Format("2006-01-02")).Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) FindCompletedWithoutReview(ctx context.Context, updatedAfter, updatedBefore time.Time) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := r.db.WithContext(ctx).Where("status = ? AND updated_at BETWEEN ? AND ?", "Completed", updatedAfter, updatedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM reviews WHERE reviews.booking_id = bookings.id)").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// TransitionStatus mengubah status hanya jika status saat ini masih sama dengan
+// from, sehingga perubahan dari request lain tidak tertimpa.
+func (r *bookingRepository) TransitionStatus(ctx context.Context, bookingID int, from, to string, events BookingEvents) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Booking{}).Where("id = ? AND status = ?", bookingID, from).Update("status", to)
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+		changed = true
+		return appendBookingEvents(tx, bookingID, events)
+	})
+	return changed, err
+}
+
+// appendBookingEvents memuat ulang booking di dalam transaksi lalu menyimpan
+// event yang dibentuk oleh events ke outbox.
+func appendBookingEvents(tx *gorm.DB, bookingID interface{}, events BookingEvents) error {
+	if events == nil {
+		return nil
+	}
+
+	var booking entity.Booking
+	if err := tx.Preload("Service").Where("id = ?", bookingID).First(&booking).Error; err != nil {
+		return err
+	}
+	return appendEvents(tx, events(booking))
+}
+
+// FindIDsByUserID mengambil ID booking milik user, baik sebagai customer maupun
+// sebagai technician pemilik service.
+func (r *bookingRepository) FindIDsByUserID(ctx context.Context, userID int) ([]int, error) {
+	var ids []int
+	err := r.db.WithContext(ctx).Model(&entity.Booking{}).
+		Where("user_id = ? OR service_id IN (?)", userID, r.db.WithContext(ctx).Model(&entity.Service{}).Select("id").Where("user_id = ?", userID)).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *bookingRepository) DeleteByIDs(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&entity.Booking{}).Error
+}
+
+// FindIDsByStatusUpdatedBefore mengambil ID booking dengan status tertentu yang
+// terakhir diubah sebelum waktu yang diberikan.
+func (r *bookingRepository) FindIDsByStatusUpdatedBefore(ctx context.Context, status string, before time.Time) ([]int, error) {
+	var ids []int
+	err := r.db.WithContext(ctx).Model(&entity.Booking{}).
+		Where("status = ? AND updated_at < ?", status, before).
+		Pluck("id", &ids).Error
+	return ids, err
+}
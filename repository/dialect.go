@@ -0,0 +1,26 @@
+package repository
+
+import "time"
+
+// Query di repository harus berjalan di MySQL, PostgreSQL dan SQLite. Fungsi
+// khusus dialek (YEAR, MONTH, DATE, konversi teks ke angka implisit) diganti
+// dengan bentuk standar di bawah ini.
+
+// sumPaymentAmount menjumlahkan payments.amount yang disimpan sebagai teks.
+// MySQL dan SQLite mengonversinya otomatis, PostgreSQL membutuhkan CAST.
+const sumPaymentAmount = "COALESCE(SUM(CAST(payments.amount AS DECIMAL(20,2))), 0)"
+
+// dayRange mengembalikan awal hari t dan awal hari berikutnya, untuk
+// menggantikan DATE(kolom) = ? dengan kolom >= ? AND kolom < ?. Batas rentang
+// dikirim dalam UTC karena SQLite membandingkan waktu sebagai teks.
+func dayRange(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start.UTC(), start.AddDate(0, 0, 1).UTC()
+}
+
+// monthRange menggantikan YEAR(kolom) = ? AND MONTH(kolom) = ? dengan rentang
+// awal bulan sampai awal bulan berikutnya.
+func monthRange(year, month int, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	return start.UTC(), start.AddDate(0, 1, 0).UTC()
+}
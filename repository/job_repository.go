@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *entity.Job) (bool, error)
+	FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.Job, error)
+	Claim(ctx context.Context, id int, worker string, now, staleBefore time.Time) (bool, error)
+	// Complete, Retry dan Fail hanya mengubah job yang masih dikunci oleh
+	// worker. Nilai false berarti lock sudah kedaluwarsa dan job diklaim ulang
+	// oleh replica lain, sehingga hasil worker ini harus dibuang.
+	Complete(ctx context.Context, id int, worker string) (bool, error)
+	Retry(ctx context.Context, id int, worker string, runAt time.Time, lastError string) (bool, error)
+	Fail(ctx context.Context, id int, worker string, lastError string) (bool, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db}
+}
+
+// Enqueue menyimpan job baru. Jika job dengan unique key yang sama sudah ada,
+// job tidak disimpan ulang dan nilai false dikembalikan.
+func (r *jobRepository) Enqueue(ctx context.Context, job *entity.Job) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindDue mengambil job Pending yang sudah waktunya dijalankan, termasuk job
+// Running yang lock-nya sudah kedaluwarsa (replica yang menjalankannya mati).
+func (r *jobRepository) FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.Job, error) {
+	var jobs []entity.Job
+	err := r.db.WithContext(ctx).Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)", "Pending", now, "Running", staleBefore).
+		Order("run_at ASC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// Claim mengunci job untuk worker tertentu. Update bersyarat memastikan hanya
+// satu replica yang berhasil mengklaim job yang sama.
+func (r *jobRepository) Claim(ctx context.Context, id int, worker string, now, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.Job{}).
+		Where("id = ? AND ((status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?))", id, "Pending", now, "Running", staleBefore).
+		Updates(map[string]interface{}{
+			"status":    "Running",
+			"locked_by": worker,
+			"locked_at": now,
+			"attempts":  gorm.Expr("attempts + 1"),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *jobRepository) Complete(ctx context.Context, id int, worker string) (bool, error) {
+	return r.release(ctx, id, worker, map[string]interface{}{
+		"status":     "Completed",
+		"last_error": "",
+	})
+}
+
+func (r *jobRepository) Retry(ctx context.Context, id int, worker string, runAt time.Time, lastError string) (bool, error) {
+	return r.release(ctx, id, worker, map[string]interface{}{
+		"status":     "Pending",
+		"run_at":     runAt,
+		"last_error": lastError,
+	})
+}
+
+func (r *jobRepository) Fail(ctx context.Context, id int, worker string, lastError string) (bool, error) {
+	return r.release(ctx, id, worker, map[string]interface{}{
+		"status":     "Failed",
+		"last_error": lastError,
+	})
+}
+
+// release melepas lock job sambil menyimpan hasilnya, hanya jika job masih
+// dikunci oleh worker.
+func (r *jobRepository) release(ctx context.Context, id int, worker string, values map[string]interface{}) (bool, error) {
+	values["locked_by"] = ""
+	values["locked_at"] = nil
+	result := r.db.WithContext(ctx).Model(&entity.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, "Running", worker).
+		Updates(values)
+	return result.RowsAffected == 1, result.Error
+}
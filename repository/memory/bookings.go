@@ -0,0 +1,346 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"gorm.io/gorm"
+)
+
+type bookingRepository struct {
+	base
+}
+
+func bookingRow(booking entity.Booking) entity.Booking {
+	booking.User = entity.User{}
+	booking.Service = entity.Service{}
+	return booking
+}
+
+// checkBooking menerapkan foreign key bookings.user_id dan bookings.service_id.
+func (t *tables) checkBooking(booking entity.Booking) error {
+	if !t.users.has(booking.UserID) || !t.services.has(booking.ServiceID) {
+		return gorm.ErrForeignKeyViolated
+	}
+	return nil
+}
+
+// bookingOutbox membentuk baris outbox dari booking beserta relasi Service,
+// sama seperti appendBookingEvents di repository database.
+func (t *tables) bookingOutbox(booking entity.Booking, events repository.BookingEvents) ([]entity.OutboxEvent, error) {
+	if events == nil {
+		return nil, nil
+	}
+	booking.Service, _ = t.services.get(booking.ServiceID)
+	return outboxRows(events(booking))
+}
+
+// referencedBooking melaporkan apakah booking masih dipakai oleh payment,
+// review atau pesan.
+func (t *tables) referencedBooking(ids ...int) bool {
+	return t.payments.exists(func(p *entity.Payment) bool { return contains(ids, p.BookingID) }) ||
+		t.reviews.exists(func(r *entity.Review) bool { return contains(ids, r.BookingID) }) ||
+		t.messages.exists(func(m *entity.Message) bool { return contains(ids, m.BookingID) })
+}
+
+// paymentTotal menjumlahkan payment milik booking yang cocok, seperti
+// JOIN payments ON payments.booking_id = bookings.id.
+func (t *tables) paymentTotal(bookings []entity.Booking) float64 {
+	var total float64
+	for _, booking := range bookings {
+		for _, payment := range t.payments.filter(func(p *entity.Payment) bool { return p.BookingID == booking.ID }) {
+			total += paymentAmount(payment)
+		}
+	}
+	return total
+}
+
+func (r *bookingRepository) Create(ctx context.Context, booking entity.Booking, events repository.BookingEvents) (entity.Booking, error) {
+	err := r.write(ctx, func(t *tables) error {
+		if err := t.checkBooking(booking); err != nil {
+			return err
+		}
+		row := bookingRow(booking)
+		if err := t.bookings.prepare(&row); err != nil {
+			return err
+		}
+		outbox, err := t.bookingOutbox(row, events)
+		if err != nil {
+			return err
+		}
+
+		t.bookings.put(row)
+		t.appendOutbox(outbox)
+		booking.ID, booking.CreatedAt, booking.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+	return booking, err
+}
+
+func (r *bookingRepository) FindByID(ctx context.Context, id int) (entity.Booking, error) {
+	var booking entity.Booking
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if booking, ok = t.bookings.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		booking.User, _ = t.users.get(booking.UserID)
+		booking.Service, _ = t.services.get(booking.ServiceID)
+		return nil
+	})
+	return booking, err
+}
+
+func (r *bookingRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Booking], error) {
+	var page listquery.Page[entity.Booking]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.bookings.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *bookingRepository) Update(ctx context.Context, booking entity.Booking, events repository.BookingEvents) (entity.Booking, error) {
+	err := r.write(ctx, func(t *tables) error {
+		if err := t.checkBooking(booking); err != nil {
+			return err
+		}
+		row := bookingRow(booking)
+		if err := t.bookings.save(&row); err != nil {
+			return err
+		}
+		outbox, err := t.bookingOutbox(row, events)
+		if err != nil {
+			return err
+		}
+
+		t.bookings.put(row)
+		t.appendOutbox(outbox)
+		booking.ID, booking.CreatedAt, booking.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+	return booking, err
+}
+
+func (r *bookingRepository) Delete(ctx context.Context, id int) error {
+	return r.DeleteByIDs(ctx, []int{id})
+}
+
+// parseID mengubah ID berbentuk teks dari URL. ID yang tidak valid tidak
+// cocok dengan baris mana pun, seperti WHERE id = 'abc' di database.
+func parseID(id string) int {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func (r *bookingRepository) UpdateBookingStatus(ctx context.Context, bookingID string, status string, events repository.BookingEvents) error {
+	_, err := r.setStatus(ctx, parseID(bookingID), nil, status, events, true)
+	return err
+}
+
+// TransitionStatus mengubah status hanya jika status saat ini masih sama dengan from.
+func (r *bookingRepository) TransitionStatus(ctx context.Context, bookingID int, from, to string, events repository.BookingEvents) (bool, error) {
+	return r.setStatus(ctx, bookingID, &from, to, events, false)
+}
+
+// setStatus mengubah status booking id (jika from diisi, hanya dari status
+// from). Seperti repository database, UpdateBookingStatus (mustExist true)
+// gagal dengan ErrRecordNotFound jika booking tidak ada dan events diisi,
+// sedangkan TransitionStatus hanya mengembalikan false.
+func (r *bookingRepository) setStatus(ctx context.Context, id int, from *string, to string, events repository.BookingEvents, mustExist bool) (bool, error) {
+	changed := false
+	err := r.write(ctx, func(t *tables) error {
+		booking, ok := t.bookings.get(id)
+		if !ok || (from != nil && booking.Status != *from) {
+			if mustExist && events != nil {
+				return gorm.ErrRecordNotFound
+			}
+			return nil
+		}
+
+		booking.Status = to
+		booking.UpdatedAt = time.Now()
+		outbox, err := t.bookingOutbox(booking, events)
+		if err != nil {
+			return err
+		}
+
+		t.bookings.put(booking)
+		t.appendOutbox(outbox)
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+func (r *bookingRepository) GetTotalBookings(ctx context.Context, startDate, endDate time.Time) (int64, error) {
+	inRange := timeFilter(startDate, endDate)
+	var total int64
+	err := r.read(ctx, func(t *tables) error {
+		total = t.bookings.count(func(b *entity.Booking) bool { return inRange(b.Date) })
+		return nil
+	})
+	return total, err
+}
+
+func (r *bookingRepository) GetTotalRevenue(ctx context.Context, startDate, endDate time.Time) (float64, error) {
+	inRange := timeFilter(startDate, endDate)
+	var totalRevenue float64
+	err := r.read(ctx, func(t *tables) error {
+		totalRevenue = t.paymentTotal(t.bookings.filter(func(b *entity.Booking) bool { return inRange(b.Date) }))
+		return nil
+	})
+	return totalRevenue, err
+}
+
+func (r *bookingRepository) GetBookingsByStatus(ctx context.Context, status string, startDate, endDate time.Time) (int64, float64, error) {
+	inRange := timeFilter(startDate, endDate)
+	var count int64
+	var totalRevenue float64
+	err := r.read(ctx, func(t *tables) error {
+		bookings := t.bookings.filter(func(b *entity.Booking) bool { return b.Status == status && inRange(b.Date) })
+		count = int64(len(bookings))
+		totalRevenue = t.paymentTotal(bookings)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return count, totalRevenue, nil
+}
+
+// onDay melaporkan apakah t berada di hari yang sama dengan day, dengan batas
+// hari di zona waktu day seperti dayRange di repository database.
+func onDay(t, day time.Time) bool {
+	start := startOfDay(day)
+	return !t.Before(start) && t.Before(start.AddDate(0, 0, 1))
+}
+
+func (r *bookingRepository) CheckServiceAvailability(ctx context.Context, serviceID int, date time.Time) (bool, error) {
+	var booked bool
+	err := r.read(ctx, func(t *tables) error {
+		booked = t.bookings.exists(func(b *entity.Booking) bool { return b.ServiceID == serviceID && onDay(b.Date, date) })
+		return nil
+	})
+	return !booked, err
+}
+
+func (r *bookingRepository) GetBookedDates(ctx context.Context, serviceID int, year int, month int) ([]time.Time, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, 0)
+
+	bookedDates := make([]time.Time, 0)
+	err := r.read(ctx, func(t *tables) error {
+		for _, booking := range t.bookings.filter(func(b *entity.Booking) bool {
+			return b.ServiceID == serviceID && !b.Date.Before(start) && b.Date.Before(end)
+		}) {
+			bookedDates = append(bookedDates, booking.Date)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bookedDates, nil
+}
+
+func (r *bookingRepository) GetConfirmedBookingsByTechnicianID(ctx context.Context, technicianID int, q listquery.Query) (listquery.Page[entity.Booking], error) {
+	var page listquery.Page[entity.Booking]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.bookings.filter(func(b *entity.Booking) bool {
+			service, ok := t.services.get(b.ServiceID)
+			return ok && service.UserID == technicianID && b.Status == "Confirmed"
+		}), q)
+		return nil
+	})
+	return page, err
+}
+
+// FindStalePending mengambil booking Pending yang terlalu lama belum dikonfirmasi
+// atau tanggal kunjungannya sudah lewat.
+func (r *bookingRepository) FindStalePending(ctx context.Context, createdBefore, dateBefore time.Time) ([]entity.Booking, error) {
+	day := startOfDay(dateBefore)
+	return r.find(ctx, func(b *entity.Booking) bool {
+		return b.Status == "Pending" && (b.CreatedAt.Before(createdBefore) || b.Date.Before(day))
+	})
+}
+
+func (r *bookingRepository) FindByStatusAndDate(ctx context.Context, status string, date time.Time) ([]entity.Booking, error) {
+	return r.find(ctx, func(b *entity.Booking) bool { return b.Status == status && onDay(b.Date, date) })
+}
+
+func (r *bookingRepository) FindByStatusBeforeDate(ctx context.Context, status string, date time.Time) ([]entity.Booking, error) {
+	day := startOfDay(date)
+	return r.find(ctx, func(b *entity.Booking) bool { return b.Status == status && b.Date.Before(day) })
+}
+
+func (r *bookingRepository) FindCompletedWithoutReview(ctx context.Context, updatedAfter, updatedBefore time.Time) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := r.read(ctx, func(t *tables) error {
+		bookings = t.bookings.filter(func(b *entity.Booking) bool {
+			return b.Status == "Completed" && between(b.UpdatedAt, updatedAfter, updatedBefore) &&
+				!t.reviews.exists(func(r *entity.Review) bool { return r.BookingID == b.ID })
+		})
+		return nil
+	})
+	return bookings, err
+}
+
+// FindIDsByUserID mengambil ID booking milik user, baik sebagai customer maupun
+// sebagai technician pemilik service.
+func (r *bookingRepository) FindIDsByUserID(ctx context.Context, userID int) ([]int, error) {
+	ids := make([]int, 0)
+	err := r.read(ctx, func(t *tables) error {
+		for _, booking := range t.bookings.filter(func(b *entity.Booking) bool {
+			service, _ := t.services.get(b.ServiceID)
+			return b.UserID == userID || service.UserID == userID
+		}) {
+			ids = append(ids, booking.ID)
+		}
+		return nil
+	})
+	return ids, err
+}
+
+func (r *bookingRepository) DeleteByIDs(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.write(ctx, func(t *tables) error {
+		if t.referencedBooking(ids...) {
+			return gorm.ErrForeignKeyViolated
+		}
+		t.bookings.remove(func(b *entity.Booking) bool { return contains(ids, b.ID) })
+		return nil
+	})
+}
+
+// FindIDsByStatusUpdatedBefore mengambil ID booking dengan status tertentu yang
+// terakhir diubah sebelum waktu yang diberikan.
+func (r *bookingRepository) FindIDsByStatusUpdatedBefore(ctx context.Context, status string, before time.Time) ([]int, error) {
+	bookings, err := r.find(ctx, func(b *entity.Booking) bool { return b.Status == status && b.UpdatedAt.Before(before) })
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(bookings))
+	for _, booking := range bookings {
+		ids = append(ids, booking.ID)
+	}
+	return ids, nil
+}
+
+func (r *bookingRepository) find(ctx context.Context, match func(*entity.Booking) bool) ([]entity.Booking, error) {
+	var bookings []entity.Booking
+	err := r.read(ctx, func(t *tables) error {
+		bookings = t.bookings.filter(match)
+		return nil
+	})
+	return bookings, err
+}
@@ -0,0 +1,476 @@
+package memory_test
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/migrate"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+// Test di file ini dijalankan terhadap repository memory dan repository
+// database (SQLite) sekaligus, sehingga perbedaan perilaku keduanya langsung
+// terlihat sebagai test yang gagal pada salah satu backend saja.
+
+func newSQLiteStorage(t *testing.T) repository.Storage {
+	t.Helper()
+
+	db, err := config.OpenDatabase(config.DatabaseConfig{
+		Driver: config.DriverSQLite,
+		Name:   filepath.Join(t.TempDir(), "contract.db"),
+	}, logger.Discard)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := migrate.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return repository.NewStorage(db)
+}
+
+// eachStorage menjalankan fn sebagai subtest untuk setiap backend.
+func eachStorage(t *testing.T, fn func(t *testing.T, storage repository.Storage)) {
+	t.Run("memory", func(t *testing.T) { fn(t, memory.NewStorage()) })
+	t.Run("sqlite", func(t *testing.T) { fn(t, newSQLiteStorage(t)) })
+}
+
+// byID mengurutkan list berdasarkan ID tanpa filter.
+var byID = listquery.Spec{Sorts: []listquery.Field{listquery.ID}, DefaultSort: "id"}
+
+func parseQuery(t *testing.T, spec listquery.Spec, rawQuery string) listquery.Query {
+	t.Helper()
+	values, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+	q, err := listquery.Parse(values, spec)
+	require.NoError(t, err)
+	return q
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+// fixture berisi satu technician, satu customer dan satu service milik technician.
+type fixture struct {
+	technician, customer entity.User
+	service              entity.Service
+}
+
+func newFixture(t *testing.T, storage repository.Storage) fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := fixture{
+		technician: entity.User{Name: "Teknisi", Email: "tech@example.com", Role: "technician"},
+		customer:   entity.User{Name: "Customer", Email: "customer@example.com"},
+	}
+	require.NoError(t, storage.Users.Create(ctx, &f.technician))
+	require.NoError(t, storage.Users.Create(ctx, &f.customer))
+
+	f.service = entity.Service{UserID: f.technician.ID, Name: "Servis AC", Description: "Cuci dan isi freon", Cost: 150000}
+	require.NoError(t, storage.Services.Create(ctx, &f.service))
+	return f
+}
+
+func (f fixture) booking(t *testing.T, storage repository.Storage, date time.Time, status string) entity.Booking {
+	t.Helper()
+	booking, err := storage.Bookings.Create(context.Background(), entity.Booking{
+		UserID:    f.customer.ID,
+		ServiceID: f.service.ID,
+		Date:      date,
+		Status:    status,
+	}, nil)
+	require.NoError(t, err)
+	return booking
+}
+
+func TestContract_UserDefaultsAndPagination(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+			require.NoError(t, storage.Users.Create(ctx, &entity.User{Name: email, Email: email}))
+		}
+
+		user, err := storage.Users.FindUserByEmail(ctx, "a@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "user", user.Role)
+		assert.Equal(t, "id", user.Language)
+		assert.False(t, user.CreatedAt.IsZero())
+
+		spec := listquery.Spec{
+			Sorts:       []listquery.Field{listquery.ID, {Name: "name", Kind: listquery.String}},
+			DefaultSort: "id",
+			Filters:     []listquery.Filter{{Param: "role", Field: listquery.Field{Name: "role", Kind: listquery.String}, Op: listquery.Eq}},
+		}
+		emails := func(q listquery.Query) ([]string, listquery.Page[entity.User]) {
+			page, err := storage.Users.FindAll(ctx, q)
+			require.NoError(t, err)
+			result := []string{}
+			for _, u := range page.Items {
+				result = append(result, u.Email)
+			}
+			return result, page
+		}
+
+		first, page := emails(parseQuery(t, spec, "limit=2"))
+		assert.Equal(t, []string{"a@example.com", "b@example.com"}, first)
+		assert.Equal(t, int64(3), page.Total)
+		require.NotNil(t, page.NextCursor)
+		rest, page := emails(parseQuery(t, spec, "limit=2&cursor="+*page.NextCursor))
+		assert.Equal(t, []string{"c@example.com"}, rest)
+		assert.Equal(t, int64(3), page.Total)
+		assert.Nil(t, page.NextCursor)
+
+		desc, page := emails(parseQuery(t, spec, "limit=2&sort=-name"))
+		assert.Equal(t, []string{"c@example.com", "b@example.com"}, desc)
+		rest, _ = emails(parseQuery(t, spec, "limit=2&sort=-name&cursor="+*page.NextCursor))
+		assert.Equal(t, []string{"a@example.com"}, rest)
+
+		none, page := emails(parseQuery(t, spec, "role=admin"))
+		assert.Equal(t, []string{}, none)
+		assert.Equal(t, int64(0), page.Total)
+		assert.Nil(t, page.NextCursor)
+
+		exists, err := storage.Users.IsEmailExists(ctx, "c@example.com")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		_, err = storage.Users.FindByID(ctx, 999)
+		assert.Error(t, err)
+		assert.Error(t, storage.Users.Create(ctx, &entity.User{Email: "x@example.com", Role: "superuser"}))
+	})
+}
+
+func TestContract_ForeignKeys(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+
+		_, err := storage.Bookings.Create(ctx, entity.Booking{UserID: f.customer.ID, ServiceID: 999, Date: day(2030, 1, 10)}, nil)
+		assert.Error(t, err)
+		_, err = storage.Payments.Create(ctx, entity.Payment{BookingID: 999, Amount: "1000", Status: "Pending"}, nil)
+		assert.Error(t, err)
+
+		booking := f.booking(t, storage, day(2030, 1, 10), "Pending")
+		_, err = storage.Payments.Create(ctx, entity.Payment{BookingID: booking.ID, Amount: "1000", Status: "Pending"}, nil)
+		require.NoError(t, err)
+		// Booking yang masih punya payment tidak boleh dihapus
+		assert.Error(t, storage.Bookings.Delete(ctx, booking.ID))
+	})
+}
+
+func TestContract_AvailabilityAndBookedDates(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+		f.booking(t, storage, day(2030, 3, 5), "Pending")
+		f.booking(t, storage, day(2030, 3, 20), "Confirmed")
+		f.booking(t, storage, day(2030, 4, 1), "Pending")
+
+		available, err := storage.Bookings.CheckServiceAvailability(ctx, f.service.ID, day(2030, 3, 5).Add(10*time.Hour))
+		require.NoError(t, err)
+		assert.False(t, available)
+
+		available, err = storage.Bookings.CheckServiceAvailability(ctx, f.service.ID, day(2030, 3, 6))
+		require.NoError(t, err)
+		assert.True(t, available)
+
+		dates, err := storage.Bookings.GetBookedDates(ctx, f.service.ID, 2030, 3)
+		require.NoError(t, err)
+		var formatted []string
+		for _, d := range dates {
+			formatted = append(formatted, d.In(time.Local).Format("2006-01-02"))
+		}
+		assert.ElementsMatch(t, []string{"2030-03-05", "2030-03-20"}, formatted)
+	})
+}
+
+func TestContract_Reports(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+		other := entity.Service{UserID: f.technician.ID, Name: "Servis Kulkas", Cost: 300000}
+		require.NoError(t, storage.Services.Create(ctx, &other))
+
+		created := time.Date(2030, 5, 10, 9, 0, 0, 0, time.UTC)
+		paid := f.booking(t, storage, day(2030, 5, 12), "Completed")
+		pending := f.booking(t, storage, day(2030, 5, 14), "Pending")
+		elsewhere, err := storage.Bookings.Create(ctx, entity.Booking{UserID: f.customer.ID, ServiceID: other.ID, Date: day(2030, 6, 1), Status: "Completed"}, nil)
+		require.NoError(t, err)
+
+		for _, p := range []entity.Payment{
+			{BookingID: paid.ID, Amount: "150000", Status: "Paid", CreatedAt: created},
+			{BookingID: pending.ID, Amount: "150000", Status: "Pending", CreatedAt: created},
+			{BookingID: elsewhere.ID, Amount: "300000", Status: "Paid", CreatedAt: created.AddDate(0, 1, 0)},
+		} {
+			_, err := storage.Payments.Create(ctx, p, nil)
+			require.NoError(t, err)
+		}
+		for _, r := range []entity.Review{
+			{BookingID: paid.ID, Rating: 5, Comment: "Mantap", CreatedAt: created},
+			{BookingID: elsewhere.ID, Rating: 2, Comment: "Lambat", CreatedAt: created.AddDate(0, 1, 0)},
+		} {
+			_, err := storage.Reviews.Create(ctx, r, nil)
+			require.NoError(t, err)
+		}
+
+		// Laporan booking difilter berdasarkan tanggal booking
+		mayStart, mayEnd := day(2030, 5, 1), day(2030, 5, 31)
+		total, err := storage.Bookings.GetTotalBookings(ctx, mayStart, mayEnd)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, total)
+		total, err = storage.Bookings.GetTotalBookings(ctx, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, total)
+		revenue, err := storage.Bookings.GetTotalRevenue(ctx, mayStart, mayEnd)
+		require.NoError(t, err)
+		assert.Equal(t, 300000.0, revenue)
+		count, revenue, err := storage.Bookings.GetBookingsByStatus(ctx, "Completed", time.Time{}, time.Time{})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+		assert.Equal(t, 450000.0, revenue)
+
+		// Laporan payment dan review difilter berdasarkan created_at dan service
+		start, end := created.AddDate(0, 0, -1), created.AddDate(0, 0, 1)
+		totalPayments, err := storage.Payments.GetTotalPayments(ctx, time.Time{}, time.Time{}, 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, totalPayments)
+		amount, err := storage.Payments.GetTotalAmount(ctx, start, end, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 300000.0, amount)
+		count, amount, err = storage.Payments.GetPaymentsByStatus(ctx, "Paid", time.Time{}, time.Time{}, other.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		assert.Equal(t, 300000.0, amount)
+
+		totalReviews, err := storage.Reviews.GetTotalReviews(ctx, start, end, 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, totalReviews)
+		average, err := storage.Reviews.GetAverageRating(ctx, time.Time{}, time.Time{}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3.5, average)
+		average, err = storage.Reviews.GetAverageRating(ctx, start, end, other.ID)
+		require.NoError(t, err)
+		assert.Zero(t, average)
+		byRating, err := storage.Reviews.GetReviewsByRating(ctx, 2, time.Time{}, time.Time{}, other.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, byRating)
+	})
+}
+
+// Filter service menggabungkan tabel bookings yang juga punya created_at,
+// jadi kolom reviews harus ditulis lengkap agar tidak ambiguous di SQL.
+func TestContract_ReviewStatsFilterByDateAndService(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+		booking := f.booking(t, storage, day(2030, 5, 12), "Completed")
+		created := time.Date(2030, 5, 20, 9, 0, 0, 0, time.UTC)
+		_, err := storage.Reviews.Create(ctx, entity.Review{BookingID: booking.ID, Rating: 4, Comment: "Rapi", CreatedAt: created}, nil)
+		require.NoError(t, err)
+
+		start, end := created.AddDate(0, 0, -1), created.AddDate(0, 0, 1)
+		total, err := storage.Reviews.GetTotalReviews(ctx, start, end, f.service.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, total)
+		average, err := storage.Reviews.GetAverageRating(ctx, start, end, f.service.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4.0, average)
+		byRating, err := storage.Reviews.GetReviewsByRating(ctx, 4, start, end, f.service.ID)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, byRating)
+	})
+}
+
+func TestContract_JobReleaseRequiresLock(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		now := time.Date(2030, 5, 1, 8, 0, 0, 0, time.UTC)
+		job := entity.Job{Type: "report", Status: "Pending", RunAt: now, MaxAttempts: 5}
+		_, err := storage.Jobs.Enqueue(ctx, &job)
+		require.NoError(t, err)
+
+		claimed, err := storage.Jobs.Claim(ctx, job.ID, "replica-a", now, now.Add(-5*time.Minute))
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		// Lock replica A kedaluwarsa dan job diklaim ulang oleh replica B
+		later := now.Add(10 * time.Minute)
+		claimed, err = storage.Jobs.Claim(ctx, job.ID, "replica-b", later, later.Add(-5*time.Minute))
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		released, err := storage.Jobs.Complete(ctx, job.ID, "replica-a")
+		require.NoError(t, err)
+		assert.False(t, released)
+		released, err = storage.Jobs.Fail(ctx, job.ID, "replica-a", "timeout")
+		require.NoError(t, err)
+		assert.False(t, released)
+
+		released, err = storage.Jobs.Retry(ctx, job.ID, "replica-b", later.Add(time.Minute), "gateway down")
+		require.NoError(t, err)
+		assert.True(t, released)
+		released, err = storage.Jobs.Complete(ctx, job.ID, "replica-b")
+		require.NoError(t, err)
+		assert.False(t, released) // Sudah dilepas oleh Retry
+	})
+}
+
+func TestContract_DistributionsAndSearch(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+		created := time.Date(2030, 5, 10, 9, 0, 0, 0, time.UTC)
+		require.NoError(t, storage.Services.Create(ctx, &entity.Service{UserID: f.technician.ID, Name: "Instalasi Listrik", Description: "Pasang stop kontak", Cost: 750000, CreatedAt: created}))
+		require.NoError(t, storage.Services.Create(ctx, &entity.Service{UserID: f.technician.ID, Name: "Bongkar Pasang AC", Cost: 90000, CreatedAt: created}))
+
+		roles, err := storage.Users.GetUserRoleDistribution(ctx, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"technician": 1, "user": 1}, roles)
+		roles, err = storage.Users.GetUserRoleDistribution(ctx, "2030-01-01", "2030-12-31")
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+
+		costs, err := storage.Services.GetServiceCostDistribution(ctx, "", "")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"50000-100000": 1, "100001-300000": 1, "700001-1000000": 1}, costs)
+		costs, err = storage.Services.GetServiceCostDistribution(ctx, "2030-05-01", "2030-05-31")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"50000-100000": 1, "700001-1000000": 1}, costs)
+
+		cost := listquery.Field{Name: "cost", Kind: listquery.Int}
+		q := listquery.New(byID, 10)
+		services, err := storage.Services.SearchServices(ctx, "ac", q.Where(cost, listquery.Gte, 100000).Where(cost, listquery.Lte, 200000))
+		require.NoError(t, err)
+		require.Len(t, services.Items, 1)
+		assert.Equal(t, int64(1), services.Total)
+		assert.Equal(t, "Servis AC", services.Items[0].Name)
+		assert.Equal(t, f.technician.Email, services.Items[0].User.Email)
+
+		services, err = storage.Services.SearchServices(ctx, "", q)
+		require.NoError(t, err)
+		assert.Len(t, services.Items, 3)
+	})
+}
+
+func TestContract_UnitOfWorkAndOutbox(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+
+		created := func(booking entity.Booking) []event.Event {
+			return []event.Event{{Type: "booking.created", Recipients: map[string]int{"customer": booking.UserID}, Data: booking.ID}}
+		}
+		booking, err := storage.Bookings.Create(ctx, entity.Booking{UserID: f.customer.ID, ServiceID: f.service.ID, Date: day(2030, 7, 1), Status: "Pending"}, created)
+		require.NoError(t, err)
+
+		page, err := storage.Outbox.FindAll(ctx, listquery.New(byID, 10))
+		require.NoError(t, err)
+		require.Len(t, page.Items, 1)
+		events := page.Items
+		assert.Equal(t, "booking.created", events[0].Type)
+		assert.Equal(t, "Pending", events[0].Status)
+
+		// Error di tengah unit of work membatalkan semua perubahan sebelumnya
+		err = storage.UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+			if _, err := repos.Bookings.TransitionStatus(ctx, booking.ID, "Pending", "Confirmed", created); err != nil {
+				return err
+			}
+			_, err := repos.Payments.Create(ctx, entity.Payment{BookingID: 999, Amount: "1", Status: "Paid"}, nil)
+			return err
+		})
+		require.Error(t, err)
+
+		booking, err = storage.Bookings.FindByID(ctx, booking.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Pending", booking.Status)
+		latest, err := storage.Outbox.LatestID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, events[0].ID, latest)
+	})
+}
+
+// Cursor tetap benar saat banyak baris punya nilai sort yang sama dan saat
+// list memakai join (kolom harus diberi nama tabel).
+func TestContract_ListQuery(t *testing.T) {
+	eachStorage(t, func(t *testing.T, storage repository.Storage) {
+		ctx := context.Background()
+		f := newFixture(t, storage)
+		b1 := f.booking(t, storage, day(2030, 7, 3), "Confirmed")
+		b2 := f.booking(t, storage, day(2030, 7, 1), "Confirmed")
+		b3 := f.booking(t, storage, day(2030, 7, 2), "Pending")
+		b4 := f.booking(t, storage, day(2030, 7, 1), "Confirmed")
+
+		spec := listquery.Spec{
+			Sorts:       []listquery.Field{listquery.ID, {Name: "date", Kind: listquery.Time}, {Name: "created_at", Kind: listquery.Time}},
+			DefaultSort: "id",
+			Filters: []listquery.Filter{
+				{Param: "status", Field: listquery.Field{Name: "status", Kind: listquery.String}, Op: listquery.Eq},
+				{Param: "date_from", Field: listquery.Field{Name: "date", Kind: listquery.Time}, Op: listquery.Gte},
+				{Param: "date_to", Field: listquery.Field{Name: "date", Kind: listquery.Time}, Op: listquery.Lte},
+			},
+		}
+		// all mengikuti next_cursor sampai habis dan mengembalikan ID per halaman
+		all := func(rawQuery string, find func(listquery.Query) (listquery.Page[entity.Booking], error)) [][]int {
+			var pages [][]int
+			q := parseQuery(t, spec, rawQuery)
+			for more := true; more; {
+				page, err := find(q)
+				require.NoError(t, err)
+				ids := []int{}
+				for _, b := range page.Items {
+					ids = append(ids, b.ID)
+				}
+				pages = append(pages, ids)
+				q, more = listquery.Next(q, page)
+			}
+			return pages
+		}
+		findAll := func(q listquery.Query) (listquery.Page[entity.Booking], error) {
+			return storage.Bookings.FindAll(ctx, q)
+		}
+
+		assert.Equal(t, [][]int{{b2.ID, b4.ID}, {b3.ID, b1.ID}}, all("sort=date&limit=2", findAll))
+		assert.Equal(t, [][]int{{b1.ID, b3.ID, b4.ID}, {b2.ID}}, all("sort=-date&limit=3", findAll))
+		assert.Equal(t, [][]int{{b1.ID, b3.ID}}, all("date_from=2030-07-02", findAll))
+		assert.Equal(t, [][]int{{b2.ID, b4.ID}}, all("date_to=2030-07-01", findAll))
+		assert.Equal(t, [][]int{{b1.ID}, {b2.ID}, {b4.ID}}, all("status=Confirmed&limit=1", findAll))
+
+		confirmed := func(q listquery.Query) (listquery.Page[entity.Booking], error) {
+			return storage.Bookings.GetConfirmedBookingsByTechnicianID(ctx, f.technician.ID, q)
+		}
+		assert.Equal(t, [][]int{{b4.ID, b2.ID}, {b1.ID}}, all("sort=-created_at&limit=2", confirmed))
+		assert.Equal(t, [][]int{{b4.ID, b2.ID}}, all("date_to=2030-07-01&sort=-id", confirmed))
+
+		page, err := confirmed(parseQuery(t, spec, "limit=1"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), page.Total)
+
+		// Offset dari route lama dilewati setelah sort dan filter
+		values, err := url.ParseQuery("sort=date&limit=2&offset=1")
+		require.NoError(t, err)
+		q, err := listquery.ParseOffset(values, spec)
+		require.NoError(t, err)
+		page, err = findAll(q)
+		require.NoError(t, err)
+		require.Len(t, page.Items, 2)
+		assert.Equal(t, []int{b4.ID, b3.ID}, []int{page.Items[0].ID, page.Items[1].ID})
+		assert.Equal(t, int64(4), page.Total)
+	})
+}
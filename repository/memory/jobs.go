@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+)
+
+type jobRepository struct {
+	base
+}
+
+// Enqueue menyimpan job baru. Jika job dengan unique key yang sama sudah ada,
+// job tidak disimpan ulang dan nilai false dikembalikan.
+func (r *jobRepository) Enqueue(ctx context.Context, job *entity.Job) (bool, error) {
+	created := false
+	err := r.write(ctx, func(t *tables) error {
+		duplicate := job.UniqueKey != nil && t.jobs.exists(func(j *entity.Job) bool {
+			return j.UniqueKey != nil && *j.UniqueKey == *job.UniqueKey
+		})
+		if duplicate {
+			return nil
+		}
+		if err := t.jobs.insert(job); err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+// dueJob mencocokkan job Pending yang sudah waktunya dijalankan dan job
+// Running yang lock-nya kedaluwarsa.
+func dueJob(now, staleBefore time.Time) func(*entity.Job) bool {
+	return func(j *entity.Job) bool {
+		return (j.Status == "Pending" && !j.RunAt.After(now)) ||
+			(j.Status == "Running" && j.LockedAt != nil && j.LockedAt.Before(staleBefore))
+	}
+}
+
+func (r *jobRepository) FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.Job, error) {
+	var jobs []entity.Job
+	err := r.read(ctx, func(t *tables) error {
+		jobs = t.jobs.filter(dueJob(now, staleBefore))
+		slices.SortStableFunc(jobs, func(a, b entity.Job) int { return a.RunAt.Compare(b.RunAt) })
+		jobs = paginate(jobs, limit, 0)
+		return nil
+	})
+	return jobs, err
+}
+
+// Claim mengunci job untuk worker tertentu. Hanya satu pemanggil yang
+// berhasil mengklaim job yang sama.
+func (r *jobRepository) Claim(ctx context.Context, id int, worker string, now, staleBefore time.Time) (bool, error) {
+	due := dueJob(now, staleBefore)
+	return r.update(ctx, func(j *entity.Job) bool { return j.ID == id && due(j) }, func(j *entity.Job) {
+		j.Status = "Running"
+		j.LockedBy = worker
+		j.LockedAt = &now
+		j.Attempts++
+	})
+}
+
+func (r *jobRepository) Complete(ctx context.Context, id int, worker string) (bool, error) {
+	return r.update(ctx, lockedBy(id, worker), func(j *entity.Job) {
+		j.Status = "Completed"
+		j.LockedBy = ""
+		j.LockedAt = nil
+		j.LastError = ""
+	})
+}
+
+func (r *jobRepository) Retry(ctx context.Context, id int, worker string, runAt time.Time, lastError string) (bool, error) {
+	return r.update(ctx, lockedBy(id, worker), func(j *entity.Job) {
+		j.Status = "Pending"
+		j.RunAt = runAt
+		j.LockedBy = ""
+		j.LockedAt = nil
+		j.LastError = lastError
+	})
+}
+
+func (r *jobRepository) Fail(ctx context.Context, id int, worker string, lastError string) (bool, error) {
+	return r.update(ctx, lockedBy(id, worker), func(j *entity.Job) {
+		j.Status = "Failed"
+		j.LockedBy = ""
+		j.LockedAt = nil
+		j.LastError = lastError
+	})
+}
+
+// lockedBy mencocokkan job yang masih dikunci oleh worker.
+func lockedBy(id int, worker string) func(*entity.Job) bool {
+	return func(j *entity.Job) bool { return j.ID == id && j.Status == "Running" && j.LockedBy == worker }
+}
+
+func (r *jobRepository) update(ctx context.Context, match func(*entity.Job) bool, fn func(*entity.Job)) (bool, error) {
+	var affected int64
+	err := r.write(ctx, func(t *tables) error {
+		affected = t.jobs.update(match, fn)
+		return nil
+	})
+	return affected == 1, err
+}
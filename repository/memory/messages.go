@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"gorm.io/gorm"
+)
+
+type messageRepository struct {
+	base
+}
+
+func (r *messageRepository) Create(ctx context.Context, message *entity.Message, events repository.MessageEvents) error {
+	return r.write(ctx, func(t *tables) error {
+		if !t.bookings.has(message.BookingID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := *message
+		row.Booking = entity.Booking{}
+		if err := t.messages.prepare(&row); err != nil {
+			return err
+		}
+
+		created := *message
+		created.ID, created.CreatedAt, created.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		var outbox []entity.OutboxEvent
+		if events != nil {
+			var err error
+			if outbox, err = outboxRows(events(created)); err != nil {
+				return err
+			}
+		}
+
+		t.messages.put(row)
+		t.appendOutbox(outbox)
+		*message = created
+		return nil
+	})
+}
+
+func (r *messageRepository) FindByID(ctx context.Context, id int) (*entity.Message, error) {
+	var message entity.Message
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if message, ok = t.messages.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *messageRepository) FindByBookingID(ctx context.Context, bookingID int, q listquery.Query) (listquery.Page[entity.Message], error) {
+	var page listquery.Page[entity.Message]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.messages.filter(func(m *entity.Message) bool { return m.BookingID == bookingID }), q)
+		return nil
+	})
+	return page, err
+}
+
+// MarkAsRead menandai semua pesan dari pihak lain di sebuah booking sebagai sudah dibaca.
+// events hanya disimpan ke outbox jika ada pesan yang berubah.
+func (r *messageRepository) MarkAsRead(ctx context.Context, bookingID, readerID int, readAt time.Time, events []event.Event) (int64, error) {
+	var updated int64
+	err := r.write(ctx, func(t *tables) error {
+		unread := func(m *entity.Message) bool {
+			return m.BookingID == bookingID && m.SenderID != readerID && m.ReadAt == nil
+		}
+		if !t.messages.exists(unread) {
+			return nil
+		}
+		outbox, err := outboxRows(events)
+		if err != nil {
+			return err
+		}
+
+		updated = t.messages.update(unread, func(m *entity.Message) { m.ReadAt = &readAt })
+		t.appendOutbox(outbox)
+		return nil
+	})
+	return updated, err
+}
+
+// GetUnreadCounts menghitung pesan yang belum dibaca per booking, baik sebagai
+// customer (bookings.user_id) maupun sebagai technician (services.user_id).
+func (r *messageRepository) GetUnreadCounts(ctx context.Context, userID int) ([]entity.UnreadCount, error) {
+	counts := make([]entity.UnreadCount, 0)
+	err := r.read(ctx, func(t *tables) error {
+		unread := make(map[int]int)
+		for _, message := range t.messages.filter(func(m *entity.Message) bool { return m.SenderID != userID && m.ReadAt == nil }) {
+			booking, ok := t.bookings.get(message.BookingID)
+			if !ok {
+				continue
+			}
+			service, ok := t.services.get(booking.ServiceID)
+			if !ok || (booking.UserID != userID && service.UserID != userID) {
+				continue
+			}
+			unread[message.BookingID]++
+		}
+
+		for bookingID, count := range unread {
+			counts = append(counts, entity.UnreadCount{BookingID: bookingID, UnreadCount: count})
+		}
+		slices.SortFunc(counts, func(a, b entity.UnreadCount) int { return a.BookingID - b.BookingID })
+		return nil
+	})
+	return counts, err
+}
+
+func (r *messageRepository) DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+	return r.write(ctx, func(t *tables) error {
+		t.messages.remove(func(m *entity.Message) bool { return contains(bookingIDs, m.BookingID) })
+		return nil
+	})
+}
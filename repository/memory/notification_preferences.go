@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+)
+
+type notificationPreferenceRepository struct {
+	base
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(ctx context.Context, userID int) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	err := r.read(ctx, func(t *tables) error {
+		preferences = t.preferences.filter(func(p *entity.NotificationPreference) bool { return p.UserID == userID })
+		return nil
+	})
+	return preferences, err
+}
+
+// Upsert menyimpan preferensi baru atau memperbarui kolom enabled jika
+// kombinasi user, event dan channel sudah ada.
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, preferences []entity.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		for _, preference := range preferences {
+			same := func(p *entity.NotificationPreference) bool {
+				return p.UserID == preference.UserID && p.EventType == preference.EventType && p.Channel == preference.Channel
+			}
+			if t.preferences.exists(same) {
+				t.preferences.update(same, func(p *entity.NotificationPreference) { p.Enabled = preference.Enabled })
+				continue
+			}
+			if err := t.preferences.insert(&preference); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *notificationPreferenceRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return r.write(ctx, func(t *tables) error {
+		t.preferences.remove(func(p *entity.NotificationPreference) bool { return p.UserID == userID })
+		return nil
+	})
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	base
+}
+
+// Append menyimpan event yang tidak terikat dengan perubahan state, mis. pengingat.
+func (r *outboxRepository) Append(ctx context.Context, events ...event.Event) error {
+	return r.write(ctx, func(t *tables) error {
+		outbox, err := outboxRows(events)
+		if err != nil {
+			return err
+		}
+		t.appendOutbox(outbox)
+		return nil
+	})
+}
+
+// dueEvent mencocokkan event Pending yang sudah bisa dikirim dan event
+// Processing yang lock-nya kedaluwarsa.
+func dueEvent(now, staleBefore time.Time) func(*entity.OutboxEvent) bool {
+	return func(e *entity.OutboxEvent) bool {
+		return (e.Status == "Pending" && !e.AvailableAt.After(now)) ||
+			(e.Status == "Processing" && e.LockedAt != nil && e.LockedAt.Before(staleBefore))
+	}
+}
+
+func (r *outboxRepository) FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := r.read(ctx, func(t *tables) error {
+		events = paginate(t.outbox.filter(dueEvent(now, staleBefore)), limit, 0)
+		return nil
+	})
+	return events, err
+}
+
+// Claim mengunci event agar hanya satu dispatcher yang mengirimkannya.
+func (r *outboxRepository) Claim(ctx context.Context, id int, now, staleBefore time.Time) (bool, error) {
+	due := dueEvent(now, staleBefore)
+	return r.update(ctx, func(e *entity.OutboxEvent) bool { return e.ID == id && due(e) }, func(e *entity.OutboxEvent) {
+		e.Status = "Processing"
+		e.LockedAt = &now
+		e.Attempts++
+	})
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error {
+	_, err := r.update(ctx, withOutboxID(id), func(e *entity.OutboxEvent) {
+		e.Status = "Delivered"
+		e.LockedAt = nil
+		e.LastError = ""
+		e.DeliveredAt = &deliveredAt
+	})
+	return err
+}
+
+func (r *outboxRepository) Retry(ctx context.Context, id int, availableAt time.Time, lastError string) error {
+	_, err := r.update(ctx, withOutboxID(id), func(e *entity.OutboxEvent) {
+		e.Status = "Pending"
+		e.LockedAt = nil
+		e.AvailableAt = availableAt
+		e.LastError = lastError
+	})
+	return err
+}
+
+func (r *outboxRepository) Fail(ctx context.Context, id int, lastError string) error {
+	_, err := r.update(ctx, withOutboxID(id), func(e *entity.OutboxEvent) {
+		e.Status = "Failed"
+		e.LockedAt = nil
+		e.LastError = lastError
+	})
+	return err
+}
+
+// FindAfter mengambil event dengan ID lebih besar dari id secara berurutan.
+func (r *outboxRepository) FindAfter(ctx context.Context, id int, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := r.read(ctx, func(t *tables) error {
+		events = paginate(t.outbox.filter(func(e *entity.OutboxEvent) bool { return e.ID > id }), limit, 0)
+		return nil
+	})
+	return events, err
+}
+
+func (r *outboxRepository) LatestID(ctx context.Context) (int, error) {
+	var id int
+	err := r.read(ctx, func(t *tables) error {
+		if n := len(t.outbox.rows); n > 0 {
+			id = t.outbox.rows[n-1].ID
+		}
+		return nil
+	})
+	return id, err
+}
+
+func (r *outboxRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.OutboxEvent], error) {
+	var page listquery.Page[entity.OutboxEvent]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.outbox.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *outboxRepository) FindByID(ctx context.Context, id int) (entity.OutboxEvent, error) {
+	var evt entity.OutboxEvent
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if evt, ok = t.outbox.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	return evt, err
+}
+
+// Replay mengembalikan event Failed ke antrean. Subscriber yang sudah
+// memproses event tidak akan memprosesnya lagi.
+func (r *outboxRepository) Replay(ctx context.Context, id int, now time.Time) (bool, error) {
+	return r.update(ctx, func(e *entity.OutboxEvent) bool { return e.ID == id && e.Status == "Failed" }, func(e *entity.OutboxEvent) {
+		e.Status = "Pending"
+		e.Attempts = 0
+		e.AvailableAt = now
+		e.LastError = ""
+	})
+}
+
+func (r *outboxRepository) IsProcessed(ctx context.Context, eventID int, handler string) (bool, error) {
+	var processed bool
+	err := r.read(ctx, func(t *tables) error {
+		_, processed = t.processed[processedKey{EventID: eventID, Handler: handler}]
+		return nil
+	})
+	return processed, err
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, eventID int, handler string) error {
+	return r.write(ctx, func(t *tables) error {
+		key := processedKey{EventID: eventID, Handler: handler}
+		if _, ok := t.processed[key]; !ok {
+			t.processed[key] = time.Now()
+		}
+		return nil
+	})
+}
+
+func withOutboxID(id int) func(*entity.OutboxEvent) bool {
+	return func(e *entity.OutboxEvent) bool { return e.ID == id }
+}
+
+// update mengubah satu event yang cocok dan melaporkan apakah ada yang berubah.
+func (r *outboxRepository) update(ctx context.Context, match func(*entity.OutboxEvent) bool, fn func(*entity.OutboxEvent)) (bool, error) {
+	var affected int64
+	err := r.write(ctx, func(t *tables) error {
+		affected = t.outbox.update(match, fn)
+		return nil
+	})
+	return affected == 1, err
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"gorm.io/gorm"
+)
+
+type paymentRepository struct {
+	base
+}
+
+func paymentRow(payment entity.Payment) entity.Payment {
+	payment.Booking = entity.Booking{}
+	return payment
+}
+
+// paymentAmount membaca payments.amount yang disimpan sebagai teks. Nilai
+// yang bukan angka dihitung 0, seperti CAST di database.
+func paymentAmount(payment entity.Payment) float64 {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(payment.Amount), 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// paymentOutbox membentuk baris outbox dari payment beserta relasi
+// Booking.Service, sama seperti appendPaymentEvents di repository database.
+func (t *tables) paymentOutbox(payment entity.Payment, events repository.PaymentEvents) ([]entity.OutboxEvent, error) {
+	if events == nil {
+		return nil, nil
+	}
+	payment.Booking, _ = t.bookings.get(payment.BookingID)
+	payment.Booking.Service, _ = t.services.get(payment.Booking.ServiceID)
+	return outboxRows(events(payment))
+}
+
+// ofService mengembalikan pencocok booking_id untuk filter service_id
+// laporan. serviceID 0 berarti semua service.
+func (t *tables) ofService(serviceID int) func(bookingID int) bool {
+	return func(bookingID int) bool {
+		if serviceID <= 0 {
+			return true
+		}
+		booking, ok := t.bookings.get(bookingID)
+		return ok && booking.ServiceID == serviceID
+	}
+}
+
+func (r *paymentRepository) Create(ctx context.Context, payment entity.Payment, events repository.PaymentEvents) (entity.Payment, error) {
+	return r.persist(ctx, payment, events, false)
+}
+
+func (r *paymentRepository) Update(ctx context.Context, payment entity.Payment, events repository.PaymentEvents) (entity.Payment, error) {
+	return r.persist(ctx, payment, events, true)
+}
+
+// persist menyimpan payment baru (Create) atau menimpa payment yang ada (Update)
+// beserta event-nya.
+func (r *paymentRepository) persist(ctx context.Context, payment entity.Payment, events repository.PaymentEvents, save bool) (entity.Payment, error) {
+	err := r.write(ctx, func(t *tables) error {
+		if !t.bookings.has(payment.BookingID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := paymentRow(payment)
+		prepare := t.payments.prepare
+		if save {
+			prepare = t.payments.save
+		}
+		if err := prepare(&row); err != nil {
+			return err
+		}
+		outbox, err := t.paymentOutbox(row, events)
+		if err != nil {
+			return err
+		}
+
+		t.payments.put(row)
+		t.appendOutbox(outbox)
+		payment.ID, payment.CreatedAt, payment.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+	return payment, err
+}
+
+func (r *paymentRepository) FindByID(ctx context.Context, id int) (entity.Payment, error) {
+	var payment entity.Payment
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if payment, ok = t.payments.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		payment.Booking, _ = t.bookings.get(payment.BookingID)
+		return nil
+	})
+	return payment, err
+}
+
+func (r *paymentRepository) Delete(ctx context.Context, id int) error {
+	return r.write(ctx, func(t *tables) error {
+		t.payments.remove(func(p *entity.Payment) bool { return p.ID == id })
+		return nil
+	})
+}
+
+func (r *paymentRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Payment], error) {
+	var page listquery.Page[entity.Payment]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.payments.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *paymentRepository) UpdatePaymentStatus(ctx context.Context, paymentID string, status string, events repository.PaymentEvents) error {
+	_, err := r.setStatus(ctx, parseID(paymentID), nil, status, events, true)
+	return err
+}
+
+// TransitionStatus mengubah status hanya jika status saat ini masih sama dengan from.
+func (r *paymentRepository) TransitionStatus(ctx context.Context, paymentID int, from, to string, events repository.PaymentEvents) (bool, error) {
+	return r.setStatus(ctx, paymentID, &from, to, events, false)
+}
+
+// setStatus sama dengan bookingRepository.setStatus untuk payment.
+func (r *paymentRepository) setStatus(ctx context.Context, id int, from *string, to string, events repository.PaymentEvents, mustExist bool) (bool, error) {
+	changed := false
+	err := r.write(ctx, func(t *tables) error {
+		payment, ok := t.payments.get(id)
+		if !ok || (from != nil && payment.Status != *from) {
+			if mustExist && events != nil {
+				return gorm.ErrRecordNotFound
+			}
+			return nil
+		}
+
+		payment.Status = to
+		payment.UpdatedAt = time.Now()
+		outbox, err := t.paymentOutbox(payment, events)
+		if err != nil {
+			return err
+		}
+
+		t.payments.put(payment)
+		t.appendOutbox(outbox)
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+// report mengambil payment untuk laporan dengan filter tanggal dibuat dan service.
+func (r *paymentRepository) report(ctx context.Context, match func(*entity.Payment) bool, startDate, endDate time.Time, serviceID int) ([]entity.Payment, error) {
+	inRange := timeFilter(startDate, endDate)
+	var payments []entity.Payment
+	err := r.read(ctx, func(t *tables) error {
+		ofService := t.ofService(serviceID)
+		payments = t.payments.filter(func(p *entity.Payment) bool {
+			return match(p) && inRange(p.CreatedAt) && ofService(p.BookingID)
+		})
+		return nil
+	})
+	return payments, err
+}
+
+func sumAmount(payments []entity.Payment) float64 {
+	var total float64
+	for _, payment := range payments {
+		total += paymentAmount(payment)
+	}
+	return total
+}
+
+func allPayments(*entity.Payment) bool { return true }
+
+func (r *paymentRepository) GetTotalPayments(ctx context.Context, startDate, endDate time.Time, serviceID int) (int64, error) {
+	payments, err := r.report(ctx, allPayments, startDate, endDate, serviceID)
+	return int64(len(payments)), err
+}
+
+func (r *paymentRepository) GetTotalAmount(ctx context.Context, startDate, endDate time.Time, serviceID int) (float64, error) {
+	payments, err := r.report(ctx, allPayments, startDate, endDate, serviceID)
+	return sumAmount(payments), err
+}
+
+func (r *paymentRepository) GetPaymentsByStatus(ctx context.Context, status string, startDate, endDate time.Time, serviceID int) (int64, float64, error) {
+	payments, err := r.report(ctx, func(p *entity.Payment) bool { return p.Status == status }, startDate, endDate, serviceID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int64(len(payments)), sumAmount(payments), nil
+}
+
+func (r *paymentRepository) FindPendingBefore(ctx context.Context, createdBefore time.Time) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.read(ctx, func(t *tables) error {
+		payments = t.payments.filter(func(p *entity.Payment) bool { return p.Status == "Pending" && p.CreatedAt.Before(createdBefore) })
+		return nil
+	})
+	return payments, err
+}
+
+func (r *paymentRepository) DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+	return r.write(ctx, func(t *tables) error {
+		t.payments.remove(func(p *entity.Payment) bool { return contains(bookingIDs, p.BookingID) })
+		return nil
+	})
+}
+
+// DeleteByStatusUpdatedBefore menghapus payment dengan status tertentu yang
+// terakhir diubah sebelum waktu yang diberikan.
+func (r *paymentRepository) DeleteByStatusUpdatedBefore(ctx context.Context, status string, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.write(ctx, func(t *tables) error {
+		deleted = t.payments.remove(func(p *entity.Payment) bool { return p.Status == status && p.UpdatedAt.Before(before) })
+		return nil
+	})
+	return deleted, err
+}
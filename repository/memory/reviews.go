@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"gorm.io/gorm"
+)
+
+type reviewRepository struct {
+	base
+}
+
+func reviewRow(review entity.Review) entity.Review {
+	review.Booking = entity.Booking{}
+	return review
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review entity.Review, events repository.ReviewEvents) (entity.Review, error) {
+	err := r.write(ctx, func(t *tables) error {
+		if !t.bookings.has(review.BookingID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := reviewRow(review)
+		if err := t.reviews.prepare(&row); err != nil {
+			return err
+		}
+
+		var outbox []entity.OutboxEvent
+		if events != nil {
+			created := row
+			created.Booking, _ = t.bookings.get(row.BookingID)
+			created.Booking.Service, _ = t.services.get(created.Booking.ServiceID)
+
+			var err error
+			if outbox, err = outboxRows(events(created)); err != nil {
+				return err
+			}
+		}
+
+		t.reviews.put(row)
+		t.appendOutbox(outbox)
+		review.ID, review.CreatedAt, review.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+	return review, err
+}
+
+func (r *reviewRepository) FindByID(ctx context.Context, id int) (entity.Review, error) {
+	var review entity.Review
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if review, ok = t.reviews.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		review.Booking, _ = t.bookings.get(review.BookingID)
+		return nil
+	})
+	return review, err
+}
+
+func (r *reviewRepository) Update(ctx context.Context, review entity.Review) (entity.Review, error) {
+	err := r.write(ctx, func(t *tables) error {
+		if !t.bookings.has(review.BookingID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := reviewRow(review)
+		if err := t.reviews.save(&row); err != nil {
+			return err
+		}
+		t.reviews.put(row)
+		review.ID, review.CreatedAt, review.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+	return review, err
+}
+
+func (r *reviewRepository) Delete(ctx context.Context, id int) error {
+	return r.write(ctx, func(t *tables) error {
+		t.reviews.remove(func(r *entity.Review) bool { return r.ID == id })
+		return nil
+	})
+}
+
+func (r *reviewRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Review], error) {
+	var page listquery.Page[entity.Review]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.reviews.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+// report mengambil review untuk laporan dengan filter tanggal dibuat dan service.
+func (r *reviewRepository) report(ctx context.Context, startDate, endDate time.Time, serviceID int) ([]entity.Review, error) {
+	inRange := timeFilter(startDate, endDate)
+	var reviews []entity.Review
+	err := r.read(ctx, func(t *tables) error {
+		ofService := t.ofService(serviceID)
+		reviews = t.reviews.filter(func(r *entity.Review) bool { return inRange(r.CreatedAt) && ofService(r.BookingID) })
+		return nil
+	})
+	return reviews, err
+}
+
+func (r *reviewRepository) GetTotalReviews(ctx context.Context, startDate, endDate time.Time, serviceID int) (int64, error) {
+	reviews, err := r.report(ctx, startDate, endDate, serviceID)
+	return int64(len(reviews)), err
+}
+
+func (r *reviewRepository) GetAverageRating(ctx context.Context, startDate, endDate time.Time, serviceID int) (float64, error) {
+	reviews, err := r.report(ctx, startDate, endDate, serviceID)
+	if err != nil || len(reviews) == 0 {
+		return 0, err
+	}
+
+	var total int
+	for _, review := range reviews {
+		total += review.Rating
+	}
+	return float64(total) / float64(len(reviews)), nil
+}
+
+func (r *reviewRepository) GetReviewsByRating(ctx context.Context, rating int, startDate, endDate time.Time, serviceID int) (int64, error) {
+	reviews, err := r.report(ctx, startDate, endDate, serviceID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, review := range reviews {
+		if review.Rating == rating {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *reviewRepository) ExistsByBookingID(ctx context.Context, bookingID int) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(t *tables) error {
+		exists = t.reviews.exists(func(r *entity.Review) bool { return r.BookingID == bookingID })
+		return nil
+	})
+	return exists, err
+}
+
+func (r *reviewRepository) DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+	return r.write(ctx, func(t *tables) error {
+		t.reviews.remove(func(r *entity.Review) bool { return contains(bookingIDs, r.BookingID) })
+		return nil
+	})
+}
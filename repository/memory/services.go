@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+type serviceRepository struct {
+	base
+}
+
+func serviceRow(service entity.Service) entity.Service {
+	service.User = entity.User{}
+	service.Bookings = nil
+	return service
+}
+
+func (r *serviceRepository) Create(ctx context.Context, service *entity.Service) error {
+	return r.write(ctx, func(t *tables) error {
+		if !t.users.has(service.UserID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := serviceRow(*service)
+		if err := t.services.insert(&row); err != nil {
+			return err
+		}
+		service.ID, service.CreatedAt, service.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
+
+func (r *serviceRepository) FindByID(ctx context.Context, id int) (*entity.Service, error) {
+	var service entity.Service
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if service, ok = t.services.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		service.User, _ = t.users.get(service.UserID)
+		service.Bookings = t.bookings.filter(func(b *entity.Booking) bool { return b.ServiceID == id })
+		return nil
+	})
+	return &service, err
+}
+
+func (r *serviceRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Service], error) {
+	var page listquery.Page[entity.Service]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.services.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *serviceRepository) Update(ctx context.Context, service *entity.Service) error {
+	return r.write(ctx, func(t *tables) error {
+		if !t.users.has(service.UserID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := serviceRow(*service)
+		if err := t.services.save(&row); err != nil {
+			return err
+		}
+		t.services.put(row)
+		service.ID, service.CreatedAt, service.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
+
+func (r *serviceRepository) Delete(ctx context.Context, id int) error {
+	return r.write(ctx, func(t *tables) error {
+		if t.bookings.exists(func(b *entity.Booking) bool { return b.ServiceID == id }) {
+			return gorm.ErrForeignKeyViolated
+		}
+		t.services.remove(func(s *entity.Service) bool { return s.ID == id })
+		return nil
+	})
+}
+
+// SearchServices mencocokkan alamat technician, nama atau deskripsi service
+// tanpa membedakan huruf besar kecil, seperti LOWER(...) LIKE %query%.
+// Relasi User hanya diisi untuk service di halaman hasil, seperti Preload.
+func (r *serviceRepository) SearchServices(ctx context.Context, searchQuery string, q listquery.Query) (listquery.Page[entity.Service], error) {
+	searchQuery = strings.ToLower(searchQuery)
+
+	var page listquery.Page[entity.Service]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.services.filter(func(s *entity.Service) bool {
+			user, ok := t.users.get(s.UserID)
+			return ok && (searchQuery == "" ||
+				strings.Contains(strings.ToLower(user.Address), searchQuery) ||
+				strings.Contains(strings.ToLower(s.Name), searchQuery) ||
+				strings.Contains(strings.ToLower(s.Description), searchQuery))
+		}), q)
+		for i := range page.Items {
+			page.Items[i].User, _ = t.users.get(page.Items[i].UserID)
+		}
+		return nil
+	})
+	return page, err
+}
+
+// costRange mengelompokkan biaya service sama seperti CASE di repository database.
+func costRange(cost int) string {
+	switch {
+	case cost >= 50000 && cost <= 100000:
+		return "50000-100000"
+	case cost >= 100001 && cost <= 300000:
+		return "100001-300000"
+	case cost >= 300001 && cost <= 500000:
+		return "300001-500000"
+	case cost >= 500001 && cost <= 700000:
+		return "500001-700000"
+	case cost >= 700001 && cost <= 1000000:
+		return "700001-1000000"
+	default:
+		return "1000001+"
+	}
+}
+
+func (r *serviceRepository) GetServiceCostDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error) {
+	inRange, err := dateFilter(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	distributionMap := make(map[string]int)
+	err = r.read(ctx, func(t *tables) error {
+		for _, service := range t.services.filter(func(s *entity.Service) bool { return inRange(s.CreatedAt) }) {
+			distributionMap[costRange(service.Cost)]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return distributionMap, nil
+}
+
+func (r *serviceRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return r.write(ctx, func(t *tables) error {
+		owned := func(s *entity.Service) bool { return s.UserID == userID }
+		referenced := t.bookings.exists(func(b *entity.Booking) bool {
+			service, ok := t.services.get(b.ServiceID)
+			return ok && owned(&service)
+		})
+		if referenced {
+			return gorm.ErrForeignKeyViolated
+		}
+		t.services.remove(owned)
+		return nil
+	})
+}
@@ -0,0 +1,424 @@
+// Package memory berisi implementasi semua repository yang menyimpan data di
+// memori proses. Perilakunya mengikuti implementasi GORM (pagination, default
+// kolom, foreign key, unique index, filter tanggal dan agregat laporan),
+// sehingga bisa dipakai untuk test service layer yang cepat dan deterministik
+// serta untuk mode demo --storage=memory. Data hilang saat proses berhenti.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"gorm.io/gorm"
+)
+
+// NewStorage membuat storage kosong. Setiap pemanggilan menghasilkan data
+// yang terpisah, jadi setiap test bisa memakai storage-nya sendiri.
+func NewStorage() repository.Storage {
+	s := &store{tables: newTables()}
+	return repository.Storage{
+		Repositories: newRepositories(s, false),
+		Jobs:         &jobRepository{base{s: s}},
+		Webhooks:     &webhookRepository{base{s: s}},
+		UnitOfWork:   &unitOfWork{s},
+	}
+}
+
+// store menyimpan semua tabel. writer dipegang selama satu operasi tulis atau
+// satu transaksi UnitOfWork, sehingga transaksi berjalan serial seperti
+// SQLite. mu melindungi tables dari pembacaan yang bersamaan dengan penulisan;
+// pembacaan di luar transaksi bisa melihat perubahan yang belum di-commit.
+type store struct {
+	writer sync.Mutex
+	mu     sync.RWMutex
+	tables tables
+}
+
+type processedKey struct {
+	EventID int
+	Handler string
+}
+
+type tables struct {
+	users        table[entity.User]
+	services     table[entity.Service]
+	bookings     table[entity.Booking]
+	payments     table[entity.Payment]
+	reviews      table[entity.Review]
+	messages     table[entity.Message]
+	applications table[entity.TechnicianApplication]
+	preferences  table[entity.NotificationPreference]
+	outbox       table[entity.OutboxEvent]
+	processed    map[processedKey]time.Time
+	jobs         table[entity.Job]
+	endpoints    table[entity.WebhookEndpoint]
+	deliveries   table[entity.WebhookDelivery]
+}
+
+func newTables() tables {
+	return tables{
+		users: table[entity.User]{columns: func(u *entity.User) (*int, *time.Time, *time.Time) {
+			return &u.ID, &u.CreatedAt, &u.UpdatedAt
+		}},
+		services: table[entity.Service]{columns: func(s *entity.Service) (*int, *time.Time, *time.Time) {
+			return &s.ID, &s.CreatedAt, &s.UpdatedAt
+		}},
+		bookings: table[entity.Booking]{columns: func(b *entity.Booking) (*int, *time.Time, *time.Time) {
+			return &b.ID, &b.CreatedAt, &b.UpdatedAt
+		}},
+		payments: table[entity.Payment]{columns: func(p *entity.Payment) (*int, *time.Time, *time.Time) {
+			return &p.ID, &p.CreatedAt, &p.UpdatedAt
+		}},
+		reviews: table[entity.Review]{columns: func(r *entity.Review) (*int, *time.Time, *time.Time) {
+			return &r.ID, &r.CreatedAt, &r.UpdatedAt
+		}},
+		messages: table[entity.Message]{columns: func(m *entity.Message) (*int, *time.Time, *time.Time) {
+			return &m.ID, &m.CreatedAt, &m.UpdatedAt
+		}},
+		applications: table[entity.TechnicianApplication]{columns: func(a *entity.TechnicianApplication) (*int, *time.Time, *time.Time) {
+			return &a.ID, &a.CreatedAt, &a.UpdatedAt
+		}},
+		preferences: table[entity.NotificationPreference]{columns: func(p *entity.NotificationPreference) (*int, *time.Time, *time.Time) {
+			return &p.ID, &p.CreatedAt, &p.UpdatedAt
+		}},
+		outbox: table[entity.OutboxEvent]{columns: func(e *entity.OutboxEvent) (*int, *time.Time, *time.Time) {
+			return &e.ID, &e.CreatedAt, &e.UpdatedAt
+		}},
+		processed: make(map[processedKey]time.Time),
+		jobs: table[entity.Job]{columns: func(j *entity.Job) (*int, *time.Time, *time.Time) {
+			return &j.ID, &j.CreatedAt, &j.UpdatedAt
+		}},
+		endpoints: table[entity.WebhookEndpoint]{columns: func(e *entity.WebhookEndpoint) (*int, *time.Time, *time.Time) {
+			return &e.ID, &e.CreatedAt, &e.UpdatedAt
+		}},
+		deliveries: table[entity.WebhookDelivery]{columns: func(d *entity.WebhookDelivery) (*int, *time.Time, *time.Time) {
+			return &d.ID, &d.CreatedAt, &d.UpdatedAt
+		}},
+	}
+}
+
+// clone menyalin semua tabel untuk rollback transaksi. Baris disimpan sebagai
+// nilai dan field pointer tidak pernah diubah di tempat, jadi salinan dangkal cukup.
+func (t *tables) clone() tables {
+	c := *t
+	c.users = t.users.clone()
+	c.services = t.services.clone()
+	c.bookings = t.bookings.clone()
+	c.payments = t.payments.clone()
+	c.reviews = t.reviews.clone()
+	c.messages = t.messages.clone()
+	c.applications = t.applications.clone()
+	c.preferences = t.preferences.clone()
+	c.outbox = t.outbox.clone()
+	c.jobs = t.jobs.clone()
+	c.endpoints = t.endpoints.clone()
+	c.deliveries = t.deliveries.clone()
+	c.processed = make(map[processedKey]time.Time, len(t.processed))
+	for k, v := range t.processed {
+		c.processed[k] = v
+	}
+	return c
+}
+
+// outboxRows mengubah event menjadi baris outbox. Dipanggil sebelum tabel
+// diubah agar kegagalan konversi tidak meninggalkan perubahan setengah jadi.
+func outboxRows(events []event.Event) ([]entity.OutboxEvent, error) {
+	return repository.NewOutboxEvents(events)
+}
+
+// appendOutbox menyimpan baris outbox di dalam operasi tulis yang sama
+// dengan perubahan state-nya.
+func (t *tables) appendOutbox(rows []entity.OutboxEvent) {
+	for _, row := range rows {
+		t.outbox.insert(&row)
+	}
+}
+
+// table menyimpan baris berurutan berdasarkan ID, seperti primary key
+// auto increment. columns mengembalikan pointer ke kolom id, created_at dan
+// updated_at sebuah baris.
+type table[T any] struct {
+	rows    []T
+	lastID  int
+	columns func(*T) (id *int, createdAt, updatedAt *time.Time)
+}
+
+func (t table[T]) clone() table[T] {
+	t.rows = slices.Clone(t.rows)
+	return t
+}
+
+func (t *table[T]) id(row *T) int {
+	id, _, _ := t.columns(row)
+	return *id
+}
+
+func (t *table[T]) index(id int) (int, bool) {
+	return slices.BinarySearchFunc(t.rows, id, func(row T, id int) int {
+		return cmp.Compare(t.id(&row), id)
+	})
+}
+
+func (t *table[T]) get(id int) (T, bool) {
+	if i, ok := t.index(id); ok {
+		return t.rows[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+func (t *table[T]) has(id int) bool {
+	_, ok := t.index(id)
+	return ok
+}
+
+// insert mengisi ID dan timestamp seperti gorm.Create lalu menyimpan baris.
+func (t *table[T]) insert(row *T) error {
+	if err := t.prepare(row); err != nil {
+		return err
+	}
+	t.put(*row)
+	return nil
+}
+
+// prepare mengisi ID (jika kosong) dan timestamp (jika kosong) tanpa
+// menyimpan baris, sehingga baris bisa dipakai sebelum operasi dipastikan berhasil.
+func (t *table[T]) prepare(row *T) error {
+	id, createdAt, updatedAt := t.columns(row)
+	if *id == 0 {
+		*id = t.lastID + 1
+	} else if t.has(*id) {
+		return gorm.ErrDuplicatedKey
+	}
+	t.lastID = max(t.lastID, *id)
+
+	now := time.Now()
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = now
+	}
+	return nil
+}
+
+// save mengikuti gorm.Save: baris dengan ID yang sudah ada ditimpa dengan
+// updated_at baru, selain itu baris disisipkan. Seperti prepare, baris
+// belum disimpan sampai put dipanggil.
+func (t *table[T]) save(row *T) error {
+	id, _, updatedAt := t.columns(row)
+	if *id == 0 || !t.has(*id) {
+		return t.prepare(row)
+	}
+	*updatedAt = time.Now()
+	return nil
+}
+
+func (t *table[T]) put(row T) {
+	i, ok := t.index(t.id(&row))
+	if ok {
+		t.rows[i] = row
+		return
+	}
+	t.rows = slices.Insert(t.rows, i, row)
+}
+
+// update menjalankan fn pada setiap baris yang cocok dan memperbarui
+// updated_at-nya seperti Model().Updates. Mengembalikan jumlah baris yang berubah.
+func (t *table[T]) update(match func(*T) bool, fn func(*T)) int64 {
+	var affected int64
+	now := time.Now()
+	for i := range t.rows {
+		if match(&t.rows[i]) {
+			fn(&t.rows[i])
+			_, _, updatedAt := t.columns(&t.rows[i])
+			*updatedAt = now
+			affected++
+		}
+	}
+	return affected
+}
+
+// remove menghapus baris yang cocok dan mengembalikan jumlah baris yang dihapus.
+func (t *table[T]) remove(match func(*T) bool) int64 {
+	before := len(t.rows)
+	t.rows = slices.DeleteFunc(t.rows, func(row T) bool { return match(&row) })
+	return int64(before - len(t.rows))
+}
+
+// filter mengembalikan salinan baris yang cocok. Hasil kosong berupa slice
+// kosong (bukan nil) seperti hasil Find GORM.
+func (t *table[T]) filter(match func(*T) bool) []T {
+	rows := make([]T, 0)
+	for i := range t.rows {
+		if match == nil || match(&t.rows[i]) {
+			rows = append(rows, t.rows[i])
+		}
+	}
+	return rows
+}
+
+func (t *table[T]) exists(match func(*T) bool) bool {
+	return slices.ContainsFunc(t.rows, func(row T) bool { return match(&row) })
+}
+
+func (t *table[T]) count(match func(*T) bool) int64 {
+	var n int64
+	for i := range t.rows {
+		if match(&t.rows[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+// paginate mengikuti Limit(limit).Offset(offset) GORM: limit negatif berarti
+// tanpa batas dan offset hanya dipakai jika lebih dari nol.
+func paginate[T any](rows []T, limit, offset int) []T {
+	if offset > 0 {
+		rows = rows[min(offset, len(rows)):]
+	}
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return slices.Clip(rows)
+}
+
+func contains(ids []int, id int) bool {
+	return slices.Contains(ids, id)
+}
+
+// base menyediakan akses ke store. Repository di dalam transaksi (tx true)
+// tidak mengambil writer lagi karena sudah dipegang oleh UnitOfWork.
+type base struct {
+	s  *store
+	tx bool
+}
+
+func (b base) read(ctx context.Context, fn func(t *tables) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.s.mu.RLock()
+	defer b.s.mu.RUnlock()
+	return fn(&b.s.tables)
+}
+
+// write menjalankan fn secara eksklusif. fn harus memvalidasi semua hal
+// sebelum mengubah tabel, karena tidak ada rollback untuk satu operasi.
+func (b base) write(ctx context.Context, fn func(t *tables) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !b.tx {
+		b.s.writer.Lock()
+		defer b.s.writer.Unlock()
+	}
+	b.s.mu.Lock()
+	defer b.s.mu.Unlock()
+	return fn(&b.s.tables)
+}
+
+type unitOfWork struct {
+	s *store
+}
+
+// Do menjalankan fn dalam transaksi: tabel disalin sebelum fn dijalankan dan
+// dikembalikan jika fn mengembalikan error atau panic.
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	u.s.writer.Lock()
+	defer u.s.writer.Unlock()
+
+	u.s.mu.RLock()
+	snapshot := u.s.tables.clone()
+	u.s.mu.RUnlock()
+
+	committed := false
+	defer func() {
+		if !committed {
+			u.s.mu.Lock()
+			u.s.tables = snapshot
+			u.s.mu.Unlock()
+		}
+	}()
+
+	if err := fn(newRepositories(u.s, true)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func newRepositories(s *store, tx bool) repository.Repositories {
+	b := base{s: s, tx: tx}
+	return repository.Repositories{
+		Users:                   &userRepository{b},
+		Services:                &serviceRepository{b},
+		Bookings:                &bookingRepository{b},
+		Payments:                &paymentRepository{b},
+		Reviews:                 &reviewRepository{b},
+		Messages:                &messageRepository{b},
+		TechnicianApplications:  &technicianApplicationRepository{b},
+		NotificationPreferences: &notificationPreferenceRepository{b},
+		Outbox:                  &outboxRepository{b},
+	}
+}
+
+// between mengikuti kolom BETWEEN start AND end (inklusif di kedua sisi).
+func between(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
+
+// startOfDay mengembalikan awal hari t di zona waktunya sendiri, sama dengan
+// batas yang dipakai repository database untuk kolom tanggal.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+var dateLayouts = []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339}
+
+// parseDate membaca filter tanggal berbentuk teks yang oleh database
+// dibandingkan langsung dengan kolom waktu.
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// dateFilter mengubah filter tanggal teks menjadi fungsi pencocok. Filter
+// hanya dipakai jika kedua tanggal diisi, seperti di repository database.
+func dateFilter(startDate, endDate string) (func(time.Time) bool, error) {
+	if startDate == "" || endDate == "" {
+		return func(time.Time) bool { return true }, nil
+	}
+	start, err := parseDate(startDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDate(endDate)
+	if err != nil {
+		return nil, err
+	}
+	return func(t time.Time) bool { return between(t, start, end) }, nil
+}
+
+// timeFilter sama dengan dateFilter untuk filter bertipe time.Time.
+func timeFilter(startDate, endDate time.Time) func(time.Time) bool {
+	if startDate.IsZero() || endDate.IsZero() {
+		return func(time.Time) bool { return true }
+	}
+	return func(t time.Time) bool { return between(t, startDate, endDate) }
+}
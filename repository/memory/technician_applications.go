@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+type technicianApplicationRepository struct {
+	base
+}
+
+func applicationRow(application entity.TechnicianApplication) entity.TechnicianApplication {
+	application.User = entity.User{}
+	return application
+}
+
+func (r *technicianApplicationRepository) Create(ctx context.Context, application *entity.TechnicianApplication) error {
+	return r.write(ctx, func(t *tables) error {
+		if !t.users.has(application.UserID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := applicationRow(*application)
+		if err := t.applications.insert(&row); err != nil {
+			return err
+		}
+		application.ID, application.CreatedAt, application.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
+
+func (r *technicianApplicationRepository) FindByID(ctx context.Context, id int) (*entity.TechnicianApplication, error) {
+	var application entity.TechnicianApplication
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if application, ok = t.applications.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *technicianApplicationRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.TechnicianApplication], error) {
+	var page listquery.Page[entity.TechnicianApplication]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.applications.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *technicianApplicationRepository) FindLatestByUserID(ctx context.Context, userID int) (*entity.TechnicianApplication, error) {
+	var applications []entity.TechnicianApplication
+	err := r.read(ctx, func(t *tables) error {
+		applications = t.applications.filter(func(a *entity.TechnicianApplication) bool { return a.UserID == userID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(applications) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &applications[len(applications)-1], nil
+}
+
+func (r *technicianApplicationRepository) Update(ctx context.Context, application *entity.TechnicianApplication) error {
+	return r.write(ctx, func(t *tables) error {
+		if !t.users.has(application.UserID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := applicationRow(*application)
+		if err := t.applications.save(&row); err != nil {
+			return err
+		}
+		t.applications.put(row)
+		application.ID, application.CreatedAt, application.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
+
+func (r *technicianApplicationRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return r.write(ctx, func(t *tables) error {
+		t.applications.remove(func(a *entity.TechnicianApplication) bool { return a.UserID == userID })
+		return nil
+	})
+}
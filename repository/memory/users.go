@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	base
+}
+
+// checkUser menerapkan CHECK constraint kolom users.role.
+func checkUser(user entity.User) error {
+	switch user.Role {
+	case "admin", "user", "technician":
+		return nil
+	}
+	return gorm.ErrCheckConstraintViolated
+}
+
+// userRow membuang relasi sebelum baris disimpan.
+func userRow(user entity.User) entity.User {
+	user.Services = nil
+	user.Bookings = nil
+	return user
+}
+
+func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.write(ctx, func(t *tables) error {
+		// Default kolom ikut diisi ke struct seperti yang dilakukan GORM
+		if user.Role == "" {
+			user.Role = "user"
+		}
+		if user.Language == "" {
+			user.Language = "id"
+		}
+		if err := checkUser(*user); err != nil {
+			return err
+		}
+
+		row := userRow(*user)
+		if err := t.users.insert(&row); err != nil {
+			return err
+		}
+		user.ID, user.CreatedAt, user.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id int) (*entity.User, error) {
+	var user entity.User
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if user, ok = t.users.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.User], error) {
+	var page listquery.Page[entity.User]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.users.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
+	return r.write(ctx, func(t *tables) error {
+		if err := checkUser(*user); err != nil {
+			return err
+		}
+		row := userRow(*user)
+		if err := t.users.save(&row); err != nil {
+			return err
+		}
+		t.users.put(row)
+		user.ID, user.CreatedAt, user.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
+
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	return r.write(ctx, func(t *tables) error {
+		referenced := t.services.exists(func(s *entity.Service) bool { return s.UserID == id }) ||
+			t.bookings.exists(func(b *entity.Booking) bool { return b.UserID == id }) ||
+			t.applications.exists(func(a *entity.TechnicianApplication) bool { return a.UserID == id })
+		if referenced {
+			return gorm.ErrForeignKeyViolated
+		}
+		t.users.remove(func(u *entity.User) bool { return u.ID == id })
+		return nil
+	})
+}
+
+func (r *userRepository) FindUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	var users []entity.User
+	err := r.read(ctx, func(t *tables) error {
+		users = t.users.filter(func(u *entity.User) bool { return u.Email == email })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &users[0], nil
+}
+
+func (r *userRepository) IsEmailExists(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func(t *tables) error {
+		exists = t.users.exists(func(u *entity.User) bool { return u.Email == email })
+		return nil
+	})
+	return exists, err
+}
+
+func (r *userRepository) GetUserRoleDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error) {
+	inRange, err := dateFilter(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	distributionMap := make(map[string]int)
+	err = r.read(ctx, func(t *tables) error {
+		for _, user := range t.users.filter(func(u *entity.User) bool { return inRange(u.CreatedAt) }) {
+			distributionMap[user.Role]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return distributionMap, nil
+}
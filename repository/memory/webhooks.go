@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	base
+}
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return r.write(ctx, func(t *tables) error {
+		return t.endpoints.insert(endpoint)
+	})
+}
+
+func (r *webhookRepository) FindEndpointByID(ctx context.Context, id int) (*entity.WebhookEndpoint, error) {
+	var endpoint entity.WebhookEndpoint
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if endpoint, ok = t.endpoints.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *webhookRepository) FindEndpoints(ctx context.Context, q listquery.Query) (listquery.Page[entity.WebhookEndpoint], error) {
+	var page listquery.Page[entity.WebhookEndpoint]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.endpoints.filter(nil), q)
+		return nil
+	})
+	return page, err
+}
+
+// FindActiveEndpointsByEvent mengambil endpoint aktif yang berlangganan eventType.
+func (r *webhookRepository) FindActiveEndpointsByEvent(ctx context.Context, eventType string) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	err := r.read(ctx, func(t *tables) error {
+		endpoints = t.endpoints.filter(func(e *entity.WebhookEndpoint) bool {
+			for _, subscribed := range strings.Split(e.EventTypes, ",") {
+				if e.Active && subscribed == eventType {
+					return true
+				}
+			}
+			return false
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+	return endpoints, nil
+}
+
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return r.write(ctx, func(t *tables) error {
+		if err := t.endpoints.save(endpoint); err != nil {
+			return err
+		}
+		t.endpoints.put(*endpoint)
+		return nil
+	})
+}
+
+// DeleteEndpoint menghapus endpoint beserta delivery log-nya.
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id int) error {
+	return r.write(ctx, func(t *tables) error {
+		t.deliveries.remove(func(d *entity.WebhookDelivery) bool { return d.EndpointID == id })
+		t.endpoints.remove(func(e *entity.WebhookEndpoint) bool { return e.ID == id })
+		return nil
+	})
+}
+
+// RecordFailure menambah jumlah kegagalan berturut-turut dan menonaktifkan
+// endpoint jika sudah mencapai disableAfter. Mengembalikan true jika endpoint
+// baru saja dinonaktifkan.
+func (r *webhookRepository) RecordFailure(ctx context.Context, endpointID, disableAfter int, now time.Time) (bool, error) {
+	var disabled int64
+	err := r.write(ctx, func(t *tables) error {
+		withID := func(e *entity.WebhookEndpoint) bool { return e.ID == endpointID }
+		t.endpoints.update(withID, func(e *entity.WebhookEndpoint) { e.ConsecutiveFailures++ })
+		disabled = t.endpoints.update(func(e *entity.WebhookEndpoint) bool {
+			return withID(e) && e.Active && e.ConsecutiveFailures >= disableAfter
+		}, func(e *entity.WebhookEndpoint) {
+			e.Active = false
+			e.DisabledAt = &now
+		})
+		return nil
+	})
+	return disabled == 1, err
+}
+
+func (r *webhookRepository) ResetFailures(ctx context.Context, endpointID int) error {
+	return r.write(ctx, func(t *tables) error {
+		t.endpoints.update(func(e *entity.WebhookEndpoint) bool { return e.ID == endpointID }, func(e *entity.WebhookEndpoint) {
+			e.ConsecutiveFailures = 0
+		})
+		return nil
+	})
+}
+
+// CreateDelivery menyimpan delivery baru. Jika event yang sama sudah pernah
+// dijadwalkan ke endpoint tersebut, delivery tidak dibuat ulang.
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	created := false
+	err := r.write(ctx, func(t *tables) error {
+		if !t.endpoints.has(delivery.EndpointID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		duplicate := t.deliveries.exists(func(d *entity.WebhookDelivery) bool {
+			return d.EndpointID == delivery.EndpointID && d.EventID == delivery.EventID
+		})
+		if duplicate {
+			return nil
+		}
+
+		row := *delivery
+		row.Endpoint = entity.WebhookEndpoint{}
+		if err := t.deliveries.insert(&row); err != nil {
+			return err
+		}
+		delivery.ID, delivery.CreatedAt, delivery.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *webhookRepository) FindDeliveryByID(ctx context.Context, id int) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.read(ctx, func(t *tables) error {
+		var ok bool
+		if delivery, ok = t.deliveries.get(id); !ok {
+			return gorm.ErrRecordNotFound
+		}
+		delivery.Endpoint, _ = t.endpoints.get(delivery.EndpointID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveryByEvent(ctx context.Context, endpointID, eventID int) (*entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := r.read(ctx, func(t *tables) error {
+		deliveries = t.deliveries.filter(func(d *entity.WebhookDelivery) bool { return d.EndpointID == endpointID && d.EventID == eventID })
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &deliveries[0], nil
+}
+
+func (r *webhookRepository) FindDeliveriesByEndpointID(ctx context.Context, endpointID int, q listquery.Query) (listquery.Page[entity.WebhookDelivery], error) {
+	var page listquery.Page[entity.WebhookDelivery]
+	err := r.read(ctx, func(t *tables) error {
+		page = listquery.Slice(t.deliveries.filter(func(d *entity.WebhookDelivery) bool { return d.EndpointID == endpointID }), q)
+		return nil
+	})
+	return page, err
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return r.write(ctx, func(t *tables) error {
+		if !t.endpoints.has(delivery.EndpointID) {
+			return gorm.ErrForeignKeyViolated
+		}
+		row := *delivery
+		row.Endpoint = entity.WebhookEndpoint{}
+		if err := t.deliveries.save(&row); err != nil {
+			return err
+		}
+		t.deliveries.put(row)
+		delivery.ID, delivery.CreatedAt, delivery.UpdatedAt = row.ID, row.CreatedAt, row.UpdatedAt
+		return nil
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+// MessageEvents membentuk domain event dari pesan yang baru disimpan.
+type MessageEvents func(message entity.Message) []event.Event
+
+type MessageRepository interface {
+	Create(ctx context.Context, message *entity.Message, events MessageEvents) error
+	FindByID(ctx context.Context, id int) (*entity.Message, error)
+	FindByBookingID(ctx context.Context, bookingID int, q listquery.Query) (listquery.Page[entity.Message], error)
+	MarkAsRead(ctx context.Context, bookingID, readerID int, readAt time.Time, events []event.Event) (int64, error)
+	GetUnreadCounts(ctx context.Context, userID int) ([]entity.UnreadCount, error)
+	DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error
+}
+
+type messageRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageRepository(db *gorm.DB) MessageRepository {
+	return &messageRepository{db}
+}
+
+func (r *messageRepository) Create(ctx context.Context, message *entity.Message, events MessageEvents) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		if events == nil {
+			return nil
+		}
+		return appendEvents(tx, events(*message))
+	})
+}
+
+func (r *messageRepository) FindByID(ctx context.Context, id int) (*entity.Message, error) {
+	var message entity.Message
+	err := r.db.WithContext(ctx).First(&message, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+func (r *messageRepository) FindByBookingID(ctx context.Context, bookingID int, q listquery.Query) (listquery.Page[entity.Message], error) {
+	query := r.db.WithContext(ctx).Where("booking_id = ?", bookingID)
+	return findPage[entity.Message](query, "messages", q)
+}
+
+// MarkAsRead menandai semua pesan dari pihak lain di sebuah booking sebagai sudah dibaca.
+// events hanya disimpan ke outbox jika ada pesan yang berubah.
+func (r *messageRepository) MarkAsRead(ctx context.Context, bookingID, readerID int, readAt time.Time, events []event.Event) (int64, error) {
+	var updated int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Message{}).
+			Where("booking_id = ? AND sender_id <> ? AND read_at IS NULL", bookingID, readerID).
+			Update("read_at", readAt)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = result.RowsAffected
+		return appendEvents(tx, events)
+	})
+	return updated, err
+}
+
+// GetUnreadCounts menghitung pesan yang belum dibaca per booking, baik sebagai
+// customer (bookings.user_id) maupun sebagai technician (services.user_id).
+func (r *messageRepository) GetUnreadCounts(ctx context.Context, userID int) ([]entity.UnreadCount, error) {
+	var counts []entity.UnreadCount
+	err := r.db.WithContext(ctx).Model(&entity.Message{}).
+		Select("messages.booking_id, count(*) as unread_count").
+		Joins("JOIN bookings ON bookings.id = messages.booking_id").
+		Joins("JOIN services ON services.id = bookings.service_id").
+		Where("(bookings.user_id = ? OR services.user_id = ?) AND messages.sender_id <> ? AND messages.read_at IS NULL", userID, userID, userID).
+		Group("messages.booking_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *messageRepository) DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("booking_id IN ?", bookingIDs).Delete(&entity.Message{}).Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationPreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID int) ([]entity.NotificationPreference, error)
+	Upsert(ctx context.Context, preferences []entity.NotificationPreference) error
+	DeleteByUserID(ctx context.Context, userID int) error
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db}
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(ctx context.Context, userID int) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&preferences).Error
+	return preferences, err
+}
+
+// Upsert menyimpan preferensi baru atau memperbarui kolom enabled jika
+// kombinasi user, event dan channel sudah ada.
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, preferences []entity.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
+
+func (r *notificationPreferenceRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.NotificationPreference{}).Error
+}
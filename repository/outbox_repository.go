@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	Append(ctx context.Context, events ...event.Event) error
+	FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.OutboxEvent, error)
+	Claim(ctx context.Context, id int, now, staleBefore time.Time) (bool, error)
+	MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error
+	Retry(ctx context.Context, id int, availableAt time.Time, lastError string) error
+	Fail(ctx context.Context, id int, lastError string) error
+	FindAfter(ctx context.Context, id int, limit int) ([]entity.OutboxEvent, error)
+	LatestID(ctx context.Context) (int, error)
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.OutboxEvent], error)
+	FindByID(ctx context.Context, id int) (entity.OutboxEvent, error)
+	Replay(ctx context.Context, id int, now time.Time) (bool, error)
+	IsProcessed(ctx context.Context, eventID int, handler string) (bool, error)
+	MarkProcessed(ctx context.Context, eventID int, handler string) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db}
+}
+
+// appendEvents menyimpan event ke tabel outbox menggunakan transaksi tx,
+// sehingga event hanya tersimpan jika perubahan state juga berhasil di-commit.
+func appendEvents(tx *gorm.DB, events []event.Event) error {
+	rows, err := NewOutboxEvents(events)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return tx.Create(&rows).Error
+}
+
+// NewOutboxEvents mengubah domain event menjadi baris outbox berstatus Pending.
+func NewOutboxEvents(events []event.Event) ([]entity.OutboxEvent, error) {
+	rows := make([]entity.OutboxEvent, 0, len(events))
+	for _, evt := range events {
+		recipients, err := json.Marshal(evt.Recipients)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := json.Marshal(evt.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		occurredAt := evt.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = time.Now()
+		}
+
+		rows = append(rows, entity.OutboxEvent{
+			Type:        evt.Type,
+			Recipients:  string(recipients),
+			Payload:     string(payload),
+			Status:      "Pending",
+			AvailableAt: occurredAt,
+			OccurredAt:  occurredAt,
+		})
+	}
+	return rows, nil
+}
+
+// Append menyimpan event yang tidak terikat dengan perubahan state, mis. pengingat.
+func (r *outboxRepository) Append(ctx context.Context, events ...event.Event) error {
+	return appendEvents(r.db.WithContext(ctx), events)
+}
+
+// FindDue mengambil event Pending yang sudah bisa dikirim, termasuk event
+// Processing yang lock-nya kedaluwarsa (dispatcher mati di tengah pengiriman).
+func (r *outboxRepository) FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := r.db.WithContext(ctx).Where("(status = ? AND available_at <= ?) OR (status = ? AND locked_at < ?)", "Pending", now, "Processing", staleBefore).
+		Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// Claim mengunci event agar hanya satu replica yang mengirimkannya.
+func (r *outboxRepository) Claim(ctx context.Context, id int, now, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).
+		Where("id = ? AND ((status = ? AND available_at <= ?) OR (status = ? AND locked_at < ?))", id, "Pending", now, "Processing", staleBefore).
+		Updates(map[string]interface{}{
+			"status":    "Processing",
+			"locked_at": now,
+			"attempts":  gorm.Expr("attempts + 1"),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id int, deliveredAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       "Delivered",
+		"locked_at":    nil,
+		"last_error":   "",
+		"delivered_at": deliveredAt,
+	}).Error
+}
+
+func (r *outboxRepository) Retry(ctx context.Context, id int, availableAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       "Pending",
+		"locked_at":    nil,
+		"available_at": availableAt,
+		"last_error":   lastError,
+	}).Error
+}
+
+func (r *outboxRepository) Fail(ctx context.Context, id int, lastError string) error {
+	return r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     "Failed",
+		"locked_at":  nil,
+		"last_error": lastError,
+	}).Error
+}
+
+// FindAfter mengambil event dengan ID lebih besar dari id secara berurutan.
+func (r *outboxRepository) FindAfter(ctx context.Context, id int, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := r.db.WithContext(ctx).Where("id > ?", id).Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *outboxRepository) LatestID(ctx context.Context) (int, error) {
+	var id int
+	err := r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+func (r *outboxRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.OutboxEvent], error) {
+	return findPage[entity.OutboxEvent](r.db.WithContext(ctx), "outbox_events", q)
+}
+
+func (r *outboxRepository) FindByID(ctx context.Context, id int) (entity.OutboxEvent, error) {
+	var evt entity.OutboxEvent
+	err := r.db.WithContext(ctx).First(&evt, id).Error
+	return evt, err
+}
+
+// Replay mengembalikan event Failed ke antrean. Subscriber yang sudah
+// memproses event tidak akan memprosesnya lagi.
+func (r *outboxRepository) Replay(ctx context.Context, id int, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).Where("id = ? AND status = ?", id, "Failed").Updates(map[string]interface{}{
+		"status":       "Pending",
+		"attempts":     0,
+		"available_at": now,
+		"last_error":   "",
+	})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *outboxRepository) IsProcessed(ctx context.Context, eventID int, handler string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.ProcessedEvent{}).Where("event_id = ? AND handler = ?", eventID, handler).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, eventID int, handler string) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.ProcessedEvent{EventID: eventID, Handler: handler}).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+// findPage menjalankan listquery.Query pada tabel table. query boleh sudah
+// berisi join dan kondisi khusus repository; kolom dari q selalu diberi
+// nama tabel agar tidak ambigu saat join. Total dihitung sebelum cursor
+// diterapkan, lalu halaman diambil dengan satu baris tambahan untuk
+// mengetahui apakah masih ada halaman berikutnya. preloads hanya dimuat
+// untuk baris halaman tersebut.
+func findPage[T any](query *gorm.DB, table string, q listquery.Query, preloads ...string) (listquery.Page[T], error) {
+	query = query.Model(new(T))
+	for _, condition := range q.Conditions {
+		query = query.Where(fmt.Sprintf("%s.%s %s ?", table, condition.Field.Name, condition.Op), condition.Value)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return listquery.Page[T]{}, err
+	}
+
+	column, id := table+"."+q.Sort.Field.Name, table+".id"
+	direction, op := "ASC", ">"
+	if q.Sort.Desc {
+		direction, op = "DESC", "<"
+	}
+	order := fmt.Sprintf("%s %s", id, direction)
+	if q.Sort.Field != listquery.ID {
+		order = fmt.Sprintf("%s %s, %s", column, direction, order)
+	}
+
+	if after := q.After; after != nil {
+		if q.Sort.Field == listquery.ID {
+			query = query.Where(fmt.Sprintf("%s %s ?", id, op), after.ID)
+		} else {
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", column, op, column, id, op), after.Value, after.Value, after.ID)
+		}
+	}
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+
+	var rows []T
+	if err := query.Order(order).Limit(q.Limit + 1).Offset(q.Offset).Find(&rows).Error; err != nil {
+		return listquery.Page[T]{}, err
+	}
+	return listquery.NewPage(rows, total, q), nil
+}
@@ -1,22 +1,33 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"gorm.io/gorm"
 )
 
+// PaymentEvents membentuk domain event dari payment yang baru disimpan
+// (termasuk relasi Booking.Service). Event disimpan ke outbox dalam transaksi yang sama.
+type PaymentEvents func(payment entity.Payment) []event.Event
+
 type PaymentRepository interface {
-	Create(payment entity.Payment) (entity.Payment, error)
-	FindByID(id int) (entity.Payment, error)
-	Update(payment entity.Payment) (entity.Payment, error)
-	Delete(id int) error
-	FindAll(limit, offset int) ([]entity.Payment, error)
-	UpdatePaymentStatus(paymentID string, status string) error
-	GetTotalPayments(startDate, endDate time.Time, serviceID int) (int64, error)
-	GetTotalAmount(startDate, endDate time.Time, serviceID int) (float64, error)
-	GetPaymentsByStatus(status string, startDate, endDate time.Time, serviceID int) (int64, float64, error)
+	Create(ctx context.Context, payment entity.Payment, events PaymentEvents) (entity.Payment, error)
+	FindByID(ctx context.Context, id int) (entity.Payment, error)
+	Update(ctx context.Context, payment entity.Payment, events PaymentEvents) (entity.Payment, error)
+	Delete(ctx context.Context, id int) error
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Payment], error)
+	UpdatePaymentStatus(ctx context.Context, paymentID string, status string, events PaymentEvents) error
+	GetTotalPayments(ctx context.Context, startDate, endDate time.Time, serviceID int) (int64, error)
+	GetTotalAmount(ctx context.Context, startDate, endDate time.Time, serviceID int) (float64, error)
+	GetPaymentsByStatus(ctx context.Context, status string, startDate, endDate time.Time, serviceID int) (int64, float64, error)
+	FindPendingBefore(ctx context.Context, createdBefore time.Time) ([]entity.Payment, error)
+	TransitionStatus(ctx context.Context, paymentID int, from, to string, events PaymentEvents) (bool, error)
+	DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error
+	DeleteByStatusUpdatedBefore(ctx context.Context, status string, before time.Time) (int64, error)
 }
 
 type paymentRepository struct {
@@ -27,40 +38,53 @@ func NewPaymentRepository(db *gorm.DB) PaymentRepository {
 	return &paymentRepository{db}
 }
 
-func (r *paymentRepository) Create(payment entity.Payment) (entity.Payment, error) {
-	err := r.db.Create(&payment).Error
+func (r *paymentRepository) Create(ctx context.Context, payment entity.Payment, events PaymentEvents) (entity.Payment, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+		return appendPaymentEvents(tx, payment.ID, events)
+	})
 	return payment, err
 }
 
-func (r *paymentRepository) FindByID(id int) (entity.Payment, error) {
+func (r *paymentRepository) FindByID(ctx context.Context, id int) (entity.Payment, error) {
 	var payment entity.Payment
-	err := r.db.Preload("Booking").First(&payment, id).Error
+	err := r.db.WithContext(ctx).Preload("Booking").First(&payment, id).Error
 	return payment, err
 }
 
-func (r *paymentRepository) Update(payment entity.Payment) (entity.Payment, error) {
-	err := r.db.Save(&payment).Error
+func (r *paymentRepository) Update(ctx context.Context, payment entity.Payment, events PaymentEvents) (entity.Payment, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&payment).Error; err != nil {
+			return err
+		}
+		return appendPaymentEvents(tx, payment.ID, events)
+	})
 	return payment, err
 }
 
-func (r *paymentRepository) Delete(id int) error {
-	err := r.db.Delete(&entity.Payment{}, id).Error
+func (r *paymentRepository) Delete(ctx context.Context, id int) error {
+	err := r.db.WithContext(ctx).Delete(&entity.Payment{}, id).Error
 	return err
 }
 
-func (r *paymentRepository) FindAll(limit, offset int) ([]entity.Payment, error) {
-	var payments []entity.Payment
-	err := r.db.Limit(limit).Offset(offset).Find(&payments).Error
-	return payments, err
+func (r *paymentRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Payment], error) {
+	return findPage[entity.Payment](r.db.WithContext(ctx), "payments", q)
 }
 
-func (r *paymentRepository) UpdatePaymentStatus(paymentID string, status string) error {
-	return r.db.Model(&entity.Payment{}).Where("id = ?", paymentID).Update("status", status).Error
+func (r *paymentRepository) UpdatePaymentStatus(ctx context.Context, paymentID string, status string, events PaymentEvents) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", paymentID).Update("status", status).Error; err != nil {
+			return err
+		}
+		return appendPaymentEvents(tx, paymentID, events)
+	})
 }
 
-func (r *paymentRepository) GetTotalPayments(startDate, endDate time.Time, serviceID int) (int64, error) {
+func (r *paymentRepository) GetTotalPayments(ctx context.Context, startDate, endDate time.Time, serviceID int) (int64, error) {
 	var total int64
-	query := r.db.Model(&entity.Payment{})
+	query := r.db.WithContext(ctx).Model(&entity.Payment{})
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
@@ -77,9 +101,9 @@ func (r *paymentRepository) GetTotalPayments(startDate, endDate time.Time, servi
 	return total, err
 }
 
-func (r *paymentRepository) GetTotalAmount(startDate, endDate time.Time, serviceID int) (float64, error) {
+func (r *paymentRepository) GetTotalAmount(ctx context.Context, startDate, endDate time.Time, serviceID int) (float64, error) {
 	var totalAmount float64
-	query := r.db.Model(&entity.Payment{}).Select("COALESCE(SUM(payments.amount), 0)")
+	query := r.db.WithContext(ctx).Model(&entity.Payment{}).Select(sumPaymentAmount)
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
@@ -96,10 +120,10 @@ func (r *paymentRepository) GetTotalAmount(startDate, endDate time.Time, service
 	return totalAmount, err
 }
 
-func (r *paymentRepository) GetPaymentsByStatus(status string, startDate, endDate time.Time, serviceID int) (int64, float64, error) {
+func (r *paymentRepository) GetPaymentsByStatus(ctx context.Context, status string, startDate, endDate time.Time, serviceID int) (int64, float64, error) {
 	var count int64
 	var totalAmount float64
-	query := r.db.Model(&entity.Payment{}).Where("payments.status = ?", status)
+	query := r.db.WithContext(ctx).Model(&entity.Payment{}).Where("payments.status = ?", status)
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
@@ -118,10 +142,58 @@ func (r *paymentRepository) GetPaymentsByStatus(status string, startDate, endDat
 		return 0, 0, err
 	}
 
-	err = query.Select("COALESCE(SUM(payments.amount), 0)").Scan(&totalAmount).Error
+	err = query.Select(sumPaymentAmount).Scan(&totalAmount).Error
 	if err != nil {
 		return 0, 0, err
 	}
 
 	return count, totalAmount, nil
 }
+
+func (r *paymentRepository) FindPendingBefore(ctx context.Context, createdBefore time.Time) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	err := r.db.WithContext(ctx).Where("status = ? AND created_at < ?", "Pending", createdBefore).Find(&payments).Error
+	return payments, err
+}
+
+// TransitionStatus mengubah status hanya jika status saat ini masih sama dengan from.
+func (r *paymentRepository) TransitionStatus(ctx context.Context, paymentID int, from, to string, events PaymentEvents) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Payment{}).Where("id = ? AND status = ?", paymentID, from).Update("status", to)
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+		changed = true
+		return appendPaymentEvents(tx, paymentID, events)
+	})
+	return changed, err
+}
+
+// appendPaymentEvents memuat ulang payment beserta booking dan service di
+// dalam transaksi lalu menyimpan event yang dibentuk oleh events ke outbox.
+func appendPaymentEvents(tx *gorm.DB, paymentID interface{}, events PaymentEvents) error {
+	if events == nil {
+		return nil
+	}
+
+	var payment entity.Payment
+	if err := tx.Preload("Booking.Service").Where("id = ?", paymentID).First(&payment).Error; err != nil {
+		return err
+	}
+	return appendEvents(tx, events(payment))
+}
+
+func (r *paymentRepository) DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("booking_id IN ?", bookingIDs).Delete(&entity.Payment{}).Error
+}
+
+// DeleteByStatusUpdatedBefore menghapus payment dengan status tertentu yang
+// terakhir diubah sebelum waktu yang diberikan.
+func (r *paymentRepository) DeleteByStatusUpdatedBefore(ctx context.Context, status string, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("status = ? AND updated_at < ?", status, before).Delete(&entity.Payment{})
+	return result.RowsAffected, result.Error
+}
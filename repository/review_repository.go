@@ -1,21 +1,30 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"gorm.io/gorm"
 )
 
+// ReviewEvents membentuk domain event dari review yang baru disimpan
+// (termasuk relasi Booking.Service). Event disimpan ke outbox dalam transaksi yang sama.
+type ReviewEvents func(review entity.Review) []event.Event
+
 type ReviewRepository interface {
-	Create(review entity.Review) (entity.Review, error)
-	FindByID(id int) (entity.Review, error)
-	Update(review entity.Review) (entity.Review, error)
-	Delete(id int) error
-	FindAll(limit, offset int) ([]entity.Review, error)
-	GetTotalReviews(startDate, endDate time.Time, serviceID int) (int64, error)
-	GetAverageRating(startDate, endDate time.Time, serviceID int) (float64, error)
-	GetReviewsByRating(rating int, startDate, endDate time.Time, serviceID int) (int64, error)
+	Create(ctx context.Context, review entity.Review, events ReviewEvents) (entity.Review, error)
+	FindByID(ctx context.Context, id int) (entity.Review, error)
+	Update(ctx context.Context, review entity.Review) (entity.Review, error)
+	Delete(ctx context.Context, id int) error
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Review], error)
+	GetTotalReviews(ctx context.Context, startDate, endDate time.Time, serviceID int) (int64, error)
+	GetAverageRating(ctx context.Context, startDate, endDate time.Time, serviceID int) (float64, error)
+	GetReviewsByRating(ctx context.Context, rating int, startDate, endDate time.Time, serviceID int) (int64, error)
+	ExistsByBookingID(ctx context.Context, bookingID int) (bool, error)
+	DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error
 }
 
 type reviewRepository struct {
@@ -26,40 +35,51 @@ func NewReviewRepository(db *gorm.DB) ReviewRepository {
 	return &reviewRepository{db}
 }
 
-func (r *reviewRepository) Create(review entity.Review) (entity.Review, error) {
-	err := r.db.Create(&review).Error
+func (r *reviewRepository) Create(ctx context.Context, review entity.Review, events ReviewEvents) (entity.Review, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		if events == nil {
+			return nil
+		}
+
+		var created entity.Review
+		if err := tx.Preload("Booking.Service").First(&created, review.ID).Error; err != nil {
+			return err
+		}
+		return appendEvents(tx, events(created))
+	})
 	return review, err
 }
 
-func (r *reviewRepository) FindByID(id int) (entity.Review, error) {
+func (r *reviewRepository) FindByID(ctx context.Context, id int) (entity.Review, error) {
 	var review entity.Review
-	err := r.db.Preload("Booking").First(&review, id).Error
+	err := r.db.WithContext(ctx).Preload("Booking").First(&review, id).Error
 	return review, err
 }
 
-func (r *reviewRepository) Update(review entity.Review) (entity.Review, error) {
-	err := r.db.Save(&review).Error
+func (r *reviewRepository) Update(ctx context.Context, review entity.Review) (entity.Review, error) {
+	err := r.db.WithContext(ctx).Save(&review).Error
 	return review, err
 }
 
-func (r *reviewRepository) Delete(id int) error {
-	err := r.db.Delete(&entity.Review{}, id).Error
+func (r *reviewRepository) Delete(ctx context.Context, id int) error {
+	err := r.db.WithContext(ctx).Delete(&entity.Review{}, id).Error
 	return err
 }
 
-func (r *reviewRepository) FindAll(limit, offset int) ([]entity.Review, error) {
-	var reviews []entity.Review
-	err := r.db.Limit(limit).Offset(offset).Find(&reviews).Error
-	return reviews, err
+func (r *reviewRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Review], error) {
+	return findPage[entity.Review](r.db.WithContext(ctx), "reviews", q)
 }
 
-func (r *reviewRepository) GetTotalReviews(startDate, endDate time.Time, serviceID int) (int64, error) {
+func (r *reviewRepository) GetTotalReviews(ctx context.Context, startDate, endDate time.Time, serviceID int) (int64, error) {
 	var total int64
-	query := r.db.Model(&entity.Review{})
+	query := r.db.WithContext(ctx).Model(&entity.Review{})
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
-		query = query.Where("created_at BETWEEN ? AND ?", startDate, endDate)
+		query = query.Where("reviews.created_at BETWEEN ? AND ?", startDate, endDate)
 	}
 
 	// Tambahkan filter booking_id jika diberikan
@@ -72,13 +92,13 @@ func (r *reviewRepository) GetTotalReviews(startDate, endDate time.Time, service
 	return total, err
 }
 
-func (r *reviewRepository) GetAverageRating(startDate, endDate time.Time, serviceID int) (float64, error) {
+func (r *reviewRepository) GetAverageRating(ctx context.Context, startDate, endDate time.Time, serviceID int) (float64, error) {
 	var averageRating float64
-	query := r.db.Model(&entity.Review{}).Select("COALESCE(AVG(rating), 0)")
+	query := r.db.WithContext(ctx).Model(&entity.Review{}).Select("COALESCE(AVG(reviews.rating), 0)")
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
-		query = query.Where("created_at BETWEEN ? AND ?", startDate, endDate)
+		query = query.Where("reviews.created_at BETWEEN ? AND ?", startDate, endDate)
 	}
 
 	// Tambahkan filter booking_id jika diberikan
@@ -91,13 +111,13 @@ func (r *reviewRepository) GetAverageRating(startDate, endDate time.Time, servic
 	return averageRating, err
 }
 
-func (r *reviewRepository) GetReviewsByRating(rating int, startDate, endDate time.Time, serviceID int) (int64, error) {
+func (r *reviewRepository) GetReviewsByRating(ctx context.Context, rating int, startDate, endDate time.Time, serviceID int) (int64, error) {
 	var count int64
-	query := r.db.Model(&entity.Review{}).Where("rating = ?", rating)
+	query := r.db.WithContext(ctx).Model(&entity.Review{}).Where("reviews.rating = ?", rating)
 
 	// Tambahkan filter tanggal jika startDate dan endDate tidak kosong
 	if !startDate.IsZero() && !endDate.IsZero() {
-		query = query.Where("created_at BETWEEN ? AND ?", startDate, endDate)
+		query = query.Where("reviews.created_at BETWEEN ? AND ?", startDate, endDate)
 	}
 
 	// Tambahkan filter booking_id jika diberikan
@@ -109,3 +129,16 @@ func (r *reviewRepository) GetReviewsByRating(rating int, startDate, endDate tim
 	err := query.Count(&count).Error
 	return count, err
 }
+
+func (r *reviewRepository) ExistsByBookingID(ctx context.Context, bookingID int) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Review{}).Where("booking_id = ?", bookingID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *reviewRepository) DeleteByBookingIDs(ctx context.Context, bookingIDs []int) error {
+	if len(bookingIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("booking_id IN ?", bookingIDs).Delete(&entity.Review{}).Error
+}
@@ -1,21 +1,23 @@
 package repository
 
 import (
+	"context"
 	"strings"
 
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"gorm.io/gorm"
 )
 
 type ServiceRepository interface {
-	Create(service *entity.Service) error
-	FindByID(id int) (*entity.Service, error)
-	FindAll(limit, offset int) ([]entity.Service, error)
-	Update(service *entity.Service) error
-	Delete(id int) error
-	GetServicesByUserID(userID int) ([]entity.Service, error)
-	SearchServices(searchQuery string, minPrice, maxPrice int) ([]entity.Service, error)
-	GetServiceCostDistribution(startDate, endDate string) (map[string]int, error)
+	Create(ctx context.Context, service *entity.Service) error
+	FindByID(ctx context.Context, id int) (*entity.Service, error)
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Service], error)
+	Update(ctx context.Context, service *entity.Service) error
+	Delete(ctx context.Context, id int) error
+	SearchServices(ctx context.Context, searchQuery string, q listquery.Query) (listquery.Page[entity.Service], error)
+	GetServiceCostDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error)
+	DeleteByUserID(ctx context.Context, userID int) error
 }
 
 type serviceRepository struct {
@@ -26,39 +28,30 @@ func NewServiceRepository(db *gorm.DB) ServiceRepository {
 	return &serviceRepository{db}
 }
 
-func (r *serviceRepository) Create(service *entity.Service) error {
-	return r.db.Create(service).Error
+func (r *serviceRepository) Create(ctx context.Context, service *entity.Service) error {
+	return r.db.WithContext(ctx).Create(service).Error
 }
 
-func (r *serviceRepository) FindByID(id int) (*entity.Service, error) {
+func (r *serviceRepository) FindByID(ctx context.Context, id int) (*entity.Service, error) {
 	var service entity.Service
-	err := r.db.Preload("User").Preload("Bookings").First(&service, id).Error
+	err := r.db.WithContext(ctx).Preload("User").Preload("Bookings").First(&service, id).Error
 	return &service, err
 }
 
-func (r *serviceRepository) FindAll(limit, offset int) ([]entity.Service, error) {
-	var services []entity.Service
-	err := r.db.Limit(limit).Offset(offset).Find(&services).Error
-	return services, err
+func (r *serviceRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.Service], error) {
+	return findPage[entity.Service](r.db.WithContext(ctx), "services", q)
 }
 
-func (r *serviceRepository) Update(service *entity.Service) error {
-	return r.db.Save(service).Error
+func (r *serviceRepository) Update(ctx context.Context, service *entity.Service) error {
+	return r.db.WithContext(ctx).Save(service).Error
 }
 
-func (r *serviceRepository) Delete(id int) error {
-	return r.db.Delete(&entity.Service{}, id).Error
+func (r *serviceRepository) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Delete(&entity.Service{}, id).Error
 }
 
-func (r *serviceRepository) GetServicesByUserID(userID int) ([]entity.Service, error) {
-	var services []entity.Service
-	err := r.db.Where("user_id = ?", userID).Find(&services).Error
-	return services, err
-}
-
-func (r *serviceRepository) SearchServices(searchQuery string, minPrice, maxPrice int) ([]entity.Service, error) {
-	var services []entity.Service
-	query := r.db.Joins("JOIN users ON users.id = services.user_id")
+func (r *serviceRepository) SearchServices(ctx context.Context, searchQuery string, q listquery.Query) (listquery.Page[entity.Service], error) {
+	query := r.db.WithContext(ctx).Joins("JOIN users ON users.id = services.user_id")
 
 	if searchQuery != "" {
 		searchQuery = strings.ToLower(searchQuery) // Ubah ke lowercase untuk pencarian case-insensitive
@@ -68,19 +61,16 @@ func (r *serviceRepository) SearchServices(searchQuery string, minPrice, maxPric
 		)
 	}
 
-	query = query.Where("cost BETWEEN ? AND ?", minPrice, maxPrice)
-
-	err := query.Preload("User").Find(&services).Error
-	return services, err
+	return findPage[entity.Service](query, "services", q, "User")
 }
 
-func (r *serviceRepository) GetServiceCostDistribution(startDate, endDate string) (map[string]int, error) {
+func (r *serviceRepository) GetServiceCostDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error) {
 	var costDistribution []struct {
 		CostRange string
 		Count     int
 	}
 
-	query := r.db.Model(&entity.Service{})
+	query := r.db.WithContext(ctx).Model(&entity.Service{})
 	if startDate != "" && endDate != "" {
 		query = query.Where("created_at BETWEEN ? AND ?", startDate, endDate)
 	}
@@ -105,3 +95,7 @@ func (r *serviceRepository) GetServiceCostDistribution(startDate, endDate string
 
 	return distributionMap, nil
 }
+
+func (r *serviceRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.Service{}).Error
+}
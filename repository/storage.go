@@ -0,0 +1,22 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Storage berisi semua repository dari satu backend penyimpanan beserta unit
+// of work-nya. Backend database dibuat dengan NewStorage, backend memori
+// dengan memory.NewStorage.
+type Storage struct {
+	Repositories
+	Jobs       JobRepository
+	Webhooks   WebhookRepository
+	UnitOfWork UnitOfWork
+}
+
+func NewStorage(db *gorm.DB) Storage {
+	return Storage{
+		Repositories: newRepositories(db),
+		Jobs:         NewJobRepository(db),
+		Webhooks:     NewWebhookRepository(db),
+		UnitOfWork:   NewUnitOfWork(db),
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+)
+
+type TechnicianApplicationRepository interface {
+	Create(ctx context.Context, application *entity.TechnicianApplication) error
+	FindByID(ctx context.Context, id int) (*entity.TechnicianApplication, error)
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.TechnicianApplication], error)
+	FindLatestByUserID(ctx context.Context, userID int) (*entity.TechnicianApplication, error)
+	Update(ctx context.Context, application *entity.TechnicianApplication) error
+	DeleteByUserID(ctx context.Context, userID int) error
+}
+
+type technicianApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewTechnicianApplicationRepository(db *gorm.DB) TechnicianApplicationRepository {
+	return &technicianApplicationRepository{db}
+}
+
+func (r *technicianApplicationRepository) Create(ctx context.Context, application *entity.TechnicianApplication) error {
+	return r.db.WithContext(ctx).Create(application).Error
+}
+
+func (r *technicianApplicationRepository) FindByID(ctx context.Context, id int) (*entity.TechnicianApplication, error) {
+	var application entity.TechnicianApplication
+	err := r.db.WithContext(ctx).First(&application, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *technicianApplicationRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.TechnicianApplication], error) {
+	return findPage[entity.TechnicianApplication](r.db.WithContext(ctx), "technician_applications", q)
+}
+
+func (r *technicianApplicationRepository) FindLatestByUserID(ctx context.Context, userID int) (*entity.TechnicianApplication, error) {
+	var application entity.TechnicianApplication
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").First(&application).Error
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+func (r *technicianApplicationRepository) Update(ctx context.Context, application *entity.TechnicianApplication) error {
+	return r.db.WithContext(ctx).Save(application).Error
+}
+
+func (r *technicianApplicationRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entity.TechnicianApplication{}).Error
+}
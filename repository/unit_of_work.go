@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories berisi repository yang berbagi satu transaksi database.
+type Repositories struct {
+	Users                   UserRepository
+	Services                ServiceRepository
+	Bookings                BookingRepository
+	Payments                PaymentRepository
+	Reviews                 ReviewRepository
+	Messages                MessageRepository
+	TechnicianApplications  TechnicianApplicationRepository
+	NotificationPreferences NotificationPreferenceRepository
+	Outbox                  OutboxRepository
+}
+
+// UnitOfWork menjalankan beberapa operasi repository secara atomik. Jika fn
+// mengembalikan error (atau panic), semua perubahan di dalamnya di-rollback.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(newRepositories(tx))
+	})
+}
+
+func newRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Users:                   NewUserRepository(db),
+		Services:                NewServiceRepository(db),
+		Bookings:                NewBookingRepository(db),
+		Payments:                NewPaymentRepository(db),
+		Reviews:                 NewReviewRepository(db),
+		Messages:                NewMessageRepository(db),
+		TechnicianApplications:  NewTechnicianApplicationRepository(db),
+		NotificationPreferences: NewNotificationPreferenceRepository(db),
+		Outbox:                  NewOutboxRepository(db),
+	}
+}
@@ -1,19 +1,21 @@
 package repository
 
 import (
+	"context"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(user *entity.User) error
-	FindByID(id int) (*entity.User, error)
-	FindAll(limit, offset int) ([]*entity.User, error)
-	Update(user *entity.User) error
-	Delete(id int) error
-	FindUserByEmail(email string) (*entity.User, error)
-	IsEmailExists(email string) (bool, error)
-	GetUserRoleDistribution(startDate, endDate string) (map[string]int, error)
+	Create(ctx context.Context, user *entity.User) error
+	FindByID(ctx context.Context, id int) (*entity.User, error)
+	FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.User], error)
+	Update(ctx context.Context, user *entity.User) error
+	Delete(ctx context.Context, id int) error
+	FindUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	IsEmailExists(ctx context.Context, email string) (bool, error)
+	GetUserRoleDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error)
 }
 
 type userRepository struct {
@@ -24,58 +26,56 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *entity.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
-func (r *userRepository) FindByID(id int) (*entity.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id int) (*entity.User, error) {
 	var user entity.User
-	err := r.db.First(&user, id).Error
+	err := r.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) FindAll(limit, offset int) ([]*entity.User, error) {
-	var users []*entity.User
-	err := r.db.Limit(limit).Offset(offset).Find(&users).Error
-	return users, err
+func (r *userRepository) FindAll(ctx context.Context, q listquery.Query) (listquery.Page[entity.User], error) {
+	return findPage[entity.User](r.db.WithContext(ctx), "users", q)
 }
 
-func (r *userRepository) Update(user *entity.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
 }
 
-func (r *userRepository) Delete(id int) error {
-	return r.db.Delete(&entity.User{}, id).Error
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Delete(&entity.User{}, id).Error
 }
 
-func (r *userRepository) FindUserByEmail(email string) (*entity.User, error) {
+func (r *userRepository) FindUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) IsEmailExists(email string) (bool, error) {
+func (r *userRepository) IsEmailExists(ctx context.Context, email string) (bool, error) {
 	var count int64
-	err := r.db.Model(&entity.User{}).Where("email = ?", email).Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&entity.User{}).Where("email = ?", email).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *userRepository) GetUserRoleDistribution(startDate, endDate string) (map[string]int, error) {
+func (r *userRepository) GetUserRoleDistribution(ctx context.Context, startDate, endDate string) (map[string]int, error) {
 	var roleDistribution []struct {
 		Role  string
 		Count int
 	}
 
-	query := r.db.Model(&entity.User{})
+	query := r.db.WithContext(ctx).Model(&entity.User{})
 	if startDate != "" && endDate != "" {
 		query = query.Where("created_at BETWEEN ? AND ?", startDate, endDate)
 	}
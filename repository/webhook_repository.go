@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	FindEndpointByID(ctx context.Context, id int) (*entity.WebhookEndpoint, error)
+	FindEndpoints(ctx context.Context, q listquery.Query) (listquery.Page[entity.WebhookEndpoint], error)
+	FindActiveEndpointsByEvent(ctx context.Context, eventType string) ([]entity.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, id int) error
+	RecordFailure(ctx context.Context, endpointID, disableAfter int, now time.Time) (bool, error)
+	ResetFailures(ctx context.Context, endpointID int) error
+	CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error)
+	FindDeliveryByID(ctx context.Context, id int) (*entity.WebhookDelivery, error)
+	FindDeliveryByEvent(ctx context.Context, endpointID, eventID int) (*entity.WebhookDelivery, error)
+	FindDeliveriesByEndpointID(ctx context.Context, endpointID int, q listquery.Query) (listquery.Page[entity.WebhookDelivery], error)
+	UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db}
+}
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Create(endpoint).Error
+}
+
+func (r *webhookRepository) FindEndpointByID(ctx context.Context, id int) (*entity.WebhookEndpoint, error) {
+	var endpoint entity.WebhookEndpoint
+	err := r.db.WithContext(ctx).First(&endpoint, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *webhookRepository) FindEndpoints(ctx context.Context, q listquery.Query) (listquery.Page[entity.WebhookEndpoint], error) {
+	return findPage[entity.WebhookEndpoint](r.db.WithContext(ctx), "webhook_endpoints", q)
+}
+
+// FindActiveEndpointsByEvent mengambil endpoint aktif yang berlangganan eventType.
+// Jumlah endpoint partner kecil, jadi pencocokan tipe event dilakukan di Go.
+func (r *webhookRepository) FindActiveEndpointsByEvent(ctx context.Context, eventType string) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	err := r.db.WithContext(ctx).Where("active = ?", true).Find(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribed []entity.WebhookEndpoint
+	for _, endpoint := range endpoints {
+		for _, t := range strings.Split(endpoint.EventTypes, ",") {
+			if t == eventType {
+				subscribed = append(subscribed, endpoint)
+				break
+			}
+		}
+	}
+	return subscribed, nil
+}
+
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Save(endpoint).Error
+}
+
+// DeleteEndpoint menghapus endpoint beserta delivery log-nya.
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity.WebhookEndpoint{}, id).Error
+	})
+}
+
+// RecordFailure menambah jumlah kegagalan berturut-turut dan menonaktifkan
+// endpoint jika sudah mencapai disableAfter. Mengembalikan true jika endpoint
+// baru saja dinonaktifkan.
+func (r *webhookRepository) RecordFailure(ctx context.Context, endpointID, disableAfter int, now time.Time) (bool, error) {
+	err := r.db.WithContext(ctx).Model(&entity.WebhookEndpoint{}).Where("id = ?", endpointID).
+		Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.WithContext(ctx).Model(&entity.WebhookEndpoint{}).
+		Where("id = ? AND active = ? AND consecutive_failures >= ?", endpointID, true, disableAfter).
+		Updates(map[string]interface{}{"active": false, "disabled_at": now})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *webhookRepository) ResetFailures(ctx context.Context, endpointID int) error {
+	return r.db.WithContext(ctx).Model(&entity.WebhookEndpoint{}).Where("id = ?", endpointID).
+		Update("consecutive_failures", 0).Error
+}
+
+// CreateDelivery menyimpan delivery baru. Jika event yang sama sudah pernah
+// dijadwalkan ke endpoint tersebut, delivery tidak dibuat ulang.
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(delivery)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *webhookRepository) FindDeliveryByID(ctx context.Context, id int) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.db.WithContext(ctx).Preload("Endpoint").First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveryByEvent(ctx context.Context, endpointID, eventID int) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("endpoint_id = ? AND event_id = ?", endpointID, eventID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveriesByEndpointID(ctx context.Context, endpointID int, q listquery.Query) (listquery.Page[entity.WebhookDelivery], error) {
+	query := r.db.WithContext(ctx).Where("endpoint_id = ?", endpointID)
+	return findPage[entity.WebhookDelivery](query, "webhook_deliveries", q)
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Omit("Endpoint").Save(delivery).Error
+}
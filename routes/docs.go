@@ -0,0 +1,268 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/openapi"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// OpenAPIPath menyajikan dokumen OpenAPI API v1.
+	OpenAPIPath = "/openapi.json"
+	// DocsPath menyajikan Swagger UI untuk dokumen di OpenAPIPath.
+	DocsPath = "/docs"
+	// DocsAssetsPath menyajikan file swagger-ui-dist untuk halaman DocsPath.
+	DocsAssetsPath = "/docs/assets"
+)
+
+// Response yang dibuat dengan gin.H di controller. Bentuknya ditulis ulang di
+// sini agar punya schema di dokumen OpenAPI.
+type (
+	statusMessage struct {
+		Message string `json:"message"`
+	}
+	loginResult struct {
+		User  entity.UserRes `json:"user"`
+		Token string         `json:"token"`
+	}
+	statusUpdate struct {
+		Status string `json:"status" validate:"required"`
+	}
+	userRoleReport struct {
+		TotalUsers       int            `json:"total_users"`
+		RoleDistribution map[string]int `json:"role_distribution"`
+	}
+	serviceCostReport struct {
+		TotalServices    int            `json:"total_services"`
+		CostDistribution map[string]int `json:"cost_distribution"`
+	}
+	availableDates struct {
+		ServiceID      int      `json:"service_id"`
+		Year           int      `json:"year"`
+		Month          int      `json:"month"`
+		AvailableDates []string `json:"available_dates"` // YYYY-MM-DD
+	}
+	markedAsRead struct {
+		MarkedAsRead int64 `json:"marked_as_read"`
+	}
+	preferenceList struct {
+		Preferences []entity.NotificationPreferenceRes `json:"preferences"`
+	}
+	replayResult struct {
+		Message string                `json:"message"`
+		Event   entity.OutboxEventRes `json:"event"`
+	}
+	webhookEndpointResult struct {
+		Message  string                    `json:"message"`
+		Endpoint entity.WebhookEndpointRes `json:"endpoint"`
+	}
+	redeliveryResult struct {
+		Message  string                 `json:"message"`
+		Delivery entity.WebhookDelivery `json:"delivery"`
+	}
+)
+
+// Query parameter yang dipakai beberapa route
+var (
+	dateRange = []openapi.Param{
+		{Name: "start_date", Type: "string", Format: "date", Description: "Start of the period (YYYY-MM-DD)"},
+		{Name: "end_date", Type: "string", Format: "date", Description: "End of the period (YYYY-MM-DD)"},
+	}
+	serviceFilter = openapi.Param{Name: "service_id", Type: "integer", Description: "Only include this service"}
+)
+
+// listParams membentuk query parameter endpoint list dari Spec-nya: limit,
+// cursor, sort dan filter. descriptions mengganti deskripsi bawaan filter,
+// mis. untuk menyebut nilai status yang valid.
+func listParams(spec listquery.Spec, descriptions map[string]string) []openapi.Param {
+	params := []openapi.Param{
+		{Name: "limit", Type: "integer", Description: fmt.Sprintf("Page size, default %d, max %d", spec.Limit(), listquery.MaxLimit)},
+		{Name: "cursor", Type: "string", Description: "next_cursor from the previous page, used with the same sort"},
+		{Name: "sort", Type: "string", Enum: spec.SortValues(), Description: fmt.Sprintf("Sort field, prefix with - for descending; default %s", spec.DefaultSort)},
+	}
+	for _, filter := range spec.Filters {
+		param := openapi.Param{Name: filter.Param, Type: "integer"}
+		bound := map[listquery.Op]string{listquery.Gte: "Minimum", listquery.Lte: "Maximum"}
+		switch filter.Field.Kind {
+		case listquery.String:
+			param.Type = "string"
+		case listquery.Time:
+			param.Type, param.Format = "string", "date"
+			bound = map[listquery.Op]string{listquery.Gte: "Earliest", listquery.Lte: "Latest"}
+		}
+		field := strings.ReplaceAll(filter.Field.Name, "_", " ")
+		param.Description = "Only include this " + field
+		if bound, ok := bound[filter.Op]; ok {
+			param.Description = fmt.Sprintf("%s %s (inclusive)", bound, field)
+		}
+		if description, ok := descriptions[filter.Param]; ok {
+			param.Description = description
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+var docTags = []openapi.Tag{
+	{Name: "Users", Description: "Registration, login and user accounts"},
+	{Name: "Technician Applications", Description: "Technician verification (KYC) workflow"},
+	{Name: "Services", Description: "Services offered by technicians"},
+	{Name: "Bookings", Description: "Bookings of a service by customers"},
+	{Name: "Messages", Description: "Conversation thread per booking"},
+	{Name: "Payments"},
+	{Name: "Reviews"},
+	{Name: "Notifications", Description: "Notification channel preferences"},
+	{Name: "Events", Description: "Realtime events over Server-Sent Events or WebSocket"},
+	{Name: "Admin Events", Description: "Outbox inspection and replay"},
+	{Name: "Webhooks", Description: "Partner webhook endpoints and delivery log"},
+}
+
+// APIDocs mengembalikan dokumentasi semua route v1. Setiap route yang
+// didaftarkan di SetupV1Routes harus punya entri di sini dengan Public dan
+// Roles yang sama dengan middleware-nya; integration test memeriksa
+// keduanya terhadap router.
+func APIDocs() []openapi.Route {
+	admin := []string{"admin"}
+	technician := []string{"technician"}
+	bookingStatuses := map[string]string{"status": "Pending, Confirmed, Completed or Cancelled"}
+
+	return []openapi.Route{
+		// User
+		{Method: http.MethodPost, Path: "/register", ID: "Register", Tag: "Users", Summary: "Register a new user", Public: true, Body: entity.RegisterUserReq{}, Status: http.StatusCreated, Response: entity.UserRes{}},
+		{Method: http.MethodPost, Path: "/login", ID: "Login", Tag: "Users", Summary: "Login and get a JWT token", Description: "Repeated failures lock the account temporarily (429 account_locked with Retry-After).", Public: true, Body: entity.LoginUserReq{}, Response: loginResult{}},
+		{Method: http.MethodPost, Path: "/register-admin", ID: "RegisterAsAdmin", Tag: "Users", Summary: "Register a new admin", Public: true, Body: entity.RegisterUserReq{}, Status: http.StatusCreated, Response: entity.UserRes{}},
+		{Method: http.MethodGet, Path: "/users/:id", ID: "GetUserByID", Tag: "Users", Summary: "Get user details by ID", Response: entity.UserRes{}},
+		{Method: http.MethodGet, Path: "/users", ID: "GetAllUsers", Tag: "Users", Summary: "List users", Query: listParams(service.UserListSpec, map[string]string{"role": "user, technician or admin"}), Response: listquery.Page[entity.UserRes]{}},
+		{Method: http.MethodPut, Path: "/users", ID: "UpdateUser", Tag: "Users", Summary: "Update user details", Body: entity.UpdateUserReq{}, Response: entity.UserRes{}},
+		{Method: http.MethodDelete, Path: "/users/:id", ID: "DeleteUser", Tag: "Users", Summary: "Delete a user with their services, bookings, payments, reviews and messages", Response: statusMessage{}},
+		{Method: http.MethodPut, Path: "/users/update-technician", ID: "UpdateTechnician", Tag: "Users", Summary: "Update technician details", Roles: []string{"technician", "admin"}, Body: entity.UpdateTechnicianReq{}, Response: entity.TechnicianRes{}},
+		{Method: http.MethodGet, Path: "/users/reports", ID: "GetUserRoleReport", Tag: "Users", Summary: "Number of users per role", Query: dateRange, Response: userRoleReport{}},
+
+		// Notification preference
+		{Method: http.MethodGet, Path: "/users/me/notification-preferences", ID: "GetPreferences", Tag: "Notifications", Summary: "Get channel preferences for every notification event", Response: preferenceList{}},
+		{Method: http.MethodPut, Path: "/users/me/notification-preferences", ID: "UpdatePreferences", Tag: "Notifications", Summary: "Opt in or out per event and channel", Body: entity.UpdateNotificationPreferencesReq{}, Response: preferenceList{}},
+
+		// Technician application
+		{Method: http.MethodPost, Path: "/technician-applications", ID: "SubmitApplication", Tag: "Technician Applications", Summary: "Submit a technician application", Form: entity.RegisterAsTechnicianReq{}, Files: []openapi.Param{
+			{Name: "id_document", Required: true, Description: "Identity card (pdf, jpg or png, max 5 MB)"},
+			{Name: "certificate", Required: true, Description: "Certificate (pdf, jpg or png, max 5 MB)"},
+		}, Status: http.StatusCreated, Response: entity.TechnicianApplicationRes{}},
+		{Method: http.MethodGet, Path: "/technician-applications/me", ID: "GetMyApplication", Tag: "Technician Applications", Summary: "Get the current user's latest application", Response: entity.TechnicianApplicationRes{}},
+		{Method: http.MethodGet, Path: "/technician-applications", ID: "GetAllApplications", Tag: "Technician Applications", Summary: "List applications", Roles: admin, Query: listParams(service.ApplicationListSpec, map[string]string{
+			"status": "Submitted, Under Review, Approved or Rejected",
+		}), Response: listquery.Page[entity.TechnicianApplicationRes]{}},
+		{Method: http.MethodGet, Path: "/technician-applications/:id", ID: "GetApplicationByID", Tag: "Technician Applications", Summary: "Get application details by ID", Roles: admin, Response: entity.TechnicianApplication{}},
+		{Method: http.MethodGet, Path: "/technician-applications/:id/documents/:document", ID: "GetApplicationDocument", Tag: "Technician Applications", Summary: "Download id-document or certificate", Roles: admin, Content: "application/octet-stream"},
+		{Method: http.MethodPut, Path: "/technician-applications/:id/review", ID: "StartReview", Tag: "Technician Applications", Summary: "Move an application to Under Review", Roles: admin, Response: entity.TechnicianApplicationRes{}},
+		{Method: http.MethodPut, Path: "/technician-applications/:id/approve", ID: "ApproveApplication", Tag: "Technician Applications", Summary: "Approve an application", Roles: admin, Body: entity.ReviewTechnicianApplicationReq{}, OptionalBody: true, Response: entity.TechnicianApplicationRes{}},
+		{Method: http.MethodPut, Path: "/technician-applications/:id/reject", ID: "RejectApplication", Tag: "Technician Applications", Summary: "Reject an application", Description: "reason is required.", Roles: admin, Body: entity.ReviewTechnicianApplicationReq{}, Response: entity.TechnicianApplicationRes{}},
+
+		// Service
+		{Method: http.MethodPost, Path: "/services", ID: "CreateService", Tag: "Services", Summary: "Create a new service", Description: "Requires an approved application with a non-expired certification.", Roles: technician, Body: entity.CreateServiceReq{}, Status: http.StatusCreated, Response: entity.ServiceRes{}},
+		{Method: http.MethodGet, Path: "/services/:id", ID: "GetServiceByID", Tag: "Services", Summary: "Get service details by ID", Response: entity.Service{}},
+		{Method: http.MethodPut, Path: "/services", ID: "UpdateService", Tag: "Services", Summary: "Update service details", Roles: technician, Body: entity.UpdateServiceReq{}, Response: entity.ServiceRes{}},
+		{Method: http.MethodDelete, Path: "/services/:id", ID: "DeleteService", Tag: "Services", Summary: "Delete a service", Roles: technician, Response: statusMessage{}},
+		{Method: http.MethodGet, Path: "/services", ID: "GetAllServices", Tag: "Services", Summary: "List services", Query: listParams(service.ServiceListSpec, nil), Response: listquery.Page[entity.Service]{}},
+		{Method: http.MethodGet, Path: "/services/user/:user_id", ID: "GetServicesByUserID", Tag: "Services", Summary: "List services of a technician", Query: listParams(service.ServiceListSpec, nil), Response: listquery.Page[entity.ServiceRes]{}},
+		{Method: http.MethodGet, Path: "/services/search", ID: "SearchServices", Tag: "Services", Summary: "Search services by name and price", Query: append([]openapi.Param{
+			{Name: "search", Type: "string", Description: "Text to search for in name and description"},
+		}, listParams(service.ServiceListSpec, nil)...), Response: listquery.Page[entity.ServiceRes]{}},
+		{Method: http.MethodGet, Path: "/services/reports", ID: "GetServiceCostReport", Tag: "Services", Summary: "Number of services per cost range", Query: dateRange, Response: serviceCostReport{}},
+
+		// Booking
+		{Method: http.MethodGet, Path: "/bookings", ID: "GetAllBookings", Tag: "Bookings", Summary: "List bookings", Query: listParams(service.BookingListSpec, bookingStatuses), Response: listquery.Page[entity.Booking]{}},
+		{Method: http.MethodGet, Path: "/bookings/:id", ID: "GetBookingByID", Tag: "Bookings", Summary: "Get booking details by ID", Response: entity.Booking{}},
+		{Method: http.MethodPost, Path: "/bookings", ID: "CreateBooking", Tag: "Bookings", Summary: "Create a new booking", Body: entity.CreateBookingReq{}, Status: http.StatusCreated, Response: entity.Booking{}},
+		{Method: http.MethodPut, Path: "/bookings", ID: "UpdateBooking", Tag: "Bookings", Summary: "Update booking details", Body: entity.UpdateBookingReq{}, Response: entity.Booking{}},
+		{Method: http.MethodDelete, Path: "/bookings/:id", ID: "DeleteBooking", Tag: "Bookings", Summary: "Delete a booking", Response: statusMessage{}},
+		{Method: http.MethodGet, Path: "/bookings/user/:user_id", ID: "GetBookingsByUserID", Tag: "Bookings", Summary: "List bookings of a customer", Query: listParams(service.BookingListSpec, bookingStatuses), Response: listquery.Page[entity.BookingRes]{}},
+		{Method: http.MethodGet, Path: "/bookings/service/:service_id", ID: "GetBookingsByServiceID", Tag: "Bookings", Summary: "List bookings of a service", Query: listParams(service.BookingListSpec, bookingStatuses), Response: listquery.Page[entity.BookingRes]{}},
+		{Method: http.MethodPut, Path: "/bookings/:id/status", ID: "UpdateBookingStatus", Tag: "Bookings", Summary: "Update booking status", Body: statusUpdate{}, Response: statusMessage{}},
+		{Method: http.MethodGet, Path: "/bookings/available-dates", ID: "GetAvailableDates", Tag: "Bookings", Summary: "Available dates of a service in a month", Query: []openapi.Param{
+			{Name: "service_id", Type: "integer", Required: true},
+			{Name: "year", Type: "integer", Required: true},
+			{Name: "month", Type: "integer", Required: true, Description: "1 to 12"},
+		}, Response: availableDates{}},
+		{Method: http.MethodGet, Path: "/bookings/technician/confirmed", ID: "GetConfirmedBookingsForTechnician", Tag: "Bookings", Summary: "Confirmed bookings of the current technician's services", Roles: technician, Query: listParams(service.BookingListSpec, bookingStatuses), Response: listquery.Page[entity.BookingRes]{}},
+		{Method: http.MethodGet, Path: "/bookings/reports", ID: "GetBookingReport", Tag: "Bookings", Summary: "Booking totals per status", Query: dateRange, Response: entity.BookingReport{}},
+
+		// Message
+		{Method: http.MethodGet, Path: "/bookings/:id/messages", ID: "GetMessages", Tag: "Messages", Summary: "List messages, newest first", Query: listParams(service.MessageListSpec, nil), Response: listquery.Page[entity.MessageRes]{}},
+		{Method: http.MethodPost, Path: "/bookings/:id/messages", ID: "SendMessage", Tag: "Messages", Summary: "Send a message (text and/or attachment)", Body: entity.CreateMessageReq{}, Form: entity.CreateMessageReq{}, Files: []openapi.Param{
+			{Name: "attachment", Description: "Optional attachment (pdf, jpg or png, max 5 MB)"},
+		}, Status: http.StatusCreated, Response: entity.MessageRes{}},
+		{Method: http.MethodPut, Path: "/bookings/:id/messages/read", ID: "MarkAsRead", Tag: "Messages", Summary: "Mark messages from the other participants as read", Response: markedAsRead{}},
+		{Method: http.MethodGet, Path: "/bookings/:id/messages/stream", ID: "StreamMessages", Tag: "Messages", Summary: "Receive new messages and read receipts via Server-Sent Events", Content: "text/event-stream"},
+		{Method: http.MethodGet, Path: "/bookings/:id/messages/:message_id/attachment", ID: "GetAttachment", Tag: "Messages", Summary: "Download a message attachment", Content: "application/octet-stream"},
+		{Method: http.MethodGet, Path: "/messages/unread", ID: "GetUnreadCounts", Tag: "Messages", Summary: "Unread message counts per booking for the current user", Response: []entity.UnreadCount{}},
+
+		// Payment
+		{Method: http.MethodGet, Path: "/payments", ID: "GetAllPayments", Tag: "Payments", Summary: "List payments", Query: listParams(service.PaymentListSpec, nil), Response: listquery.Page[entity.Payment]{}},
+		{Method: http.MethodGet, Path: "/payments/:id", ID: "GetPaymentByID", Tag: "Payments", Summary: "Get payment details by ID", Response: entity.Payment{}},
+		{Method: http.MethodPost, Path: "/payments", ID: "CreatePayment", Tag: "Payments", Summary: "Create a new payment", Body: entity.CreatePaymentReq{}, Status: http.StatusCreated, Response: entity.Payment{}},
+		{Method: http.MethodPut, Path: "/payments", ID: "UpdatePayment", Tag: "Payments", Summary: "Update payment details", Body: entity.UpdatePaymentReq{}, Response: entity.Payment{}},
+		{Method: http.MethodDelete, Path: "/payments/:id", ID: "DeletePayment", Tag: "Payments", Summary: "Delete a payment", Response: statusMessage{}},
+		{Method: http.MethodPut, Path: "/payments/:id/status", ID: "UpdatePaymentStatus", Tag: "Payments", Summary: "Update payment status", Body: statusUpdate{}, Response: statusMessage{}},
+		{Method: http.MethodGet, Path: "/payments/reports", ID: "GetPaymentReport", Tag: "Payments", Summary: "Payment totals per status", Query: append(dateRange, serviceFilter), Response: entity.PaymentReport{}},
+
+		// Review
+		{Method: http.MethodGet, Path: "/reviews", ID: "GetAllReviews", Tag: "Reviews", Summary: "List reviews", Query: listParams(service.ReviewListSpec, nil), Response: listquery.Page[entity.Review]{}},
+		{Method: http.MethodGet, Path: "/reviews/:id", ID: "GetReviewByID", Tag: "Reviews", Summary: "Get review details by ID", Response: entity.Review{}},
+		{Method: http.MethodPost, Path: "/reviews", ID: "CreateReview", Tag: "Reviews", Summary: "Create a new review", Body: entity.CreateReviewReq{}, Status: http.StatusCreated, Response: entity.Review{}},
+		{Method: http.MethodPut, Path: "/reviews", ID: "UpdateReview", Tag: "Reviews", Summary: "Update review details", Body: entity.UpdateReviewReq{}, Response: entity.Review{}},
+		{Method: http.MethodDelete, Path: "/reviews/:id", ID: "DeleteReview", Tag: "Reviews", Summary: "Delete a review", Response: statusMessage{}},
+		{Method: http.MethodGet, Path: "/reviews/reports", ID: "GetReviewReport", Tag: "Reviews", Summary: "Average rating and rating distribution", Query: append(dateRange, serviceFilter), Response: entity.ReviewReport{}},
+
+		// Outbox
+		{Method: http.MethodGet, Path: "/admin/events", ID: "GetEvents", Tag: "Admin Events", Summary: "List outbox events", Roles: admin, Query: listParams(service.OutboxEventListSpec, map[string]string{
+			"status": "Pending, Processing, Delivered or Failed",
+		}), Response: listquery.Page[entity.OutboxEventRes]{}},
+		{Method: http.MethodGet, Path: "/admin/events/:id", ID: "GetEventByID", Tag: "Admin Events", Summary: "Get an outbox event with its payload and last error", Roles: admin, Response: entity.OutboxEventRes{}},
+		{Method: http.MethodPost, Path: "/admin/events/:id/replay", ID: "ReplayEvent", Tag: "Admin Events", Summary: "Queue a Failed event for delivery again", Roles: admin, Response: replayResult{}},
+
+		// Webhook
+		{Method: http.MethodPost, Path: "/admin/webhooks", ID: "CreateEndpoint", Tag: "Webhooks", Summary: "Register a webhook endpoint", Description: "The secret is only returned in this response.", Roles: admin, Body: entity.CreateWebhookEndpointReq{}, Status: http.StatusCreated, Response: webhookEndpointResult{}},
+		{Method: http.MethodGet, Path: "/admin/webhooks", ID: "GetEndpoints", Tag: "Webhooks", Summary: "List webhook endpoints", Roles: admin, Query: listParams(service.WebhookEndpointListSpec, nil), Response: listquery.Page[entity.WebhookEndpointRes]{}},
+		{Method: http.MethodGet, Path: "/admin/webhooks/:id", ID: "GetEndpointByID", Tag: "Webhooks", Summary: "Get webhook endpoint details", Roles: admin, Response: entity.WebhookEndpointRes{}},
+		{Method: http.MethodPut, Path: "/admin/webhooks/:id", ID: "UpdateEndpoint", Tag: "Webhooks", Summary: "Update a webhook endpoint", Description: `"active": true re-enables an endpoint that was disabled automatically.`, Roles: admin, Body: entity.UpdateWebhookEndpointReq{}, Response: webhookEndpointResult{}},
+		{Method: http.MethodDelete, Path: "/admin/webhooks/:id", ID: "DeleteEndpoint", Tag: "Webhooks", Summary: "Delete a webhook endpoint and its delivery log", Roles: admin, Response: statusMessage{}},
+		{Method: http.MethodGet, Path: "/admin/webhooks/:id/deliveries", ID: "GetDeliveries", Tag: "Webhooks", Summary: "Delivery log of an endpoint", Roles: admin, Query: listParams(service.WebhookDeliveryListSpec, map[string]string{"status": "Pending, Succeeded or Failed"}), Response: listquery.Page[entity.WebhookDelivery]{}},
+		{Method: http.MethodPost, Path: "/admin/webhooks/:id/deliveries/:delivery_id/redeliver", ID: "Redeliver", Tag: "Webhooks", Summary: "Send a delivery again", Roles: admin, Status: http.StatusAccepted, Response: redeliveryResult{}},
+
+		// Event
+		{Method: http.MethodGet, Path: "/events/stream", ID: "StreamEvents", Tag: "Events", Summary: "Receive events via Server-Sent Events", Content: "text/event-stream"},
+		{Method: http.MethodGet, Path: "/events/ws", ID: "WebSocketEvents", Tag: "Events", Summary: "Receive events via WebSocket (JSON frames)", Description: "Upgrades the connection to WebSocket.", Status: http.StatusSwitchingProtocols},
+	}
+}
+
+// OpenAPIDocument membentuk dokumen OpenAPI untuk route v1.
+func OpenAPIDocument() *openapi.Document {
+	info := openapi.Info{
+		Title:       "Perbaiki.id Service Booking API",
+		Version:     "1",
+		Description: "Errors are returned as application/problem+json (RFC 7807); tell them apart by code.",
+	}
+	return openapi.Build(info, V1Prefix, docTags, APIDocs())
+}
+
+// SetupDocsRoutes mendaftarkan dokumen OpenAPI dan Swagger UI. Keduanya
+// tanpa autentikasi. Swagger UI hanya didaftarkan jika swaggerUIDir berisi
+// file swagger-ui-dist, karena halaman tidak memuat apa pun dari CDN.
+func SetupDocsRoutes(router gin.IRouter, swaggerUIDir string) {
+	doc := OpenAPIDocument()
+	router.GET(OpenAPIPath, gin.WrapH(openapi.Handler(doc)))
+	if swaggerUIDir == "" {
+		return
+	}
+	router.GET(DocsPath, gin.WrapH(openapi.DocsHandler(doc.Info.Title, OpenAPIPath, DocsAssetsPath)))
+	for _, name := range openapi.SwaggerUIFiles {
+		router.StaticFile(DocsAssetsPath+"/"+name, filepath.Join(swaggerUIDir, name))
+	}
+}
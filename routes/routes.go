@@ -1,24 +1,131 @@
 package routes
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/controller"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/health"
+	"github.com/Ayyasy123/dibimbing-capstone.git/metrics"
 	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/Ayyasy123/dibimbing-capstone.git/outbox"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
 	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/scheduler"
 	"github.com/Ayyasy123/dibimbing-capstone.git/service"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupUserRoutes(db *gorm.DB, router *gin.Engine) {
-	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
+// RateLimits berisi store dan kebijakan rate limit untuk SetupRoutes. Nilai
+// kosong (Store nil) menonaktifkan rate limit dan lockout login.
+type RateLimits struct {
+	Store   ratelimit.Store
+	Auth    ratelimit.Policy // /register, /login dan /register-admin, per IP dan per email
+	API     ratelimit.Policy // Route lain, per IP dan per user dari token
+	Lockout *ratelimit.Lockout
+}
+
+// Options berisi pengaturan opsional SetupRoutes. Nilai kosong berarti rate
+// limit nonaktif dan tanpa alias route lama.
+type Options struct {
+	RateLimits RateLimits
+	Legacy     LegacyRoutes
+	// TrustedProxies adalah IP atau CIDR reverse proxy yang boleh menentukan
+	// IP client lewat X-Forwarded-For. Kosong berarti tidak ada, sehingga
+	// client tidak bisa memalsukan IP untuk menghindari rate limit per IP.
+	TrustedProxies []string
+	// MetricsToken wajib dikirim sebagai bearer token untuk membaca
+	// /metrics. Kosong berarti /metrics tidak didaftarkan.
+	MetricsToken string
+	// SwaggerUIDir berisi file swagger-ui-dist untuk /docs. Kosong berarti
+	// hanya /openapi.json yang disajikan.
+	SwaggerUIDir string
+}
+
+// SetupRoutes mendaftarkan semua route API ke router dan job terjadwal ke
+// scheduler. storage bisa berupa database (repository.NewStorage) atau memori
+// (memory.NewStorage). checks berisi pemeriksaan untuk /readyz. Dipakai oleh
+// server maupun integration test.
+//
+// Route API berada di bawah prefix versi (/api/v1), sedangkan /ping, health
+// check, /metrics dan dokumentasi (/openapi.json, /docs) tetap di root.
+func SetupRoutes(router *gin.Engine, storage repository.Storage, hub *realtime.Hub, dispatcher *outbox.Dispatcher, sched *scheduler.Scheduler, checks *health.Checker, opts Options) {
+	limits := opts.RateLimits
+
+	// Nilai sudah divalidasi config, jadi error di sini adalah bug
+	if err := router.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		panic(fmt.Sprintf("routes: invalid trusted proxies: %v", err))
+	}
+
+	router.GET("/ping", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "pong",
+		})
+	})
+	SetupHealthRoutes(router, checks)
+
+	// Metrik Prometheus (HTTP, database dan bisnis), hanya untuk scraper
+	// yang memegang token
+	if opts.MetricsToken != "" {
+		router.GET("/metrics", middleware.BearerToken(opts.MetricsToken), gin.WrapH(metrics.Handler()))
+	}
+
+	// Dokumen OpenAPI dan Swagger UI untuk route v1
+	SetupDocsRoutes(router, opts.SwaggerUIDir)
+
+	// Rate limit API hanya berlaku untuk route yang didaftarkan setelah ini,
+	// sehingga health check dan scraping metrics tidak pernah ditolak
+	router.Use(middleware.RateLimit(limits.Store, "api", limits.API, middleware.AccountFromToken))
+
+	// Service dibuat sekali dan dipakai bersama oleh semua versi API
+	services := NewServices(storage, sched, limits.Lockout)
+	SetupWebhookDelivery(services.Webhooks, dispatcher, sched)
+	SetupScheduledJobs(storage, sched)
+
+	SetupV1Routes(router.Group(V1Prefix), services, hub, limits)
+	if opts.Legacy.Enabled {
+		SetupLegacyRoutes(router, opts.Legacy, services, hub, limits)
+	}
+
+	// Path yang tidak dikenal juga dijawab dengan problem+json
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(apperror.NotFound("route_not_found", "route not found"))
+	})
+}
+
+// SetupHealthRoutes mendaftarkan /healthz (liveness: proses masih melayani
+// request) dan /readyz (readiness: database, migrasi dan worker siap).
+// Keduanya tanpa autentikasi karena dipanggil oleh orchestrator.
+func SetupHealthRoutes(router *gin.Engine, checks *health.Checker) {
+	router.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+	})
+
+	router.GET("/readyz", func(c *gin.Context) {
+		report := checks.Run(c.Request.Context())
+		status := http.StatusOK
+		if report.Status != health.StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	})
+}
+
+func SetupUserRoutes(router gin.IRouter, userService service.UserService, limits RateLimits) {
 	userController := controller.NewUserController(userService)
 
-	// Public routes (no authentication required)
-	router.POST("/register", userController.Register)
-	router.POST("/login", userController.Login)
+	// Public routes (no authentication required), dibatasi lebih ketat per
+	// IP dan per email untuk mencegah brute force
+	authLimit := middleware.RateLimit(limits.Store, "auth", limits.Auth, middleware.AccountFromJSON("email"))
+	router.POST("/register", authLimit, userController.Register)
+	router.POST("/login", authLimit, userController.Login)
 	// Endpoint untuk register sebagai admin (hanya bisa diakses oleh admin)
-	router.POST("/register-admin", userController.RegisterAsAdmin)
+	router.POST("/register-admin", authLimit, userController.RegisterAsAdmin)
 
 	// Protected routes (require JWT authentication)
 	userRoutes := router.Group("/users")
@@ -30,16 +137,46 @@ func SetupUserRoutes(db *gorm.DB, router *gin.Engine) {
 		userRoutes.DELETE("/:id", userController.DeleteUser)
 
 		// Role-based routes
-		userRoutes.POST("/register-technician", userController.RegisterAsTechnician)
 		userRoutes.PUT("/update-technician", middleware.RoleAuth("technician", "admin"), userController.UpdateTechnician)
 		userRoutes.GET("/reports", userController.GetUserRoleReport)
 
 	}
 }
 
-func SetupServiceRoutes(db *gorm.DB, router *gin.Engine) {
-	serviceRepo := repository.NewServiceRepository(db)
-	serviceService := service.NewServiceService(serviceRepo)
+func SetupNotificationPreferenceRoutes(router gin.IRouter, preferenceService service.NotificationPreferenceService) {
+	preferenceController := controller.NewNotificationPreferenceController(preferenceService)
+
+	// Protected routes (require JWT authentication)
+	preferenceRoutes := router.Group("/users/me/notification-preferences")
+	preferenceRoutes.Use(middleware.JWTAuth())
+	{
+		preferenceRoutes.GET("", preferenceController.GetPreferences)
+		preferenceRoutes.PUT("", preferenceController.UpdatePreferences)
+	}
+}
+
+func SetupTechnicianApplicationRoutes(router gin.IRouter, applicationService service.TechnicianApplicationService) {
+	applicationController := controller.NewTechnicianApplicationController(applicationService)
+
+	// Protected routes (require JWT authentication)
+	applicationRoutes := router.Group("/technician-applications")
+	applicationRoutes.Use(middleware.JWTAuth())
+	{
+		// Pengajuan technician oleh user (multipart/form-data dengan id_document dan certificate)
+		applicationRoutes.POST("", applicationController.SubmitApplication)
+		applicationRoutes.GET("/me", applicationController.GetMyApplication)
+
+		// Review pengajuan (hanya admin)
+		applicationRoutes.GET("", middleware.RoleAuth("admin"), applicationController.GetAllApplications)
+		applicationRoutes.GET("/:id", middleware.RoleAuth("admin"), applicationController.GetApplicationByID)
+		applicationRoutes.GET("/:id/documents/:document", middleware.RoleAuth("admin"), applicationController.GetApplicationDocument)
+		applicationRoutes.PUT("/:id/review", middleware.RoleAuth("admin"), applicationController.StartReview)
+		applicationRoutes.PUT("/:id/approve", middleware.RoleAuth("admin"), applicationController.ApproveApplication)
+		applicationRoutes.PUT("/:id/reject", middleware.RoleAuth("admin"), applicationController.RejectApplication)
+	}
+}
+
+func SetupServiceRoutes(router gin.IRouter, serviceService service.ServiceService) {
 	serviceController := controller.NewServiceController(serviceService)
 
 	// Protected routes (require JWT authentication)
@@ -57,9 +194,7 @@ func SetupServiceRoutes(db *gorm.DB, router *gin.Engine) {
 	}
 }
 
-func SetupBookingRoutes(db *gorm.DB, router *gin.Engine) {
-	bookingRepo := repository.NewBookingRepository(db)
-	bookingService := service.NewBookingService(bookingRepo)
+func SetupBookingRoutes(router gin.IRouter, bookingService service.BookingService) {
 	bookingController := controller.NewBookingController(bookingService)
 
 	// Protected routes (require JWT authentication)
@@ -80,9 +215,24 @@ func SetupBookingRoutes(db *gorm.DB, router *gin.Engine) {
 	}
 }
 
-func SetupPaymentRoutes(db *gorm.DB, router *gin.Engine) {
-	paymentRepo := repository.NewPaymentRepository(db)
-	paymentService := service.NewPaymentService(paymentRepo)
+func SetupMessageRoutes(router gin.IRouter, messageService service.MessageService, hub *realtime.Hub) {
+	messageController := controller.NewMessageController(messageService, hub)
+
+	// Protected routes (require JWT authentication)
+	// Hanya customer booking, technician pemilik service dan admin yang bisa mengakses percakapan
+	messageRoutes := router.Group("/bookings/:id/messages")
+	{
+		messageRoutes.GET("", middleware.JWTAuth(), messageController.GetMessages) // /bookings/:id/messages?limit=20&cursor=<next_cursor>
+		messageRoutes.POST("", middleware.JWTAuth(), messageController.SendMessage)
+		messageRoutes.PUT("/read", middleware.JWTAuth(), messageController.MarkAsRead)
+		messageRoutes.GET("/stream", middleware.StreamAuth(), messageController.StreamMessages)
+		messageRoutes.GET("/:message_id/attachment", middleware.JWTAuth(), messageController.GetAttachment)
+	}
+
+	router.GET("/messages/unread", middleware.JWTAuth(), messageController.GetUnreadCounts)
+}
+
+func SetupPaymentRoutes(router gin.IRouter, paymentService service.PaymentService) {
 	paymentController := controller.NewPaymentController(paymentService)
 
 	// Protected routes (require JWT authentication)
@@ -99,9 +249,7 @@ func SetupPaymentRoutes(db *gorm.DB, router *gin.Engine) {
 	}
 }
 
-func SetupReviewRoutes(db *gorm.DB, router *gin.Engine) {
-	reviewRepo := repository.NewReviewRepository(db)
-	reviewService := service.NewReviewService(reviewRepo)
+func SetupReviewRoutes(router gin.IRouter, reviewService service.ReviewService) {
 	reviewController := controller.NewReviewController(reviewService)
 
 	// Protected routes (require JWT authentication)
@@ -116,3 +264,106 @@ func SetupReviewRoutes(db *gorm.DB, router *gin.Engine) {
 		reviewRoutes.GET("/reports", reviewController.GetReviewReport)
 	}
 }
+
+func SetupEventRoutes(router gin.IRouter, hub *realtime.Hub) {
+	eventController := controller.NewEventController(hub)
+
+	// Protected routes (require JWT authentication)
+	// Event: booking.created, booking.<status> (mis. booking.confirmed), payment.created,
+	// payment.<status> (mis. payment.paid), message.created, message.read, review.created
+	eventRoutes := router.Group("/events")
+	eventRoutes.Use(middleware.StreamAuth())
+	{
+		eventRoutes.GET("/stream", eventController.StreamEvents) // Server-Sent Events
+		eventRoutes.GET("/ws", eventController.WebSocketEvents)  // WebSocket
+	}
+}
+
+func SetupOutboxRoutes(router gin.IRouter, outboxService service.OutboxService) {
+	outboxController := controller.NewOutboxController(outboxService)
+
+	// Inspeksi dan replay event outbox (hanya admin)
+	adminRoutes := router.Group("/admin/events")
+	adminRoutes.Use(middleware.JWTAuth(), middleware.RoleAuth("admin"))
+	{
+		adminRoutes.GET("", outboxController.GetEvents) // /admin/events?status=Failed&limit=10&cursor=<next_cursor>
+		adminRoutes.GET("/:id", outboxController.GetEventByID)
+		adminRoutes.POST("/:id/replay", outboxController.ReplayEvent)
+	}
+}
+
+// SetupWebhookDelivery menjadikan event dari outbox sebagai delivery webhook,
+// lalu mengirimnya lewat scheduler dengan retry dan backoff.
+func SetupWebhookDelivery(webhookService service.WebhookService, dispatcher *outbox.Dispatcher, sched *scheduler.Scheduler) {
+	dispatcher.Subscribe("webhook", webhookService.HandleEvent)
+	sched.Register("webhook_delivery", func(ctx context.Context, job entity.Job) error {
+		var payload service.WebhookDeliveryJobPayload
+		if err := scheduler.Decode(job, &payload); err != nil {
+			return err
+		}
+		return webhookService.Deliver(ctx, payload.DeliveryID, job.Attempts >= job.MaxAttempts)
+	})
+}
+
+func SetupWebhookRoutes(router gin.IRouter, webhookService service.WebhookService) {
+	webhookController := controller.NewWebhookController(webhookService)
+
+	// Pengelolaan webhook partner (hanya admin)
+	webhookRoutes := router.Group("/admin/webhooks")
+	webhookRoutes.Use(middleware.JWTAuth(), middleware.RoleAuth("admin"))
+	{
+		webhookRoutes.POST("", webhookController.CreateEndpoint)
+		webhookRoutes.GET("", webhookController.GetEndpoints)
+		webhookRoutes.GET("/:id", webhookController.GetEndpointByID)
+		webhookRoutes.PUT("/:id", webhookController.UpdateEndpoint)
+		webhookRoutes.DELETE("/:id", webhookController.DeleteEndpoint)
+		webhookRoutes.GET("/:id/deliveries", webhookController.GetDeliveries)
+		webhookRoutes.POST("/:id/deliveries/:delivery_id/redeliver", webhookController.Redeliver)
+	}
+}
+
+func SetupScheduledJobs(storage repository.Storage, sched *scheduler.Scheduler) {
+	bookingRepo := storage.Bookings
+	paymentRepo := storage.Payments
+	reviewRepo := storage.Reviews
+	outboxRepo := storage.Outbox
+	maintenanceService := service.NewMaintenanceService(bookingRepo, paymentRepo, reviewRepo, outboxRepo, storage.UnitOfWork, sched)
+
+	// Job berulang: setiap periode hanya dijalankan sekali di semua replica
+	sched.Every("expire_bookings", 15*time.Minute, func(ctx context.Context, job entity.Job) error {
+		_, err := maintenanceService.ExpireStaleBookings(ctx, time.Now())
+		return err
+	})
+	sched.Every("expire_payments", 15*time.Minute, func(ctx context.Context, job entity.Job) error {
+		_, err := maintenanceService.ExpireStalePayments(ctx, time.Now())
+		return err
+	})
+	sched.Every("auto_complete_bookings", time.Hour, func(ctx context.Context, job entity.Job) error {
+		_, err := maintenanceService.AutoCompleteBookings(ctx, time.Now())
+		return err
+	})
+	sched.Every("schedule_visit_reminders", time.Hour, func(ctx context.Context, job entity.Job) error {
+		_, err := maintenanceService.ScheduleVisitReminders(ctx, time.Now())
+		return err
+	})
+	sched.Every("schedule_review_requests", time.Hour, func(ctx context.Context, job entity.Job) error {
+		_, err := maintenanceService.ScheduleReviewRequests(ctx, time.Now())
+		return err
+	})
+
+	// Job sekali jalan per booking
+	sched.Register("visit_reminder", func(ctx context.Context, job entity.Job) error {
+		var payload service.BookingJobPayload
+		if err := scheduler.Decode(job, &payload); err != nil {
+			return err
+		}
+		return maintenanceService.SendVisitReminder(ctx, payload.BookingID)
+	})
+	sched.Register("review_request", func(ctx context.Context, job entity.Job) error {
+		var payload service.BookingJobPayload
+		if err := scheduler.Decode(job, &payload); err != nil {
+			return err
+		}
+		return maintenanceService.SendReviewRequest(ctx, payload.BookingID)
+	})
+}
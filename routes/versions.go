@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/middleware"
+	"github.com/Ayyasy123/dibimbing-capstone.git/ratelimit"
+	"github.com/Ayyasy123/dibimbing-capstone.git/realtime"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/scheduler"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/gin-gonic/gin"
+)
+
+// V1Prefix adalah prefix route API versi 1.
+const V1Prefix = "/api/v1"
+
+// legacyDeprecatedAt adalah tanggal route tanpa versi dinyatakan usang,
+// dikirim di header Deprecation.
+var legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Services berisi service yang dipakai bersama oleh semua versi API. Versi
+// baru (mis. /api/v2 dengan Payment.Amount berupa angka) cukup membuat
+// controller dan DTO sendiri di atas service yang sama, tanpa membuat ulang
+// service atau mendaftarkan ulang subscriber dan job.
+type Services struct {
+	Users                   service.UserService
+	NotificationPreferences service.NotificationPreferenceService
+	TechnicianApplications  service.TechnicianApplicationService
+	Services                service.ServiceService
+	Bookings                service.BookingService
+	Messages                service.MessageService
+	Payments                service.PaymentService
+	Reviews                 service.ReviewService
+	Outbox                  service.OutboxService
+	Webhooks                service.WebhookService
+}
+
+func NewServices(storage repository.Storage, sched *scheduler.Scheduler, lockout *ratelimit.Lockout) Services {
+	return Services{
+		Users:                   service.NewUserService(storage.Users, storage.UnitOfWork, lockout),
+		NotificationPreferences: service.NewNotificationPreferenceService(storage.NotificationPreferences),
+		TechnicianApplications:  service.NewTechnicianApplicationService(storage.TechnicianApplications, storage.Users, storage.UnitOfWork),
+		Services:                service.NewServiceService(storage.Services, storage.TechnicianApplications),
+		Bookings:                service.NewBookingService(storage.Bookings),
+		Messages:                service.NewMessageService(storage.Messages, storage.Bookings),
+		Payments:                service.NewPaymentService(storage.Payments, storage.UnitOfWork),
+		Reviews:                 service.NewReviewService(storage.Reviews),
+		Outbox:                  service.NewOutboxService(storage.Outbox),
+		Webhooks:                service.NewWebhookService(storage.Webhooks, sched, nil),
+	}
+}
+
+// SetupV1Routes mendaftarkan route API versi 1 ke api, biasanya
+// router.Group(V1Prefix).
+func SetupV1Routes(api gin.IRouter, services Services, hub *realtime.Hub, limits RateLimits) {
+	SetupUserRoutes(api, services.Users, limits)
+	SetupNotificationPreferenceRoutes(api, services.NotificationPreferences)
+	SetupTechnicianApplicationRoutes(api, services.TechnicianApplications)
+	SetupServiceRoutes(api, services.Services)
+	SetupBookingRoutes(api, services.Bookings)
+	SetupMessageRoutes(api, services.Messages, hub)
+	SetupPaymentRoutes(api, services.Payments)
+	SetupReviewRoutes(api, services.Reviews)
+	SetupOutboxRoutes(api, services.Outbox)
+	SetupWebhookRoutes(api, services.Webhooks)
+	SetupEventRoutes(api, hub)
+}
+
+// LegacyRoutes mengatur alias tanpa versi (/bookings, /payments, ...) untuk
+// client yang belum pindah ke /api/v1. Alias menjalankan handler v1 yang
+// sama, kecuali endpoint list yang tetap memakai kontrak lama (offset dan
+// array tanpa envelope), dan hanya sementara: setelah Sunset, matikan Enabled.
+type LegacyRoutes struct {
+	Enabled bool
+	Sunset  time.Time // tanggal alias dihapus, kosong jika belum ditentukan
+}
+
+// SetupLegacyRoutes mendaftarkan alias tanpa versi untuk route v1. Setiap
+// response alias membawa header Deprecation, Sunset dan Link ke route v1.
+func SetupLegacyRoutes(router *gin.Engine, legacy LegacyRoutes, services Services, hub *realtime.Hub, limits RateLimits) {
+	aliases := router.Group("", middleware.Deprecated(middleware.Deprecation{
+		Since:     legacyDeprecatedAt,
+		Sunset:    legacy.Sunset,
+		Successor: func(path string) string { return V1Prefix + path },
+	}), middleware.LegacyLists())
+	SetupV1Routes(aliases, services, hub, limits)
+}
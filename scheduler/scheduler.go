@@ -0,0 +1,277 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/logging"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+const (
+	defaultMaxAttempts = 5
+	baseBackoff        = 30 * time.Second
+	maxBackoff         = time.Hour
+)
+
+// Handler menjalankan satu job. Error yang dikembalikan membuat job dicoba
+// lagi dengan backoff sampai MaxAttempts tercapai.
+type Handler func(ctx context.Context, job entity.Job) error
+
+type recurringJob struct {
+	jobType string
+	period  time.Duration
+}
+
+// Scheduler menjalankan job yang tersimpan di database. Setiap replica API
+// boleh menjalankan Scheduler; job hanya dijalankan oleh replica yang berhasil
+// mengklaimnya.
+type Scheduler struct {
+	repo        repository.JobRepository
+	worker      string
+	interval    time.Duration
+	lockTimeout time.Duration
+	batchSize   int
+
+	mu        sync.RWMutex
+	handlers  map[string]Handler
+	recurring []recurringJob
+	running   bool
+	lastTick  time.Time
+
+	now    func() time.Time
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(repo repository.JobRepository) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		repo:        repo,
+		worker:      fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		interval:    10 * time.Second,
+		lockTimeout: 5 * time.Minute,
+		batchSize:   20,
+		handlers:    make(map[string]Handler),
+		now:         time.Now,
+	}
+}
+
+// Register mendaftarkan handler untuk tipe job tertentu.
+func (s *Scheduler) Register(jobType string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+// Every menjadwalkan job berulang setiap period. Unique key dibentuk dari awal
+// periode sehingga setiap periode hanya dijalankan sekali di semua replica.
+func (s *Scheduler) Every(jobType string, period time.Duration, handler Handler) {
+	s.Register(jobType, handler)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recurring = append(s.recurring, recurringJob{jobType: jobType, period: period})
+}
+
+// Enqueue menjadwalkan job sekali jalan. uniqueKey boleh kosong; jika diisi,
+// job dengan key yang sama tidak akan dijadwalkan dua kali.
+func (s *Scheduler) Enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time, uniqueKey string) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	job := &entity.Job{
+		Type:        jobType,
+		Payload:     string(raw),
+		Status:      "Pending",
+		RunAt:       runAt,
+		MaxAttempts: defaultMaxAttempts,
+	}
+	if uniqueKey != "" {
+		job.UniqueKey = &uniqueKey
+	}
+
+	_, err = s.repo.Enqueue(ctx, job)
+	return err
+}
+
+// Start menjalankan loop scheduler di goroutine tersendiri.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	s.heartbeat(true)
+	go func() {
+		defer close(s.done)
+		defer s.heartbeat(false)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.heartbeat(true)
+			s.Tick(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop menghentikan loop dan menunggu job yang sedang berjalan selesai.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// Check mengembalikan error jika loop scheduler tidak berjalan, atau tidak
+// memulai tick baru selama lebih dari lockTimeout (mis. tertahan job yang
+// macet). Dipakai oleh pemeriksaan readiness.
+func (s *Scheduler) Check(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.running {
+		return errors.New("scheduler is not running")
+	}
+	if idle := s.now().Sub(s.lastTick); idle > s.lockTimeout {
+		return fmt.Errorf("scheduler has not ticked for %s", idle.Round(time.Second))
+	}
+	return nil
+}
+
+func (s *Scheduler) heartbeat(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = running
+	s.lastTick = s.now()
+}
+
+// Tick menjadwalkan job berulang lalu menjalankan semua job yang sudah jatuh tempo.
+// Job yang sudah diklaim tetap dijalankan sampai selesai walaupun ctx dibatalkan.
+func (s *Scheduler) Tick(ctx context.Context) {
+	now := s.now()
+	s.scheduleRecurring(ctx, now)
+
+	jobs, err := s.repo.FindDue(ctx, now, now.Add(-s.lockTimeout), s.batchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "scheduler: failed to load due jobs", "error", err)
+		return
+	}
+
+	for _, job := range jobs {
+		claimed, err := s.repo.Claim(ctx, job.ID, s.worker, now, now.Add(-s.lockTimeout))
+		if err != nil {
+			slog.ErrorContext(ctx, "scheduler: failed to claim job", "job_id", job.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			// Sudah diklaim oleh replica lain
+			continue
+		}
+
+		job.Attempts++
+		s.run(context.WithoutCancel(ctx), job)
+	}
+}
+
+func (s *Scheduler) scheduleRecurring(ctx context.Context, now time.Time) {
+	s.mu.RLock()
+	recurring := s.recurring
+	s.mu.RUnlock()
+
+	for _, r := range recurring {
+		start := now.Truncate(r.period)
+		key := r.jobType + ":" + start.UTC().Format(time.RFC3339)
+		if err := s.Enqueue(ctx, r.jobType, nil, start, key); err != nil {
+			slog.ErrorContext(ctx, "scheduler: failed to schedule job", "job_type", r.jobType, "error", err)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job entity.Job) {
+	s.mu.RLock()
+	handler, ok := s.handlers[job.Type]
+	s.mu.RUnlock()
+
+	// Log dari handler (service dan repository) ikut membawa ID dan tipe job
+	ctx = logging.With(ctx, "job_id", job.ID, "job_type", job.Type)
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler registered for job type %s", job.Type)
+	} else {
+		err = safeRun(ctx, handler, job)
+	}
+
+	if err == nil {
+		released, err := s.repo.Complete(ctx, job.ID, s.worker)
+		s.logRelease(ctx, released, err, "scheduler: failed to complete job")
+		return
+	}
+
+	slog.WarnContext(ctx, "scheduler: job failed", "attempt", job.Attempts, "error", err)
+
+	if job.Attempts >= job.MaxAttempts {
+		released, err := s.repo.Fail(ctx, job.ID, s.worker, err.Error())
+		s.logRelease(ctx, released, err, "scheduler: failed to mark job as failed")
+		return
+	}
+
+	released, err := s.repo.Retry(ctx, job.ID, s.worker, s.now().Add(Backoff(job.Attempts)), err.Error())
+	s.logRelease(ctx, released, err, "scheduler: failed to reschedule job")
+}
+
+// logRelease mencatat hasil Complete, Retry atau Fail. Jika lock sudah
+// diambil alih replica lain (job berjalan lebih lama dari lockTimeout), hasil
+// worker ini dibuang agar tidak menimpa state milik replica tersebut.
+func (s *Scheduler) logRelease(ctx context.Context, released bool, err error, msg string) {
+	switch {
+	case err != nil:
+		slog.ErrorContext(ctx, msg, "error", err)
+	case !released:
+		slog.WarnContext(ctx, "scheduler: lost job lock, result discarded", "worker", s.worker, "lock_timeout", s.lockTimeout)
+	}
+}
+
+// safeRun mengubah panic di handler menjadi error agar loop scheduler tidak berhenti.
+func safeRun(ctx context.Context, handler Handler, job entity.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// Backoff mengembalikan jeda sebelum percobaan berikutnya: 30s, 1m, 2m, ...
+// dengan batas maksimal 1 jam.
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+// Decode membaca payload JSON job ke dalam v.
+func Decode(job entity.Job, v interface{}) error {
+	return json.Unmarshal([]byte(job.Payload), v)
+}
@@ -0,0 +1,257 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+)
+
+// memoryJobRepository meniru semantik klaim bersyarat dari database.
+type memoryJobRepository struct {
+	mu   sync.Mutex
+	jobs []*entity.Job
+}
+
+func (r *memoryJobRepository) Enqueue(ctx context.Context, job *entity.Job) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job.UniqueKey != nil {
+		for _, existing := range r.jobs {
+			if existing.UniqueKey != nil && *existing.UniqueKey == *job.UniqueKey {
+				return false, nil
+			}
+		}
+	}
+	job.ID = len(r.jobs) + 1
+	copied := *job
+	r.jobs = append(r.jobs, &copied)
+	return true, nil
+}
+
+func (r *memoryJobRepository) due(job *entity.Job, now, staleBefore time.Time) bool {
+	return (job.Status == "Pending" && !job.RunAt.After(now)) ||
+		(job.Status == "Running" && job.LockedAt != nil && job.LockedAt.Before(staleBefore))
+}
+
+func (r *memoryJobRepository) FindDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []entity.Job
+	for _, job := range r.jobs {
+		if r.due(job, now, staleBefore) && len(jobs) < limit {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *memoryJobRepository) Claim(ctx context.Context, id int, worker string, now, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id-1]
+	if !r.due(job, now, staleBefore) {
+		return false, nil
+	}
+	job.Status = "Running"
+	job.LockedBy = worker
+	job.LockedAt = &now
+	job.Attempts++
+	return true, nil
+}
+
+func (r *memoryJobRepository) Complete(ctx context.Context, id int, worker string) (bool, error) {
+	return r.release(id, worker, func(job *entity.Job) { job.Status = "Completed" })
+}
+
+func (r *memoryJobRepository) Retry(ctx context.Context, id int, worker string, runAt time.Time, lastError string) (bool, error) {
+	return r.release(id, worker, func(job *entity.Job) {
+		job.Status = "Pending"
+		job.RunAt = runAt
+		job.LastError = lastError
+	})
+}
+
+func (r *memoryJobRepository) Fail(ctx context.Context, id int, worker string, lastError string) (bool, error) {
+	return r.release(id, worker, func(job *entity.Job) {
+		job.Status = "Failed"
+		job.LastError = lastError
+	})
+}
+
+func (r *memoryJobRepository) release(id int, worker string, fn func(job *entity.Job)) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id-1]
+	if job.Status != "Running" || job.LockedBy != worker {
+		return false, nil
+	}
+	fn(job)
+	job.LockedBy = ""
+	job.LockedAt = nil
+	return true, nil
+}
+
+func newTestScheduler(repo *memoryJobRepository, now *time.Time) *Scheduler {
+	s := New(repo)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestRecurringJobRunsOncePerPeriodAcrossReplicas(t *testing.T) {
+	repo := &memoryJobRepository{}
+	now := time.Date(2026, 10, 19, 8, 5, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	runs := 0
+	handler := func(ctx context.Context, job entity.Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs++
+		return nil
+	}
+
+	replicaA := newTestScheduler(repo, &now)
+	replicaB := newTestScheduler(repo, &now)
+	replicaA.Every("expire_bookings", 15*time.Minute, handler)
+	replicaB.Every("expire_bookings", 15*time.Minute, handler)
+
+	var wg sync.WaitGroup
+	for _, s := range []*Scheduler{replicaA, replicaB, replicaA, replicaB} {
+		wg.Add(1)
+		go func(s *Scheduler) {
+			defer wg.Done()
+			s.Tick(context.Background())
+		}(s)
+	}
+	wg.Wait()
+
+	if runs != 1 {
+		t.Fatalf("expected job to run once in the first period, ran %d times", runs)
+	}
+
+	now = now.Add(15 * time.Minute)
+	replicaB.Tick(context.Background())
+	replicaA.Tick(context.Background())
+
+	if runs != 2 {
+		t.Fatalf("expected job to run again in the next period, ran %d times", runs)
+	}
+}
+
+func TestFailedJobIsRetriedWithBackoffThenFails(t *testing.T) {
+	repo := &memoryJobRepository{}
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, &now)
+
+	attempts := 0
+	s.Register("flaky", func(ctx context.Context, job entity.Job) error {
+		attempts++
+		return errors.New("gateway down")
+	})
+	if err := s.Enqueue(context.Background(), "flaky", nil, now, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Tick(context.Background())
+	job := repo.jobs[0]
+	if job.Status != "Pending" || !job.RunAt.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("expected retry in 30s, got status %s run_at %s", job.Status, job.RunAt)
+	}
+
+	// Belum waktunya dicoba lagi
+	s.Tick(context.Background())
+	if attempts != 1 {
+		t.Fatalf("expected job to wait for backoff, ran %d times", attempts)
+	}
+
+	for i := 0; i < defaultMaxAttempts; i++ {
+		now = now.Add(maxBackoff)
+		s.Tick(context.Background())
+	}
+
+	if attempts != defaultMaxAttempts || job.Status != "Failed" || job.LastError != "gateway down" {
+		t.Fatalf("expected job to fail after %d attempts, got %d attempts and status %s", defaultMaxAttempts, attempts, job.Status)
+	}
+}
+
+func TestStaleRunningJobIsReclaimed(t *testing.T) {
+	repo := &memoryJobRepository{}
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, &now)
+
+	ran := false
+	s.Register("reminder", func(ctx context.Context, job entity.Job) error {
+		ran = true
+		return nil
+	})
+
+	lockedAt := now.Add(-10 * time.Minute)
+	repo.jobs = append(repo.jobs, &entity.Job{ID: 1, Type: "reminder", Status: "Running", LockedAt: &lockedAt, MaxAttempts: 5})
+
+	s.Tick(context.Background())
+
+	if !ran || repo.jobs[0].Status != "Completed" {
+		t.Fatalf("expected stale job to be reclaimed and completed, got status %s", repo.jobs[0].Status)
+	}
+}
+
+func TestLateResultFromLostLockIsDiscarded(t *testing.T) {
+	repo := &memoryJobRepository{}
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	replicaA := newTestScheduler(repo, &now)
+	replicaB := newTestScheduler(repo, &now)
+	replicaA.worker, replicaB.worker = "replica-a", "replica-b"
+
+	// Replica A berjalan lebih lama dari lockTimeout sehingga replica B
+	// mengklaim ulang job dan menyelesaikannya lebih dulu
+	replicaA.Register("report", func(ctx context.Context, job entity.Job) error {
+		now = now.Add(replicaA.lockTimeout + time.Second)
+		replicaB.Tick(context.Background())
+		return errors.New("timeout")
+	})
+	replicaB.Register("report", func(ctx context.Context, job entity.Job) error { return nil })
+	if err := replicaA.Enqueue(context.Background(), "report", nil, now, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	replicaA.Tick(context.Background())
+
+	job := repo.jobs[0]
+	if job.Status != "Completed" || job.LastError != "" {
+		t.Fatalf("expected result of replica B to be kept, got status %s last_error %q", job.Status, job.LastError)
+	}
+}
+
+func TestCheckReportsStoppedOrStuckLoop(t *testing.T) {
+	repo := &memoryJobRepository{}
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	s := newTestScheduler(repo, &now)
+
+	if err := s.Check(context.Background()); err == nil {
+		t.Fatal("expected scheduler that was never started to be unhealthy")
+	}
+
+	s.heartbeat(true)
+	now = now.Add(s.lockTimeout)
+	if err := s.Check(context.Background()); err != nil {
+		t.Fatalf("expected running scheduler to be healthy, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := s.Check(context.Background()); err == nil || err.Error() != "scheduler has not ticked for 5m1s" {
+		t.Fatalf("expected stuck scheduler to be unhealthy, got %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 3: 2 * time.Minute, 10: time.Hour}
+	for attempts, want := range cases {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
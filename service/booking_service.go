@@ -1,25 +1,35 @@
 package service
 
 import (
-	"errors"
+	"context"
 	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
 )
 
+var (
+	ErrBookingNotFound       = apperror.NotFound("booking_not_found", "booking not found")
+	ErrBookingDateNotAllowed = apperror.BusinessRule("booking_date_not_allowed", "booking cannot be accepted for today or past dates")
+	ErrServiceAlreadyBooked  = apperror.Conflict("service_already_booked", "service is already booked on the requested date")
+	ErrInvalidBookingStatus  = apperror.Validation("invalid_booking_status", "invalid status")
+)
+
 type BookingService interface {
-	CreateBooking(req entity.CreateBookingReq) (entity.Booking, error)
-	GetBookingByID(id int) (entity.Booking, error)
-	UpdateBooking(req entity.UpdateBookingReq) (entity.Booking, error)
-	DeleteBooking(id int) error
-	GetAllBookings(limit, offset int) ([]entity.Booking, error)
-	GetBookingsByUserID(userID int) ([]entity.BookingRes, error)
-	GetBookingsByServiceID(serviceID int) ([]entity.BookingRes, error)
-	UpdateBookingStatus(bookingID string, status string) error
-	GetBookingReport(startDate, endDate time.Time) (entity.BookingReport, error)
-	GetAvailableDates(serviceID int, year int, month int) ([]time.Time, error)
-	GetConfirmedBookingsForTechnician(technicianID int) ([]entity.BookingRes, error)
+	CreateBooking(ctx context.Context, req entity.CreateBookingReq) (entity.Booking, error)
+	GetBookingByID(ctx context.Context, id int) (entity.Booking, error)
+	UpdateBooking(ctx context.Context, req entity.UpdateBookingReq) (entity.Booking, error)
+	DeleteBooking(ctx context.Context, id int) error
+	GetAllBookings(ctx context.Context, q listquery.Query) (listquery.Page[entity.Booking], error)
+	GetBookingsByUserID(ctx context.Context, userID int, q listquery.Query) (listquery.Page[entity.BookingRes], error)
+	GetBookingsByServiceID(ctx context.Context, serviceID int, q listquery.Query) (listquery.Page[entity.BookingRes], error)
+	UpdateBookingStatus(ctx context.Context, bookingID string, status string) error
+	GetBookingReport(ctx context.Context, startDate, endDate time.Time) (entity.BookingReport, error)
+	GetAvailableDates(ctx context.Context, serviceID int, year int, month int) ([]time.Time, error)
+	GetConfirmedBookingsForTechnician(ctx context.Context, technicianID int, q listquery.Query) (listquery.Page[entity.BookingRes], error)
 }
 
 type bookingService struct {
@@ -27,25 +37,24 @@ type bookingService struct {
 }
 
 func NewBookingService(repo repository.BookingRepository) BookingService {
-	return &bookingService{repo}
+	return &bookingService{repo: repo}
 }
 
-func (s *bookingService) CreateBooking(req entity.CreateBookingReq) (entity.Booking, error) {
-	// Dapatkan tanggal hari ini (awal hari, 00:00:00)
-	today := time.Now().UTC().Truncate(24 * time.Hour)
+func (s *bookingService) CreateBooking(ctx context.Context, req entity.CreateBookingReq) (_ entity.Booking, err error) {
+	ctx, end := startSpan(ctx, "BookingService.CreateBooking")
+	defer end(&err)
 
-	// Jika tanggal booking adalah hari ini atau sebelumnya, tolak booking
-	if req.Date.Before(today) || req.Date.Equal(today) {
-		return entity.Booking{}, errors.New("booking cannot be accepted for today or past dates")
+	if !isBookableDate(req.Date) {
+		return entity.Booking{}, ErrBookingDateNotAllowed
 	}
 
 	// Cek ketersediaan layanan pada tanggal yang diminta
-	isAvailable, err := s.repo.CheckServiceAvailability(req.ServiceID, req.Date)
+	isAvailable, err := s.repo.CheckServiceAvailability(ctx, req.ServiceID, req.Date)
 	if err != nil {
 		return entity.Booking{}, err
 	}
 	if !isAvailable {
-		return entity.Booking{}, errors.New("service is already booked on the requested date")
+		return entity.Booking{}, ErrServiceAlreadyBooked
 	}
 
 	// Buat booking baru
@@ -56,19 +65,42 @@ func (s *bookingService) CreateBooking(req entity.CreateBookingReq) (entity.Book
 		Status:      "Pending", // Default status
 		Description: req.Description,
 	}
-	return s.repo.Create(booking)
+
+	booking, err = s.repo.Create(ctx, booking, bookingEvent("booking.created"))
+	if err == nil {
+		recordBookingCreated(booking)
+	}
+	return booking, err
 }
 
-func (s *bookingService) GetBookingByID(id int) (entity.Booking, error) {
-	return s.repo.FindByID(id)
+func (s *bookingService) GetBookingByID(ctx context.Context, id int) (_ entity.Booking, err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetBookingByID")
+	defer end(&err)
+
+	booking, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return booking, notFound(err, ErrBookingNotFound)
+	}
+	return booking, nil
 }
 
-func (s *bookingService) UpdateBooking(req entity.UpdateBookingReq) (entity.Booking, error) {
-	booking, err := s.repo.FindByID(req.ID)
+func (s *bookingService) UpdateBooking(ctx context.Context, req entity.UpdateBookingReq) (_ entity.Booking, err error) {
+	ctx, end := startSpan(ctx, "BookingService.UpdateBooking")
+	defer end(&err)
+
+	booking, err := s.repo.FindByID(ctx, req.ID)
 	if err != nil {
-		return booking, err
+		return booking, notFound(err, ErrBookingNotFound)
+	}
+
+	// Tanggal hanya dicek jika dipindahkan, agar booking yang sudah berjalan
+	// (hari ini atau sebelumnya) tetap bisa diperbarui
+	if !req.Date.Equal(booking.Date) && !isBookableDate(req.Date) {
+		return booking, ErrBookingDateNotAllowed
 	}
 
+	statusChanged := booking.Status != req.Status
+
 	booking.UserID = req.UserID
 	booking.ServiceID = req.ServiceID
 	booking.Date = req.Date
@@ -76,66 +108,65 @@ func (s *bookingService) UpdateBooking(req entity.UpdateBookingReq) (entity.Book
 	booking.Status = req.Status
 	booking.Description = req.Description
 
-	return s.repo.Update(booking)
-}
+	var events repository.BookingEvents
+	if statusChanged {
+		events = bookingEvent(event.StatusType("booking", booking.Status))
+	}
 
-func (s *bookingService) DeleteBooking(id int) error {
-	return s.repo.Delete(id)
+	booking, err = s.repo.Update(ctx, booking, events)
+	if err == nil && statusChanged {
+		recordBookingStatus(booking.Status)
+	}
+	return booking, err
 }
 
-func (s *bookingService) GetAllBookings(limit, offset int) ([]entity.Booking, error) {
-	return s.repo.FindAll(limit, offset)
+// isBookableDate melaporkan apakah date paling cepat besok (00:00:00 UTC);
+// hari ini (jam berapa pun) atau sebelumnya ditolak.
+func isBookableDate(date time.Time) bool {
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return !date.Before(tomorrow)
 }
 
-func (s *bookingService) GetBookingsByUserID(userID int) ([]entity.BookingRes, error) {
-	bookings, err := s.repo.GetBookingsByUserID(userID)
-	if err != nil {
-		return nil, err
-	}
+func (s *bookingService) DeleteBooking(ctx context.Context, id int) (err error) {
+	ctx, end := startSpan(ctx, "BookingService.DeleteBooking")
+	defer end(&err)
 
-	var bookingRes []entity.BookingRes
-	for _, booking := range bookings {
-		bookingRes = append(bookingRes, entity.BookingRes{
-			ID:        booking.ID,
-			UserID:    booking.UserID,
-			ServiceID: booking.ServiceID,
-			Date:      booking.Date,
-			// Time:        booking.Time,
-			Status:      booking.Status,
-			Description: booking.Description,
-			CreatedAt:   booking.CreatedAt,
-			UpdatedAt:   booking.UpdatedAt,
-		})
-	}
+	return s.repo.Delete(ctx, id)
+}
 
-	return bookingRes, nil
+func (s *bookingService) GetAllBookings(ctx context.Context, q listquery.Query) (_ listquery.Page[entity.Booking], err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetAllBookings")
+	defer end(&err)
+
+	return s.repo.FindAll(ctx, q)
 }
 
-func (s *bookingService) GetBookingsByServiceID(serviceID int) ([]entity.BookingRes, error) {
-	bookings, err := s.repo.GetBookingsByServiceID(serviceID)
+func (s *bookingService) GetBookingsByUserID(ctx context.Context, userID int, q listquery.Query) (_ listquery.Page[entity.BookingRes], err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetBookingsByUserID")
+	defer end(&err)
+
+	page, err := s.repo.FindAll(ctx, q.Where(fieldUserID, listquery.Eq, userID))
 	if err != nil {
-		return nil, err
+		return listquery.Page[entity.BookingRes]{}, err
 	}
+	return listquery.Map(page, toBookingRes), nil
+}
 
-	var bookingRes []entity.BookingRes
-	for _, booking := range bookings {
-		bookingRes = append(bookingRes, entity.BookingRes{
-			ID:        booking.ID,
-			UserID:    booking.UserID,
-			ServiceID: booking.ServiceID,
-			Date:      booking.Date,
-			// Time:        booking.Time,
-			Status:      booking.Status,
-			Description: booking.Description,
-			CreatedAt:   booking.CreatedAt,
-			UpdatedAt:   booking.UpdatedAt,
-		})
-	}
+func (s *bookingService) GetBookingsByServiceID(ctx context.Context, serviceID int, q listquery.Query) (_ listquery.Page[entity.BookingRes], err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetBookingsByServiceID")
+	defer end(&err)
 
-	return bookingRes, nil
+	page, err := s.repo.FindAll(ctx, q.Where(fieldServiceID, listquery.Eq, serviceID))
+	if err != nil {
+		return listquery.Page[entity.BookingRes]{}, err
+	}
+	return listquery.Map(page, toBookingRes), nil
 }
 
-func (s *bookingService) UpdateBookingStatus(bookingID string, status string) error {
+func (s *bookingService) UpdateBookingStatus(ctx context.Context, bookingID string, status string) (err error) {
+	ctx, end := startSpan(ctx, "BookingService.UpdateBookingStatus")
+	defer end(&err)
+
 	// Validasi status yang diperbolehkan
 	allowedStatuses := map[string]bool{
 		"Confirmed":   true,
@@ -146,34 +177,42 @@ func (s *bookingService) UpdateBookingStatus(bookingID string, status string) er
 	}
 
 	if !allowedStatuses[status] {
-		return errors.New("invalid status")
+		return ErrInvalidBookingStatus
 	}
 
-	return s.repo.UpdateBookingStatus(bookingID, status)
+	err = s.repo.UpdateBookingStatus(ctx, bookingID, status, bookingEvent(event.StatusType("booking", status)))
+	if err != nil {
+		return notFound(err, ErrBookingNotFound)
+	}
+	recordBookingStatus(status)
+	return nil
 }
 
-func (s *bookingService) GetBookingReport(startDate, endDate time.Time) (entity.BookingReport, error) {
+func (s *bookingService) GetBookingReport(ctx context.Context, startDate, endDate time.Time) (_ entity.BookingReport, err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetBookingReport")
+	defer end(&err)
+
 	// Get total bookings
-	totalBooking, err := s.repo.GetTotalBookings(startDate, endDate)
+	totalBooking, err := s.repo.GetTotalBookings(ctx, startDate, endDate)
 	if err != nil {
 		return entity.BookingReport{}, err
 	}
 
 	// Get total revenue
-	totalRevenue, err := s.repo.GetTotalRevenue(startDate, endDate)
+	totalRevenue, err := s.repo.GetTotalRevenue(ctx, startDate, endDate)
 	if err != nil {
 		return entity.BookingReport{}, err
 	}
 
 	// Define the statuses to query
-	statuses := []string{"Pending", "In Progress", "Completed", "Canceled"}
+	statuses := []string{"Pending", "In Progress", "Completed", "Canceled", "Expired"}
 
 	// Initialize the status details array
 	statusDetails := []entity.BookingStatusDetail{}
 
 	// Loop through each status and get the count and revenue
 	for _, status := range statuses {
-		count, revenue, err := s.repo.GetBookingsByStatus(status, startDate, endDate)
+		count, revenue, err := s.repo.GetBookingsByStatus(ctx, status, startDate, endDate)
 		if err != nil {
 			return entity.BookingReport{}, err
 		}
@@ -196,9 +235,12 @@ func (s *bookingService) GetBookingReport(startDate, endDate time.Time) (entity.
 	return report, nil
 }
 
-func (s *bookingService) GetAvailableDates(serviceID int, year int, month int) ([]time.Time, error) {
+func (s *bookingService) GetAvailableDates(ctx context.Context, serviceID int, year int, month int) (_ []time.Time, err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetAvailableDates")
+	defer end(&err)
+
 	// Dapatkan tanggal-tanggal yang sudah dipesan
-	bookedDates, err := s.repo.GetBookedDates(serviceID, year, month)
+	bookedDates, err := s.repo.GetBookedDates(ctx, serviceID, year, month)
 	if err != nil {
 		return nil, err
 	}
@@ -236,27 +278,41 @@ func (s *bookingService) GetAvailableDates(serviceID int, year int, month int) (
 	return availableDates, nil
 }
 
-func (s *bookingService) GetConfirmedBookingsForTechnician(technicianID int) ([]entity.BookingRes, error) {
+func (s *bookingService) GetConfirmedBookingsForTechnician(ctx context.Context, technicianID int, q listquery.Query) (_ listquery.Page[entity.BookingRes], err error) {
+	ctx, end := startSpan(ctx, "BookingService.GetConfirmedBookingsForTechnician")
+	defer end(&err)
+
 	// Ambil booking dengan status "Confirmed" yang terkait dengan service_id dari technician
-	bookings, err := s.repo.GetConfirmedBookingsByTechnicianID(technicianID)
+	page, err := s.repo.GetConfirmedBookingsByTechnicianID(ctx, technicianID, q)
 	if err != nil {
-		return nil, err
+		return listquery.Page[entity.BookingRes]{}, err
 	}
+	return listquery.Map(page, toBookingRes), nil
+}
 
-	// Map hasil ke struct BookingRes
-	var bookingRes []entity.BookingRes
-	for _, booking := range bookings {
-		bookingRes = append(bookingRes, entity.BookingRes{
-			ID:          booking.ID,
-			UserID:      booking.UserID,
-			ServiceID:   booking.ServiceID,
-			Date:        booking.Date,
-			Status:      booking.Status,
-			Description: booking.Description,
-			CreatedAt:   booking.CreatedAt,
-			UpdatedAt:   booking.UpdatedAt,
-		})
+// bookingEvent membentuk event berisi data booking terbaru untuk customer dan
+// technician yang terkait.
+func bookingEvent(eventType string) repository.BookingEvents {
+	return func(booking entity.Booking) []event.Event {
+		return []event.Event{{Type: eventType, Recipients: bookingRecipients(booking), Data: toBookingRes(booking)}}
 	}
+}
 
-	return bookingRes, nil
+// bookingRecipients mengembalikan user yang terkait dengan booking: customer
+// yang memesan dan technician pemilik service.
+func bookingRecipients(booking entity.Booking) map[string]int {
+	return map[string]int{"customer": booking.UserID, "technician": booking.Service.UserID}
+}
+
+func toBookingRes(booking entity.Booking) entity.BookingRes {
+	return entity.BookingRes{
+		ID:          booking.ID,
+		UserID:      booking.UserID,
+		ServiceID:   booking.ServiceID,
+		Date:        booking.Date,
+		Status:      booking.Status,
+		Description: booking.Description,
+		CreatedAt:   booking.CreatedAt,
+		UpdatedAt:   booking.UpdatedAt,
+	}
 }
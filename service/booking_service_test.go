@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository/memory"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStorage menyiapkan repository memory berisi satu technician, satu
+// customer dan satu service milik technician.
+func newStorage(t *testing.T) (repository.Storage, entity.User, entity.Service) {
+	t.Helper()
+	ctx := context.Background()
+	storage := memory.NewStorage()
+
+	technician := entity.User{Name: "Teknisi", Email: "tech@example.com", Role: "technician"}
+	require.NoError(t, storage.Users.Create(ctx, &technician))
+	customer := entity.User{Name: "Customer", Email: "customer@example.com"}
+	require.NoError(t, storage.Users.Create(ctx, &customer))
+
+	svc := entity.Service{UserID: technician.ID, Name: "Servis AC", Cost: 150000}
+	require.NoError(t, storage.Services.Create(ctx, &svc))
+	return storage, customer, svc
+}
+
+func TestBookingService_CreateBooking(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	_, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: today})
+	assert.ErrorIs(t, err, service.ErrBookingDateNotAllowed)
+	_, err = bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: today.Add(23 * time.Hour)})
+	assert.ErrorIs(t, err, service.ErrBookingDateNotAllowed)
+
+	date := time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)
+	booking, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: date})
+	require.NoError(t, err)
+	assert.Equal(t, "Pending", booking.Status)
+
+	_, err = bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: date})
+	assert.ErrorIs(t, err, service.ErrServiceAlreadyBooked)
+
+	// Booking baru langsung masuk ke outbox bersama penerima notifikasinya
+	events, err := storage.Outbox.FindAll(ctx, listquery.New(service.OutboxEventListSpec, 10))
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	assert.Equal(t, "booking.created", events.Items[0].Type)
+}
+
+func TestBookingService_UpdateBookingChecksOnlyMovedDate(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+
+	// Booking hari ini sudah berjalan, tetapi status dan deskripsinya tetap bisa diubah
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	booking, err := storage.Bookings.Create(ctx, entity.Booking{UserID: customer.ID, ServiceID: svc.ID, Date: today, Status: "Confirmed"}, nil)
+	require.NoError(t, err)
+	req := entity.UpdateBookingReq{ID: booking.ID, UserID: customer.ID, ServiceID: svc.ID, Date: today, Status: "In Progress", Description: "Teknisi sudah datang"}
+	updated, err := bookings.UpdateBooking(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "In Progress", updated.Status)
+
+	// Memindahkan booking tetap harus ke tanggal setelah hari ini
+	req.Date = today.AddDate(0, 0, -1)
+	_, err = bookings.UpdateBooking(ctx, req)
+	assert.ErrorIs(t, err, service.ErrBookingDateNotAllowed)
+	req.Date = today.AddDate(0, 0, 2)
+	updated, err = bookings.UpdateBooking(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, req.Date.Equal(updated.Date))
+}
+
+func TestBookingService_GetAvailableDates(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+
+	for _, d := range []int{5, 20} {
+		_, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 2, d, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+	}
+
+	dates, err := bookings.GetAvailableDates(ctx, svc.ID, 2030, 2)
+	require.NoError(t, err)
+	assert.Len(t, dates, 26)
+	for _, date := range dates {
+		assert.NotContains(t, []int{5, 20}, date.Day())
+	}
+
+	// Bulan yang sudah lewat tidak punya tanggal tersedia
+	dates, err = bookings.GetAvailableDates(ctx, svc.ID, 2020, 2)
+	require.NoError(t, err)
+	assert.Empty(t, dates)
+}
+
+func TestBookingService_GetBookingReport(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+
+	var ids []int
+	for _, d := range []int{5, 6, 7} {
+		booking, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 4, d, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		ids = append(ids, booking.ID)
+	}
+	_, err := storage.Payments.Create(ctx, entity.Payment{BookingID: ids[0], Amount: "150000", Status: "Paid"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, bookings.UpdateBookingStatus(ctx, strconv.Itoa(ids[0]), "Completed"))
+
+	report, err := bookings.GetBookingReport(ctx, time.Date(2030, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2030, 4, 6, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.TotalBooking)
+	assert.Equal(t, 150000.0, report.TotalRevenue)
+	assert.Contains(t, report.Status, entity.BookingStatusDetail{BookingStatus: "Completed", BookingCount: 1, Revenue: 150000})
+	assert.Contains(t, report.Status, entity.BookingStatusDetail{BookingStatus: "Pending", BookingCount: 1})
+}
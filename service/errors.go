@@ -0,0 +1,18 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"gorm.io/gorm"
+)
+
+// notFound mengubah record yang tidak ditemukan menjadi appErr. Error
+// database lain dikembalikan apa adanya sehingga tetap dijawab 500, bukan
+// 404.
+func notFound(err error, appErr *apperror.Error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return appErr.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package service
+
+import "github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+
+// Spec list setiap endpoint: kolom yang boleh dipakai di sort= dan filter
+// query string yang diizinkan. Controller memakainya untuk listquery.Parse
+// dan dokumentasi OpenAPI untuk parameter query.
+
+var (
+	fieldCreatedAt = listquery.Field{Name: "created_at", Kind: listquery.Time}
+	fieldStatus    = listquery.Field{Name: "status", Kind: listquery.String}
+	fieldUserID    = listquery.Field{Name: "user_id", Kind: listquery.Int}
+	fieldServiceID = listquery.Field{Name: "service_id", Kind: listquery.Int}
+	fieldBookingID = listquery.Field{Name: "booking_id", Kind: listquery.Int}
+	fieldName      = listquery.Field{Name: "name", Kind: listquery.String}
+	fieldCost      = listquery.Field{Name: "cost", Kind: listquery.Int}
+	fieldDate      = listquery.Field{Name: "date", Kind: listquery.Time}
+	fieldRating    = listquery.Field{Name: "rating", Kind: listquery.Int}
+)
+
+// createdBetween adalah filter date_from dan date_to pada created_at.
+var createdBetween = []listquery.Filter{
+	{Param: "date_from", Field: fieldCreatedAt, Op: listquery.Gte},
+	{Param: "date_to", Field: fieldCreatedAt, Op: listquery.Lte},
+}
+
+var UserListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID, fieldName, fieldCreatedAt},
+	DefaultSort: "id",
+	Filters: []listquery.Filter{
+		{Param: "role", Field: listquery.Field{Name: "role", Kind: listquery.String}, Op: listquery.Eq},
+	},
+}
+
+// ServiceListSpec juga dipakai pencarian service; min_price dan max_price
+// inklusif.
+var ServiceListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID, fieldName, fieldCost, fieldCreatedAt},
+	DefaultSort: "id",
+	Filters: []listquery.Filter{
+		{Param: "user_id", Field: fieldUserID, Op: listquery.Eq},
+		{Param: "min_price", Field: fieldCost, Op: listquery.Gte},
+		{Param: "max_price", Field: fieldCost, Op: listquery.Lte},
+	},
+}
+
+// BookingListSpec memfilter date_from dan date_to pada tanggal kunjungan.
+var BookingListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID, fieldDate, fieldCreatedAt},
+	DefaultSort: "id",
+	Filters: []listquery.Filter{
+		{Param: "status", Field: fieldStatus, Op: listquery.Eq},
+		{Param: "user_id", Field: fieldUserID, Op: listquery.Eq},
+		{Param: "service_id", Field: fieldServiceID, Op: listquery.Eq},
+		{Param: "date_from", Field: fieldDate, Op: listquery.Gte},
+		{Param: "date_to", Field: fieldDate, Op: listquery.Lte},
+	},
+}
+
+var PaymentListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID, fieldCreatedAt},
+	DefaultSort: "id",
+	Filters: append([]listquery.Filter{
+		{Param: "status", Field: fieldStatus, Op: listquery.Eq},
+		{Param: "booking_id", Field: fieldBookingID, Op: listquery.Eq},
+	}, createdBetween...),
+}
+
+var ReviewListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID, fieldRating, fieldCreatedAt},
+	DefaultSort: "id",
+	Filters: append([]listquery.Filter{
+		{Param: "rating", Field: fieldRating, Op: listquery.Eq},
+		{Param: "booking_id", Field: fieldBookingID, Op: listquery.Eq},
+	}, createdBetween...),
+}
+
+// ApplicationListSpec mengurutkan pengajuan terlama lebih dulu, sesuai
+// urutan antrean review admin.
+var ApplicationListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID, fieldCreatedAt},
+	DefaultSort: "created_at",
+	Filters: []listquery.Filter{
+		{Param: "status", Field: fieldStatus, Op: listquery.Eq},
+		{Param: "user_id", Field: fieldUserID, Op: listquery.Eq},
+	},
+}
+
+var OutboxEventListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID},
+	DefaultSort: "-id",
+	Filters: []listquery.Filter{
+		{Param: "status", Field: fieldStatus, Op: listquery.Eq},
+		{Param: "type", Field: listquery.Field{Name: "type", Kind: listquery.String}, Op: listquery.Eq},
+	},
+}
+
+var WebhookEndpointListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID},
+	DefaultSort: "id",
+}
+
+var WebhookDeliveryListSpec = listquery.Spec{
+	Sorts:       []listquery.Field{listquery.ID},
+	DefaultSort: "-id",
+	Filters: []listquery.Filter{
+		{Param: "status", Field: fieldStatus, Op: listquery.Eq},
+		{Param: "event_type", Field: listquery.Field{Name: "event_type", Kind: listquery.String}, Op: listquery.Eq},
+	},
+}
+
+// MessageListSpec mengambil pesan terbaru lebih dulu.
+var MessageListSpec = listquery.Spec{
+	Sorts:        []listquery.Field{listquery.ID},
+	DefaultSort:  "-id",
+	DefaultLimit: 20,
+}
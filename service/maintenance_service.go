@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+const (
+	// Booking Pending yang belum dikonfirmasi teknisi dalam 48 jam dianggap kedaluwarsa
+	bookingPendingTTL = 48 * time.Hour
+	// Payment Pending yang belum dibayar dalam 24 jam dianggap kedaluwarsa
+	paymentPendingTTL = 24 * time.Hour
+	// Booking In Progress otomatis selesai satu hari setelah tanggal kunjungan
+	autoCompleteAfter = 24 * time.Hour
+	// Permintaan review dikirim 2 jam setelah booking selesai, paling lambat 7 hari
+	reviewRequestDelay  = 2 * time.Hour
+	reviewRequestWindow = 7 * 24 * time.Hour
+)
+
+// JobEnqueuer menjadwalkan job sekali jalan; diimplementasikan oleh scheduler.
+type JobEnqueuer interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time, uniqueKey string) error
+}
+
+// BookingJobPayload adalah payload job yang hanya membutuhkan ID booking.
+type BookingJobPayload struct {
+	BookingID int `json:"booking_id"`
+}
+
+// MaintenanceService berisi aturan berbasis waktu yang dijalankan oleh scheduler.
+type MaintenanceService interface {
+	ExpireStaleBookings(ctx context.Context, now time.Time) (int, error)
+	ExpireStalePayments(ctx context.Context, now time.Time) (int, error)
+	AutoCompleteBookings(ctx context.Context, now time.Time) (int, error)
+	ScheduleVisitReminders(ctx context.Context, now time.Time) (int, error)
+	SendVisitReminder(ctx context.Context, bookingID int) error
+	ScheduleReviewRequests(ctx context.Context, now time.Time) (int, error)
+	SendReviewRequest(ctx context.Context, bookingID int) error
+	PurgeExpired(ctx context.Context, before time.Time) (int, int, error)
+}
+
+type maintenanceService struct {
+	bookingRepo repository.BookingRepository
+	paymentRepo repository.PaymentRepository
+	reviewRepo  repository.ReviewRepository
+	outboxRepo  repository.OutboxRepository
+	uow         repository.UnitOfWork
+	jobs        JobEnqueuer
+}
+
+func NewMaintenanceService(bookingRepo repository.BookingRepository, paymentRepo repository.PaymentRepository, reviewRepo repository.ReviewRepository, outboxRepo repository.OutboxRepository, uow repository.UnitOfWork, jobs JobEnqueuer) MaintenanceService {
+	return &maintenanceService{
+		bookingRepo: bookingRepo,
+		paymentRepo: paymentRepo,
+		reviewRepo:  reviewRepo,
+		outboxRepo:  outboxRepo,
+		uow:         uow,
+		jobs:        jobs,
+	}
+}
+
+func (s *maintenanceService) ExpireStaleBookings(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.ExpireStaleBookings")
+	defer end(&err)
+
+	today := now.UTC().Truncate(24 * time.Hour)
+	bookings, err := s.bookingRepo.FindStalePending(ctx, now.Add(-bookingPendingTTL), today)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.transitionBookings(ctx, bookings, "Pending", "Expired")
+}
+
+func (s *maintenanceService) ExpireStalePayments(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.ExpireStalePayments")
+	defer end(&err)
+
+	payments, err := s.paymentRepo.FindPendingBefore(ctx, now.Add(-paymentPendingTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, payment := range payments {
+		ok, err := s.paymentRepo.TransitionStatus(ctx, payment.ID, "Pending", "Expired", paymentEvent("payment.expired"))
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+			recordPaymentStatus("Expired", payment.Amount)
+		}
+	}
+
+	return expired, nil
+}
+
+func (s *maintenanceService) AutoCompleteBookings(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.AutoCompleteBookings")
+	defer end(&err)
+
+	cutoff := now.Add(-autoCompleteAfter).UTC().Truncate(24 * time.Hour)
+	bookings, err := s.bookingRepo.FindByStatusBeforeDate(ctx, "In Progress", cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.transitionBookings(ctx, bookings, "In Progress", "Completed")
+}
+
+// ScheduleVisitReminders menjadwalkan satu job pengingat untuk setiap booking
+// Confirmed yang dikunjungi besok. Unique key mencegah pengingat ganda.
+func (s *maintenanceService) ScheduleVisitReminders(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.ScheduleVisitReminders")
+	defer end(&err)
+
+	tomorrow := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	bookings, err := s.bookingRepo.FindByStatusAndDate(ctx, "Confirmed", tomorrow)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, booking := range bookings {
+		key := "visit_reminder:" + strconv.Itoa(booking.ID) + ":" + tomorrow.Format("2006-01-02")
+		if err := s.jobs.Enqueue(ctx, "visit_reminder", BookingJobPayload{BookingID: booking.ID}, now, key); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(bookings), nil
+}
+
+func (s *maintenanceService) SendVisitReminder(ctx context.Context, bookingID int) (err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.SendVisitReminder")
+	defer end(&err)
+
+	booking, err := s.bookingRepo.FindByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	// Booking mungkin sudah dibatalkan setelah pengingat dijadwalkan
+	if booking.Status != "Confirmed" {
+		return nil
+	}
+
+	return s.outboxRepo.Append(ctx, event.Event{Type: "booking.reminder", Recipients: bookingRecipients(booking), Data: toBookingRes(booking)})
+}
+
+// ScheduleReviewRequests menjadwalkan permintaan review untuk booking yang sudah
+// selesai tetapi belum direview.
+func (s *maintenanceService) ScheduleReviewRequests(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.ScheduleReviewRequests")
+	defer end(&err)
+
+	bookings, err := s.bookingRepo.FindCompletedWithoutReview(ctx, now.Add(-reviewRequestWindow), now.Add(-reviewRequestDelay))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, booking := range bookings {
+		key := "review_request:" + strconv.Itoa(booking.ID)
+		if err := s.jobs.Enqueue(ctx, "review_request", BookingJobPayload{BookingID: booking.ID}, now, key); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(bookings), nil
+}
+
+func (s *maintenanceService) SendReviewRequest(ctx context.Context, bookingID int) (err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.SendReviewRequest")
+	defer end(&err)
+
+	reviewed, err := s.reviewRepo.ExistsByBookingID(ctx, bookingID)
+	if err != nil || reviewed {
+		return err
+	}
+
+	booking, err := s.bookingRepo.FindByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	return s.outboxRepo.Append(ctx, event.Event{Type: "review.requested", Recipients: map[string]int{"customer": booking.UserID}, Data: toBookingRes(booking)})
+}
+
+// transitionBookings mengubah status booking satu per satu; event hanya dicatat
+// untuk booking yang berhasil diubah.
+func (s *maintenanceService) transitionBookings(ctx context.Context, bookings []entity.Booking, from, to string) (int, error) {
+	changed := 0
+	for _, booking := range bookings {
+		ok, err := s.bookingRepo.TransitionStatus(ctx, booking.ID, from, to, bookingEvent(event.StatusType("booking", to)))
+		if err != nil {
+			return changed, err
+		}
+		if ok {
+			changed++
+			recordBookingStatus(to)
+		}
+	}
+
+	return changed, nil
+}
+
+// PurgeExpired menghapus booking dan payment berstatus Expired yang terakhir
+// diubah sebelum batas waktu. Booking dihapus beserta payment, review dan
+// pesannya. Mengembalikan jumlah booking dan payment yang dihapus.
+func (s *maintenanceService) PurgeExpired(ctx context.Context, before time.Time) (_ int, _ int, err error) {
+	ctx, end := startSpan(ctx, "MaintenanceService.PurgeExpired")
+	defer end(&err)
+
+	var bookings, payments int
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		bookingIDs, err := repos.Bookings.FindIDsByStatusUpdatedBefore(ctx, "Expired", before)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Payments.DeleteByBookingIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Reviews.DeleteByBookingIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Messages.DeleteByBookingIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Bookings.DeleteByIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+
+		deleted, err := repos.Payments.DeleteByStatusUpdatedBefore(ctx, "Expired", before)
+		if err != nil {
+			return err
+		}
+
+		bookings, payments = len(bookingIDs), int(deleted)
+		return nil
+	})
+	return bookings, payments, err
+}
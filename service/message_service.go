@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+var (
+	ErrConversationForbidden = apperror.Forbidden("conversation_forbidden", "you don't have access to this booking conversation")
+	ErrMessageEmpty          = apperror.Validation("message_empty", "message body or attachment is required")
+	ErrMessageTooLong        = apperror.Validation("message_too_long", "message body must not exceed 2000 characters")
+	ErrAttachmentNotFound    = apperror.NotFound("attachment_not_found", "attachment not found")
+)
+
+const maxMessageLength = 2000
+
+type MessageService interface {
+	SendMessage(ctx context.Context, bookingID, senderID int, role string, req *entity.CreateMessageReq, attachment string) (*entity.MessageRes, error)
+	GetMessages(ctx context.Context, bookingID, userID int, role string, q listquery.Query) (listquery.Page[entity.MessageRes], error)
+	MarkAsRead(ctx context.Context, bookingID, userID int, role string) (int64, error)
+	GetUnreadCounts(ctx context.Context, userID int) ([]entity.UnreadCount, error)
+	GetAttachment(ctx context.Context, bookingID, messageID, userID int, role string) (string, error)
+	CheckAccess(ctx context.Context, bookingID, userID int, role string) error
+}
+
+type messageService struct {
+	messageRepo repository.MessageRepository
+	bookingRepo repository.BookingRepository
+}
+
+func NewMessageService(messageRepo repository.MessageRepository, bookingRepo repository.BookingRepository) MessageService {
+	return &messageService{messageRepo: messageRepo, bookingRepo: bookingRepo}
+}
+
+func (s *messageService) SendMessage(ctx context.Context, bookingID, senderID int, role string, req *entity.CreateMessageReq, attachment string) (_ *entity.MessageRes, err error) {
+	ctx, end := startSpan(ctx, "MessageService.SendMessage")
+	defer end(&err)
+
+	participants, err := s.participants(ctx, bookingID, senderID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" && attachment == "" {
+		return nil, ErrMessageEmpty
+	}
+	if len(body) > maxMessageLength {
+		return nil, ErrMessageTooLong
+	}
+
+	message := &entity.Message{
+		BookingID:  bookingID,
+		SenderID:   senderID,
+		Body:       body,
+		Attachment: attachment,
+	}
+
+	err = s.messageRepo.Create(ctx, message, func(message entity.Message) []event.Event {
+		return []event.Event{{Type: "message.created", Recipients: participants, Data: toMessageRes(message)}}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messageRes := toMessageRes(*message)
+	return &messageRes, nil
+}
+
+func (s *messageService) GetMessages(ctx context.Context, bookingID, userID int, role string, q listquery.Query) (_ listquery.Page[entity.MessageRes], err error) {
+	ctx, end := startSpan(ctx, "MessageService.GetMessages")
+	defer end(&err)
+
+	if _, err := s.participants(ctx, bookingID, userID, role); err != nil {
+		return listquery.Page[entity.MessageRes]{}, err
+	}
+
+	page, err := s.messageRepo.FindByBookingID(ctx, bookingID, q)
+	if err != nil {
+		return listquery.Page[entity.MessageRes]{}, err
+	}
+	return listquery.Map(page, toMessageRes), nil
+}
+
+func (s *messageService) MarkAsRead(ctx context.Context, bookingID, userID int, role string) (_ int64, err error) {
+	ctx, end := startSpan(ctx, "MessageService.MarkAsRead")
+	defer end(&err)
+
+	participants, err := s.participants(ctx, bookingID, userID, role)
+	if err != nil {
+		return 0, err
+	}
+	// Admin yang hanya melihat percakapan bukan penerima pesan, jadi tidak
+	// menandai pesan dibaca atau mengirim read receipt
+	if _, viewer := participants["admin"]; viewer {
+		return 0, nil
+	}
+
+	// Read receipt untuk peserta lain, hanya dikirim jika ada pesan yang berubah
+	readAt := time.Now()
+	receipt := event.Event{Type: "message.read", Recipients: participants, Data: map[string]interface{}{
+		"booking_id": bookingID,
+		"reader_id":  userID,
+		"read_at":    readAt,
+	}}
+
+	return s.messageRepo.MarkAsRead(ctx, bookingID, userID, readAt, []event.Event{receipt})
+}
+
+func (s *messageService) GetUnreadCounts(ctx context.Context, userID int) (_ []entity.UnreadCount, err error) {
+	ctx, end := startSpan(ctx, "MessageService.GetUnreadCounts")
+	defer end(&err)
+
+	counts, err := s.messageRepo.GetUnreadCounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if counts == nil {
+		counts = []entity.UnreadCount{}
+	}
+	return counts, nil
+}
+
+func (s *messageService) GetAttachment(ctx context.Context, bookingID, messageID, userID int, role string) (_ string, err error) {
+	ctx, end := startSpan(ctx, "MessageService.GetAttachment")
+	defer end(&err)
+
+	if _, err := s.participants(ctx, bookingID, userID, role); err != nil {
+		return "", err
+	}
+
+	message, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return "", notFound(err, ErrAttachmentNotFound)
+	}
+	if message.BookingID != bookingID || message.Attachment == "" {
+		return "", ErrAttachmentNotFound
+	}
+
+	return message.Attachment, nil
+}
+
+func (s *messageService) CheckAccess(ctx context.Context, bookingID, userID int, role string) (err error) {
+	ctx, end := startSpan(ctx, "MessageService.CheckAccess")
+	defer end(&err)
+
+	_, err = s.participants(ctx, bookingID, userID, role)
+	return err
+}
+
+// participants mengecek akses user ke percakapan booking dan mengembalikan
+// user yang perlu menerima event: customer, technician dan admin yang sedang
+// mengakses percakapan.
+func (s *messageService) participants(ctx context.Context, bookingID, userID int, role string) (map[string]int, error) {
+	booking, err := s.bookingRepo.FindByID(ctx, bookingID)
+	if err != nil {
+		return nil, notFound(err, ErrBookingNotFound)
+	}
+
+	customerID := booking.UserID
+	technicianID := booking.Service.UserID
+
+	if role != "admin" && userID != customerID && userID != technicianID {
+		return nil, ErrConversationForbidden
+	}
+
+	participants := map[string]int{"customer": customerID, "technician": technicianID}
+	if userID != customerID && userID != technicianID {
+		participants["admin"] = userID
+	}
+
+	return participants, nil
+}
+
+func toMessageRes(message entity.Message) entity.MessageRes {
+	return entity.MessageRes{
+		ID:            message.ID,
+		BookingID:     message.BookingID,
+		SenderID:      message.SenderID,
+		Body:          message.Body,
+		HasAttachment: message.Attachment != "",
+		ReadAt:        message.ReadAt,
+		CreatedAt:     message.CreatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/metrics"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+// Helper berikut memperbarui metrik bisnis Prometheus. Semuanya dipanggil
+// setelah perubahan berhasil disimpan (dan transaksi di-commit) agar metrik
+// tidak menghitung perubahan yang di-rollback.
+
+func recordBookingCreated(booking entity.Booking) {
+	metrics.BookingsCreated.WithLabelValues(booking.Status).Inc()
+}
+
+func recordBookingStatus(status string) {
+	if status != "" {
+		metrics.BookingStatusChanges.WithLabelValues(status).Inc()
+	}
+}
+
+func recordPaymentCreated(payment entity.Payment) {
+	metrics.PaymentsCreated.WithLabelValues(payment.Status).Inc()
+	metrics.AddPaymentAmount(payment.Status, payment.Amount)
+}
+
+func recordPaymentStatus(status, amount string) {
+	metrics.PaymentStatusChanges.WithLabelValues(status).Inc()
+	metrics.AddPaymentAmount(status, amount)
+}
+
+func recordReviewCreated(review entity.Review) {
+	metrics.ReviewsCreated.WithLabelValues(strconv.Itoa(review.Rating)).Inc()
+}
+
+// refreshActiveTechnicians menghitung ulang gauge technician aktif. Kegagalan
+// hanya dicatat di log karena perubahan utamanya sudah tersimpan.
+func refreshActiveTechnicians(ctx context.Context, users repository.UserRepository) {
+	if err := metrics.RefreshActiveTechnicians(ctx, users); err != nil {
+		slog.WarnContext(ctx, "metrics: failed to refresh active technicians", "error", err)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/notification"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+var (
+	ErrUnknownEventType = apperror.Validation("unknown_event_type", "unknown event type")
+	ErrUnknownChannel   = apperror.Validation("unknown_channel", "unknown channel")
+)
+
+type NotificationPreferenceService interface {
+	GetPreferences(ctx context.Context, userID int) ([]entity.NotificationPreferenceRes, error)
+	UpdatePreferences(ctx context.Context, userID int, req *entity.UpdateNotificationPreferencesReq) ([]entity.NotificationPreferenceRes, error)
+}
+
+type notificationPreferenceService struct {
+	repo repository.NotificationPreferenceRepository
+}
+
+func NewNotificationPreferenceService(repo repository.NotificationPreferenceRepository) NotificationPreferenceService {
+	return &notificationPreferenceService{repo}
+}
+
+// GetPreferences mengembalikan status setiap kombinasi event dan channel,
+// termasuk yang belum pernah diatur (menggunakan nilai default).
+func (s *notificationPreferenceService) GetPreferences(ctx context.Context, userID int) (_ []entity.NotificationPreferenceRes, err error) {
+	ctx, end := startSpan(ctx, "NotificationPreferenceService.GetPreferences")
+	defer end(&err)
+
+	preferences, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var preferenceRes []entity.NotificationPreferenceRes
+	for _, eventType := range notification.EventTypes() {
+		for _, channel := range notification.ChannelNames() {
+			preferenceRes = append(preferenceRes, entity.NotificationPreferenceRes{
+				EventType: eventType,
+				Channel:   channel,
+				Enabled:   notification.IsEnabled(preferences, eventType, channel),
+			})
+		}
+	}
+
+	return preferenceRes, nil
+}
+
+func (s *notificationPreferenceService) UpdatePreferences(ctx context.Context, userID int, req *entity.UpdateNotificationPreferencesReq) (_ []entity.NotificationPreferenceRes, err error) {
+	ctx, end := startSpan(ctx, "NotificationPreferenceService.UpdatePreferences")
+	defer end(&err)
+
+	validEvents := make(map[string]bool)
+	for _, eventType := range notification.EventTypes() {
+		validEvents[eventType] = true
+	}
+	validChannels := make(map[string]bool)
+	for _, channel := range notification.ChannelNames() {
+		validChannels[channel] = true
+	}
+
+	var preferences []entity.NotificationPreference
+	for _, preference := range req.Preferences {
+		if !validEvents[preference.EventType] {
+			return nil, ErrUnknownEventType.WithMessage("unknown event type: " + preference.EventType)
+		}
+		if !validChannels[preference.Channel] {
+			return nil, ErrUnknownChannel.WithMessage("unknown channel: " + preference.Channel)
+		}
+
+		preferences = append(preferences, entity.NotificationPreference{
+			UserID:    userID,
+			EventType: preference.EventType,
+			Channel:   preference.Channel,
+			Enabled:   preference.Enabled,
+		})
+	}
+
+	err = s.repo.Upsert(ctx, preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPreferences(ctx, userID)
+}
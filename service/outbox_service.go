@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+var (
+	ErrEventNotFound      = apperror.NotFound("event_not_found", "event not found")
+	ErrEventNotReplayable = apperror.Conflict("event_not_replayable", "only failed events can be replayed")
+)
+
+type OutboxService interface {
+	GetEvents(ctx context.Context, q listquery.Query) (listquery.Page[entity.OutboxEventRes], error)
+	GetEventByID(ctx context.Context, id int) (*entity.OutboxEventRes, error)
+	ReplayEvent(ctx context.Context, id int) (*entity.OutboxEventRes, error)
+}
+
+type outboxService struct {
+	repo repository.OutboxRepository
+}
+
+func NewOutboxService(repo repository.OutboxRepository) OutboxService {
+	return &outboxService{repo: repo}
+}
+
+func (s *outboxService) GetEvents(ctx context.Context, q listquery.Query) (_ listquery.Page[entity.OutboxEventRes], err error) {
+	ctx, end := startSpan(ctx, "OutboxService.GetEvents")
+	defer end(&err)
+
+	page, err := s.repo.FindAll(ctx, q)
+	if err != nil {
+		return listquery.Page[entity.OutboxEventRes]{}, err
+	}
+	return listquery.Map(page, toOutboxEventRes), nil
+}
+
+func (s *outboxService) GetEventByID(ctx context.Context, id int) (_ *entity.OutboxEventRes, err error) {
+	ctx, end := startSpan(ctx, "OutboxService.GetEventByID")
+	defer end(&err)
+
+	evt, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	eventRes := toOutboxEventRes(evt)
+	return &eventRes, nil
+}
+
+// ReplayEvent mengembalikan event Failed ke antrean dispatcher. Subscriber yang
+// sebelumnya sudah berhasil memproses event tidak akan menerimanya lagi.
+func (s *outboxService) ReplayEvent(ctx context.Context, id int) (_ *entity.OutboxEventRes, err error) {
+	ctx, end := startSpan(ctx, "OutboxService.ReplayEvent")
+	defer end(&err)
+
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return nil, notFound(err, ErrEventNotFound)
+	}
+
+	replayed, err := s.repo.Replay(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !replayed {
+		return nil, ErrEventNotReplayable
+	}
+
+	return s.GetEventByID(ctx, id)
+}
+
+func toOutboxEventRes(evt entity.OutboxEvent) entity.OutboxEventRes {
+	eventRes := entity.OutboxEventRes{
+		ID:          evt.ID,
+		Type:        evt.Type,
+		Status:      evt.Status,
+		Attempts:    evt.Attempts,
+		LastError:   evt.LastError,
+		Payload:     json.RawMessage(evt.Payload),
+		OccurredAt:  evt.OccurredAt,
+		AvailableAt: evt.AvailableAt,
+		DeliveredAt: evt.DeliveredAt,
+	}
+	if evt.Payload == "" {
+		eventRes.Payload = json.RawMessage("null")
+	}
+	_ = json.Unmarshal([]byte(evt.Recipients), &eventRes.Recipients)
+	return eventRes
+}
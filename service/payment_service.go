@@ -1,66 +1,138 @@
 package service
 
 import (
-	"errors"
+	"context"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
 )
 
+var (
+	ErrPaymentNotFound      = apperror.NotFound("payment_not_found", "payment not found")
+	ErrBookingNotPayable    = apperror.BusinessRule("booking_not_payable", "cannot create payment for this booking")
+	ErrInvalidPaymentStatus = apperror.Validation("invalid_payment_status", "invalid status")
+)
+
 type PaymentService interface {
-	CreatePayment(req entity.CreatePaymentReq) (entity.Payment, error)
-	GetPaymentByID(id int) (entity.Payment, error)
-	UpdatePayment(req entity.UpdatePaymentReq) (entity.Payment, error)
-	DeletePayment(id int) error
-	GetAllPayments(limit, offset int) ([]entity.Payment, error)
-	UpdatePaymentStatus(paymentID string, status string) error
-	GetPaymentReport(startDate, endDate time.Time, serviceID int) (entity.PaymentReport, error)
+	CreatePayment(ctx context.Context, req entity.CreatePaymentReq) (entity.Payment, error)
+	GetPaymentByID(ctx context.Context, id int) (entity.Payment, error)
+	UpdatePayment(ctx context.Context, req entity.UpdatePaymentReq) (entity.Payment, error)
+	DeletePayment(ctx context.Context, id int) error
+	GetAllPayments(ctx context.Context, q listquery.Query) (listquery.Page[entity.Payment], error)
+	UpdatePaymentStatus(ctx context.Context, paymentID string, status string) error
+	GetPaymentReport(ctx context.Context, startDate, endDate time.Time, serviceID int) (entity.PaymentReport, error)
 }
 
 type paymentService struct {
 	repo repository.PaymentRepository
+	uow  repository.UnitOfWork
 }
 
-func NewPaymentService(repo repository.PaymentRepository) PaymentService {
-	return &paymentService{repo}
+func NewPaymentService(repo repository.PaymentRepository, uow repository.UnitOfWork) PaymentService {
+	return &paymentService{repo: repo, uow: uow}
 }
 
-func (s *paymentService) CreatePayment(req entity.CreatePaymentReq) (entity.Payment, error) {
+func (s *paymentService) CreatePayment(ctx context.Context, req entity.CreatePaymentReq) (_ entity.Payment, err error) {
+	ctx, end := startSpan(ctx, "PaymentService.CreatePayment")
+	defer end(&err)
+
 	payment := entity.Payment{
 		BookingID: req.BookingID,
 		Amount:    req.Amount,
 		Status:    "Pending", // Default status
 	}
-	return s.repo.Create(payment)
+
+	// Cek booking, simpan payment dan konfirmasi booking Pending dalam satu
+	// transaksi: booking tidak dibatalkan di antara pengecekan dan penyimpanan,
+	// dan payment tidak tersimpan tanpa booking ikut dikonfirmasi
+	confirmed := false
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		booking, err := repos.Bookings.FindByID(ctx, req.BookingID)
+		if err != nil {
+			return notFound(err, ErrBookingNotFound)
+		}
+		if booking.Status == "Cancelled" || booking.Status == "Expired" {
+			return ErrBookingNotPayable.WithMessage("cannot create payment for a " + strings.ToLower(booking.Status) + " booking")
+		}
+
+		payment, err = repos.Payments.Create(ctx, payment, paymentEvent("payment.created"))
+		if err != nil {
+			return err
+		}
+		confirmed, err = repos.Bookings.TransitionStatus(ctx, booking.ID, "Pending", "Confirmed", bookingEvent(event.StatusType("booking", "Confirmed")))
+		return err
+	})
+	if err == nil {
+		recordPaymentCreated(payment)
+		if confirmed {
+			recordBookingStatus("Confirmed")
+		}
+	}
+	return payment, err
 }
 
-func (s *paymentService) GetPaymentByID(id int) (entity.Payment, error) {
-	return s.repo.FindByID(id)
+func (s *paymentService) GetPaymentByID(ctx context.Context, id int) (_ entity.Payment, err error) {
+	ctx, end := startSpan(ctx, "PaymentService.GetPaymentByID")
+	defer end(&err)
+
+	payment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return payment, notFound(err, ErrPaymentNotFound)
+	}
+	return payment, nil
 }
 
-func (s *paymentService) UpdatePayment(req entity.UpdatePaymentReq) (entity.Payment, error) {
-	payment, err := s.repo.FindByID(req.ID)
+func (s *paymentService) UpdatePayment(ctx context.Context, req entity.UpdatePaymentReq) (_ entity.Payment, err error) {
+	ctx, end := startSpan(ctx, "PaymentService.UpdatePayment")
+	defer end(&err)
+
+	payment, err := s.repo.FindByID(ctx, req.ID)
 	if err != nil {
-		return payment, err
+		return payment, notFound(err, ErrPaymentNotFound)
 	}
 
+	statusChanged := payment.Status != req.Status
+
 	payment.BookingID = req.BookingID
 	payment.Amount = req.Amount
 	payment.Status = req.Status
 
-	return s.repo.Update(payment)
+	var events repository.PaymentEvents
+	if statusChanged {
+		events = paymentEvent(event.StatusType("payment", payment.Status))
+	}
+
+	payment, err = s.repo.Update(ctx, payment, events)
+	if err == nil && statusChanged {
+		recordPaymentStatus(payment.Status, payment.Amount)
+	}
+	return payment, err
 }
 
-func (s *paymentService) DeletePayment(id int) error {
-	return s.repo.Delete(id)
+func (s *paymentService) DeletePayment(ctx context.Context, id int) (err error) {
+	ctx, end := startSpan(ctx, "PaymentService.DeletePayment")
+	defer end(&err)
+
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *paymentService) GetAllPayments(limit, offset int) ([]entity.Payment, error) {
-	return s.repo.FindAll(limit, offset)
+func (s *paymentService) GetAllPayments(ctx context.Context, q listquery.Query) (_ listquery.Page[entity.Payment], err error) {
+	ctx, end := startSpan(ctx, "PaymentService.GetAllPayments")
+	defer end(&err)
+
+	return s.repo.FindAll(ctx, q)
 }
 
-func (s *paymentService) UpdatePaymentStatus(paymentID string, status string) error {
+func (s *paymentService) UpdatePaymentStatus(ctx context.Context, paymentID string, status string) (err error) {
+	ctx, end := startSpan(ctx, "PaymentService.UpdatePaymentStatus")
+	defer end(&err)
+
 	// Validasi status yang diperbolehkan
 	allowedStatuses := map[string]bool{
 		"Paid":     true,
@@ -70,42 +142,60 @@ func (s *paymentService) UpdatePaymentStatus(paymentID string, status string) er
 	}
 
 	if !allowedStatuses[status] {
-		return errors.New("invalid status")
+		return ErrInvalidPaymentStatus
 	}
 
-	return s.repo.UpdatePaymentStatus(paymentID, status)
+	id, err := strconv.Atoi(paymentID)
+	if err != nil {
+		return apperror.InvalidParameter("invalid payment ID")
+	}
+
+	payment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrPaymentNotFound)
+	}
+
+	err = s.repo.UpdatePaymentStatus(ctx, paymentID, status, paymentEvent(event.StatusType("payment", status)))
+	if err == nil {
+		recordPaymentStatus(status, payment.Amount)
+	}
+	return err
 }
 
-func (s *paymentService) GetPaymentReport(startDate, endDate time.Time, serviceID int) (entity.PaymentReport, error) {
+func (s *paymentService) GetPaymentReport(ctx context.Context, startDate, endDate time.Time, serviceID int) (_ entity.PaymentReport, err error) {
+	ctx, end := startSpan(ctx, "PaymentService.GetPaymentReport")
+	defer end(&err)
+
 	// Ambil total pembayaran
-	totalPayment, err := s.repo.GetTotalPayments(startDate, endDate, serviceID)
+	totalPayment, err := s.repo.GetTotalPayments(ctx, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.PaymentReport{}, err
 	}
 
 	// Ambil total jumlah uang
-	totalAmount, err := s.repo.GetTotalAmount(startDate, endDate, serviceID)
+	totalAmount, err := s.repo.GetTotalAmount(ctx, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.PaymentReport{}, err
 	}
 
-	// Ambil jumlah dan total uang untuk setiap status
-	paidCount, paidAmount, err := s.repo.GetPaymentsByStatus("paid", startDate, endDate, serviceID)
+	// Ambil jumlah dan total uang untuk setiap status. Status ditulis persis
+	// seperti yang disimpan karena PostgreSQL dan SQLite membedakan huruf besar
+	paidCount, paidAmount, err := s.repo.GetPaymentsByStatus(ctx, "Paid", startDate, endDate, serviceID)
 	if err != nil {
 		return entity.PaymentReport{}, err
 	}
 
-	pendingCount, pendingAmount, err := s.repo.GetPaymentsByStatus("pending", startDate, endDate, serviceID)
+	pendingCount, pendingAmount, err := s.repo.GetPaymentsByStatus(ctx, "Pending", startDate, endDate, serviceID)
 	if err != nil {
 		return entity.PaymentReport{}, err
 	}
 
-	refundedCount, refundedAmount, err := s.repo.GetPaymentsByStatus("refunded", startDate, endDate, serviceID)
+	refundedCount, refundedAmount, err := s.repo.GetPaymentsByStatus(ctx, "Refunded", startDate, endDate, serviceID)
 	if err != nil {
 		return entity.PaymentReport{}, err
 	}
 
-	failedCount, failedAmount, err := s.repo.GetPaymentsByStatus("failed", startDate, endDate, serviceID)
+	failedCount, failedAmount, err := s.repo.GetPaymentsByStatus(ctx, "Failed", startDate, endDate, serviceID)
 	if err != nil {
 		return entity.PaymentReport{}, err
 	}
@@ -140,3 +230,18 @@ func (s *paymentService) GetPaymentReport(startDate, endDate time.Time, serviceI
 
 	return report, nil
 }
+
+// paymentEvent membentuk event berisi data payment terbaru untuk customer dan
+// technician dari booking yang dibayar.
+func paymentEvent(eventType string) repository.PaymentEvents {
+	return func(payment entity.Payment) []event.Event {
+		return []event.Event{{Type: eventType, Recipients: bookingRecipients(payment.Booking), Data: entity.PaymentRes{
+			ID:        payment.ID,
+			BookingID: payment.BookingID,
+			Amount:    payment.Amount,
+			Status:    payment.Status,
+			CreatedAt: payment.CreatedAt,
+			UpdatedAt: payment.UpdatedAt,
+		}}}
+	}
+}
@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentService_UpdatePaymentStatus(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+	payments := service.NewPaymentService(storage.Payments, storage.UnitOfWork)
+
+	booking, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	payment, err := payments.CreatePayment(ctx, entity.CreatePaymentReq{BookingID: booking.ID, Amount: "150000"})
+	require.NoError(t, err)
+	assert.Equal(t, "Pending", payment.Status)
+
+	// Booking Pending dikonfirmasi bersama pembuatan payment
+	booking, err = bookings.GetBookingByID(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Confirmed", booking.Status)
+
+	assert.EqualError(t, payments.UpdatePaymentStatus(ctx, strconv.Itoa(payment.ID), "Unknown"), "invalid status")
+	require.NoError(t, payments.UpdatePaymentStatus(ctx, strconv.Itoa(payment.ID), "Paid"))
+
+	paid, err := payments.GetPaymentByID(ctx, payment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Paid", paid.Status)
+
+	assert.ErrorIs(t, payments.UpdatePaymentStatus(ctx, "999", "Paid"), service.ErrPaymentNotFound)
+
+	// Booking yang dibatalkan tidak bisa dibayar
+	canceled, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 3, 6, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	require.NoError(t, bookings.UpdateBookingStatus(ctx, strconv.Itoa(canceled.ID), "Cancelled"))
+	_, err = payments.CreatePayment(ctx, entity.CreatePaymentReq{BookingID: canceled.ID, Amount: "150000"})
+	assert.EqualError(t, err, "cannot create payment for a cancelled booking")
+}
+
+// failingBookingTransitions membungkus unit of work sehingga perubahan status
+// booking di dalam transaksi selalu gagal.
+type failingBookingTransitions struct {
+	repository.UnitOfWork
+}
+
+type failingBookingRepo struct {
+	repository.BookingRepository
+}
+
+func (failingBookingRepo) TransitionStatus(context.Context, int, string, string, repository.BookingEvents) (bool, error) {
+	return false, errors.New("transisi gagal")
+}
+
+func (u failingBookingTransitions) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+		repos.Bookings = failingBookingRepo{repos.Bookings}
+		return fn(repos)
+	})
+}
+
+func TestPaymentService_CreatePaymentRollsBackWhenBookingUpdateFails(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+	payments := service.NewPaymentService(storage.Payments, failingBookingTransitions{storage.UnitOfWork})
+
+	booking, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	_, err = payments.CreatePayment(ctx, entity.CreatePaymentReq{BookingID: booking.ID, Amount: "150000"})
+	require.Error(t, err)
+
+	// Payment tidak tersimpan tanpa booking ikut dikonfirmasi
+	page, err := storage.Payments.FindAll(ctx, listquery.New(service.PaymentListSpec, 10))
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	booking, err = bookings.GetBookingByID(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Pending", booking.Status)
+}
+
+func TestPaymentService_GetPaymentReport(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	bookings := service.NewBookingService(storage.Bookings)
+	payments := service.NewPaymentService(storage.Payments, storage.UnitOfWork)
+
+	for i, amount := range []string{"100000", "250000"} {
+		booking, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 3, 5+i, 0, 0, 0, 0, time.UTC)})
+		require.NoError(t, err)
+		payment, err := payments.CreatePayment(ctx, entity.CreatePaymentReq{BookingID: booking.ID, Amount: amount})
+		require.NoError(t, err)
+		if i == 0 {
+			require.NoError(t, payments.UpdatePaymentStatus(ctx, strconv.Itoa(payment.ID), "Paid"))
+		}
+	}
+
+	report, err := payments.GetPaymentReport(ctx, time.Time{}, time.Time{}, svc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.TotalPayment)
+	assert.Equal(t, 350000.0, report.TotalAmount)
+	assert.Contains(t, report.Status, entity.PaymentStatusDetail{PaymentStatus: "Paid", PaymentCount: 1, Amount: 100000})
+	assert.Contains(t, report.Status, entity.PaymentStatusDetail{PaymentStatus: "Pending", PaymentCount: 1, Amount: 250000})
+
+	report, err = payments.GetPaymentReport(ctx, time.Time{}, time.Time{}, svc.ID+1)
+	require.NoError(t, err)
+	assert.Zero(t, report.TotalPayment)
+}
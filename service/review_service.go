@@ -1,19 +1,25 @@
 package service
 
 import (
+	"context"
 	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
 )
 
+var ErrReviewNotFound = apperror.NotFound("review_not_found", "review not found")
+
 type ReviewService interface {
-	CreateReview(req entity.CreateReviewReq) (entity.Review, error)
-	GetReviewByID(id int) (entity.Review, error)
-	UpdateReview(req entity.UpdateReviewReq) (entity.Review, error)
-	DeleteReview(id int) error
-	GetAllReviews(limit, offset int) ([]entity.Review, error)
-	GetReviewReport(startDate, endDate time.Time, serviceID int) (entity.ReviewReport, error)
+	CreateReview(ctx context.Context, req entity.CreateReviewReq) (entity.Review, error)
+	GetReviewByID(ctx context.Context, id int) (entity.Review, error)
+	UpdateReview(ctx context.Context, req entity.UpdateReviewReq) (entity.Review, error)
+	DeleteReview(ctx context.Context, id int) error
+	GetAllReviews(ctx context.Context, q listquery.Query) (listquery.Page[entity.Review], error)
+	GetReviewReport(ctx context.Context, startDate, endDate time.Time, serviceID int) (entity.ReviewReport, error)
 }
 
 type reviewService struct {
@@ -21,78 +27,115 @@ type reviewService struct {
 }
 
 func NewReviewService(repo repository.ReviewRepository) ReviewService {
-	return &reviewService{repo}
+	return &reviewService{repo: repo}
 }
 
-func (s *reviewService) CreateReview(req entity.CreateReviewReq) (entity.Review, error) {
+func (s *reviewService) CreateReview(ctx context.Context, req entity.CreateReviewReq) (_ entity.Review, err error) {
+	ctx, end := startSpan(ctx, "ReviewService.CreateReview")
+	defer end(&err)
+
 	review := entity.Review{
 		BookingID: req.BookingID,
 		Rating:    req.Rating,
 		Comment:   req.Comment,
 	}
-	return s.repo.Create(review)
+
+	// Beritahu technician (dan customer) bahwa ada review baru
+	review, err = s.repo.Create(ctx, review, func(review entity.Review) []event.Event {
+		return []event.Event{{Type: "review.created", Recipients: bookingRecipients(review.Booking), Data: entity.ReviewRes{
+			ID:        review.ID,
+			BookingID: review.BookingID,
+			Rating:    review.Rating,
+			Comment:   review.Comment,
+			CreatedAt: review.CreatedAt,
+			UpdatedAt: review.UpdatedAt,
+		}}}
+	})
+	if err == nil {
+		recordReviewCreated(review)
+	}
+	return review, err
 }
 
-func (s *reviewService) GetReviewByID(id int) (entity.Review, error) {
-	return s.repo.FindByID(id)
+func (s *reviewService) GetReviewByID(ctx context.Context, id int) (_ entity.Review, err error) {
+	ctx, end := startSpan(ctx, "ReviewService.GetReviewByID")
+	defer end(&err)
+
+	review, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return review, notFound(err, ErrReviewNotFound)
+	}
+	return review, nil
 }
 
-func (s *reviewService) UpdateReview(req entity.UpdateReviewReq) (entity.Review, error) {
-	review, err := s.repo.FindByID(req.ID)
+func (s *reviewService) UpdateReview(ctx context.Context, req entity.UpdateReviewReq) (_ entity.Review, err error) {
+	ctx, end := startSpan(ctx, "ReviewService.UpdateReview")
+	defer end(&err)
+
+	review, err := s.repo.FindByID(ctx, req.ID)
 	if err != nil {
-		return review, err
+		return review, notFound(err, ErrReviewNotFound)
 	}
 
 	review.BookingID = req.BookingID
 	review.Rating = req.Rating
 	review.Comment = req.Comment
 
-	return s.repo.Update(review)
+	return s.repo.Update(ctx, review)
 }
 
-func (s *reviewService) DeleteReview(id int) error {
-	return s.repo.Delete(id)
+func (s *reviewService) DeleteReview(ctx context.Context, id int) (err error) {
+	ctx, end := startSpan(ctx, "ReviewService.DeleteReview")
+	defer end(&err)
+
+	return s.repo.Delete(ctx, id)
 }
 
-func (s *reviewService) GetAllReviews(limit, offset int) ([]entity.Review, error) {
-	return s.repo.FindAll(limit, offset)
+func (s *reviewService) GetAllReviews(ctx context.Context, q listquery.Query) (_ listquery.Page[entity.Review], err error) {
+	ctx, end := startSpan(ctx, "ReviewService.GetAllReviews")
+	defer end(&err)
+
+	return s.repo.FindAll(ctx, q)
 }
 
-func (s *reviewService) GetReviewReport(startDate, endDate time.Time, serviceID int) (entity.ReviewReport, error) {
+func (s *reviewService) GetReviewReport(ctx context.Context, startDate, endDate time.Time, serviceID int) (_ entity.ReviewReport, err error) {
+	ctx, end := startSpan(ctx, "ReviewService.GetReviewReport")
+	defer end(&err)
+
 	// Ambil total review (dengan atau tanpa filter tanggal dan service_id)
-	totalReviews, err := s.repo.GetTotalReviews(startDate, endDate, serviceID)
+	totalReviews, err := s.repo.GetTotalReviews(ctx, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
 
 	// Ambil rata-rata rating (dengan atau tanpa filter tanggal dan service_id)
-	averageRating, err := s.repo.GetAverageRating(startDate, endDate, serviceID)
+	averageRating, err := s.repo.GetAverageRating(ctx, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
 
 	// Ambil jumlah review untuk setiap rating (1-5)
-	rating1Count, err := s.repo.GetReviewsByRating(1, startDate, endDate, serviceID)
+	rating1Count, err := s.repo.GetReviewsByRating(ctx, 1, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
 
-	rating2Count, err := s.repo.GetReviewsByRating(2, startDate, endDate, serviceID)
+	rating2Count, err := s.repo.GetReviewsByRating(ctx, 2, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
 
-	rating3Count, err := s.repo.GetReviewsByRating(3, startDate, endDate, serviceID)
+	rating3Count, err := s.repo.GetReviewsByRating(ctx, 3, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
 
-	rating4Count, err := s.repo.GetReviewsByRating(4, startDate, endDate, serviceID)
+	rating4Count, err := s.repo.GetReviewsByRating(ctx, 4, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
 
-	rating5Count, err := s.repo.GetReviewsByRating(5, startDate, endDate, serviceID)
+	rating5Count, err := s.repo.GetReviewsByRating(ctx, 5, startDate, endDate, serviceID)
 	if err != nil {
 		return entity.ReviewReport{}, err
 	}
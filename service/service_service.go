@@ -1,48 +1,77 @@
 package service
 
 import (
+	"context"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
 )
 
 type ServiceService interface {
-	CreateService(req entity.CreateServiceReq) (*entity.Service, error)
-	GetServiceByID(id int) (*entity.Service, error)
-	UpdateService(req entity.UpdateServiceReq) (*entity.Service, error)
-	DeleteService(id int) error
-	GetAllServices(limit, offset int) ([]entity.Service, error)
-	GetServicesByUserID(userID int) ([]entity.ServiceRes, error)
-	SearchServices(searchQuery string, minPrice, maxPrice int) ([]entity.ServiceRes, error)
-	GetServiceCostReport(startDate, endDate string) (map[string]interface{}, error)
+	CreateService(ctx context.Context, req entity.CreateServiceReq) (*entity.Service, error)
+	GetServiceByID(ctx context.Context, id int) (*entity.Service, error)
+	UpdateService(ctx context.Context, req entity.UpdateServiceReq) (*entity.Service, error)
+	DeleteService(ctx context.Context, id int) error
+	GetAllServices(ctx context.Context, q listquery.Query) (listquery.Page[entity.Service], error)
+	GetServicesByUserID(ctx context.Context, userID int, q listquery.Query) (listquery.Page[entity.ServiceRes], error)
+	SearchServices(ctx context.Context, searchQuery string, q listquery.Query) (listquery.Page[entity.ServiceRes], error)
+	GetServiceCostReport(ctx context.Context, startDate, endDate string) (map[string]interface{}, error)
 }
 
+var (
+	ErrServiceNotFound       = apperror.NotFound("service_not_found", "service not found")
+	ErrTechnicianNotVerified = apperror.Forbidden("technician_not_verified", "only approved technicians with a valid certification can create services")
+)
+
 type serviceService struct {
-	serviceRepo repository.ServiceRepository
+	serviceRepo     repository.ServiceRepository
+	applicationRepo repository.TechnicianApplicationRepository
 }
 
-func NewServiceService(serviceRepo repository.ServiceRepository) ServiceService {
-	return &serviceService{serviceRepo}
+func NewServiceService(serviceRepo repository.ServiceRepository, applicationRepo repository.TechnicianApplicationRepository) ServiceService {
+	return &serviceService{serviceRepo: serviceRepo, applicationRepo: applicationRepo}
 }
 
-func (s *serviceService) CreateService(req entity.CreateServiceReq) (*entity.Service, error) {
+func (s *serviceService) CreateService(ctx context.Context, req entity.CreateServiceReq) (_ *entity.Service, err error) {
+	ctx, end := startSpan(ctx, "ServiceService.CreateService")
+	defer end(&err)
+
+	// Hanya technician yang pengajuannya disetujui (dan sertifikasinya masih berlaku) yang boleh membuat service
+	application, err := s.applicationRepo.FindLatestByUserID(ctx, req.UserID)
+	if err != nil || !IsVerifiedTechnician(application) {
+		return nil, ErrTechnicianNotVerified
+	}
+
 	service := &entity.Service{
 		UserID:      req.UserID,
 		Name:        req.Name,
 		Description: req.Description,
 		Cost:        req.Cost,
 	}
-	err := s.serviceRepo.Create(service)
+	err = s.serviceRepo.Create(ctx, service)
 	return service, err
 }
 
-func (s *serviceService) GetServiceByID(id int) (*entity.Service, error) {
-	return s.serviceRepo.FindByID(id)
+func (s *serviceService) GetServiceByID(ctx context.Context, id int) (_ *entity.Service, err error) {
+	ctx, end := startSpan(ctx, "ServiceService.GetServiceByID")
+	defer end(&err)
+
+	service, err := s.serviceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrServiceNotFound)
+	}
+	return service, nil
 }
 
-func (s *serviceService) UpdateService(req entity.UpdateServiceReq) (*entity.Service, error) {
-	service, err := s.serviceRepo.FindByID(req.ID)
+func (s *serviceService) UpdateService(ctx context.Context, req entity.UpdateServiceReq) (_ *entity.Service, err error) {
+	ctx, end := startSpan(ctx, "ServiceService.UpdateService")
+	defer end(&err)
+
+	service, err := s.serviceRepo.FindByID(ctx, req.ID)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrServiceNotFound)
 	}
 
 	service.UserID = req.UserID
@@ -50,64 +79,51 @@ func (s *serviceService) UpdateService(req entity.UpdateServiceReq) (*entity.Ser
 	service.Description = req.Description
 	service.Cost = req.Cost
 
-	err = s.serviceRepo.Update(service)
+	err = s.serviceRepo.Update(ctx, service)
 	return service, err
 }
 
-func (s *serviceService) DeleteService(id int) error {
-	return s.serviceRepo.Delete(id)
-}
+func (s *serviceService) DeleteService(ctx context.Context, id int) (err error) {
+	ctx, end := startSpan(ctx, "ServiceService.DeleteService")
+	defer end(&err)
 
-func (s *serviceService) GetAllServices(limit, offset int) ([]entity.Service, error) {
-	return s.serviceRepo.FindAll(limit, offset)
+	return s.serviceRepo.Delete(ctx, id)
 }
 
-func (s *serviceService) GetServicesByUserID(userID int) ([]entity.ServiceRes, error) {
-	services, err := s.serviceRepo.GetServicesByUserID(userID)
-	if err != nil {
-		return nil, err
-	}
-
-	var serviceRes []entity.ServiceRes
-	for _, service := range services {
-		serviceRes = append(serviceRes, entity.ServiceRes{
-			ID:          service.ID,
-			UserID:      service.UserID,
-			Name:        service.Name,
-			Description: service.Description,
-			Cost:        service.Cost,
-			CreatedAt:   service.CreatedAt,
-			UpdatedAt:   service.UpdatedAt,
-		})
-	}
+func (s *serviceService) GetAllServices(ctx context.Context, q listquery.Query) (_ listquery.Page[entity.Service], err error) {
+	ctx, end := startSpan(ctx, "ServiceService.GetAllServices")
+	defer end(&err)
 
-	return serviceRes, nil
+	return s.serviceRepo.FindAll(ctx, q)
 }
 
-func (s *serviceService) SearchServices(searchQuery string, minPrice, maxPrice int) ([]entity.ServiceRes, error) {
-	services, err := s.serviceRepo.SearchServices(searchQuery, minPrice, maxPrice)
+func (s *serviceService) GetServicesByUserID(ctx context.Context, userID int, q listquery.Query) (_ listquery.Page[entity.ServiceRes], err error) {
+	ctx, end := startSpan(ctx, "ServiceService.GetServicesByUserID")
+	defer end(&err)
+
+	page, err := s.serviceRepo.FindAll(ctx, q.Where(fieldUserID, listquery.Eq, userID))
 	if err != nil {
-		return nil, err
+		return listquery.Page[entity.ServiceRes]{}, err
 	}
+	return listquery.Map(page, toServiceRes), nil
+}
 
-	var serviceRes []entity.ServiceRes
-	for _, service := range services {
-		serviceRes = append(serviceRes, entity.ServiceRes{
-			ID:          service.ID,
-			UserID:      service.UserID,
-			Name:        service.Name,
-			Description: service.Description,
-			Cost:        service.Cost,
-			CreatedAt:   service.CreatedAt,
-			UpdatedAt:   service.UpdatedAt,
-		})
-	}
+func (s *serviceService) SearchServices(ctx context.Context, searchQuery string, q listquery.Query) (_ listquery.Page[entity.ServiceRes], err error) {
+	ctx, end := startSpan(ctx, "ServiceService.SearchServices")
+	defer end(&err)
 
-	return serviceRes, nil
+	page, err := s.serviceRepo.SearchServices(ctx, searchQuery, q)
+	if err != nil {
+		return listquery.Page[entity.ServiceRes]{}, err
+	}
+	return listquery.Map(page, toServiceRes), nil
 }
 
-func (s *serviceService) GetServiceCostReport(startDate, endDate string) (map[string]interface{}, error) {
-	costDistribution, err := s.serviceRepo.GetServiceCostDistribution(startDate, endDate)
+func (s *serviceService) GetServiceCostReport(ctx context.Context, startDate, endDate string) (_ map[string]interface{}, err error) {
+	ctx, end := startSpan(ctx, "ServiceService.GetServiceCostReport")
+	defer end(&err)
+
+	costDistribution, err := s.serviceRepo.GetServiceCostDistribution(ctx, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -124,3 +140,15 @@ func (s *serviceService) GetServiceCostReport(startDate, endDate string) (map[st
 
 	return report, nil
 }
+
+func toServiceRes(service entity.Service) entity.ServiceRes {
+	return entity.ServiceRes{
+		ID:          service.ID,
+		UserID:      service.UserID,
+		Name:        service.Name,
+		Description: service.Description,
+		Cost:        service.Cost,
+		CreatedAt:   service.CreatedAt,
+		UpdatedAt:   service.UpdatedAt,
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+)
+
+type TechnicianApplicationService interface {
+	SubmitApplication(ctx context.Context, userID int, req *entity.RegisterAsTechnicianReq, idDocument, certificate string) (*entity.TechnicianApplicationRes, error)
+	GetMyApplication(ctx context.Context, userID int) (*entity.TechnicianApplicationRes, error)
+	GetApplicationByID(ctx context.Context, id int) (*entity.TechnicianApplication, error)
+	GetAllApplications(ctx context.Context, q listquery.Query) (listquery.Page[*entity.TechnicianApplicationRes], error)
+	StartReview(ctx context.Context, id, reviewerID int) (*entity.TechnicianApplicationRes, error)
+	ApproveApplication(ctx context.Context, id, reviewerID int, req *entity.ReviewTechnicianApplicationReq) (*entity.TechnicianApplicationRes, error)
+	RejectApplication(ctx context.Context, id, reviewerID int, req *entity.ReviewTechnicianApplicationReq) (*entity.TechnicianApplicationRes, error)
+}
+
+type technicianApplicationService struct {
+	applicationRepo repository.TechnicianApplicationRepository
+	userRepo        repository.UserRepository
+	uow             repository.UnitOfWork
+}
+
+func NewTechnicianApplicationService(applicationRepo repository.TechnicianApplicationRepository, userRepo repository.UserRepository, uow repository.UnitOfWork) TechnicianApplicationService {
+	return &technicianApplicationService{applicationRepo: applicationRepo, userRepo: userRepo, uow: uow}
+}
+
+var (
+	ErrApplicationNotFound     = apperror.NotFound("application_not_found", "technician application not found")
+	ErrAdminCannotApply        = apperror.BusinessRule("admin_cannot_apply", "admin cannot apply as technician")
+	ErrApplicationInProgress   = apperror.Conflict("application_in_progress", "technician application is already being processed")
+	ErrAlreadyTechnician       = apperror.Conflict("already_technician", "user is already a verified technician")
+	ErrCertificationExpired    = apperror.BusinessRule("certification_expired", "certification has already expired")
+	ErrRejectionReasonRequired = apperror.Validation("rejection_reason_required", "reason is required when rejecting an application")
+	ErrInvalidStatusTransition = apperror.Conflict("invalid_status_transition", "status cannot be changed from its current value")
+)
+
+// Transisi status yang diperbolehkan: Submitted -> Under Review -> Approved/Rejected
+var applicationTransitions = map[string]map[string]bool{
+	"Submitted":    {"Under Review": true},
+	"Under Review": {"Approved": true, "Rejected": true},
+}
+
+// IsVerifiedTechnician mengecek apakah pengajuan terakhir user sudah disetujui
+// dan sertifikasinya belum kedaluwarsa.
+func IsVerifiedTechnician(application *entity.TechnicianApplication) bool {
+	if application == nil || application.Status != "Approved" {
+		return false
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return !application.CertificationExpiresAt.Before(today)
+}
+
+func (s *technicianApplicationService) SubmitApplication(ctx context.Context, userID int, req *entity.RegisterAsTechnicianReq, idDocument, certificate string) (_ *entity.TechnicianApplicationRes, err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.SubmitApplication")
+	defer end(&err)
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	if user.Role == "admin" {
+		return nil, ErrAdminCannotApply
+	}
+
+	// Tolak jika masih ada pengajuan yang sedang diproses atau sudah disetujui
+	latest, err := s.applicationRepo.FindLatestByUserID(ctx, userID)
+	if err == nil {
+		if latest.Status == "Submitted" || latest.Status == "Under Review" {
+			return nil, ErrApplicationInProgress
+		}
+		if IsVerifiedTechnician(latest) {
+			return nil, ErrAlreadyTechnician
+		}
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if !req.CertificationExpiresAt.After(today) {
+		return nil, ErrCertificationExpired
+	}
+
+	application := &entity.TechnicianApplication{
+		UserID:                 userID,
+		Status:                 "Submitted", // Default status
+		Address:                req.Address,
+		Phone:                  req.Phone,
+		Expertise:              req.Expertise,
+		Availability:           req.Availability,
+		IDDocument:             idDocument,
+		Certificate:            certificate,
+		CertificationExpiresAt: req.CertificationExpiresAt,
+	}
+
+	err = s.applicationRepo.Create(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+
+	return toTechnicianApplicationRes(application), nil
+}
+
+func (s *technicianApplicationService) GetMyApplication(ctx context.Context, userID int) (_ *entity.TechnicianApplicationRes, err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.GetMyApplication")
+	defer end(&err)
+
+	application, err := s.applicationRepo.FindLatestByUserID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrApplicationNotFound)
+	}
+	return toTechnicianApplicationRes(application), nil
+}
+
+func (s *technicianApplicationService) GetApplicationByID(ctx context.Context, id int) (_ *entity.TechnicianApplication, err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.GetApplicationByID")
+	defer end(&err)
+
+	application, err := s.applicationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrApplicationNotFound)
+	}
+	return application, nil
+}
+
+func (s *technicianApplicationService) GetAllApplications(ctx context.Context, q listquery.Query) (_ listquery.Page[*entity.TechnicianApplicationRes], err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.GetAllApplications")
+	defer end(&err)
+
+	page, err := s.applicationRepo.FindAll(ctx, q)
+	if err != nil {
+		return listquery.Page[*entity.TechnicianApplicationRes]{}, err
+	}
+
+	return listquery.Map(page, func(application entity.TechnicianApplication) *entity.TechnicianApplicationRes {
+		return toTechnicianApplicationRes(&application)
+	}), nil
+}
+
+func (s *technicianApplicationService) StartReview(ctx context.Context, id, reviewerID int) (_ *entity.TechnicianApplicationRes, err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.StartReview")
+	defer end(&err)
+
+	application, err := transitionApplication(ctx, s.applicationRepo, id, reviewerID, "Under Review", "")
+	if err != nil {
+		return nil, err
+	}
+	return toTechnicianApplicationRes(application), nil
+}
+
+func (s *technicianApplicationService) ApproveApplication(ctx context.Context, id, reviewerID int, req *entity.ReviewTechnicianApplicationReq) (_ *entity.TechnicianApplicationRes, err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.ApproveApplication")
+	defer end(&err)
+
+	application, err := s.applicationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrApplicationNotFound)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if application.CertificationExpiresAt.Before(today) {
+		return nil, ErrCertificationExpired
+	}
+
+	// Status pengajuan dan role user diubah dalam satu transaksi agar
+	// pengajuan tidak tercatat Approved sementara user belum menjadi technician
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		application, err = transitionApplication(ctx, repos.TechnicianApplications, id, reviewerID, "Approved", req.Reason)
+		if err != nil {
+			return err
+		}
+
+		// Setelah disetujui, user resmi menjadi technician
+		user, err := repos.Users.FindByID(ctx, application.UserID)
+		if err != nil {
+			return notFound(err, ErrUserNotFound)
+		}
+
+		user.Role = "technician"
+		user.Address = application.Address
+		user.Phone = application.Phone
+		user.Expertise = application.Expertise
+		user.Availability = application.Availability
+		return repos.Users.Update(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	refreshActiveTechnicians(ctx, s.userRepo)
+
+	return toTechnicianApplicationRes(application), nil
+}
+
+func (s *technicianApplicationService) RejectApplication(ctx context.Context, id, reviewerID int, req *entity.ReviewTechnicianApplicationReq) (_ *entity.TechnicianApplicationRes, err error) {
+	ctx, end := startSpan(ctx, "TechnicianApplicationService.RejectApplication")
+	defer end(&err)
+
+	if req.Reason == "" {
+		return nil, ErrRejectionReasonRequired
+	}
+
+	application, err := transitionApplication(ctx, s.applicationRepo, id, reviewerID, "Rejected", req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	return toTechnicianApplicationRes(application), nil
+}
+
+func transitionApplication(ctx context.Context, applicationRepo repository.TechnicianApplicationRepository, id, reviewerID int, status, reason string) (*entity.TechnicianApplication, error) {
+	application, err := applicationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrApplicationNotFound)
+	}
+
+	if !applicationTransitions[application.Status][status] {
+		return nil, ErrInvalidStatusTransition.WithMessage("cannot change application status from " + application.Status + " to " + status)
+	}
+
+	now := time.Now()
+	application.Status = status
+	application.ReviewerID = &reviewerID
+	application.ReviewedAt = &now
+	if reason != "" {
+		application.ReviewReason = reason
+	}
+
+	err = applicationRepo.Update(ctx, application)
+	if err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+func toTechnicianApplicationRes(application *entity.TechnicianApplication) *entity.TechnicianApplicationRes {
+	return &entity.TechnicianApplicationRes{
+		ID:                     application.ID,
+		UserID:                 application.UserID,
+		Status:                 application.Status,
+		Address:                application.Address,
+		Phone:                  application.Phone,
+		Expertise:              application.Expertise,
+		Availability:           application.Availability,
+		CertificationExpiresAt: application.CertificationExpiresAt,
+		ReviewerID:             application.ReviewerID,
+		ReviewReason:           application.ReviewReason,
+		ReviewedAt:             application.ReviewedAt,
+		CreatedAt:              application.CreatedAt,
+		UpdatedAt:              application.UpdatedAt,
+	}
+}
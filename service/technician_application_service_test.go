@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository/memory"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applicationReq(expiresAt time.Time) *entity.RegisterAsTechnicianReq {
+	return &entity.RegisterAsTechnicianReq{
+		Address:                "Jl. Merdeka 1",
+		Phone:                  "081234567890",
+		Expertise:              "AC",
+		Availability:           "Senin-Jumat",
+		CertificationExpiresAt: expiresAt,
+	}
+}
+
+func TestTechnicianApplicationService_ApproveFlow(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	applications := service.NewTechnicianApplicationService(storage.TechnicianApplications, storage.Users, storage.UnitOfWork)
+	services := service.NewServiceService(storage.Services, storage.TechnicianApplications)
+
+	admin := entity.User{Name: "Admin", Email: "admin@example.com", Role: "admin"}
+	require.NoError(t, storage.Users.Create(ctx, &admin))
+	user := entity.User{Name: "Budi", Email: "budi@example.com", Role: "user"}
+	require.NoError(t, storage.Users.Create(ctx, &user))
+
+	_, err := applications.SubmitApplication(ctx, admin.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	assert.ErrorIs(t, err, service.ErrAdminCannotApply)
+
+	application, err := applications.SubmitApplication(ctx, user.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "Submitted", application.Status)
+	_, err = applications.SubmitApplication(ctx, user.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	assert.ErrorIs(t, err, service.ErrApplicationInProgress)
+
+	// Belum disetujui: service belum boleh dibuat dan approve harus lewat review
+	_, err = services.CreateService(ctx, entity.CreateServiceReq{UserID: user.ID, Name: "Cuci AC", Cost: 75000})
+	assert.ErrorIs(t, err, service.ErrTechnicianNotVerified)
+	_, err = applications.ApproveApplication(ctx, application.ID, admin.ID, &entity.ReviewTechnicianApplicationReq{})
+	assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+
+	_, err = applications.StartReview(ctx, application.ID, admin.ID)
+	require.NoError(t, err)
+	approved, err := applications.ApproveApplication(ctx, application.ID, admin.ID, &entity.ReviewTechnicianApplicationReq{})
+	require.NoError(t, err)
+	assert.Equal(t, "Approved", approved.Status)
+	assert.Equal(t, &admin.ID, approved.ReviewerID)
+
+	updated, err := storage.Users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "technician", updated.Role)
+
+	svc, err := services.CreateService(ctx, entity.CreateServiceReq{UserID: user.ID, Name: "Cuci AC", Cost: 75000})
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, svc.UserID)
+	_, err = applications.SubmitApplication(ctx, user.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	assert.ErrorIs(t, err, service.ErrAlreadyTechnician)
+}
+
+// failingUserUpdates membungkus unit of work sehingga update user di dalam
+// transaksi selalu gagal.
+type failingUserUpdates struct {
+	repository.UnitOfWork
+}
+
+type failingUserRepo struct {
+	repository.UserRepository
+}
+
+func (failingUserRepo) Update(context.Context, *entity.User) error {
+	return errors.New("update gagal")
+}
+
+func (u failingUserUpdates) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return u.UnitOfWork.Do(ctx, func(repos repository.Repositories) error {
+		repos.Users = failingUserRepo{repos.Users}
+		return fn(repos)
+	})
+}
+
+func TestTechnicianApplicationService_ApproveRollsBackWhenRoleUpdateFails(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	applications := service.NewTechnicianApplicationService(storage.TechnicianApplications, storage.Users, failingUserUpdates{storage.UnitOfWork})
+
+	user := entity.User{Name: "Budi", Email: "budi@example.com", Role: "user"}
+	require.NoError(t, storage.Users.Create(ctx, &user))
+	application, err := applications.SubmitApplication(ctx, user.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	require.NoError(t, err)
+	_, err = applications.StartReview(ctx, application.ID, 1)
+	require.NoError(t, err)
+
+	_, err = applications.ApproveApplication(ctx, application.ID, 1, &entity.ReviewTechnicianApplicationReq{})
+	require.Error(t, err)
+
+	// Pengajuan tetap Under Review dan role user tidak berubah
+	stored, err := storage.TechnicianApplications.FindByID(ctx, application.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Under Review", stored.Status)
+	unchanged, err := storage.Users.FindByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user", unchanged.Role)
+}
+
+func TestTechnicianApplicationService_Reject(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	applications := service.NewTechnicianApplicationService(storage.TechnicianApplications, storage.Users, storage.UnitOfWork)
+
+	user := entity.User{Name: "Budi", Email: "budi@example.com", Role: "user"}
+	require.NoError(t, storage.Users.Create(ctx, &user))
+	application, err := applications.SubmitApplication(ctx, user.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	require.NoError(t, err)
+	_, err = applications.StartReview(ctx, application.ID, 1)
+	require.NoError(t, err)
+
+	_, err = applications.RejectApplication(ctx, application.ID, 1, &entity.ReviewTechnicianApplicationReq{})
+	assert.ErrorIs(t, err, service.ErrRejectionReasonRequired)
+	rejected, err := applications.RejectApplication(ctx, application.ID, 1, &entity.ReviewTechnicianApplicationReq{Reason: "Sertifikat tidak terbaca"})
+	require.NoError(t, err)
+	assert.Equal(t, "Rejected", rejected.Status)
+
+	// Setelah ditolak user boleh mengajukan lagi
+	_, err = applications.SubmitApplication(ctx, user.ID, applicationReq(time.Now().AddDate(1, 0, 0)), "ktp.pdf", "cert.pdf")
+	assert.NoError(t, err)
+}
+
+func TestServiceService_CreateServiceRequiresValidCertification(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.NewStorage()
+	services := service.NewServiceService(storage.Services, storage.TechnicianApplications)
+
+	technician := entity.User{Name: "Teknisi", Email: "tech@example.com", Role: "technician"}
+	require.NoError(t, storage.Users.Create(ctx, &technician))
+	req := entity.CreateServiceReq{UserID: technician.ID, Name: "Cuci AC", Cost: 75000}
+
+	// Role technician saja tidak cukup tanpa pengajuan yang disetujui
+	_, err := services.CreateService(ctx, req)
+	assert.ErrorIs(t, err, service.ErrTechnicianNotVerified)
+
+	application := entity.TechnicianApplication{UserID: technician.ID, Status: "Approved", CertificationExpiresAt: time.Now().AddDate(0, 0, -1)}
+	require.NoError(t, storage.TechnicianApplications.Create(ctx, &application))
+	_, err = services.CreateService(ctx, req)
+	assert.ErrorIs(t, err, service.ErrTechnicianNotVerified)
+
+	application.CertificationExpiresAt = time.Now().UTC().Truncate(24 * time.Hour)
+	require.NoError(t, storage.TechnicianApplications.Update(ctx, &application))
+	_, err = services.CreateService(ctx, req)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tracer membuat span untuk setiap method service, dinamai
+// "<Service>.<Method>" (mis. "BookingService.CreateBooking"). Span menjadi
+// child dari span HTTP di context dan parent dari span query GORM.
+var tracer = otel.Tracer("github.com/Ayyasy123/dibimbing-capstone.git/service")
+
+// startSpan memulai span service. end menutup span dan, jika *errp berisi
+// error, mencatatnya di span dengan status Error. Method memakai named
+// return err agar error yang dikembalikan terbaca saat defer:
+//
+//	ctx, end := startSpan(ctx, "BookingService.CreateBooking")
+//	defer end(&err)
+func startSpan(ctx context.Context, name string) (context.Context, func(errp *error)) {
+	ctx, span := tracer.Start(ctx, name)
+	return ctx, func(errp *error) {
+		if errp != nil && *errp != nil {
+			span.RecordError(*errp)
+			span.SetStatus(codes.Error, (*errp).Error())
+		}
+		span.End()
+	}
+}
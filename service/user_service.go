@@ -1,42 +1,100 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
 	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
 	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
 	"github.com/Ayyasy123/dibimbing-capstone.git/utils"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+// LoginGuard mengunci akun sementara setelah login gagal berulang.
+// Diimplementasikan oleh ratelimit.Lockout.
+type LoginGuard interface {
+	// Check mengembalikan sisa waktu kunci, atau 0 jika akun boleh login.
+	Check(ctx context.Context, account string) (time.Duration, error)
+	Failed(ctx context.Context, account string) error
+	Succeeded(ctx context.Context, account string) error
+}
+
+var (
+	ErrEmailTaken         = apperror.Conflict("email_taken", "email already registered")
+	ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrUserNotFound       = apperror.NotFound("user_not_found", "user not found")
+	ErrAccountLocked      = apperror.TooManyRequests("account_locked", "too many failed login attempts, account is temporarily locked")
+	ErrInvalidLanguage    = apperror.Validation("invalid_language", "language must be id or en")
+	ErrPasswordRequired   = apperror.Validation("password_required", "password is required")
+	// Role technician hanya bisa didapat melalui pengajuan yang disetujui admin
+	ErrTechnicianRoleRequiresApplication = apperror.BusinessRule("technician_role_requires_application", "technician role is granted through an approved technician application")
+	ErrUserUpdateForbidden               = apperror.Forbidden("user_update_forbidden", "you can only update your own account")
+	ErrRoleChangeForbidden               = apperror.Forbidden("role_change_forbidden", "only admins can change roles")
+)
+
+// dummyPasswordHash adalah hash bcrypt (bcrypt.DefaultCost) yang dibandingkan
+// saat email tidak terdaftar, agar Login memakan waktu yang sama.
+var dummyPasswordHash = []byte("$2a$10$SrVkHFuqyA/vpQSxr1AoUulcvnacyvSWdesNKYmm3YG2oC5wwA5fu")
+
+// AccountLockedError dikembalikan Login selama akun terkunci. Error ini
+// membungkus ErrAccountLocked dan membawa sisa waktu kunci untuk header
+// Retry-After.
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return ErrAccountLocked.Message
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
 type UserService interface {
-	Register(req *entity.RegisterUserReq) (*entity.UserRes, error)
-	Login(req *entity.LoginUserReq) (*entity.UserRes, string, error)
-	GetUserByID(id int) (*entity.UserRes, error)
-	GetAllUsers(limit, offset int) ([]*entity.UserRes, error)
-	UpdateUser(req *entity.UpdateUserReq) (*entity.UserRes, error)
-	RegisterAsTechnician(req *entity.RegisterAsTechnicianReq) (*entity.TechnicianRes, error)
-	UpdateTechnician(req *entity.UpdateTechnicianReq) (*entity.TechnicianRes, error)
-	DeleteUser(id int) error
-	RegisterAsAdmin(req *entity.RegisterUserReq) (*entity.UserRes, error)
-	GetUserRoleReport(startDate, endDate string) (map[string]interface{}, error)
+	Register(ctx context.Context, req *entity.RegisterUserReq) (*entity.UserRes, error)
+	Login(ctx context.Context, req *entity.LoginUserReq) (*entity.UserRes, string, error)
+	GetUserByID(ctx context.Context, id int) (*entity.UserRes, error)
+	GetAllUsers(ctx context.Context, q listquery.Query) (listquery.Page[*entity.UserRes], error)
+	// UpdateUser dan UpdateTechnician dipanggil atas nama callerID dengan
+	// role callerRole; selain admin hanya boleh mengubah akunnya sendiri.
+	UpdateUser(ctx context.Context, req *entity.UpdateUserReq, callerID int, callerRole string) (*entity.UserRes, error)
+	UpdateTechnician(ctx context.Context, req *entity.UpdateTechnicianReq, callerID int, callerRole string) (*entity.TechnicianRes, error)
+	DeleteUser(ctx context.Context, id int) error
+	RegisterAsAdmin(ctx context.Context, req *entity.RegisterUserReq) (*entity.UserRes, error)
+	GetUserRoleReport(ctx context.Context, startDate, endDate string) (map[string]interface{}, error)
+	ResetPassword(ctx context.Context, email, password string) error
 }
 
 type userService struct {
 	userRepository repository.UserRepository
+	uow            repository.UnitOfWork
+	loginGuard     LoginGuard
 }
 
-func NewUserService(userRepository repository.UserRepository) UserService {
-	return &userService{userRepository: userRepository}
+// NewUserService membuat UserService. loginGuard boleh nil jika lockout
+// login tidak dibutuhkan, mis. pada command CLI.
+func NewUserService(userRepository repository.UserRepository, uow repository.UnitOfWork, loginGuard LoginGuard) UserService {
+	return &userService{userRepository: userRepository, uow: uow, loginGuard: loginGuard}
 }
 
-func (s *userService) Register(req *entity.RegisterUserReq) (*entity.UserRes, error) {
-	exists, err := s.userRepository.IsEmailExists(req.Email)
+func (s *userService) Register(ctx context.Context, req *entity.RegisterUserReq) (_ *entity.UserRes, err error) {
+	ctx, end := startSpan(ctx, "UserService.Register")
+	defer end(&err)
+
+	exists, err := s.userRepository.IsEmailExists(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("email already registered")
+		return nil, ErrEmailTaken
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
@@ -51,7 +109,7 @@ func (s *userService) Register(req *entity.RegisterUserReq) (*entity.UserRes, er
 		Role:     "user", // Default role
 	}
 
-	err = s.userRepository.Create(user)
+	err = s.userRepository.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -68,23 +126,43 @@ func (s *userService) Register(req *entity.RegisterUserReq) (*entity.UserRes, er
 	return userRes, nil
 }
 
-func (s *userService) Login(req *entity.LoginUserReq) (*entity.UserRes, string, error) {
-	// Cari user berdasarkan email
-	user, err := s.userRepository.FindUserByEmail(req.Email)
+func (s *userService) Login(ctx context.Context, req *entity.LoginUserReq) (_ *entity.UserRes, _ string, err error) {
+	ctx, end := startSpan(ctx, "UserService.Login")
+	defer end(&err)
+
+	// Akun yang terkunci ditolak sebelum password diperiksa
+	account := strings.ToLower(strings.TrimSpace(req.Email))
+	if locked := s.lockedFor(ctx, account); locked > 0 {
+		return nil, "", &AccountLockedError{RetryAfter: locked}
+	}
+
+	// Cari user berdasarkan email. Email yang tidak terdaftar dijawab sama
+	// dengan password yang salah dan juga dihitung sebagai login gagal, agar
+	// response maupun lockout tidak membocorkan email mana yang terdaftar
+	user, err := s.userRepository.FindUserByEmail(ctx, req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Tetap jalankan bcrypt agar waktu response tidak membedakan email
+		// yang tidak terdaftar dari password yang salah
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(req.Password))
+		s.recordLogin(ctx, account, false)
+		return nil, "", ErrInvalidCredentials
+	}
 	if err != nil {
-		return nil, "", errors.New("user not found")
+		return nil, "", err
 	}
 
 	// Bandingkan password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
-		return nil, "", errors.New("invalid password")
+		s.recordLogin(ctx, account, false)
+		return nil, "", ErrInvalidCredentials
 	}
+	s.recordLogin(ctx, account, true)
 
 	// Generate token JWT
 	token, err := utils.GenerateJWT(user.ID, user.Role)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	userRes := &entity.UserRes{
@@ -99,10 +177,40 @@ func (s *userService) Login(req *entity.LoginUserReq) (*entity.UserRes, string,
 	return userRes, token, nil
 }
 
-func (s *userService) GetUserByID(id int) (*entity.UserRes, error) {
-	user, err := s.userRepository.FindByID(id)
+// lockedFor mengembalikan sisa waktu kunci akun. Jika store lockout gagal,
+// login tetap diizinkan agar gangguan store tidak mengunci semua user.
+func (s *userService) lockedFor(ctx context.Context, account string) time.Duration {
+	if s.loginGuard == nil {
+		return 0
+	}
+	locked, err := s.loginGuard.Check(ctx, account)
 	if err != nil {
-		return nil, err
+		slog.WarnContext(ctx, "failed to check login lockout", "error", err)
+		return 0
+	}
+	return locked
+}
+
+func (s *userService) recordLogin(ctx context.Context, account string, success bool) {
+	if s.loginGuard == nil {
+		return
+	}
+	record := s.loginGuard.Failed
+	if success {
+		record = s.loginGuard.Succeeded
+	}
+	if err := record(ctx, account); err != nil {
+		slog.WarnContext(ctx, "failed to record login attempt", "success", success, "error", err)
+	}
+}
+
+func (s *userService) GetUserByID(ctx context.Context, id int) (_ *entity.UserRes, err error) {
+	ctx, end := startSpan(ctx, "UserService.GetUserByID")
+	defer end(&err)
+
+	user, err := s.userRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
 	}
 
 	userRes := &entity.UserRes{
@@ -117,31 +225,49 @@ func (s *userService) GetUserByID(id int) (*entity.UserRes, error) {
 	return userRes, nil
 }
 
-func (s *userService) GetAllUsers(limit, offset int) ([]*entity.UserRes, error) {
-	users, err := s.userRepository.FindAll(limit, offset)
+func (s *userService) GetAllUsers(ctx context.Context, q listquery.Query) (_ listquery.Page[*entity.UserRes], err error) {
+	ctx, end := startSpan(ctx, "UserService.GetAllUsers")
+	defer end(&err)
+
+	page, err := s.userRepository.FindAll(ctx, q)
 	if err != nil {
-		return nil, err
+		return listquery.Page[*entity.UserRes]{}, err
 	}
 
-	var userRes []*entity.UserRes
-	for _, user := range users {
-		userRes = append(userRes, &entity.UserRes{
+	return listquery.Map(page, func(user entity.User) *entity.UserRes {
+		return &entity.UserRes{
 			ID:        user.ID,
 			Name:      user.Name,
 			Email:     user.Email,
 			Role:      user.Role,
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
-		})
-	}
+		}
+	}), nil
+}
 
-	return userRes, nil
+// checkRoleChange memastikan role hanya diubah oleh admin, dan role
+// technician hanya didapat melalui pengajuan yang disetujui.
+func checkRoleChange(newRole, callerRole string) error {
+	if callerRole != "admin" {
+		return ErrRoleChangeForbidden
+	}
+	if newRole == "technician" {
+		return ErrTechnicianRoleRequiresApplication
+	}
+	return nil
 }
 
-func (s *userService) UpdateUser(req *entity.UpdateUserReq) (*entity.UserRes, error) {
-	user, err := s.userRepository.FindByID(req.ID)
+func (s *userService) UpdateUser(ctx context.Context, req *entity.UpdateUserReq, callerID int, callerRole string) (_ *entity.UserRes, err error) {
+	ctx, end := startSpan(ctx, "UserService.UpdateUser")
+	defer end(&err)
+
+	if callerRole != "admin" && req.ID != callerID {
+		return nil, ErrUserUpdateForbidden
+	}
+	user, err := s.userRepository.FindByID(ctx, req.ID)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrUserNotFound)
 	}
 
 	if req.Name != "" {
@@ -157,7 +283,11 @@ func (s *userService) UpdateUser(req *entity.UpdateUserReq) (*entity.UserRes, er
 		}
 		user.Password = string(hashedPassword)
 	}
-	if req.Role != "" {
+	roleChanged := req.Role != "" && req.Role != user.Role
+	if roleChanged {
+		if err := checkRoleChange(req.Role, callerRole); err != nil {
+			return nil, err
+		}
 		user.Role = req.Role
 	}
 	if req.Address != "" {
@@ -166,11 +296,20 @@ func (s *userService) UpdateUser(req *entity.UpdateUserReq) (*entity.UserRes, er
 	if req.Phone != "" {
 		user.Phone = req.Phone
 	}
+	if req.Language != "" {
+		if req.Language != "id" && req.Language != "en" {
+			return nil, ErrInvalidLanguage
+		}
+		user.Language = req.Language
+	}
 
-	err = s.userRepository.Update(user)
+	err = s.userRepository.Update(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+	if roleChanged {
+		refreshActiveTechnicians(ctx, s.userRepository)
+	}
 
 	userRes := &entity.UserRes{
 		ID:        user.ID,
@@ -184,44 +323,16 @@ func (s *userService) UpdateUser(req *entity.UpdateUserReq) (*entity.UserRes, er
 	return userRes, nil
 }
 
-func (s *userService) RegisterAsTechnician(req *entity.RegisterAsTechnicianReq) (*entity.TechnicianRes, error) {
-	user, err := s.userRepository.FindByID(req.ID)
-	if err != nil {
-		return nil, errors.New("user not found")
-	}
-
-	// Update user role and technician-specific fields
-	user.Role = "technician"
-	user.Address = req.Address
-	user.Phone = req.Phone
-	user.Expertise = req.Expertise
-	user.Availability = req.Availability
+func (s *userService) UpdateTechnician(ctx context.Context, req *entity.UpdateTechnicianReq, callerID int, callerRole string) (_ *entity.TechnicianRes, err error) {
+	ctx, end := startSpan(ctx, "UserService.UpdateTechnician")
+	defer end(&err)
 
-	err = s.userRepository.Update(user)
-	if err != nil {
-		return nil, err
-	}
-
-	technicianRes := &entity.TechnicianRes{
-		ID:           user.ID,
-		Name:         user.Name,
-		Email:        user.Email,
-		Role:         user.Role,
-		Address:      user.Address,
-		Phone:        user.Phone,
-		Expertise:    user.Expertise,
-		Availability: user.Availability,
-		CreatedAt:    user.CreatedAt,
-		UpdatedAt:    user.UpdatedAt,
+	if callerRole != "admin" && req.ID != callerID {
+		return nil, ErrUserUpdateForbidden
 	}
-
-	return technicianRes, nil
-}
-
-func (s *userService) UpdateTechnician(req *entity.UpdateTechnicianReq) (*entity.TechnicianRes, error) {
-	user, err := s.userRepository.FindByID(req.ID)
+	user, err := s.userRepository.FindByID(ctx, req.ID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, notFound(err, ErrUserNotFound)
 	}
 
 	if req.Name != "" {
@@ -237,7 +348,11 @@ func (s *userService) UpdateTechnician(req *entity.UpdateTechnicianReq) (*entity
 		}
 		user.Password = string(hashedPassword)
 	}
-	if req.Role != "" {
+	roleChanged := req.Role != "" && req.Role != user.Role
+	if roleChanged {
+		if err := checkRoleChange(req.Role, callerRole); err != nil {
+			return nil, err
+		}
 		user.Role = req.Role
 	}
 	if req.Address != "" {
@@ -253,10 +368,13 @@ func (s *userService) UpdateTechnician(req *entity.UpdateTechnicianReq) (*entity
 		user.Availability = req.Availability
 	}
 
-	err = s.userRepository.Update(user)
+	err = s.userRepository.Update(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+	if roleChanged {
+		refreshActiveTechnicians(ctx, s.userRepository)
+	}
 
 	technicianRes := &entity.TechnicianRes{
 		ID:           user.ID,
@@ -274,18 +392,60 @@ func (s *userService) UpdateTechnician(req *entity.UpdateTechnicianReq) (*entity
 	return technicianRes, nil
 }
 
-func (s *userService) DeleteUser(id int) error {
-	return s.userRepository.Delete(id)
+// DeleteUser menghapus user beserta service miliknya, semua booking yang terkait
+// (termasuk payment, review dan pesan di dalamnya), pengajuan technician dan
+// preferensi notifikasi dalam satu transaksi.
+func (s *userService) DeleteUser(ctx context.Context, id int) (err error) {
+	ctx, end := startSpan(ctx, "UserService.DeleteUser")
+	defer end(&err)
+
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		bookingIDs, err := repos.Bookings.FindIDsByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := repos.Payments.DeleteByBookingIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Reviews.DeleteByBookingIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Messages.DeleteByBookingIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Bookings.DeleteByIDs(ctx, bookingIDs); err != nil {
+			return err
+		}
+		if err := repos.Services.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+		if err := repos.TechnicianApplications.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+		if err := repos.NotificationPreferences.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+
+		return repos.Users.Delete(ctx, id)
+	})
+	if err == nil {
+		refreshActiveTechnicians(ctx, s.userRepository)
+	}
+	return err
 }
 
-func (s *userService) RegisterAsAdmin(req *entity.RegisterUserReq) (*entity.UserRes, error) {
+func (s *userService) RegisterAsAdmin(ctx context.Context, req *entity.RegisterUserReq) (_ *entity.UserRes, err error) {
+	ctx, end := startSpan(ctx, "UserService.RegisterAsAdmin")
+	defer end(&err)
+
 	// Cek apakah email sudah terdaftar
-	exists, err := s.userRepository.IsEmailExists(req.Email)
+	exists, err := s.userRepository.IsEmailExists(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
-		return nil, errors.New("email already registered")
+		return nil, ErrEmailTaken
 	}
 
 	// Hash password
@@ -303,7 +463,7 @@ func (s *userService) RegisterAsAdmin(req *entity.RegisterUserReq) (*entity.User
 	}
 
 	// Simpan ke database
-	err = s.userRepository.Create(user)
+	err = s.userRepository.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -321,8 +481,11 @@ func (s *userService) RegisterAsAdmin(req *entity.RegisterUserReq) (*entity.User
 	return userRes, nil
 }
 
-func (s *userService) GetUserRoleReport(startDate, endDate string) (map[string]interface{}, error) {
-	roleDistribution, err := s.userRepository.GetUserRoleDistribution(startDate, endDate)
+func (s *userService) GetUserRoleReport(ctx context.Context, startDate, endDate string) (_ map[string]interface{}, err error) {
+	ctx, end := startSpan(ctx, "UserService.GetUserRoleReport")
+	defer end(&err)
+
+	roleDistribution, err := s.userRepository.GetUserRoleDistribution(ctx, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -339,3 +502,27 @@ func (s *userService) GetUserRoleReport(startDate, endDate string) (map[string]i
 
 	return report, nil
 }
+
+// ResetPassword mengganti password user berdasarkan email, dipakai oleh
+// operator lewat CLI ketika user tidak bisa login.
+func (s *userService) ResetPassword(ctx context.Context, email, password string) (err error) {
+	ctx, end := startSpan(ctx, "UserService.ResetPassword")
+	defer end(&err)
+
+	if password == "" {
+		return ErrPasswordRequired
+	}
+
+	user, err := s.userRepository.FindUserByEmail(ctx, email)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+
+	return s.userRepository.Update(ctx, user)
+}
@@ -0,0 +1,38 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserService_DeleteUserRemovesRelatedRows(t *testing.T) {
+	ctx := context.Background()
+	storage, customer, svc := newStorage(t)
+	users := service.NewUserService(storage.Users, storage.UnitOfWork, nil)
+	bookings := service.NewBookingService(storage.Bookings)
+	payments := service.NewPaymentService(storage.Payments, storage.UnitOfWork)
+
+	booking, err := bookings.CreateBooking(ctx, entity.CreateBookingReq{UserID: customer.ID, ServiceID: svc.ID, Date: time.Date(2030, 3, 5, 0, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+	payment, err := payments.CreatePayment(ctx, entity.CreatePaymentReq{BookingID: booking.ID, Amount: "150000"})
+	require.NoError(t, err)
+
+	// Menghapus technician ikut menghapus service, booking di service itu dan
+	// payment-nya dalam satu unit of work
+	require.NoError(t, users.DeleteUser(ctx, svc.UserID))
+	_, err = storage.Services.FindByID(ctx, svc.ID)
+	assert.Error(t, err)
+	_, err = bookings.GetBookingByID(ctx, booking.ID)
+	assert.ErrorIs(t, err, service.ErrBookingNotFound)
+	_, err = payments.GetPaymentByID(ctx, payment.ID)
+	assert.ErrorIs(t, err, service.ErrPaymentNotFound)
+
+	_, err = storage.Users.FindByID(ctx, customer.ID)
+	assert.NoError(t, err)
+}
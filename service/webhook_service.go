@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/event"
+	"github.com/Ayyasy123/dibimbing-capstone.git/listquery"
+	"github.com/Ayyasy123/dibimbing-capstone.git/repository"
+	"github.com/Ayyasy123/dibimbing-capstone.git/webhook"
+)
+
+// Endpoint dinonaktifkan otomatis setelah 15 percobaan gagal berturut-turut
+// (kira-kira tiga event yang gagal dikirim sampai percobaan terakhir).
+const webhookDisableAfter = 15
+
+var (
+	ErrWebhookNotFound         = apperror.NotFound("webhook_not_found", "webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = apperror.NotFound("webhook_delivery_not_found", "webhook delivery not found")
+	ErrWebhookDisabled         = apperror.Conflict("webhook_disabled", "webhook endpoint is disabled")
+	ErrInvalidWebhookURL       = apperror.Validation("invalid_webhook_url", "url must be a valid http or https URL")
+	ErrWebhookEventTypesEmpty  = apperror.Validation("webhook_event_types_empty", "event_types must not be empty")
+)
+
+// WebhookDeliveryJobPayload adalah payload job pengiriman webhook.
+type WebhookDeliveryJobPayload struct {
+	DeliveryID int `json:"delivery_id"`
+}
+
+// WebhookEventTypes mengembalikan tipe event yang bisa dilanggan partner.
+func WebhookEventTypes() []string {
+	return []string{
+		"booking.created", "booking.confirmed", "booking.in_progress", "booking.completed",
+		"booking.cancelled", "booking.expired",
+		"payment.created", "payment.paid", "payment.failed", "payment.refunded", "payment.expired",
+		"review.created",
+	}
+}
+
+type WebhookService interface {
+	CreateEndpoint(ctx context.Context, req *entity.CreateWebhookEndpointReq) (*entity.WebhookEndpointRes, error)
+	GetEndpoints(ctx context.Context, q listquery.Query) (listquery.Page[entity.WebhookEndpointRes], error)
+	GetEndpointByID(ctx context.Context, id int) (*entity.WebhookEndpointRes, error)
+	UpdateEndpoint(ctx context.Context, id int, req *entity.UpdateWebhookEndpointReq) (*entity.WebhookEndpointRes, error)
+	DeleteEndpoint(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context, endpointID int, q listquery.Query) (listquery.Page[entity.WebhookDelivery], error)
+	Redeliver(ctx context.Context, endpointID, deliveryID int) (*entity.WebhookDelivery, error)
+	HandleEvent(ctx context.Context, evt event.Event) error
+	Deliver(ctx context.Context, deliveryID int, lastAttempt bool) error
+	RotateSecret(ctx context.Context, id int) (*entity.WebhookEndpointRes, error)
+}
+
+type webhookService struct {
+	repo   repository.WebhookRepository
+	jobs   JobEnqueuer
+	client *http.Client
+}
+
+func NewWebhookService(repo repository.WebhookRepository, jobs JobEnqueuer, client *http.Client) WebhookService {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &webhookService{repo: repo, jobs: jobs, client: client}
+}
+
+func (s *webhookService) CreateEndpoint(ctx context.Context, req *entity.CreateWebhookEndpointReq) (_ *entity.WebhookEndpointRes, err error) {
+	ctx, end := startSpan(ctx, "WebhookService.CreateEndpoint")
+	defer end(&err)
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := validateWebhookEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := &entity.WebhookEndpoint{
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Description: req.Description,
+		Active:      true,
+	}
+
+	err = s.repo.CreateEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Secret hanya ditampilkan sekali saat endpoint dibuat
+	endpointRes := toWebhookEndpointRes(*endpoint)
+	endpointRes.Secret = secret
+	return &endpointRes, nil
+}
+
+func (s *webhookService) GetEndpoints(ctx context.Context, q listquery.Query) (_ listquery.Page[entity.WebhookEndpointRes], err error) {
+	ctx, end := startSpan(ctx, "WebhookService.GetEndpoints")
+	defer end(&err)
+
+	page, err := s.repo.FindEndpoints(ctx, q)
+	if err != nil {
+		return listquery.Page[entity.WebhookEndpointRes]{}, err
+	}
+	return listquery.Map(page, toWebhookEndpointRes), nil
+}
+
+func (s *webhookService) GetEndpointByID(ctx context.Context, id int) (_ *entity.WebhookEndpointRes, err error) {
+	ctx, end := startSpan(ctx, "WebhookService.GetEndpointByID")
+	defer end(&err)
+
+	endpoint, err := s.repo.FindEndpointByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookNotFound)
+	}
+
+	endpointRes := toWebhookEndpointRes(*endpoint)
+	return &endpointRes, nil
+}
+
+func (s *webhookService) UpdateEndpoint(ctx context.Context, id int, req *entity.UpdateWebhookEndpointReq) (_ *entity.WebhookEndpointRes, err error) {
+	ctx, end := startSpan(ctx, "WebhookService.UpdateEndpoint")
+	defer end(&err)
+
+	endpoint, err := s.repo.FindEndpointByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookNotFound)
+	}
+
+	if req.URL != "" {
+		if err := validateWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+		endpoint.URL = req.URL
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := validateWebhookEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = eventTypes
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+		if endpoint.Active {
+			// Aktifkan kembali endpoint yang dinonaktifkan otomatis
+			endpoint.ConsecutiveFailures = 0
+			endpoint.DisabledAt = nil
+		}
+	}
+
+	err = s.repo.UpdateEndpoint(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointRes := toWebhookEndpointRes(*endpoint)
+	return &endpointRes, nil
+}
+
+func (s *webhookService) DeleteEndpoint(ctx context.Context, id int) (err error) {
+	ctx, end := startSpan(ctx, "WebhookService.DeleteEndpoint")
+	defer end(&err)
+
+	if _, err := s.repo.FindEndpointByID(ctx, id); err != nil {
+		return notFound(err, ErrWebhookNotFound)
+	}
+	return s.repo.DeleteEndpoint(ctx, id)
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, endpointID int, q listquery.Query) (_ listquery.Page[entity.WebhookDelivery], err error) {
+	ctx, end := startSpan(ctx, "WebhookService.GetDeliveries")
+	defer end(&err)
+
+	if _, err := s.repo.FindEndpointByID(ctx, endpointID); err != nil {
+		return listquery.Page[entity.WebhookDelivery]{}, notFound(err, ErrWebhookNotFound)
+	}
+
+	return s.repo.FindDeliveriesByEndpointID(ctx, endpointID, q)
+}
+
+// Redeliver menjadwalkan ulang pengiriman sebuah delivery secara manual.
+func (s *webhookService) Redeliver(ctx context.Context, endpointID, deliveryID int) (_ *entity.WebhookDelivery, err error) {
+	ctx, end := startSpan(ctx, "WebhookService.Redeliver")
+	defer end(&err)
+
+	delivery, err := s.repo.FindDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookDeliveryNotFound)
+	}
+	if delivery.EndpointID != endpointID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if !delivery.Endpoint.Active {
+		return nil, ErrWebhookDisabled
+	}
+
+	delivery.Status = "Pending"
+	err = s.repo.UpdateDelivery(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := "webhook_delivery:" + strconv.Itoa(delivery.ID) + ":redeliver:" + strconv.FormatInt(now.UnixNano(), 10)
+	err = s.jobs.Enqueue(ctx, "webhook_delivery", WebhookDeliveryJobPayload{DeliveryID: delivery.ID}, now, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// HandleEvent dipasang sebagai subscriber outbox: membuat delivery untuk setiap
+// endpoint yang berlangganan lalu menjadwalkan pengirimannya.
+func (s *webhookService) HandleEvent(ctx context.Context, evt event.Event) (err error) {
+	ctx, end := startSpan(ctx, "WebhookService.HandleEvent")
+	defer end(&err)
+
+	endpoints, err := s.repo.FindActiveEndpointsByEvent(ctx, evt.Type)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":          evt.ID,
+		"type":        evt.Type,
+		"occurred_at": evt.OccurredAt,
+		"data":        evt.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, endpoint := range endpoints {
+		delivery := &entity.WebhookDelivery{
+			EndpointID: endpoint.ID,
+			EventID:    evt.ID,
+			EventType:  evt.Type,
+			Payload:    string(payload),
+			Status:     "Pending",
+		}
+
+		created, err := s.repo.CreateDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+		if !created {
+			// Event dikirim ulang oleh dispatcher; pakai delivery yang sudah ada
+			delivery, err = s.repo.FindDeliveryByEvent(ctx, endpoint.ID, evt.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		key := "webhook_delivery:" + strconv.Itoa(delivery.ID)
+		if err := s.jobs.Enqueue(ctx, "webhook_delivery", WebhookDeliveryJobPayload{DeliveryID: delivery.ID}, now, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deliver mengirim satu delivery dan mencatat hasilnya. Error dikembalikan agar
+// scheduler mencoba lagi dengan exponential backoff.
+func (s *webhookService) Deliver(ctx context.Context, deliveryID int, lastAttempt bool) (err error) {
+	ctx, end := startSpan(ctx, "WebhookService.Deliver")
+	defer end(&err)
+
+	delivery, err := s.repo.FindDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.Status == "Succeeded" {
+		return nil
+	}
+
+	endpoint := delivery.Endpoint
+	if !endpoint.Active {
+		delivery.Status = "Failed"
+		delivery.LastError = "endpoint is disabled"
+		return s.repo.UpdateDelivery(ctx, delivery)
+	}
+
+	now := time.Now()
+	result, sendErr := webhook.Send(ctx, s.client, webhook.Request{
+		URL:        endpoint.URL,
+		Secret:     endpoint.Secret,
+		EventType:  delivery.EventType,
+		DeliveryID: delivery.ID,
+		Body:       []byte(delivery.Payload),
+	}, now)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = result.StatusCode
+	delivery.ResponseBody = result.ResponseBody
+	delivery.DurationMs = result.Duration.Milliseconds()
+
+	if sendErr == nil {
+		delivery.Status = "Succeeded"
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		return s.repo.ResetFailures(ctx, endpoint.ID)
+	}
+
+	delivery.LastError = sendErr.Error()
+	delivery.Status = "Pending"
+	if lastAttempt {
+		delivery.Status = "Failed"
+	}
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	disabled, err := s.repo.RecordFailure(ctx, endpoint.ID, webhookDisableAfter, now)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		slog.WarnContext(ctx, "webhook: endpoint disabled after consecutive failures", "endpoint_id", endpoint.ID, "failures", webhookDisableAfter)
+	}
+
+	return sendErr
+}
+
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// validateWebhookEventTypes mengecek tipe event dan mengembalikannya sebagai
+// string yang dipisah koma.
+func validateWebhookEventTypes(eventTypes []string) (string, error) {
+	if len(eventTypes) == 0 {
+		return "", ErrWebhookEventTypesEmpty
+	}
+
+	valid := make(map[string]bool)
+	for _, t := range WebhookEventTypes() {
+		valid[t] = true
+	}
+
+	seen := make(map[string]bool)
+	var unique []string
+	for _, t := range eventTypes {
+		if !valid[t] {
+			return "", ErrUnknownEventType.WithMessage("unknown event type: " + t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return strings.Join(unique, ","), nil
+}
+
+func toWebhookEndpointRes(endpoint entity.WebhookEndpoint) entity.WebhookEndpointRes {
+	return entity.WebhookEndpointRes{
+		ID:                  endpoint.ID,
+		URL:                 endpoint.URL,
+		EventTypes:          strings.Split(endpoint.EventTypes, ","),
+		Description:         endpoint.Description,
+		Active:              endpoint.Active,
+		ConsecutiveFailures: endpoint.ConsecutiveFailures,
+		DisabledAt:          endpoint.DisabledAt,
+		CreatedAt:           endpoint.CreatedAt,
+		UpdatedAt:           endpoint.UpdatedAt,
+	}
+}
+
+// RotateSecret membuat secret baru untuk endpoint. Seperti saat pembuatan,
+// secret baru hanya ditampilkan sekali.
+func (s *webhookService) RotateSecret(ctx context.Context, id int) (_ *entity.WebhookEndpointRes, err error) {
+	ctx, end := startSpan(ctx, "WebhookService.RotateSecret")
+	defer end(&err)
+
+	endpoint, err := s.repo.FindEndpointByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrWebhookNotFound)
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = secret
+
+	if err := s.repo.UpdateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	endpointRes := toWebhookEndpointRes(*endpoint)
+	endpointRes.Secret = secret
+	return &endpointRes, nil
+}
@@ -0,0 +1,35 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/config"
+	"github.com/Ayyasy123/dibimbing-capstone.git/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_StdoutExporter(t *testing.T) {
+	var buf bytes.Buffer
+	shutdown, err := tracing.Setup(context.Background(), config.TracingConfig{
+		Exporter:    config.TracingStdout,
+		SampleRatio: 1,
+		ServiceName: "capstone-test",
+	}, &buf)
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "BookingService.CreateBooking")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	assert.Contains(t, buf.String(), `"Name":"BookingService.CreateBooking"`)
+	assert.Contains(t, buf.String(), "capstone-test")
+}
+
+func TestSetup_RejectsUnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), config.TracingConfig{Exporter: "jaeger"}, nil)
+	assert.Error(t, err)
+}
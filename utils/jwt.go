@@ -2,16 +2,19 @@ package utils
 
 import (
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var secretKey = os.Getenv("JWT_SECRET_KEY")
-var jwtKey = []byte(secretKey)
+var jwtKey []byte
+var jwtTTL = 24 * time.Hour
 
-// var jwtKey = []byte("your-secret-key")
+// ConfigureJWT mengatur secret dan masa berlaku token; dipanggil saat startup.
+func ConfigureJWT(secret string, ttl time.Duration) {
+	jwtKey = []byte(secret)
+	jwtTTL = ttl
+}
 
 type Claims struct {
 	UserID int    `json:"user_id"`
@@ -20,7 +23,11 @@ type Claims struct {
 }
 
 func GenerateJWT(userID int, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // Token berlaku selama 24 jam
+	if len(jwtKey) == 0 {
+		return "", errors.New("JWT secret is not configured")
+	}
+
+	expirationTime := time.Now().Add(jwtTTL) // Default token berlaku selama 24 jam
 
 	claims := &Claims{
 		UserID: userID,
@@ -43,6 +50,9 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if len(jwtKey) == 0 {
+			return nil, errors.New("JWT secret is not configured")
+		}
 		return jwtKey, nil
 	})
 
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+var uploadDir = "uploads"
+
+const maxUploadSize = 5 << 20 // 5 MB
+
+// ErrInvalidUpload dikembalikan SaveUpload jika file tidak ada atau tidak
+// memenuhi syarat ukuran dan jenis.
+var ErrInvalidUpload = apperror.Validation("invalid_upload", "invalid upload")
+
+var allowedUploadExts = map[string]bool{
+	".pdf":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+}
+
+// SetUploadDir mengatur direktori penyimpanan file upload; dipanggil saat startup.
+func SetUploadDir(dir string) {
+	uploadDir = dir
+}
+
+// UploadDir mengembalikan direktori penyimpanan file upload (default: "uploads").
+func UploadDir() string {
+	return uploadDir
+}
+
+// SaveUpload menyimpan file dari field multipart ke upload dir dan
+// mengembalikan path relatif terhadap upload dir. File yang tidak valid
+// ditolak dengan ErrInvalidUpload.
+func SaveUpload(ctx *gin.Context, field, prefix string) (string, error) {
+	file, err := ctx.FormFile(field)
+	if err != nil {
+		return "", ErrInvalidUpload.WithMessage(field + " is required")
+	}
+
+	if file.Size > maxUploadSize {
+		return "", ErrInvalidUpload.WithMessage(field + " must not exceed 5 MB")
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !allowedUploadExts[ext] {
+		return "", ErrInvalidUpload.WithMessage(field + " must be a pdf, jpg or png file")
+	}
+
+	if err := os.MkdirAll(UploadDir(), 0o755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s_%s_%d%s", prefix, field, time.Now().UnixNano(), ext)
+	if err := ctx.SaveUploadedFile(file, filepath.Join(UploadDir(), name)); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// UploadPath mengembalikan path lengkap dari file yang disimpan oleh SaveUpload.
+func UploadPath(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", errors.New("invalid file name")
+	}
+	return filepath.Join(UploadDir(), name), nil
+}
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// ErrValidationFailed dikembalikan saat satu atau lebih field request tidak
+// valid. Daftar field ada di Fields (field "errors" pada response).
+var ErrValidationFailed = apperror.Validation("validation_failed", "request validation failed")
+
+// typeRule adalah Rule untuk field JSON yang tipenya salah, mis. string
+// dikirim untuk field angka.
+const typeRule = "type"
+
+// messages berisi pesan aturan khusus per bahasa. {0} diganti nama field.
+var messages = map[string]map[string]string{
+	"en": {
+		"email_addr":  "{0} must be a valid email address",
+		"phone_id":    "{0} must be an Indonesian phone number, e.g. 081234567890",
+		"rating":      fmt.Sprintf("{0} must be between %d and %d", MinRating, MaxRating),
+		"future_date": "{0} must be a date after today",
+		"money":       "{0} must be an amount greater than 0",
+		typeRule:      "{0} has an invalid type",
+	},
+	"id": {
+		"email_addr":  "{0} harus berupa alamat email yang valid",
+		"phone_id":    "{0} harus berupa nomor HP Indonesia, mis. 081234567890",
+		"rating":      fmt.Sprintf("{0} harus bernilai antara %d dan %d", MinRating, MaxRating),
+		"future_date": "{0} harus berupa tanggal setelah hari ini",
+		"money":       "{0} harus berupa nominal lebih dari 0",
+		typeRule:      "{0} memiliki tipe yang tidak valid",
+	},
+}
+
+// universal berisi translator bahasa Inggris (default) dan Indonesia.
+var universal *ut.UniversalTranslator
+
+func registerTranslations(v *validator.Validate) error {
+	universal = ut.New(en.New(), en.New(), id.New())
+
+	defaults := map[string]func(*validator.Validate, ut.Translator) error{
+		"en": en_translations.RegisterDefaultTranslations,
+		"id": id_translations.RegisterDefaultTranslations,
+	}
+	for lang, register := range defaults {
+		trans, _ := universal.GetTranslator(lang)
+		if err := register(v, trans); err != nil {
+			return fmt.Errorf("register %s translations: %w", lang, err)
+		}
+
+		for rule, message := range messages[lang] {
+			if err := trans.Add(rule, message, true); err != nil {
+				return fmt.Errorf("register %s message for %s: %w", lang, rule, err)
+			}
+			if rule == typeRule {
+				continue
+			}
+			if err := v.RegisterTranslation(rule, trans, noopRegister, translateField); err != nil {
+				return fmt.Errorf("register %s translation for %s: %w", lang, rule, err)
+			}
+		}
+	}
+	return nil
+}
+
+// noopRegister dipakai karena pesan sudah ditambahkan lewat trans.Add.
+func noopRegister(ut.Translator) error { return nil }
+
+func translateField(trans ut.Translator, fe validator.FieldError) string {
+	message, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+	return message
+}
+
+// Fields mengubah error binding menjadi daftar field yang tidak valid dengan
+// pesan dalam bahasa dari header Accept-Language (id atau en, default en).
+// Error lain, mis. JSON yang rusak, menghasilkan nil.
+func Fields(err error, acceptLanguage string) []apperror.FieldError {
+	trans := translator(acceptLanguage)
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperror.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			message := fe.Error()
+			if trans != nil {
+				message = fe.Translate(trans)
+			}
+			fields = append(fields, apperror.FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: message,
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		message := typeErr.Error()
+		if trans != nil {
+			if translated, err := trans.T(typeRule, typeErr.Field); err == nil {
+				message = translated
+			}
+		}
+		return []apperror.FieldError{{Field: typeErr.Field, Rule: typeRule, Message: message}}
+	}
+
+	return nil
+}
+
+// fieldPath membuang nama struct di depan namespace, sehingga
+// "UpdateNotificationPreferencesReq.preferences[0].channel" menjadi
+// "preferences[0].channel".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// translator memilih bahasa pertama dari Accept-Language yang didukung,
+// mis. "id-ID,id;q=0.9,en;q=0.8" memilih id.
+func translator(acceptLanguage string) ut.Translator {
+	if universal == nil {
+		return nil
+	}
+
+	var locales []string
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.Split(part, ";")[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		locales = append(locales, strings.ToLower(strings.Split(tag, "-")[0]))
+	}
+
+	trans, _ := universal.FindTranslator(locales...)
+	return trans
+}
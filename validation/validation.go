@@ -0,0 +1,147 @@
+// Package validation memasang go-playground/validator sebagai validator
+// binding Gin. Aturan ditulis di tag `validate` pada struct request di
+// package entity, dan divalidasi otomatis oleh ShouldBind/ShouldBindJSON.
+// Selain aturan bawaan validator, tersedia aturan khusus:
+//
+//	email_addr   alamat email tanpa nama tampilan, domain wajib punya titik
+//	phone_id     nomor HP Indonesia: 08xx, 628xx atau +628xx
+//	rating       rating 1 sampai 5
+//	future_date  tanggal mulai besok (UTC)
+//	money        nominal lebih dari 0; string boleh berisi 2 angka desimal
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+var (
+	phonePattern = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,10}$`)
+	moneyPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})?$`)
+
+	setupOnce sync.Once
+	setupErr  error
+)
+
+// Setup mengganti tag validator binding Gin dari `binding` menjadi
+// `validate`, mendaftarkan aturan khusus dan terjemahan pesan error. Aman
+// dipanggil berkali-kali; konfigurasi hanya dipasang sekali.
+func Setup() error {
+	setupOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			setupErr = fmt.Errorf("unexpected gin validator engine %T", binding.Validator.Engine())
+			return
+		}
+		setupErr = configure(v)
+	})
+	return setupErr
+}
+
+func configure(v *validator.Validate) error {
+	v.SetTagName("validate")
+
+	// Nama field di pesan error mengikuti nama field JSON atau form
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.Split(field.Tag.Get(tag), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	rules := map[string]validator.Func{
+		"email_addr":  isEmail,
+		"phone_id":    isPhone,
+		"rating":      isRating,
+		"future_date": isFutureDate,
+		"money":       isMoney,
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("register rule %s: %w", tag, err)
+		}
+	}
+
+	return registerTranslations(v)
+}
+
+func isEmail(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if len(value) > 254 {
+		return false
+	}
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Name != "" || addr.Address != value {
+		return false
+	}
+	domain := value[strings.LastIndex(value, "@")+1:]
+	return strings.Contains(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// isPhone menerima spasi dan tanda hubung sebagai pemisah, mis.
+// "0812-3456-7890" atau "+62 812 3456 7890".
+func isPhone(fl validator.FieldLevel) bool {
+	value := strings.NewReplacer(" ", "", "-", "").Replace(fl.Field().String())
+	return phonePattern.MatchString(value)
+}
+
+func isRating(fl validator.FieldLevel) bool {
+	switch fl.Field().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rating := fl.Field().Int()
+		return rating >= MinRating && rating <= MaxRating
+	default:
+		return false
+	}
+}
+
+func isFutureDate(fl validator.FieldLevel) bool {
+	date, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	// Sama seperti bookingService: hari dihitung dalam UTC, dan jam berapa pun
+	// pada hari ini belum termasuk tanggal di masa depan
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	return !date.Before(tomorrow)
+}
+
+func isMoney(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		if !moneyPattern.MatchString(field.String()) {
+			return false
+		}
+		amount, err := strconv.ParseFloat(field.String(), 64)
+		return err == nil && amount > 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int() > 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint() > 0
+	case reflect.Float32, reflect.Float64:
+		return field.Float() > 0
+	default:
+		return false
+	}
+}
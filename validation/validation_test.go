@@ -0,0 +1,101 @@
+package validation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/apperror"
+	"github.com/Ayyasy123/dibimbing-capstone.git/entity"
+	"github.com/Ayyasy123/dibimbing-capstone.git/validation"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rules mengembalikan aturan yang dilanggar per field.
+func rules(t *testing.T, obj interface{}) map[string]string {
+	t.Helper()
+	require.NoError(t, validation.Setup())
+
+	result := map[string]string{}
+	for _, field := range validation.Fields(binding.Validator.ValidateStruct(obj), "") {
+		result[field.Field] = field.Rule
+	}
+	return result
+}
+
+func TestCustomRules(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	valid := entity.RegisterAsTechnicianReq{Address: "Jl. Merdeka", Phone: "081234567890", Expertise: "AC", Availability: "Senin", CertificationExpiresAt: tomorrow}
+	assert.Empty(t, rules(t, &valid))
+
+	for _, phone := range []string{"0812 3456 7890", "+6281234567890", "6281234567890", "0812-3456-789"} {
+		req := valid
+		req.Phone = phone
+		assert.Empty(t, rules(t, &req), phone)
+	}
+	for _, phone := range []string{"12345", "0212345678", "+1 555 123 4567", "08123", "0812345678901234"} {
+		req := valid
+		req.Phone = phone
+		assert.Equal(t, map[string]string{"phone": "phone_id"}, rules(t, &req), phone)
+	}
+
+	// Hari ini tetap ditolak walaupun jamnya sudah lewat tengah malam UTC
+	for _, date := range []time.Time{tomorrow.AddDate(0, 0, -1), tomorrow.Add(-time.Minute), tomorrow.Add(-12 * time.Hour)} {
+		req := valid
+		req.CertificationExpiresAt = date
+		assert.Equal(t, map[string]string{"certification_expires_at": "future_date"}, rules(t, &req), date)
+	}
+
+	for _, email := range []string{"budi@example.com", "budi.santoso+kerja@mail.co.id"} {
+		assert.Empty(t, rules(t, &entity.LoginUserReq{Email: email, Password: "x"}), email)
+	}
+	for _, email := range []string{"budi", "budi@localhost", "Budi <budi@example.com>", " budi@example.com", "budi@example."} {
+		assert.Equal(t, map[string]string{"email": "email_addr"}, rules(t, &entity.LoginUserReq{Email: email, Password: "x"}), email)
+	}
+
+	for rating, want := range map[int]map[string]string{1: {}, 5: {}, 6: {"rating": "rating"}, -1: {"rating": "rating"}} {
+		assert.Equal(t, want, rules(t, &entity.CreateReviewReq{BookingID: 1, Rating: rating, Comment: "ok"}), rating)
+	}
+
+	for amount, want := range map[string]map[string]string{"75000": {}, "75000.50": {}, "0": {"amount": "money"}, "-5": {"amount": "money"}, "1.234": {"amount": "money"}, "Rp75.000": {"amount": "money"}} {
+		assert.Equal(t, want, rules(t, &entity.CreatePaymentReq{BookingID: 1, Amount: amount, Status: "Pending"}), amount)
+	}
+	assert.Equal(t, map[string]string{"cost": "money"}, rules(t, &entity.CreateServiceReq{Name: "Servis AC", Cost: -1000}))
+}
+
+func TestFieldsAreTranslated(t *testing.T) {
+	require.NoError(t, validation.Setup())
+	err := binding.Validator.ValidateStruct(&entity.RegisterUserReq{Email: "budi"})
+
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "name", Rule: "required", Message: "name is a required field"},
+		{Field: "email", Rule: "email_addr", Message: "email must be a valid email address"},
+		{Field: "password", Rule: "required", Message: "password is a required field"},
+	}, validation.Fields(err, "fr-FR, en;q=0.5"))
+
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "name", Rule: "required", Message: "name wajib diisi"},
+		{Field: "email", Rule: "email_addr", Message: "email harus berupa alamat email yang valid"},
+		{Field: "password", Rule: "required", Message: "password wajib diisi"},
+	}, validation.Fields(err, "id-ID,id;q=0.9,en;q=0.8"))
+}
+
+func TestFieldsNestedAndTypeErrors(t *testing.T) {
+	require.NoError(t, validation.Setup())
+
+	req := entity.UpdateNotificationPreferencesReq{Preferences: []entity.NotificationPreferenceRes{{EventType: "booking.confirmed"}}}
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "preferences[0].channel", Rule: "required", Message: "channel is a required field"},
+	}, validation.Fields(binding.Validator.ValidateStruct(&req), ""))
+
+	var review entity.CreateReviewReq
+	err := binding.JSON.BindBody([]byte(`{"booking_id":1,"rating":"lima","comment":"ok"}`), &review)
+	assert.Equal(t, []apperror.FieldError{
+		{Field: "rating", Rule: "type", Message: "rating memiliki tipe yang tidak valid"},
+	}, validation.Fields(err, "id"))
+
+	// Error yang bukan error validasi tidak punya daftar field
+	err = binding.JSON.BindBody([]byte(`{"booking_id":`), &review)
+	assert.Nil(t, validation.Fields(err, ""))
+}
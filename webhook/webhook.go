@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Header yang dikirim bersama setiap webhook.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBody membatasi isi response yang disimpan di delivery log.
+const maxResponseBody = 2048
+
+// Request adalah satu pengiriman webhook ke endpoint partner.
+type Request struct {
+	URL        string
+	Secret     string
+	EventType  string
+	DeliveryID int
+	Body       []byte
+}
+
+// Result adalah hasil pengiriman yang dicatat di delivery log.
+type Result struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+}
+
+// GenerateSecret membuat secret acak untuk endpoint baru.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign menghitung signature HMAC-SHA256 dari "<timestamp>.<body>". Partner
+// memverifikasi dengan menghitung ulang nilai yang sama menggunakan secret-nya.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify mengecek signature dari header webhook.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Send mengirim webhook dan mengembalikan error jika endpoint tidak merespons
+// dengan status 2xx.
+func Send(ctx context.Context, client *http.Client, req Request, now time.Time) (Result, error) {
+	timestamp := now.Unix()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return Result{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Perbaiki.id-Webhook/1.0")
+	httpReq.Header.Set(HeaderEvent, req.EventType)
+	httpReq.Header.Set(HeaderDelivery, strconv.Itoa(req.DeliveryID))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	start := time.Now()
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return Result{Duration: time.Since(start)}, err
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBody))
+	result := Result{StatusCode: res.StatusCode, ResponseBody: string(body), Duration: time.Since(start)}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return result, fmt.Errorf("endpoint responded with status %d", res.StatusCode)
+	}
+	return result, nil
+}
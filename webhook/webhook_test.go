@@ -0,0 +1,72 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Ayyasy123/dibimbing-capstone.git/webhook"
+)
+
+func TestSendSignsPayload(t *testing.T) {
+	body := []byte(`{"type":"booking.confirmed","data":{"id":12}}`)
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+
+		if r.Header.Get(webhook.HeaderEvent) != "booking.confirmed" || r.Header.Get(webhook.HeaderDelivery) != "7" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		if timestamp != now.Unix() || !webhook.Verify("secret", timestamp, received, r.Header.Get(webhook.HeaderSignature)) {
+			t.Errorf("signature does not verify")
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result, err := webhook.Send(context.Background(), server.Client(), webhook.Request{
+		URL:        server.URL,
+		Secret:     "secret",
+		EventType:  "booking.confirmed",
+		DeliveryID: 7,
+		Body:       body,
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.StatusCode != http.StatusAccepted || result.ResponseBody != "ok" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestSendReturnsErrorOnNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	result, err := webhook.Send(context.Background(), server.Client(), webhook.Request{URL: server.URL, Secret: "secret", Body: []byte(`{}`)}, time.Now())
+	if err == nil {
+		t.Fatal("expected error for 503 response")
+	}
+	if result.StatusCode != http.StatusServiceUnavailable || result.ResponseBody != "maintenance\n" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	signature := webhook.Sign("secret", 1760860800, []byte(`{"amount":"150000"}`))
+	if webhook.Verify("secret", 1760860800, []byte(`{"amount":"1"}`), signature) {
+		t.Error("expected tampered body to fail verification")
+	}
+	if webhook.Verify("other", 1760860800, []byte(`{"amount":"150000"}`), signature) {
+		t.Error("expected wrong secret to fail verification")
+	}
+}